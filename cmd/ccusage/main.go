@@ -1,37 +1,9 @@
 package main
 
-import (
-	"context"
-	"fmt"
-	"os"
-
-	"github.com/sdpower/ccusage-go/internal/commands"
-	"github.com/spf13/cobra"
-)
+import "github.com/sdpower/ccusage-go/pkg/cli"
 
 var version = "v0.9.0"
 
 func main() {
-	ctx := context.Background()
-
-	rootCmd := &cobra.Command{
-		Use:     "ccusage",
-		Short:   "Claude Code usage analysis tool",
-		Long:    `A CLI tool for analyzing Claude Code usage data from local JSONL files.`,
-		Version: version,
-	}
-
-	rootCmd.AddCommand(
-		commands.NewDailyCommand(),
-		commands.NewMonthlyCommand(),
-		commands.NewWeeklyCommand(),
-		commands.NewSessionCommand(),
-		commands.NewBlocksCommand(),
-		commands.NewMonitorCommand(),
-	)
-
-	if err := rootCmd.ExecuteContext(ctx); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
-	}
+	cli.Main(version)
 }
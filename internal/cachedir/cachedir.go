@@ -0,0 +1,52 @@
+// Package cachedir locates the directory ccusage keeps its caches in:
+// fetched pricing and exchange rates, the parse index, dedupe stores and
+// remote data mirrors.
+package cachedir
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Dir returns the ccusage cache directory: ccusage under $XDG_CACHE_HOME
+// when it is set to an absolute path, else under the platform's cache
+// directory (~/.cache on Linux, ~/Library/Caches on macOS, %LocalAppData%
+// on Windows); a relative $XDG_CACHE_HOME is ignored, as the XDG
+// specification asks
+func Dir() (string, error) {
+	if xdg := os.Getenv("XDG_CACHE_HOME"); filepath.IsAbs(xdg) {
+		return filepath.Join(xdg, "ccusage"), nil
+	}
+	cacheHome, err := os.UserCacheDir()
+	if err != nil {
+		homeDir, homeErr := os.UserHomeDir() // Rejected a relative $XDG_CACHE_HOME
+		if homeErr != nil {
+			return "", err
+		}
+		cacheHome = filepath.Join(homeDir, ".cache")
+	}
+	return filepath.Join(cacheHome, "ccusage"), nil
+}
+
+// Path returns the file or directory name in the cache directory
+func Path(name string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// Legacy returns ~/.cache/ccusage, where caches were kept on every platform
+// before Dir, "" when it is Dir itself or there is no home directory
+func Legacy() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	legacy := filepath.Join(homeDir, ".cache", "ccusage")
+	if dir, err := Dir(); err == nil && dir == legacy {
+		return ""
+	}
+	return legacy
+}
@@ -0,0 +1,35 @@
+package cachedir
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	t.Setenv("XDG_CACHE_HOME", "/var/cache/me")
+	dir, err := Dir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/var/cache/me", "ccusage"), dir)
+	path, err := Path("pricing.json")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/var/cache/me", "ccusage", "pricing.json"), path)
+	assert.Equal(t, filepath.Join(home, ".cache", "ccusage"), Legacy())
+
+	// A relative $XDG_CACHE_HOME is invalid and ignored
+	t.Setenv("XDG_CACHE_HOME", "")
+	want, err := Dir()
+	require.NoError(t, err)
+	t.Setenv("XDG_CACHE_HOME", "cache")
+	dir, err = Dir()
+	require.NoError(t, err)
+	assert.Equal(t, want, dir)
+
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, ".cache"))
+	assert.Empty(t, Legacy())
+}
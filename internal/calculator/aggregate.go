@@ -0,0 +1,338 @@
+package calculator
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// GroupTotals accumulates token counts and costs for one report group (a day,
+// a month, a project, a source file, a billing block, ...)
+type GroupTotals struct {
+	InputTokens         int
+	OutputTokens        int
+	CacheCreationTokens int
+	CacheReadTokens     int
+	TotalTokens         int
+	Cost                float64
+	APICost             float64
+	CacheCreateCost     float64
+	CacheReadCost       float64
+	CacheSavings        float64 // Estimated saving from prompt caching
+	RequestCount        int
+	BatchRequests       int             // Requests made through the Batch API
+	BatchCost           float64         // Cost of the batch requests
+	Models              map[string]bool // Unique models, with <synthetic> as ModelOther
+	SessionIDs          map[string]bool // Unique session IDs
+	SourceFiles         map[string]bool // Unique log files the entries came from
+	FirstSeen           time.Time       // Earliest entry timestamp
+	LastSeen            time.Time       // Latest entry timestamp
+	SessionName         string          // Name of the earliest entry that has one
+	PeakContextTokens   int             // Largest request prompt (see ContextTokens)
+	ContextUtilization  float64         // Highest share of the context window a request used
+
+	sessionNameAt time.Time
+}
+
+// NewGroupTotals returns empty totals
+func NewGroupTotals() *GroupTotals {
+	return &GroupTotals{
+		Models:      make(map[string]bool),
+		SessionIDs:  make(map[string]bool),
+		SourceFiles: make(map[string]bool),
+	}
+}
+
+// Add folds a single entry into the totals
+func (g *GroupTotals) Add(entry types.UsageEntry) {
+	g.InputTokens += entry.InputTokens
+	g.OutputTokens += entry.OutputTokens
+	if cc, ok := entry.Raw["cache_creation_input_tokens"].(int); ok {
+		g.CacheCreationTokens += cc
+	}
+	if cr, ok := entry.Raw["cache_read_input_tokens"].(int); ok {
+		g.CacheReadTokens += cr
+	}
+	// Total includes cache tokens (matches TypeScript's getTotalTokens)
+	g.TotalTokens = g.InputTokens + g.OutputTokens + g.CacheCreationTokens + g.CacheReadTokens
+
+	g.Cost += entry.Cost
+	g.APICost += entry.APICost
+	g.CacheCreateCost += entry.CacheCreateCost
+	g.CacheReadCost += entry.CacheReadCost
+	g.CacheSavings += entry.CacheSavings
+	g.RequestCount++
+	if IsBatch(entry) {
+		g.BatchRequests++
+		g.BatchCost += entry.Cost
+	}
+	g.PeakContextTokens = max(g.PeakContextTokens, ContextTokens(entry))
+	g.ContextUtilization = max(g.ContextUtilization, ContextUtilization(entry))
+
+	if entry.Model != "" {
+		g.Models[displayModel(entry.Model)] = true
+	}
+	if entry.SessionID != "" {
+		g.SessionIDs[entry.SessionID] = true
+	}
+	if entry.SourceFile != "" {
+		g.SourceFiles[entry.SourceFile] = true
+	}
+
+	if g.RequestCount == 1 || entry.Timestamp.Before(g.FirstSeen) {
+		g.FirstSeen = entry.Timestamp
+	}
+	if g.RequestCount == 1 || entry.Timestamp.After(g.LastSeen) {
+		g.LastSeen = entry.Timestamp
+	}
+	if entry.SessionName != "" && (g.SessionName == "" || entry.Timestamp.Before(g.sessionNameAt)) {
+		g.SessionName = entry.SessionName
+		g.sessionNameAt = entry.Timestamp
+	}
+}
+
+// Merge folds the totals of another group into g
+func (g *GroupTotals) Merge(other *GroupTotals) {
+	if other.RequestCount == 0 {
+		return
+	}
+	if g.RequestCount == 0 || other.FirstSeen.Before(g.FirstSeen) {
+		g.FirstSeen = other.FirstSeen
+	}
+	if g.RequestCount == 0 || other.LastSeen.After(g.LastSeen) {
+		g.LastSeen = other.LastSeen
+	}
+	if other.SessionName != "" && (g.SessionName == "" || other.sessionNameAt.Before(g.sessionNameAt)) {
+		g.SessionName = other.SessionName
+		g.sessionNameAt = other.sessionNameAt
+	}
+
+	g.InputTokens += other.InputTokens
+	g.OutputTokens += other.OutputTokens
+	g.CacheCreationTokens += other.CacheCreationTokens
+	g.CacheReadTokens += other.CacheReadTokens
+	g.TotalTokens += other.TotalTokens
+	g.Cost += other.Cost
+	g.APICost += other.APICost
+	g.CacheCreateCost += other.CacheCreateCost
+	g.CacheReadCost += other.CacheReadCost
+	g.CacheSavings += other.CacheSavings
+	g.RequestCount += other.RequestCount
+	g.BatchRequests += other.BatchRequests
+	g.BatchCost += other.BatchCost
+	g.PeakContextTokens = max(g.PeakContextTokens, other.PeakContextTokens)
+	g.ContextUtilization = max(g.ContextUtilization, other.ContextUtilization)
+	for model := range other.Models {
+		g.Models[model] = true
+	}
+	for sessionID := range other.SessionIDs {
+		g.SessionIDs[sessionID] = true
+	}
+	for file := range other.SourceFiles {
+		g.SourceFiles[file] = true
+	}
+}
+
+// TokenCounts returns the group's tokens by type
+func (g *GroupTotals) TokenCounts() types.TokenCounts {
+	return types.TokenCounts{
+		InputTokens:              g.InputTokens,
+		OutputTokens:             g.OutputTokens,
+		CacheCreationInputTokens: g.CacheCreationTokens,
+		CacheReadInputTokens:     g.CacheReadTokens,
+	}
+}
+
+// SortedModels returns the group's models in order, nil when there are none
+func (g *GroupTotals) SortedModels() []string {
+	return sortedSet(g.Models)
+}
+
+// SortedSessionIDs returns the group's session IDs in order, nil when there are none
+func (g *GroupTotals) SortedSessionIDs() []string {
+	return sortedSet(g.SessionIDs)
+}
+
+// SortedSourceFiles returns the group's log files in order, nil when there are none
+func (g *GroupTotals) SortedSourceFiles() []string {
+	return sortedSet(g.SourceFiles)
+}
+
+func sortedSet(set map[string]bool) []string {
+	var values []string
+	for value := range set {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return values
+}
+
+// KeyFunc maps an entry to its group key; an empty key skips the entry
+type KeyFunc func(entry types.UsageEntry) string
+
+// DailyKey groups entries by date (YYYY-MM-DD), using the loader's DateKey
+// when present and converting the timestamp to loc otherwise
+func DailyKey(loc *time.Location) KeyFunc {
+	return func(entry types.UsageEntry) string {
+		// Skip invalid timestamps
+		if entry.Timestamp.IsZero() || entry.Timestamp.Year() < 2020 {
+			return ""
+		}
+		if entry.DateKey != "" {
+			return entry.DateKey
+		}
+		return entry.Timestamp.In(locationOrLocal(loc)).Format("2006-01-02")
+	}
+}
+
+// MonthlyKey groups entries by month (YYYY-MM), consistent with DailyKey
+func MonthlyKey(loc *time.Location) KeyFunc {
+	daily := DailyKey(loc)
+	return func(entry types.UsageEntry) string {
+		date := daily(entry)
+		if len(date) < 7 {
+			return ""
+		}
+		return date[:7]
+	}
+}
+
+// HourKey groups entries by hour of the day ("00" to "23") in loc
+func HourKey(loc *time.Location) KeyFunc {
+	return func(entry types.UsageEntry) string {
+		if entry.Timestamp.IsZero() || entry.Timestamp.Year() < 2020 {
+			return ""
+		}
+		return entry.Timestamp.In(locationOrLocal(loc)).Format("15")
+	}
+}
+
+// Groups of AgentKey
+const (
+	AgentMain     = "main"
+	AgentSubagent = "sub-agent"
+)
+
+// AgentKey groups entries by whether the main conversation or a sub-agent
+// (Task tool) made the request
+func AgentKey() KeyFunc {
+	return func(entry types.UsageEntry) string {
+		if entry.IsSidechain {
+			return AgentSubagent
+		}
+		return AgentMain
+	}
+}
+
+// CwdUnknown groups entries of CwdKey logged without a working directory
+const CwdUnknown = "unknown"
+
+// CwdKey groups entries by the working directory they were made from, which
+// identifies the repository more reliably than the encoded project folder
+func CwdKey() KeyFunc {
+	return func(entry types.UsageEntry) string {
+		if entry.Cwd == "" {
+			return CwdUnknown
+		}
+		return entry.Cwd
+	}
+}
+
+// ModelFamilyKey groups entries by model family (see ModelFamily)
+func ModelFamilyKey() KeyFunc {
+	return func(entry types.UsageEntry) string {
+		return ModelFamily(entry.Model)
+	}
+}
+
+// ConversationUnknown groups entries of ConversationKey without a session ID
+const ConversationUnknown = "unknown"
+
+// ConversationKey groups entries by conversation: the sessionId, which the
+// loader takes from the log file name for lines that lack one
+func ConversationKey() KeyFunc {
+	return func(entry types.UsageEntry) string {
+		if entry.SessionID == "" {
+			return ConversationUnknown
+		}
+		return entry.SessionID
+	}
+}
+
+// AccountUnknown groups entries of AccountKey with neither an account nor a
+// source file
+const AccountUnknown = "unknown"
+
+// AccountKey groups entries by who made the request: the account logged with
+// the entry (merged team exports), else the name in names of the data
+// directory it was read from (see DataRoot), else that directory itself
+func AccountKey(names map[string]string) KeyFunc {
+	return func(entry types.UsageEntry) string {
+		if entry.Account != "" {
+			return entry.Account
+		}
+		root := DataRoot(entry.SourceFile)
+		if root == "" {
+			return AccountUnknown
+		}
+		if name, ok := names[root]; ok {
+			return name
+		}
+		return root
+	}
+}
+
+func locationOrLocal(loc *time.Location) *time.Location {
+	if loc == nil {
+		return time.Local
+	}
+	return loc
+}
+
+// Aggregate groups already cost-calculated entries by key and totals each
+// group. It is the one implementation behind every report's grouping; use
+// GroupAggregator to aggregate while loading instead.
+func Aggregate(entries []types.UsageEntry, key KeyFunc) map[string]*GroupTotals {
+	agg := NewGroupAggregator(nil, key)
+	for _, entry := range entries {
+		agg.Add(entry)
+	}
+	return agg.Groups
+}
+
+// GroupAggregator groups a stream of entries by key, calculating missing costs
+// as entries arrive. It satisfies loader.Aggregator, so reports can be built
+// without keeping every entry in memory.
+type GroupAggregator struct {
+	Groups map[string]*GroupTotals
+
+	calc *Calculator
+	key  KeyFunc
+}
+
+// NewGroupAggregator creates an aggregator; calc may be nil when entries already carry costs
+func NewGroupAggregator(calc *Calculator, key KeyFunc) *GroupAggregator {
+	return &GroupAggregator{
+		Groups: make(map[string]*GroupTotals),
+		calc:   calc,
+		key:    key,
+	}
+}
+
+// Add calculates the entry's cost if needed and adds it to its group
+func (a *GroupAggregator) Add(entry types.UsageEntry) {
+	k := a.key(entry)
+	if k == "" {
+		return
+	}
+	if a.calc != nil {
+		a.calc.CalculateCost(&entry)
+	}
+	group, ok := a.Groups[k]
+	if !ok {
+		group = NewGroupTotals()
+		a.Groups[k] = group
+	}
+	group.Add(entry)
+}
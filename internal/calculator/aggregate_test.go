@@ -0,0 +1,58 @@
+package calculator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateByCustomKey(t *testing.T) {
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	entries := []types.UsageEntry{
+		{Timestamp: start.Add(time.Hour), Model: "claude-opus-4", SessionID: "s1", SourceFile: "a.jsonl", InputTokens: 10, OutputTokens: 20, Cost: 1},
+		{Timestamp: start, Model: "claude-sonnet-4", SessionID: "s2", SourceFile: "b.jsonl", SessionName: "first", InputTokens: 5, OutputTokens: 5, Cost: 0.5},
+		{Timestamp: start.Add(2 * time.Hour), Model: "<synthetic>", SessionID: "s1", SourceFile: "a.jsonl", SessionName: "later", InputTokens: 1, Cost: 0.25},
+		{Timestamp: start, Model: "claude-opus-4", SessionID: "skip"},
+	}
+
+	groups := Aggregate(entries, func(entry types.UsageEntry) string {
+		if entry.SessionID == "skip" {
+			return ""
+		}
+		return entry.SourceFile
+	})
+
+	assert.Len(t, groups, 2)
+	a := groups["a.jsonl"]
+	assert.Equal(t, 2, a.RequestCount)
+	assert.Equal(t, 31, a.TotalTokens)
+	assert.Equal(t, []string{"claude-opus-4", ModelOther}, a.SortedModels(), "<synthetic> is listed as other")
+	assert.Equal(t, start.Add(time.Hour), a.FirstSeen)
+	assert.Equal(t, start.Add(2*time.Hour), a.LastSeen)
+	assert.Equal(t, "later", a.SessionName)
+
+	total := NewGroupTotals()
+	for _, group := range groups {
+		total.Merge(group)
+	}
+	assert.Equal(t, 3, total.RequestCount)
+	assert.InDelta(t, 1.75, total.Cost, 1e-9)
+	assert.Equal(t, start, total.FirstSeen)
+	assert.Equal(t, start.Add(2*time.Hour), total.LastSeen)
+	assert.Equal(t, "first", total.SessionName)
+	assert.Equal(t, []string{"claude-opus-4", "claude-sonnet-4", ModelOther}, total.SortedModels())
+	assert.Equal(t, []string{"s1", "s2"}, total.SortedSessionIDs())
+	assert.Equal(t, []string{"a.jsonl", "b.jsonl"}, total.SortedSourceFiles())
+	assert.Equal(t, 16, total.TokenCounts().InputTokens)
+}
+
+func TestAccountKey(t *testing.T) {
+	key := AccountKey(map[string]string{"/home/alice/.claude": "alice"})
+
+	assert.Equal(t, "bob", key(types.UsageEntry{Account: "bob", SourceFile: "/home/alice/.claude/projects/app/s.jsonl"}), "a logged account wins")
+	assert.Equal(t, "alice", key(types.UsageEntry{SourceFile: "/home/alice/.claude/projects/app/s.jsonl"}))
+	assert.Equal(t, "/mnt/ci/.claude", key(types.UsageEntry{SourceFile: "/mnt/ci/.claude/projects/app/s.jsonl"}), "unlabeled directories group by path")
+	assert.Equal(t, AccountUnknown, key(types.UsageEntry{}))
+}
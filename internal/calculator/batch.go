@@ -0,0 +1,16 @@
+package calculator
+
+import "github.com/sdpower/ccusage-go/internal/types"
+
+// ServiceTierBatch is the usage.service_tier of requests made through the
+// Message Batches API
+const ServiceTierBatch = "batch"
+
+// BatchDiscount is the share of the regular token prices the Batch API
+// takes off
+const BatchDiscount = 0.5
+
+// IsBatch reports whether a request was made through the Batch API
+func IsBatch(entry types.UsageEntry) bool {
+	return entry.ServiceTier == ServiceTierBatch
+}
@@ -0,0 +1,35 @@
+package calculator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchDiscount(t *testing.T) {
+	calc := New(&mockPricing{inputPrice: 0.01, outputPrice: 0.03, cacheReadPrice: 0.001})
+	entries := []types.UsageEntry{
+		{Model: "sonnet", InputTokens: 100, OutputTokens: 50, ServiceTier: ServiceTierBatch,
+			Raw: map[string]interface{}{"cache_read_input_tokens": 1000}},
+		{Model: "sonnet", InputTokens: 100, OutputTokens: 50, ServiceTier: "standard",
+			Raw: map[string]interface{}{"cache_read_input_tokens": 1000}},
+		{Model: "sonnet", InputTokens: 100, OutputTokens: 50, ServiceTier: ServiceTierBatch, Cost: 7, CostFromLog: true},
+	}
+	_, err := calc.CalculateCosts(context.Background(), entries)
+	assert.NoError(t, err)
+
+	assert.InDelta(t, 3.5, entries[1].Cost, 1e-9)
+	assert.InDelta(t, 1.75, entries[0].Cost, 1e-9, "batch requests cost half")
+	assert.InDelta(t, entries[1].CacheSavings/2, entries[0].CacheSavings, 1e-9)
+	assert.InDelta(t, 7, entries[2].Cost, 1e-9, "logged costs already include the discount")
+
+	summary := calc.calculateSummary(entries)
+	assert.Equal(t, 2, summary.BatchRequests)
+	assert.InDelta(t, 8.75, summary.BatchCost, 1e-9)
+
+	total := Aggregate(entries, AgentKey())[AgentMain]
+	assert.Equal(t, 2, total.BatchRequests)
+	assert.InDelta(t, 8.75, total.BatchCost, 1e-9)
+}
@@ -0,0 +1,58 @@
+package calculator
+
+import (
+	"fmt"
+	"time"
+)
+
+// BlockAnchor selects where a billing block starts relative to its first entry
+type BlockAnchor string
+
+const (
+	BlockAnchorHour  BlockAnchor = "hour"  // First entry's time floored to the hour
+	BlockAnchorExact BlockAnchor = "exact" // First entry's time
+	BlockAnchorFixed BlockAnchor = "fixed" // Wall-clock slots of the block duration from local midnight
+)
+
+// ParseBlockAnchor validates a --block-anchor value
+func ParseBlockAnchor(value string) (BlockAnchor, error) {
+	switch anchor := BlockAnchor(value); anchor {
+	case BlockAnchorHour, BlockAnchorExact, BlockAnchorFixed:
+		return anchor, nil
+	}
+	return "", fmt.Errorf("invalid --block-anchor %q, use hour, exact or fixed", value)
+}
+
+// SetBlockAnchor selects how IdentifySessionBlocks places block starts
+// ("" means hour). loc is the timezone of the fixed slots (nil means local
+// time); the other anchors ignore it.
+func (c *Calculator) SetBlockAnchor(anchor BlockAnchor, loc *time.Location) {
+	if anchor == "" {
+		anchor = BlockAnchorHour
+	}
+	c.blockAnchor = anchor
+	c.blockLocation = loc
+}
+
+// blockBounds returns the start and end of a block whose first entry is at t.
+// Fixed slots never cross midnight, so the last slot of a day may be shorter
+// when the duration does not divide 24 hours.
+func (c *Calculator) blockBounds(t time.Time, duration time.Duration) (start, end time.Time) {
+	switch c.blockAnchor {
+	case BlockAnchorExact:
+		return t, t.Add(duration)
+	case BlockAnchorFixed:
+		local := t.In(locationOrLocal(c.blockLocation))
+		midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+		nextMidnight := time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, local.Location())
+		start = midnight.Add(local.Sub(midnight) / duration * duration)
+		end = start.Add(duration)
+		if end.After(nextMidnight) {
+			end = nextMidnight
+		}
+		return start, end
+	default:
+		start = floorToHour(t)
+		return start, start.Add(duration)
+	}
+}
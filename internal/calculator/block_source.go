@@ -0,0 +1,115 @@
+package calculator
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// BlockSource selects which entries IdentifySessionBlocks treats as one stream
+type BlockSource string
+
+const (
+	BlockSourceMerged BlockSource = "merged" // All entries form one stream
+	BlockSourceRoot   BlockSource = "root"   // One stream per data directory (machine or account)
+	BlockSourceFile   BlockSource = "file"   // One stream per log file (terminal)
+)
+
+// ParseBlockSource validates a --block-source value
+func ParseBlockSource(value string) (BlockSource, error) {
+	switch source := BlockSource(value); source {
+	case BlockSourceMerged, BlockSourceRoot, BlockSourceFile:
+		return source, nil
+	}
+	return "", fmt.Errorf("invalid --block-source %q, use merged, root or file", value)
+}
+
+// SetBlockSource selects how IdentifySessionBlocks splits entries into
+// streams before finding blocks ("" means merged). With separate streams,
+// two machines syncing into one data directory each get their own blocks
+// instead of one block interleaving both.
+func (c *Calculator) SetBlockSource(source BlockSource) {
+	if source == "" {
+		source = BlockSourceMerged
+	}
+	c.blockSource = source
+}
+
+// blockSourceKey returns the KeyFunc of the calculator's block source, nil
+// when entries are not split
+func (c *Calculator) blockSourceKey() KeyFunc {
+	switch c.blockSource {
+	case BlockSourceRoot:
+		return func(entry types.UsageEntry) string { return DataRoot(entry.SourceFile) }
+	case BlockSourceFile:
+		return func(entry types.UsageEntry) string { return entry.SourceFile }
+	}
+	return nil
+}
+
+// DataRoot returns the data directory a log file was read from: the path
+// before its projects/ (Claude Code) or sessions/ (Codex CLI) directory, or
+// the parent of the file's directory when it has neither
+func DataRoot(file string) string {
+	if file == "" {
+		return ""
+	}
+	segments := strings.Split(strings.ReplaceAll(file, `\`, "/"), "/")
+	for i := len(segments) - 2; i > 0; i-- {
+		if segments[i] == "projects" || segments[i] == "sessions" {
+			return strings.Join(segments[:i], "/")
+		}
+	}
+	return filepath.Dir(filepath.Dir(file))
+}
+
+// identifySourceBlocks finds blocks in each source's entries separately and
+// merges them into one timeline. A gap in one source is dropped when another
+// source has a block overlapping it, since the account was not idle then.
+func (c *Calculator) identifySourceBlocks(entries []types.UsageEntry, sessionDurationHours int, key KeyFunc) []types.SessionBlock {
+	bySource := make(map[string][]types.UsageEntry)
+	for _, entry := range entries {
+		source := key(entry)
+		bySource[source] = append(bySource[source], entry)
+	}
+
+	var all []types.SessionBlock
+	for source, sourceEntries := range bySource {
+		for _, block := range c.identifyBlocks(sourceEntries, sessionDurationHours) {
+			block.Source = source
+			all = append(all, block)
+		}
+	}
+
+	blocks := []types.SessionBlock{}
+	for _, block := range all {
+		if block.IsGap && overlapsOtherSource(block, all) {
+			continue
+		}
+		blocks = append(blocks, block)
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		if !blocks[i].StartTime.Equal(blocks[j].StartTime) {
+			return blocks[i].StartTime.Before(blocks[j].StartTime)
+		}
+		return blocks[i].Source < blocks[j].Source
+	})
+	return blocks
+}
+
+// overlapsOtherSource reports whether a usage block of another source
+// overlaps gap
+func overlapsOtherSource(gap types.SessionBlock, blocks []types.SessionBlock) bool {
+	for _, block := range blocks {
+		if block.IsGap || block.Source == gap.Source {
+			continue
+		}
+		if block.StartTime.Before(gap.EndTime) && gap.StartTime.Before(block.EndTime) {
+			return true
+		}
+	}
+	return false
+}
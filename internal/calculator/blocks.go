@@ -1,6 +1,7 @@
 package calculator
 
 import (
+	"fmt"
 	"sort"
 	"time"
 
@@ -10,17 +11,67 @@ import (
 const (
 	// DefaultSessionDurationHours is Claude's billing block duration
 	DefaultSessionDurationHours = 5
-	// BlocksWarningThreshold is the percentage threshold for warnings
-	BlocksWarningThreshold = 0.8 // 80%
 )
 
+// UsageThresholds are the shares of the token limit, in percent, from which
+// a block's usage is a warning and critical
+type UsageThresholds struct {
+	Warn     float64
+	Critical float64
+}
+
+// DefaultUsageThresholds warn at 80% of the token limit and are critical at 95%
+var DefaultUsageThresholds = UsageThresholds{Warn: 80, Critical: 95}
+
+// NewUsageThresholds validates --warn-at and --critical-at; 0 keeps the
+// default of either
+func NewUsageThresholds(warn, critical float64) (UsageThresholds, error) {
+	thresholds := DefaultUsageThresholds
+	if warn != 0 {
+		thresholds.Warn = warn
+	}
+	if critical != 0 {
+		thresholds.Critical = critical
+	}
+	if thresholds.Warn <= 0 || thresholds.Critical <= 0 {
+		return UsageThresholds{}, fmt.Errorf("usage thresholds must be positive percentages of the token limit")
+	}
+	if thresholds.Warn >= thresholds.Critical {
+		return UsageThresholds{}, fmt.Errorf("the warning threshold (%g%%) must be below the critical one (%g%%)", thresholds.Warn, thresholds.Critical)
+	}
+	return thresholds, nil
+}
+
 // floorToHour floors a timestamp to the beginning of the hour
 func floorToHour(t time.Time) time.Time {
 	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
 }
 
+// SetGapThreshold sets how long a pause must last for IdentifySessionBlocks
+// to end the current block early and record a gap. The billing window of a
+// block stays the session length; 0 uses the session length as threshold.
+func (c *Calculator) SetGapThreshold(threshold time.Duration) {
+	c.gapThreshold = threshold
+}
+
+// SetActiveWindow sets how recent a block's last request must be for the
+// block to count as active; 0 uses the gap threshold. A short window stops
+// the live monitor from showing a block as active hours after the last
+// request, without changing how blocks are split.
+func (c *Calculator) SetActiveWindow(window time.Duration) {
+	c.activeWindow = window
+}
+
 // IdentifySessionBlocks groups entries into time-based blocks with gap detection
 func (c *Calculator) IdentifySessionBlocks(entries []types.UsageEntry, sessionDurationHours int) []types.SessionBlock {
+	if key := c.blockSourceKey(); key != nil && len(entries) > 0 {
+		return c.identifySourceBlocks(entries, sessionDurationHours, key)
+	}
+	return c.identifyBlocks(entries, sessionDurationHours)
+}
+
+// identifyBlocks finds the blocks of one stream of entries
+func (c *Calculator) identifyBlocks(entries []types.UsageEntry, sessionDurationHours int) []types.SessionBlock {
 	if len(entries) == 0 {
 		return []types.SessionBlock{}
 	}
@@ -30,6 +81,10 @@ func (c *Calculator) IdentifySessionBlocks(entries []types.UsageEntry, sessionDu
 	}
 
 	sessionDuration := time.Duration(sessionDurationHours) * time.Hour
+	gapThreshold := c.gapThreshold
+	if gapThreshold <= 0 {
+		gapThreshold = sessionDuration
+	}
 	blocks := []types.SessionBlock{}
 
 	// Sort entries by timestamp
@@ -40,6 +95,7 @@ func (c *Calculator) IdentifySessionBlocks(entries []types.UsageEntry, sessionDu
 	})
 
 	var currentBlockStart *time.Time
+	var currentBlockEnd time.Time
 	var currentBlockEntries []types.UsageEntry
 	now := time.Now()
 
@@ -47,31 +103,33 @@ func (c *Calculator) IdentifySessionBlocks(entries []types.UsageEntry, sessionDu
 		entryTime := entry.Timestamp
 
 		if currentBlockStart == nil {
-			// First entry - start a new block (floored to the hour)
-			floored := floorToHour(entryTime)
-			currentBlockStart = &floored
+			// First entry - start a new block (by default floored to the hour)
+			start, end := c.blockBounds(entryTime, sessionDuration)
+			currentBlockStart, currentBlockEnd = &start, end
 			currentBlockEntries = []types.UsageEntry{entry}
 		} else {
-			timeSinceBlockStart := entryTime.Sub(*currentBlockStart)
 			lastEntry := currentBlockEntries[len(currentBlockEntries)-1]
 			timeSinceLastEntry := entryTime.Sub(lastEntry.Timestamp)
+			// Fixed slots are half-open, so an entry on a boundary opens the next slot
+			pastBlockEnd := entryTime.After(currentBlockEnd) ||
+				(c.blockAnchor == BlockAnchorFixed && entryTime.Equal(currentBlockEnd))
 
-			if timeSinceBlockStart > sessionDuration || timeSinceLastEntry > sessionDuration {
+			if pastBlockEnd || timeSinceLastEntry > gapThreshold {
 				// Close current block
-				block := c.createBlock(*currentBlockStart, currentBlockEntries, now, sessionDuration)
+				block := c.createBlock(*currentBlockStart, currentBlockEnd, currentBlockEntries, now, gapThreshold)
 				blocks = append(blocks, block)
 
 				// Add gap block if there's a significant gap
-				if timeSinceLastEntry > sessionDuration {
-					gapBlock := c.createGapBlock(lastEntry.Timestamp, entryTime, sessionDuration)
+				if timeSinceLastEntry > gapThreshold {
+					gapBlock := c.createGapBlock(lastEntry.Timestamp, entryTime, gapThreshold)
 					if gapBlock != nil {
 						blocks = append(blocks, *gapBlock)
 					}
 				}
 
-				// Start new block (floored to the hour)
-				floored := floorToHour(entryTime)
-				currentBlockStart = &floored
+				// Start new block
+				start, end := c.blockBounds(entryTime, sessionDuration)
+				currentBlockStart, currentBlockEnd = &start, end
 				currentBlockEntries = []types.UsageEntry{entry}
 			} else {
 				// Add to current block
@@ -82,16 +140,17 @@ func (c *Calculator) IdentifySessionBlocks(entries []types.UsageEntry, sessionDu
 
 	// Close the last block
 	if currentBlockStart != nil && len(currentBlockEntries) > 0 {
-		block := c.createBlock(*currentBlockStart, currentBlockEntries, now, sessionDuration)
+		block := c.createBlock(*currentBlockStart, currentBlockEnd, currentBlockEntries, now, gapThreshold)
 		blocks = append(blocks, block)
 	}
 
 	return blocks
 }
 
-// createBlock creates a session block from a start time and usage entries
-func (c *Calculator) createBlock(startTime time.Time, entries []types.UsageEntry, now time.Time, sessionDuration time.Duration) types.SessionBlock {
-	endTime := startTime.Add(sessionDuration)
+// createBlock creates a session block from its bounds and usage entries. The
+// block is active until the active window (gapThreshold unless set) passes
+// without activity or it ends.
+func (c *Calculator) createBlock(startTime, endTime time.Time, entries []types.UsageEntry, now time.Time, gapThreshold time.Duration) types.SessionBlock {
 	var actualEndTime *time.Time
 	if len(entries) > 0 {
 		lastTime := entries[len(entries)-1].Timestamp
@@ -102,53 +161,29 @@ func (c *Calculator) createBlock(startTime time.Time, entries []types.UsageEntry
 	isActive := false
 	if actualEndTime != nil {
 		timeSinceLastActivity := now.Sub(*actualEndTime)
-		isActive = timeSinceLastActivity < sessionDuration && now.Before(endTime)
+		activeWindow := gapThreshold
+		if c.activeWindow > 0 {
+			activeWindow = c.activeWindow
+		}
+		isActive = timeSinceLastActivity < activeWindow && now.Before(endTime)
 	}
 
 	// Aggregate token counts and costs
-	tokenCounts := types.TokenCounts{}
-	costUSD := 0.0
-	apiCostUSD := 0.0
-	cacheCreateCostUSD := 0.0
-	cacheReadCostUSD := 0.0
-	modelMap := make(map[string]bool)
+	totals := NewGroupTotals()
 	var usageLimitResetTime *time.Time
-
 	for _, entry := range entries {
-		tokenCounts.InputTokens += entry.InputTokens
-		tokenCounts.OutputTokens += entry.OutputTokens
-
-		// Extract cache tokens from Raw data if available
-		if entry.Raw != nil {
-			if cc, ok := entry.Raw["cache_creation_input_tokens"].(int); ok {
-				tokenCounts.CacheCreationInputTokens += cc
-			}
-			if cr, ok := entry.Raw["cache_read_input_tokens"].(int); ok {
-				tokenCounts.CacheReadInputTokens += cr
-			}
-			// Check for usage limit reset time
-			if resetTime, ok := entry.Raw["usage_limit_reset_time"].(string); ok {
-				if t, err := time.Parse(time.RFC3339, resetTime); err == nil {
-					usageLimitResetTime = &t
-				}
+		totals.Add(entry)
+		// Check for usage limit reset time
+		if resetTime, ok := entry.Raw["usage_limit_reset_time"].(string); ok {
+			if t, err := time.Parse(time.RFC3339, resetTime); err == nil {
+				usageLimitResetTime = &t
 			}
 		}
-
-		costUSD += entry.Cost
-		apiCostUSD += entry.APICost
-		cacheCreateCostUSD += entry.CacheCreateCost
-		cacheReadCostUSD += entry.CacheReadCost
-		if entry.Model != "" {
-			modelMap[entry.Model] = true
-		}
 	}
-
-	// Convert model map to sorted slice
-	models := []string{}
-	for model := range modelMap {
-		models = append(models, model)
+	models := totals.SortedModels()
+	if models == nil {
+		models = []string{}
 	}
-	sort.Strings(models)
 
 	return types.SessionBlock{
 		ID:                  startTime.Format(time.RFC3339),
@@ -158,25 +193,25 @@ func (c *Calculator) createBlock(startTime time.Time, entries []types.UsageEntry
 		IsActive:            isActive,
 		IsGap:               false,
 		Entries:             entries,
-		TokenCounts:         tokenCounts,
-		CostUSD:             costUSD,
-		APICostUSD:           apiCostUSD,
-		CacheCreateCostUSD:   cacheCreateCostUSD,
-		CacheReadCostUSD:     cacheReadCostUSD,
+		TokenCounts:         totals.TokenCounts(),
+		CostUSD:             totals.Cost,
+		APICostUSD:          totals.APICost,
+		CacheCreateCostUSD:  totals.CacheCreateCost,
+		CacheReadCostUSD:    totals.CacheReadCost,
 		Models:              models,
 		UsageLimitResetTime: usageLimitResetTime,
 	}
 }
 
 // createGapBlock creates a gap block representing periods with no activity
-func (c *Calculator) createGapBlock(lastActivityTime, nextActivityTime time.Time, sessionDuration time.Duration) *types.SessionBlock {
-	// Only create gap blocks for gaps longer than the session duration
+func (c *Calculator) createGapBlock(lastActivityTime, nextActivityTime time.Time, gapThreshold time.Duration) *types.SessionBlock {
+	// Only create gap blocks for gaps longer than the gap threshold
 	gapDuration := nextActivityTime.Sub(lastActivityTime)
-	if gapDuration <= sessionDuration {
+	if gapDuration <= gapThreshold {
 		return nil
 	}
 
-	gapStart := lastActivityTime.Add(sessionDuration)
+	gapStart := lastActivityTime.Add(gapThreshold)
 	gapEnd := nextActivityTime
 
 	return &types.SessionBlock{
@@ -223,15 +258,88 @@ func CalculateBurnRate(block types.SessionBlock) *types.BurnRate {
 	}
 }
 
-// ProjectBlockUsage projects total usage for an active session block
+// BurnRateSeries buckets a block's usage by minute, from the minute of its
+// first entry to that of its last one, or to now while the block is active.
+// Idle minutes are included with zero usage, so the series shows bursts and
+// pauses that the single average of CalculateBurnRate hides.
+func BurnRateSeries(block types.SessionBlock) []types.BurnRatePoint {
+	if len(block.Entries) == 0 || block.IsGap {
+		return nil
+	}
+
+	first := block.Entries[0].Timestamp.Truncate(time.Minute)
+	last := block.Entries[len(block.Entries)-1].Timestamp.Truncate(time.Minute)
+	if block.IsActive {
+		now := time.Now()
+		if now.After(block.EndTime) {
+			now = block.EndTime
+		}
+		if now = now.Truncate(time.Minute); now.After(last) {
+			last = now
+		}
+	}
+
+	return MinuteSeries(block.Entries, first, last)
+}
+
+// MinuteSeries buckets the usage of entries by minute, from the minute of
+// from to that of to, with zero usage for idle minutes. Entries outside the
+// range are left out, so it also gives the last minutes across blocks.
+func MinuteSeries(entries []types.UsageEntry, from, to time.Time) []types.BurnRatePoint {
+	first, last := from.Truncate(time.Minute), to.Truncate(time.Minute)
+	if last.Before(first) {
+		return nil
+	}
+	series := make([]types.BurnRatePoint, int(last.Sub(first)/time.Minute)+1)
+	for i := range series {
+		series[i].Minute = first.Add(time.Duration(i) * time.Minute)
+	}
+	for _, entry := range entries {
+		if entry.Timestamp.Before(first) {
+			continue
+		}
+		i := int(entry.Timestamp.Truncate(time.Minute).Sub(first) / time.Minute)
+		if i >= len(series) {
+			continue
+		}
+		tokens := entry.InputTokens + entry.OutputTokens
+		if cc, ok := entry.Raw["cache_creation_input_tokens"].(int); ok {
+			tokens += cc
+		}
+		if cr, ok := entry.Raw["cache_read_input_tokens"].(int); ok {
+			tokens += cr
+		}
+		series[i].Tokens += tokens
+		series[i].CostUSD += entry.Cost
+	}
+	return series
+}
+
+// ProjectBlockUsage projects total usage for an active session block at its
+// average burn rate
 func ProjectBlockUsage(block types.SessionBlock) *types.ProjectedUsage {
+	return ProjectBlockUsageWith(block, ProjectionAverage)
+}
+
+// ProjectBlockUsageWith projects total usage for an active session block at
+// the burn rate selected by method ("" means average)
+func ProjectBlockUsageWith(block types.SessionBlock, method ProjectionMethod) *types.ProjectedUsage {
 	if !block.IsActive || block.IsGap {
 		return nil
 	}
 
-	burnRate := CalculateBurnRate(block)
-	if burnRate == nil {
-		return nil
+	var tokensPerMinute, costPerMinute float64
+	if method == ProjectionRecent {
+		var ok bool
+		if tokensPerMinute, costPerMinute, ok = recentBurnRate(block); !ok {
+			return nil
+		}
+	} else {
+		burnRate := CalculateBurnRate(block)
+		if burnRate == nil {
+			return nil
+		}
+		tokensPerMinute, costPerMinute = burnRate.TokensPerMinute, burnRate.CostPerHour/60
 	}
 
 	now := time.Now()
@@ -243,18 +351,20 @@ func ProjectBlockUsage(block types.SessionBlock) *types.ProjectedUsage {
 
 	// Current tokens plus projected additional tokens
 	currentTokens := block.TokenCounts.GetTotal()
-	additionalTokens := int(burnRate.TokensPerMinute * remainingMinutes)
+	additionalTokens := int(tokensPerMinute * remainingMinutes)
 	totalTokens := currentTokens + additionalTokens
 
 	// Current cost plus projected additional cost
-	additionalCost := (burnRate.CostPerHour / 60) * remainingMinutes
+	additionalCost := costPerMinute * remainingMinutes
 	totalCost := block.CostUSD + additionalCost
 
-	return &types.ProjectedUsage{
+	projection := &types.ProjectedUsage{
 		TotalTokens:      totalTokens,
 		TotalCost:        totalCost,
 		RemainingMinutes: remainingMinutes,
 	}
+	setProjectionInterval(projection, block, currentTokens)
+	return projection
 }
 
 // FilterRecentBlocks filters blocks to include only those from the last N days
@@ -65,3 +65,251 @@ func TestIdentifySessionBlocksCostBreakdown(t *testing.T) {
 	assert.InDelta(t, 3.0, block.CacheCreateCostUSD, 0.001, "Cache create cost should be sum")
 	assert.InDelta(t, 1.5, block.CacheReadCostUSD, 0.001, "Cache read cost should be sum")
 }
+
+func TestBlockAnchors(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2025, 3, 1, h, m, 0, 0, time.UTC) }
+	entries := []types.UsageEntry{
+		{Timestamp: at(9, 40), InputTokens: 1},
+		{Timestamp: at(12, 30), InputTokens: 1},
+		{Timestamp: at(14, 50), InputTokens: 1},
+	}
+
+	tests := []struct {
+		anchor BlockAnchor
+		starts []time.Time
+	}{
+		{BlockAnchorHour, []time.Time{at(9, 0), at(14, 0)}},
+		{BlockAnchorExact, []time.Time{at(9, 40), at(14, 50)}},
+		{BlockAnchorFixed, []time.Time{at(5, 0), at(10, 0)}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.anchor), func(t *testing.T) {
+			calc := New(nil)
+			calc.SetBlockAnchor(tt.anchor, time.UTC)
+			blocks := calc.IdentifySessionBlocks(entries, DefaultSessionDurationHours)
+			require.Len(t, blocks, len(tt.starts))
+			for i, start := range tt.starts {
+				assert.True(t, start.Equal(blocks[i].StartTime), "block %d starts at %s, want %s", i, blocks[i].StartTime, start)
+			}
+		})
+	}
+
+	// The last fixed slot of a day ends at midnight
+	calc := New(nil)
+	calc.SetBlockAnchor(BlockAnchorFixed, time.UTC)
+	blocks := calc.IdentifySessionBlocks([]types.UsageEntry{{Timestamp: at(21, 0)}}, DefaultSessionDurationHours)
+	require.Len(t, blocks, 1)
+	assert.True(t, at(20, 0).Equal(blocks[0].StartTime))
+	assert.True(t, time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC).Equal(blocks[0].EndTime))
+
+	_, err := ParseBlockAnchor("midnight")
+	assert.Error(t, err)
+}
+
+func TestBurnRateSeries(t *testing.T) {
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	block := types.SessionBlock{
+		StartTime: start,
+		EndTime:   start.Add(5 * time.Hour),
+		Entries: []types.UsageEntry{
+			{Timestamp: start.Add(30 * time.Second), InputTokens: 100, OutputTokens: 50, Cost: 1},
+			{Timestamp: start.Add(50 * time.Second), InputTokens: 10, Cost: 0.5,
+				Raw: map[string]interface{}{"cache_read_input_tokens": 40}},
+			{Timestamp: start.Add(3*time.Minute + 5*time.Second), OutputTokens: 20, Cost: 0.25},
+		},
+	}
+
+	series := BurnRateSeries(block)
+	require.Len(t, series, 4, "idle minutes are part of the series")
+	assert.True(t, start.Equal(series[0].Minute))
+	assert.Equal(t, []int{200, 0, 0, 20}, []int{series[0].Tokens, series[1].Tokens, series[2].Tokens, series[3].Tokens})
+	assert.InDelta(t, 1.5, series[0].CostUSD, 1e-9)
+
+	// An active block runs up to the current minute
+	now := time.Now()
+	active := types.SessionBlock{
+		StartTime: now.Add(-time.Hour),
+		EndTime:   now.Add(4 * time.Hour),
+		IsActive:  true,
+		Entries:   []types.UsageEntry{{Timestamp: now.Add(-10 * time.Minute), InputTokens: 1}},
+	}
+	series = BurnRateSeries(active)
+	require.NotEmpty(t, series)
+	assert.False(t, series[len(series)-1].Minute.Before(now.Truncate(time.Minute)))
+
+	assert.Nil(t, BurnRateSeries(types.SessionBlock{IsGap: true}))
+}
+
+func TestNewUsageThresholds(t *testing.T) {
+	thresholds, err := NewUsageThresholds(0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultUsageThresholds, thresholds)
+
+	thresholds, err = NewUsageThresholds(70, 0)
+	require.NoError(t, err)
+	assert.Equal(t, UsageThresholds{Warn: 70, Critical: 95}, thresholds)
+
+	_, err = NewUsageThresholds(96, 0)
+	assert.Error(t, err, "warning above the default critical threshold")
+	_, err = NewUsageThresholds(-10, 90)
+	assert.Error(t, err)
+}
+
+func TestMinuteSeries(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 30, 0, time.UTC)
+	entries := []types.UsageEntry{
+		{Timestamp: now.Add(-2 * time.Hour), InputTokens: 1000},
+		{Timestamp: now.Add(-59 * time.Minute), InputTokens: 10, Cost: 0.1},
+		{Timestamp: now, OutputTokens: 5, Cost: 0.05},
+	}
+
+	series := MinuteSeries(entries, now.Add(-59*time.Minute), now)
+	require.Len(t, series, 60)
+	assert.Equal(t, 10, series[0].Tokens, "entries before the range are left out")
+	assert.Equal(t, 5, series[59].Tokens)
+	assert.InDelta(t, 0.05, series[59].CostUSD, 1e-9)
+	assert.Nil(t, MinuteSeries(entries, now, now.Add(-time.Hour)))
+}
+
+func TestRecentProjectionDiscountsIdleTime(t *testing.T) {
+	now := time.Now()
+	block := types.SessionBlock{
+		StartTime: now.Add(-2 * time.Hour),
+		EndTime:   now.Add(3 * time.Hour),
+		IsActive:  true,
+		Entries: []types.UsageEntry{
+			{Timestamp: now.Add(-2 * time.Hour), InputTokens: 10000, Cost: 1},
+			{Timestamp: now.Add(-110 * time.Minute), InputTokens: 10000, Cost: 1},
+		},
+		TokenCounts: types.TokenCounts{InputTokens: 20000},
+		CostUSD:     2,
+	}
+
+	average := ProjectBlockUsageWith(block, ProjectionAverage)
+	recent := ProjectBlockUsageWith(block, ProjectionRecent)
+	require.NotNil(t, average)
+	require.NotNil(t, recent)
+	assert.InDelta(t, average.TotalTokens, ProjectBlockUsage(block).TotalTokens, 100, "average is the default")
+	assert.Greater(t, average.TotalTokens, 300000, "the average keeps extrapolating the early burst")
+	assert.Less(t, recent.TotalTokens, 21000, "after 110 idle minutes the recent rate has decayed")
+	assert.Less(t, recent.TotalCost, average.TotalCost)
+
+	_, err := ParseProjectionMethod("median")
+	assert.Error(t, err)
+}
+
+func TestGapThresholdSplitsBlocks(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2025, 3, 1, h, m, 0, 0, time.UTC) }
+	entries := []types.UsageEntry{
+		{Timestamp: at(10, 0), InputTokens: 1},
+		{Timestamp: at(10, 30), InputTokens: 1},
+		{Timestamp: at(12, 0), InputTokens: 1},
+	}
+
+	calc := New(nil)
+	require.Len(t, calc.IdentifySessionBlocks(entries, DefaultSessionDurationHours), 1)
+
+	calc.SetGapThreshold(time.Hour)
+	blocks := calc.IdentifySessionBlocks(entries, DefaultSessionDurationHours)
+	require.Len(t, blocks, 3)
+	assert.Len(t, blocks[0].Entries, 2)
+	assert.True(t, at(15, 0).Equal(blocks[0].EndTime), "the billing window keeps the session length")
+	assert.True(t, blocks[1].IsGap)
+	assert.True(t, at(11, 30).Equal(blocks[1].StartTime))
+	assert.True(t, at(12, 0).Equal(blocks[2].StartTime))
+}
+
+func TestActiveWindow(t *testing.T) {
+	entries := []types.UsageEntry{
+		{Timestamp: time.Now().Add(-100 * time.Minute), InputTokens: 1},
+		{Timestamp: time.Now().Add(-90 * time.Minute), InputTokens: 1},
+	}
+
+	calc := New(nil)
+	blocks := calc.IdentifySessionBlocks(entries, DefaultSessionDurationHours)
+	require.Len(t, blocks, 1)
+	assert.True(t, blocks[0].IsActive, "active within the session length by default")
+
+	calc.SetActiveWindow(30 * time.Minute)
+	blocks = calc.IdentifySessionBlocks(entries, DefaultSessionDurationHours)
+	require.Len(t, blocks, 1)
+	assert.False(t, blocks[0].IsActive)
+	assert.Len(t, blocks[0].Entries, 2, "the window does not split blocks")
+
+	calc.SetActiveWindow(2 * time.Hour)
+	assert.True(t, calc.IdentifySessionBlocks(entries, DefaultSessionDurationHours)[0].IsActive)
+}
+
+func TestBlockSource(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2025, 3, 1, hour, 0, 0, 0, time.UTC) }
+	laptop := "/sync/laptop/.claude/projects/app/a.jsonl"
+	desktop := `C:\sync\desktop\.claude\projects\app\b.jsonl`
+	entries := []types.UsageEntry{
+		{Timestamp: at(10), InputTokens: 1, SourceFile: laptop},
+		{Timestamp: at(12), InputTokens: 1, SourceFile: desktop},
+		{Timestamp: at(17), InputTokens: 1, SourceFile: laptop},
+		{Timestamp: at(23), InputTokens: 1, SourceFile: laptop},
+	}
+
+	calc := New(nil)
+	blocks := calc.IdentifySessionBlocks(entries, DefaultSessionDurationHours)
+	require.Len(t, blocks, 4, "merged: 10-15, 17-22, gap, 23-04")
+	assert.Len(t, blocks[0].Entries, 2, "the desktop request joins the laptop block")
+
+	calc.SetBlockSource(BlockSourceRoot)
+	blocks = calc.IdentifySessionBlocks(entries, DefaultSessionDurationHours)
+	var usage, gaps []types.SessionBlock
+	for _, block := range blocks {
+		if block.IsGap {
+			gaps = append(gaps, block)
+		} else {
+			usage = append(usage, block)
+		}
+	}
+	require.Len(t, usage, 4)
+	assert.Equal(t, "/sync/laptop/.claude", usage[0].Source)
+	assert.Equal(t, "C:/sync/desktop/.claude", usage[1].Source)
+	assert.True(t, at(12).Equal(usage[1].StartTime))
+	// The laptop's 10:00-17:00 pause overlaps the desktop block and is dropped;
+	// the 17:00-23:00 one is not covered by another machine
+	require.Len(t, gaps, 1)
+	assert.True(t, at(22).Equal(gaps[0].StartTime))
+
+	for i := 1; i < len(blocks); i++ {
+		assert.False(t, blocks[i].StartTime.Before(blocks[i-1].StartTime), "blocks are in time order")
+	}
+
+	_, err := ParseBlockSource("machine")
+	assert.Error(t, err)
+}
+
+func TestProjectionInterval(t *testing.T) {
+	now := time.Now().Truncate(time.Minute)
+	block := types.SessionBlock{
+		StartTime: now.Add(-time.Hour),
+		EndTime:   now.Add(4 * time.Hour),
+		IsActive:  true,
+	}
+	for i := 4; i >= 0; i-- {
+		block.Entries = append(block.Entries, types.UsageEntry{Timestamp: now.Add(-time.Duration(i) * time.Minute), InputTokens: 100, Cost: 0.01})
+	}
+	block.TokenCounts = types.TokenCounts{InputTokens: 500}
+	block.CostUSD = 0.05
+
+	steady := ProjectBlockUsage(block)
+	require.NotNil(t, steady)
+	assert.Equal(t, steady.TotalTokens, steady.LowTokens, "a steady rate has no spread")
+	assert.Equal(t, steady.TotalTokens, steady.HighTokens)
+
+	block.Entries[2].InputTokens = 1000
+	block.Entries[2].Cost = 0.1
+	block.TokenCounts.InputTokens = 1400
+	block.CostUSD = 0.14
+	bursty := ProjectBlockUsage(block)
+	require.NotNil(t, bursty)
+	assert.Less(t, bursty.LowTokens, bursty.TotalTokens)
+	assert.Greater(t, bursty.HighTokens, bursty.TotalTokens)
+	assert.GreaterOrEqual(t, bursty.LowTokens, 1400, "never below the tokens already used")
+	assert.Less(t, bursty.LowCost, bursty.TotalCost)
+	assert.Greater(t, bursty.HighCost, bursty.TotalCost)
+}
@@ -0,0 +1,32 @@
+package calculator
+
+import (
+	"context"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// CacheSavings estimates what prompt caching saved on cacheReadTokens: the
+// price of sending them as regular input minus what the cache reads cost
+func CacheSavings(cacheReadTokens int, inputPrice, cacheReadPrice float64) float64 {
+	if cacheReadTokens <= 0 || inputPrice <= cacheReadPrice {
+		return 0
+	}
+	return float64(cacheReadTokens) * (inputPrice - cacheReadPrice)
+}
+
+// applyCacheSavings sets an entry's estimated cache savings. It is always
+// derived from token prices, whatever the cost mode, since logs record no
+// savings of their own.
+func (c *Calculator) applyCacheSavings(ctx context.Context, entry *types.UsageEntry) {
+	entry.CacheSavings = 0
+	cacheRead, ok := entry.Raw["cache_read_input_tokens"].(int)
+	if !ok || cacheRead <= 0 {
+		return
+	}
+	inputPrice, _, _, cacheReadPrice, err := c.entryPrices(ctx, *entry)
+	if err != nil {
+		return
+	}
+	entry.CacheSavings = CacheSavings(cacheRead, inputPrice, cacheReadPrice)
+}
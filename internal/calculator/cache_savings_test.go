@@ -0,0 +1,40 @@
+package calculator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheSavings(t *testing.T) {
+	pricing := &mockPricing{inputPrice: 0.01, outputPrice: 0.03, cacheReadPrice: 0.001}
+	newEntries := func() []types.UsageEntry {
+		return []types.UsageEntry{
+			{Model: "claude-sonnet-4-5-20250514", SessionID: "s1", InputTokens: 10,
+				Raw: map[string]interface{}{"cache_read_input_tokens": 1000}},
+			{Model: "claude-sonnet-4-5-20250514", SessionID: "s1", InputTokens: 10, Cost: 5, CostFromLog: true,
+				Raw: map[string]interface{}{"cache_read_input_tokens": 500}},
+			{Model: "claude-sonnet-4-5-20250514", SessionID: "s1", InputTokens: 10},
+		}
+	}
+
+	// Savings are estimated from token prices in every cost mode
+	for _, mode := range []CostMode{CostModeAuto, CostModeCalculate, CostModeDisplay} {
+		calc := New(pricing)
+		calc.SetMode(mode)
+		entries, err := calc.CalculateCosts(context.Background(), newEntries())
+		require.NoError(t, err)
+		assert.InDelta(t, 9.0, entries[0].CacheSavings, 1e-9, "mode %s", mode)
+		assert.InDelta(t, 4.5, entries[1].CacheSavings, 1e-9, "mode %s", mode)
+		assert.Zero(t, entries[2].CacheSavings, "mode %s", mode)
+
+		sessions := calc.GenerateSessionReport(entries)
+		require.Len(t, sessions, 1)
+		assert.InDelta(t, 13.5, sessions[0].CacheSavings, 1e-9, "mode %s", mode)
+	}
+
+	assert.Zero(t, CacheSavings(100, 0.001, 0.001), "no saving when cache reads cost as much as input")
+}
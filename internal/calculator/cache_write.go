@@ -0,0 +1,48 @@
+package calculator
+
+import (
+	"context"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// CacheWrite1hMultiplier is what a 1-hour cache write costs relative to a
+// base input token when the pricing service lists no rate for it, as
+// Anthropic bills them. 5-minute writes cost the model's cache creation rate.
+const CacheWrite1hMultiplier = 2.0
+
+// CacheWrite1hPricingService is implemented by pricing services that list
+// the rate of 1-hour cache writes. ok is false for models without one.
+type CacheWrite1hPricingService interface {
+	GetCacheWrite1hPriceAt(ctx context.Context, model string, at time.Time) (price float64, ok bool)
+}
+
+// cacheWriteCost prices an entry's cacheCreate tokens: its 1-hour writes at
+// cacheWrite1hPrice, the others (5-minute writes, and all of them when the
+// log does not tell) at cacheCreatePrice. inputPrice and cacheCreatePrice
+// are the entry's rates from entryPrices.
+func (c *Calculator) cacheWriteCost(ctx context.Context, entry types.UsageEntry, cacheCreate int, inputPrice, cacheCreatePrice float64) float64 {
+	hour := min(entry.CacheCreation1hTokens, cacheCreate)
+	cost := float64(cacheCreate-hour) * cacheCreatePrice
+	if hour > 0 {
+		cost += float64(hour) * c.cacheWrite1hPrice(ctx, entry, inputPrice)
+	}
+	return cost
+}
+
+// cacheWrite1hPrice returns the per-token price of an entry's 1-hour cache
+// writes: the rate the pricing service lists, batch discount applied, else
+// CacheWrite1hMultiplier times the input rate. Long-context requests always
+// use the multiplier, since only base rates are listed.
+func (c *Calculator) cacheWrite1hPrice(ctx context.Context, entry types.UsageEntry, inputPrice float64) float64 {
+	if service, ok := c.pricingService.(CacheWrite1hPricingService); ok && !IsLongContext(entry) {
+		if price, ok := service.GetCacheWrite1hPriceAt(ctx, entry.Model, entry.Timestamp); ok {
+			if IsBatch(entry) {
+				price *= 1 - BatchDiscount
+			}
+			return price
+		}
+	}
+	return inputPrice * CacheWrite1hMultiplier
+}
@@ -0,0 +1,63 @@
+package calculator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hourWritePricing lists a 1-hour cache write rate for listedModel
+type hourWritePricing struct {
+	tieredPricing
+	listedModel string
+	hourPrice   float64
+}
+
+func (p *hourWritePricing) GetCacheWrite1hPriceAt(ctx context.Context, model string, at time.Time) (float64, bool) {
+	return p.hourPrice, model == p.listedModel
+}
+
+func TestCacheWriteTTLPricing(t *testing.T) {
+	pricing := &hourWritePricing{
+		tieredPricing: tieredPricing{mockPricing: mockPricing{inputPrice: 0.001, cacheCreatePrice: 0.00125}, tieredModel: "sonnet"},
+		listedModel:   "sonnet",
+		hourPrice:     0.003,
+	}
+	calc := New(pricing)
+	calc.SetMode(CostModeCalculate)
+
+	write := func(model string, total, hour int) types.UsageEntry {
+		return types.UsageEntry{
+			Model:                 model,
+			CacheCreation1hTokens: hour,
+			Raw:                   map[string]interface{}{"cache_creation_input_tokens": total},
+		}
+	}
+	batch := write("sonnet", 1000, 400)
+	batch.ServiceTier = ServiceTierBatch
+	long := write("sonnet", 1000, 400)
+	long.InputTokens = LongContextThreshold
+
+	entries := []types.UsageEntry{
+		write("sonnet", 1000, 0),    // All 5-minute writes
+		write("sonnet", 1000, 400),  // Listed 1-hour rate
+		write("opus", 1000, 400),    // Not listed: twice the input rate
+		write("sonnet", 1000, 5000), // 1-hour count capped at the total
+		batch,
+		long,
+	}
+	_, err := calc.CalculateCosts(context.Background(), entries)
+	require.NoError(t, err)
+
+	assert.InDelta(t, 1000*0.00125, entries[0].CacheCreateCost, 1e-9)
+	assert.InDelta(t, 600*0.00125+400*0.003, entries[1].CacheCreateCost, 1e-9)
+	assert.InDelta(t, 600*0.00125+400*0.001*CacheWrite1hMultiplier, entries[2].CacheCreateCost, 1e-9)
+	assert.InDelta(t, 1000*0.003, entries[3].CacheCreateCost, 1e-9)
+	assert.InDelta(t, (600*0.00125+400*0.003)*(1-BatchDiscount), entries[4].CacheCreateCost, 1e-9)
+	assert.InDelta(t, 2*(600*0.00125+400*0.001*CacheWrite1hMultiplier), entries[5].CacheCreateCost, 1e-9,
+		"long-context requests derive the 1-hour rate from the long-context input rate")
+}
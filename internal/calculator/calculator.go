@@ -10,41 +10,62 @@ import (
 
 type Calculator struct {
 	pricingService PricingService
+	mode           CostMode
+	blockAnchor    BlockAnchor
+	blockLocation  *time.Location // Timezone of BlockAnchorFixed slots
+	gapThreshold   time.Duration  // Idle time that ends a block (0 = session length)
+	activeWindow   time.Duration  // Recency of the last request that keeps a block active (0 = gap threshold)
+	blockSource    BlockSource    // Entry streams blocks are found in ("" = merged)
+	location       *time.Location // Timezone of report periods
 }
 
 type PricingService interface {
 	GetModelPrice(ctx context.Context, model string) (inputPrice, outputPrice, cacheCreatePrice, cacheReadPrice float64, err error)
 }
 
+// PricingWarmer is implemented by pricing services that can load their
+// prices in the background
+type PricingWarmer interface {
+	Warm(ctx context.Context)
+}
+
+// WarmPricing starts loading prices while usage data is read, so the first
+// cost calculation does not wait for a pricing fetch. Every cost mode needs
+// them: cache savings are estimated from the rates.
+func (c *Calculator) WarmPricing(ctx context.Context) {
+	if warmer, ok := c.pricingService.(PricingWarmer); ok {
+		warmer.Warm(ctx)
+	}
+}
+
 func New(pricingService PricingService) *Calculator {
 	return &Calculator{
 		pricingService: pricingService,
+		mode:           CostModeAuto,
+		blockAnchor:    BlockAnchorHour,
 	}
 }
 
 func (c *Calculator) CalculateCosts(ctx context.Context, entries []types.UsageEntry) ([]types.UsageEntry, error) {
 	for i := range entries {
-		if entries[i].Cost == 0 {
-			c.calculateSingleCost(ctx, &entries[i])
-		}
+		c.applyCost(ctx, &entries[i])
 	}
 	return entries, nil
 }
 
 // CalculateCost implements the loader.CostCalculator interface for stream processing
 func (c *Calculator) CalculateCost(entry *types.UsageEntry) error {
-	if entry.Cost == 0 {
-		c.calculateSingleCost(context.Background(), entry)
-	}
+	c.applyCost(context.Background(), entry)
 	return nil
 }
 
-// calculateSingleCost calculates cost for a single entry
-func (c *Calculator) calculateSingleCost(ctx context.Context, entry *types.UsageEntry) {
-	inputPrice, outputPrice, cacheCreatePrice, cacheReadPrice, err := c.pricingService.GetModelPrice(ctx, entry.Model)
+// calculateSingleCost calculates cost for a single entry, reporting whether
+// the model had a price
+func (c *Calculator) calculateSingleCost(ctx context.Context, entry *types.UsageEntry) bool {
+	inputPrice, outputPrice, cacheCreatePrice, cacheReadPrice, err := c.entryPrices(ctx, *entry)
 	if err != nil {
 		// Continue without cost if pricing fails
-		return
+		return false
 	}
 
 	// Calculate API cost (input + output only, no cache)
@@ -56,7 +77,7 @@ func (c *Calculator) calculateSingleCost(ctx context.Context, entry *types.Usage
 	cost := apiCost
 	if entry.Raw != nil {
 		if cacheCreate, ok := entry.Raw["cache_creation_input_tokens"].(int); ok {
-			entry.CacheCreateCost = float64(cacheCreate) * cacheCreatePrice
+			entry.CacheCreateCost = c.cacheWriteCost(ctx, *entry, cacheCreate, inputPrice, cacheCreatePrice)
 			cost += entry.CacheCreateCost
 		}
 		if cacheRead, ok := entry.Raw["cache_read_input_tokens"].(int); ok {
@@ -65,198 +86,155 @@ func (c *Calculator) calculateSingleCost(ctx context.Context, entry *types.Usage
 		}
 	}
 	entry.Cost = cost
+	return true
+}
+
+// CacheHitRate returns cache_read / (input + cache_read) as a percentage.
+// ok is false when there are no input or cache read tokens.
+func CacheHitRate(inputTokens, cacheReadTokens int) (rate float64, ok bool) {
+	denominator := inputTokens + cacheReadTokens
+	if denominator <= 0 {
+		return 0, false
+	}
+	return float64(cacheReadTokens) / float64(denominator) * 100, true
+}
+
+// SetTimezone sets the timezone whose calendar days, weeks and months the
+// period reports cover (nil means local time). It should match the loader's
+// timezone, which assigns each entry its DateKey.
+func (c *Calculator) SetTimezone(loc *time.Location) {
+	c.location = loc
 }
 
+// GenerateDailyReport reports the calendar day of date (its year, month and
+// day, whatever its location) in the calculator's timezone
 func (c *Calculator) GenerateDailyReport(entries []types.UsageEntry, date time.Time) types.UsageReport {
-	filteredEntries := c.filterByDate(entries, date)
-	return c.generateReport(filteredEntries, "daily", date, date.Add(24*time.Hour))
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, locationOrLocal(c.location))
+	end := start.AddDate(0, 0, 1)
+
+	filteredEntries := c.filterByDays(entries, start, end)
+	return c.generateReport(filteredEntries, "daily", start, end)
 }
 
 func (c *Calculator) GenerateMonthlyReport(entries []types.UsageEntry, year int, month int) types.UsageReport {
-	// Note: Since timezone conversion is now handled at the loader level via DateKey,
-	// this method is primarily used for JSON/CSV output formats.
-	// For table format, entries are already timezone-converted with DateKey set.
-	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, locationOrLocal(c.location))
 	end := start.AddDate(0, 1, 0)
 
-	filteredEntries := c.filterByDateRange(entries, start, end)
+	filteredEntries := c.filterByDays(entries, start, end)
 	return c.generateReport(filteredEntries, "monthly", start, end)
 }
 
 func (c *Calculator) GenerateWeeklyReport(entries []types.UsageEntry, year int, week int) types.UsageReport {
 	start := c.getWeekStart(year, week)
-	end := start.Add(7 * 24 * time.Hour)
+	end := start.AddDate(0, 0, 7)
 
-	filteredEntries := c.filterByDateRange(entries, start, end)
+	filteredEntries := c.filterByDays(entries, start, end)
 	return c.generateReport(filteredEntries, "weekly", start, end)
 }
 
-func (c *Calculator) GenerateSessionReport(entries []types.UsageEntry) []types.SessionInfo {
-	sessionMap := make(map[string][]types.UsageEntry)
+// GenerateRollingReport reports the entries in [start, end), for rolling
+// windows such as the last 7 days that are not aligned to calendar periods
+func (c *Calculator) GenerateRollingReport(entries []types.UsageEntry, start, end time.Time) types.UsageReport {
+	filteredEntries := c.filterByDateRange(entries, start, end)
+	return c.generateReport(filteredEntries, "rolling", start, end)
+}
 
+func (c *Calculator) GenerateSessionReport(entries []types.UsageEntry) []types.SessionInfo {
 	// Group by project path instead of session ID (like TypeScript version)
-	for _, entry := range entries {
-		// Use project path as the grouping key
-		projectKey := entry.ProjectPath
-		if projectKey == "" {
-			projectKey = "unknown"
+	groups := Aggregate(entries, func(entry types.UsageEntry) string {
+		if entry.ProjectPath == "" {
+			return "unknown"
 		}
-		sessionMap[projectKey] = append(sessionMap[projectKey], entry)
-	}
+		return entry.ProjectPath
+	})
 
 	var sessions []types.SessionInfo
-	for projectPath, sessionEntries := range sessionMap {
-		if len(sessionEntries) == 0 {
-			continue
-		}
-
-		sort.Slice(sessionEntries, func(i, j int) bool {
-			return sessionEntries[i].Timestamp.Before(sessionEntries[j].Timestamp)
-		})
-
-		session := types.SessionInfo{
-			SessionID:    projectPath, // Use project path as session ID for display
-			StartTime:    sessionEntries[0].Timestamp,
-			EndTime:      sessionEntries[len(sessionEntries)-1].Timestamp,
-			RequestCount: len(sessionEntries),
-			ProjectPath:  projectPath,
-			LastActivity: sessionEntries[len(sessionEntries)-1].Timestamp, // Use last entry timestamp
-		}
-
-		session.Duration = session.EndTime.Sub(session.StartTime)
-		
-		// Track unique models, session IDs, and source files
-		modelSet := make(map[string]bool)
-		sessionIDSet := make(map[string]bool)
-		sourceFileSet := make(map[string]bool)
-
-		for _, entry := range sessionEntries {
-			session.TotalCost += entry.Cost
-			session.TotalAPICost += entry.APICost
-			session.CacheCreateCost += entry.CacheCreateCost
-			session.CacheReadCost += entry.CacheReadCost
-			session.TotalTokens += entry.TotalTokens
-			session.InputTokens += entry.InputTokens
-			session.OutputTokens += entry.OutputTokens
-
-			// Collect session name from first entry that has one
-			if session.SessionName == "" && entry.SessionName != "" {
-				session.SessionName = entry.SessionName
-			}
-
-			// Track unique session IDs
-			if entry.SessionID != "" {
-				sessionIDSet[entry.SessionID] = true
-			}
-
-			// Track unique source files
-			if entry.SourceFile != "" {
-				sourceFileSet[entry.SourceFile] = true
-			}
-
-			// Track models (exclude synthetic)
-			if entry.Model != "" && entry.Model != "<synthetic>" {
-				modelSet[entry.Model] = true
-			}
-
-			// Extract cache tokens from Raw data
-			if entry.Raw != nil {
-				if cc, ok := entry.Raw["cache_creation_input_tokens"].(int); ok {
-					session.CacheCreationTokens += cc
-				}
-				if cr, ok := entry.Raw["cache_read_input_tokens"].(int); ok {
-					session.CacheReadTokens += cr
-				}
-			}
-		}
-
-		// Convert model set to sorted slice
-		for model := range modelSet {
-			session.ModelsUsed = append(session.ModelsUsed, model)
-		}
-		sort.Strings(session.ModelsUsed)
+	for projectPath, group := range groups {
+		// Use project path as session ID for display
+		sessions = append(sessions, sessionInfo(projectPath, projectPath, group))
+	}
+	sortSessions(sessions)
+	return sessions
+}
 
-		// Convert session ID set to sorted slice
-		for sid := range sessionIDSet {
-			session.SessionIDs = append(session.SessionIDs, sid)
+// GenerateConversationReport reports every conversation (ConversationKey) on
+// its own, where GenerateSessionReport merges the conversations of a project
+func (c *Calculator) GenerateConversationReport(entries []types.UsageEntry) []types.SessionInfo {
+	key := ConversationKey()
+	projects := make(map[string]string)
+	for _, entry := range entries {
+		if conversation := key(entry); projects[conversation] == "" {
+			projects[conversation] = entry.ProjectPath
 		}
-		sort.Strings(session.SessionIDs)
+	}
 
-		// Convert source file set to sorted slice
-		for sf := range sourceFileSet {
-			session.SourceFiles = append(session.SourceFiles, sf)
-		}
-		sort.Strings(session.SourceFiles)
+	var conversations []types.SessionInfo
+	for conversation, group := range Aggregate(entries, key) {
+		conversations = append(conversations, sessionInfo(conversation, projects[conversation], group))
+	}
+	sortSessions(conversations)
+	return conversations
+}
 
-		sessions = append(sessions, session)
+// sessionInfo describes the totals of one session report row
+func sessionInfo(sessionID, projectPath string, group *GroupTotals) types.SessionInfo {
+	return types.SessionInfo{
+		SessionID:           sessionID,
+		StartTime:           group.FirstSeen,
+		EndTime:             group.LastSeen,
+		Duration:            group.LastSeen.Sub(group.FirstSeen),
+		TotalCost:           group.Cost,
+		TotalAPICost:        group.APICost,
+		TotalTokens:         group.TotalTokens,
+		InputTokens:         group.InputTokens,
+		OutputTokens:        group.OutputTokens,
+		CacheCreationTokens: group.CacheCreationTokens,
+		CacheCreateCost:     group.CacheCreateCost,
+		CacheReadTokens:     group.CacheReadTokens,
+		CacheReadCost:       group.CacheReadCost,
+		CacheSavings:        group.CacheSavings,
+		RequestCount:        group.RequestCount,
+		ProjectPath:         projectPath,
+		SessionName:         group.SessionName,
+		SessionIDs:          group.SortedSessionIDs(),
+		SourceFiles:         group.SortedSourceFiles(),
+		ModelsUsed:          group.SortedModels(),
+		LastActivity:        group.LastSeen,
+		PeakContextTokens:   group.PeakContextTokens,
+		ContextUtilization:  group.ContextUtilization,
+		NearContextLimit:    group.ContextUtilization >= ContextWarnThreshold,
 	}
+}
 
+// sortSessions orders session report rows by start time
+func sortSessions(sessions []types.SessionInfo) {
 	sort.Slice(sessions, func(i, j int) bool {
 		return sessions[i].StartTime.Before(sessions[j].StartTime)
 	})
-
-	return sessions
 }
 
 func (c *Calculator) AggregateBySourceFile(entries []types.UsageEntry) []types.SourceFileStat {
-	fileMap := make(map[string]*types.SourceFileStat)
-
-	for _, entry := range entries {
-		if entry.SourceFile == "" {
-			continue
-		}
-
-		stat, exists := fileMap[entry.SourceFile]
-		if !exists {
-			stat = &types.SourceFileStat{FilePath: entry.SourceFile}
-			fileMap[entry.SourceFile] = stat
-		}
-
-		stat.InputTokens += entry.InputTokens
-		stat.OutputTokens += entry.OutputTokens
-		stat.Cost += entry.Cost
-		stat.APICost += entry.APICost
-		stat.CacheCreateCost += entry.CacheCreateCost
-		stat.CacheReadCost += entry.CacheReadCost
-		stat.EntryCount++
-
-		// Cache tokens from Raw
-		if entry.Raw != nil {
-			if cc, ok := entry.Raw["cache_creation_input_tokens"].(int); ok {
-				stat.CacheCreateTokens += cc
-			}
-			if cr, ok := entry.Raw["cache_read_input_tokens"].(int); ok {
-				stat.CacheReadTokens += cr
-			}
-		}
-
-		// Total tokens
-		stat.TotalTokens = stat.InputTokens + stat.OutputTokens + stat.CacheCreateTokens + stat.CacheReadTokens
-
-		// Track models
-		if entry.Model != "" && entry.Model != "<synthetic>" {
-			found := false
-			for _, m := range stat.ModelsUsed {
-				if m == entry.Model {
-					found = true
-					break
-				}
-			}
-			if !found {
-				stat.ModelsUsed = append(stat.ModelsUsed, entry.Model)
-			}
-		}
-
-		// Track last activity
-		if entry.Timestamp.After(stat.LastActivity) {
-			stat.LastActivity = entry.Timestamp
-		}
-	}
+	groups := Aggregate(entries, func(entry types.UsageEntry) string {
+		return entry.SourceFile
+	})
 
 	var stats []types.SourceFileStat
-	for _, stat := range fileMap {
-		sort.Strings(stat.ModelsUsed)
-		stats = append(stats, *stat)
+	for path, group := range groups {
+		stats = append(stats, types.SourceFileStat{
+			FilePath:          path,
+			InputTokens:       group.InputTokens,
+			OutputTokens:      group.OutputTokens,
+			CacheCreateTokens: group.CacheCreationTokens,
+			CacheCreateCost:   group.CacheCreateCost,
+			CacheReadTokens:   group.CacheReadTokens,
+			CacheReadCost:     group.CacheReadCost,
+			TotalTokens:       group.TotalTokens,
+			Cost:              group.Cost,
+			APICost:           group.APICost,
+			ModelsUsed:        group.SortedModels(),
+			LastActivity:      group.LastSeen,
+			EntryCount:        group.RequestCount,
+		})
 	}
 	sort.Slice(stats, func(i, j int) bool {
 		return stats[i].FilePath < stats[j].FilePath
@@ -265,39 +243,20 @@ func (c *Calculator) AggregateBySourceFile(entries []types.UsageEntry) []types.S
 }
 
 func (c *Calculator) GenerateBlocksReport(entries []types.UsageEntry) []types.BlockInfo {
-	blockMap := make(map[string]*types.BlockInfo)
-
-	for _, entry := range entries {
-		if entry.BlockType == "" {
-			continue
-		}
-
-		if block, exists := blockMap[entry.BlockType]; exists {
-			block.Count++
-			block.TotalTokens += entry.TotalTokens
-			block.TotalCost += entry.Cost
-
-			if entry.Timestamp.Before(block.FirstSeen) {
-				block.FirstSeen = entry.Timestamp
-			}
-			if entry.Timestamp.After(block.LastSeen) {
-				block.LastSeen = entry.Timestamp
-			}
-		} else {
-			blockMap[entry.BlockType] = &types.BlockInfo{
-				BlockType:   entry.BlockType,
-				Count:       1,
-				TotalTokens: entry.TotalTokens,
-				TotalCost:   entry.Cost,
-				FirstSeen:   entry.Timestamp,
-				LastSeen:    entry.Timestamp,
-			}
-		}
-	}
+	groups := Aggregate(entries, func(entry types.UsageEntry) string {
+		return entry.BlockType
+	})
 
 	var blocks []types.BlockInfo
-	for _, block := range blockMap {
-		blocks = append(blocks, *block)
+	for blockType, group := range groups {
+		blocks = append(blocks, types.BlockInfo{
+			BlockType:   blockType,
+			Count:       group.RequestCount,
+			TotalTokens: group.TotalTokens,
+			TotalCost:   group.Cost,
+			FirstSeen:   group.FirstSeen,
+			LastSeen:    group.LastSeen,
+		})
 	}
 
 	sort.Slice(blocks, func(i, j int) bool {
@@ -307,10 +266,20 @@ func (c *Calculator) GenerateBlocksReport(entries []types.UsageEntry) []types.Bl
 	return blocks
 }
 
-func (c *Calculator) filterByDate(entries []types.UsageEntry, date time.Time) []types.UsageEntry {
-	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-	end := start.Add(24 * time.Hour)
-	return c.filterByDateRange(entries, start, end)
+// filterByDays keeps the entries whose day (DailyKey, as in the tables) lies
+// in [start, end), both midnights in the calculator's timezone
+func (c *Calculator) filterByDays(entries []types.UsageEntry, start, end time.Time) []types.UsageEntry {
+	first := start.Format("2006-01-02")
+	last := end.AddDate(0, 0, -1).Format("2006-01-02")
+	key := DailyKey(c.location)
+
+	var filtered []types.UsageEntry
+	for _, entry := range entries {
+		if day := key(entry); day != "" && day >= first && day <= last {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
 }
 
 func (c *Calculator) filterByDateRange(entries []types.UsageEntry, start, end time.Time) []types.UsageEntry {
@@ -336,38 +305,95 @@ func (c *Calculator) generateReport(entries []types.UsageEntry, period string, s
 		TotalTokens: summary.TotalTokens,
 		Entries:     entries,
 		Summary:     summary,
+
+		UnknownModels: c.UnknownModels(context.Background(), entries),
 	}
 }
 
 func (c *Calculator) calculateSummary(entries []types.UsageEntry) types.UsageSummary {
 	summary := types.UsageSummary{
-		Models:   make(map[string]int),
-		Projects: make(map[string]int),
+		Models:        make(map[string]int),
+		Projects:      make(map[string]int),
+		ModelFamilies: make(map[string]*types.ModelUsage),
 	}
 
 	for _, entry := range entries {
 		summary.TotalRequests++
 		summary.TotalCost += entry.Cost
+		summary.CacheSavings += entry.CacheSavings
 		summary.TotalTokens += entry.TotalTokens
 		summary.InputTokens += entry.InputTokens
 		summary.OutputTokens += entry.OutputTokens
 
-		// Skip synthetic model in statistics
-		if entry.Model != "<synthetic>" {
-			summary.Models[entry.Model]++
-		}
+		summary.Models[displayModel(entry.Model)]++
+		addModelUsage(summary.ModelFamilies, ModelFamily(entry.Model), entry)
 		summary.Projects[entry.ProjectPath]++
+		if IsBatch(entry) {
+			summary.BatchRequests++
+			summary.BatchCost += entry.Cost
+		}
 	}
 
 	if summary.TotalRequests > 0 {
 		summary.AverageCost = summary.TotalCost / float64(summary.TotalRequests)
+		summary.AverageTokensPerRequest = float64(summary.TotalTokens) / float64(summary.TotalRequests)
+	}
+	if summary.InputTokens > 0 {
+		summary.OutputInputRatio = float64(summary.OutputTokens) / float64(summary.InputTokens)
+	}
+	if summary.OutputTokens > 0 {
+		summary.CostPer1KOutputTokens = summary.TotalCost / float64(summary.OutputTokens) * 1000
 	}
 
 	return summary
 }
 
+// addModelUsage adds an entry to the usage of key, creating it as needed
+func addModelUsage(usage map[string]*types.ModelUsage, key string, entry types.UsageEntry) {
+	u, ok := usage[key]
+	if !ok {
+		u = &types.ModelUsage{Model: key}
+		usage[key] = u
+	}
+	u.InputTokens += entry.InputTokens
+	u.OutputTokens += entry.OutputTokens
+	if cc, ok := entry.Raw["cache_creation_input_tokens"].(int); ok {
+		u.CacheCreationInputTokens += cc
+	}
+	if cr, ok := entry.Raw["cache_read_input_tokens"].(int); ok {
+		u.CacheReadInputTokens += cr
+	}
+	u.TotalTokens += entry.TotalTokens
+	u.Cost += entry.Cost
+	u.RequestCount++
+}
+
+// ModelBreakdown returns the usage of entries per model, the most costly
+// first (then by tokens and name)
+func ModelBreakdown(entries []types.UsageEntry) []types.ModelUsage {
+	usage := make(map[string]*types.ModelUsage)
+	for _, entry := range entries {
+		addModelUsage(usage, displayModel(entry.Model), entry)
+	}
+	breakdown := make([]types.ModelUsage, 0, len(usage))
+	for _, u := range usage {
+		breakdown = append(breakdown, *u)
+	}
+	sort.Slice(breakdown, func(i, j int) bool {
+		a, b := breakdown[i], breakdown[j]
+		if a.Cost != b.Cost {
+			return a.Cost > b.Cost
+		}
+		if a.TotalTokens != b.TotalTokens {
+			return a.TotalTokens > b.TotalTokens
+		}
+		return a.Model < b.Model
+	})
+	return breakdown
+}
+
 func (c *Calculator) getWeekStart(year, week int) time.Time {
-	jan1 := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	jan1 := time.Date(year, 1, 1, 0, 0, 0, 0, locationOrLocal(c.location))
 
 	// Find first Monday
 	daysToMonday := (8 - int(jan1.Weekday())) % 7
@@ -0,0 +1,45 @@
+package calculator
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// UsageByCommit attributes already cost-calculated entries to the commits
+// they led up to. Each commit gets the requests made after the previous
+// commit, up to and including its own time; the oldest commit's interval
+// starts at start (zero for no bound). Requests after the newest commit, up
+// to now, go to an extra leading row without a Hash when there are any.
+// Commits are returned newest first.
+func (c *Calculator) UsageByCommit(entries []types.UsageEntry, commits []types.CommitUsage, start, now time.Time) []types.CommitUsage {
+	rows := append([]types.CommitUsage(nil), commits...)
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].CommittedAt.Before(rows[j].CommittedAt) })
+	rows = append(rows, types.CommitUsage{CommittedAt: now})
+	for i := range rows {
+		rows[i].Since = start
+		if i > 0 {
+			rows[i].Since = rows[i-1].CommittedAt
+		}
+	}
+
+	for _, entry := range entries {
+		// First commit at or after the request
+		i := sort.Search(len(rows), func(i int) bool { return !rows[i].CommittedAt.Before(entry.Timestamp) })
+		if i == len(rows) || (i == 0 && !start.IsZero() && !entry.Timestamp.After(start)) {
+			continue
+		}
+		rows[i].Requests++
+		rows[i].TotalTokens += entry.TotalTokens
+		rows[i].Cost += entry.Cost
+	}
+
+	if rows[len(rows)-1].Requests == 0 {
+		rows = rows[:len(rows)-1]
+	}
+	for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+		rows[i], rows[j] = rows[j], rows[i]
+	}
+	return rows
+}
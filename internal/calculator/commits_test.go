@@ -0,0 +1,43 @@
+package calculator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageByCommit(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2025, 3, 1, hour, 0, 0, 0, time.UTC) }
+	entries := []types.UsageEntry{
+		{Timestamp: at(8), TotalTokens: 1, Cost: 0.01},  // Before start
+		{Timestamp: at(10), TotalTokens: 10, Cost: 0.1}, // a
+		{Timestamp: at(11), TotalTokens: 20, Cost: 0.2}, // a, at its commit time
+		{Timestamp: at(13), TotalTokens: 30, Cost: 0.3}, // b
+		{Timestamp: at(15), TotalTokens: 40, Cost: 0.4}, // Uncommitted
+		{Timestamp: at(17), TotalTokens: 50, Cost: 0.5}, // After now
+	}
+	commits := []types.CommitUsage{
+		{Hash: "b", CommittedAt: at(14)},
+		{Hash: "a", CommittedAt: at(11)},
+	}
+
+	calc := New(nil)
+	rows := calc.UsageByCommit(entries, commits, at(9), at(16))
+	require.Len(t, rows, 3)
+	assert.Empty(t, rows[0].Hash, "work after the newest commit comes first")
+	assert.Equal(t, 40, rows[0].TotalTokens)
+	assert.Equal(t, "b", rows[1].Hash)
+	assert.Equal(t, at(11), rows[1].Since)
+	assert.Equal(t, 1, rows[1].Requests)
+	assert.Equal(t, "a", rows[2].Hash)
+	assert.Equal(t, at(9), rows[2].Since)
+	assert.Equal(t, 2, rows[2].Requests)
+	assert.InDelta(t, 0.3, rows[2].Cost, 1e-9)
+
+	rows = calc.UsageByCommit(entries[:4], commits, time.Time{}, at(16))
+	require.Len(t, rows, 2, "no uncommitted row without usage")
+	assert.Equal(t, 3, rows[1].Requests, "the root commit's interval is unbounded")
+}
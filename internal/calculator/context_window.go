@@ -0,0 +1,67 @@
+package calculator
+
+import (
+	"strings"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// ContextWarnThreshold is the context window utilization from which a session
+// is flagged as approaching the model's limit
+const ContextWarnThreshold = 0.8
+
+// contextWindows lists context window sizes (input tokens) by model name prefix
+var contextWindows = []struct {
+	prefix string
+	tokens int
+}{
+	{"claude-", 200_000},
+	{"gpt-4.1", 1_047_576},
+	{"gpt-5", 272_000},
+	{"o3", 200_000},
+	{"o4-mini", 200_000},
+}
+
+// extendedContextWindow is the long-context window some models offer on
+// request (Claude Sonnet 4's 1M beta). A request larger than the model's
+// standard window must have used it.
+const extendedContextWindow = 1_000_000
+
+// ContextTokens returns the prompt size of a request: its input plus cache
+// creation and cache read tokens. Every request resends the conversation so
+// far, so this is the conversation's context at that point.
+func ContextTokens(entry types.UsageEntry) int {
+	tokens := entry.InputTokens
+	if cc, ok := entry.Raw["cache_creation_input_tokens"].(int); ok {
+		tokens += cc
+	}
+	if cr, ok := entry.Raw["cache_read_input_tokens"].(int); ok {
+		tokens += cr
+	}
+	return tokens
+}
+
+// ContextWindow returns the context window a request of contextTokens to
+// model ran in, or 0 when the model's window is unknown
+func ContextWindow(model string, contextTokens int) int {
+	for _, w := range contextWindows {
+		if strings.HasPrefix(model, w.prefix) {
+			if contextTokens > w.tokens && extendedContextWindow > w.tokens {
+				return extendedContextWindow
+			}
+			return w.tokens
+		}
+	}
+	return 0
+}
+
+// ContextUtilization returns the share of its context window a request used
+// (0 when the window is unknown)
+func ContextUtilization(entry types.UsageEntry) float64 {
+	tokens := ContextTokens(entry)
+	window := ContextWindow(entry.Model, tokens)
+	if window == 0 {
+		return 0
+	}
+	return float64(tokens) / float64(window)
+}
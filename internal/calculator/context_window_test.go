@@ -0,0 +1,50 @@
+package calculator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextWindow(t *testing.T) {
+	assert.Equal(t, 200_000, ContextWindow("claude-opus-4-1-20250805", 150_000))
+	assert.Equal(t, 1_000_000, ContextWindow("claude-sonnet-4-5-20250929", 350_000), "larger prompts used the 1M window")
+	assert.Equal(t, 272_000, ContextWindow("gpt-5-codex", 10_000))
+	assert.Zero(t, ContextWindow("<synthetic>", 10_000))
+}
+
+func TestSessionReportContextUtilization(t *testing.T) {
+	request := func(project string, input, cacheRead int) types.UsageEntry {
+		return types.UsageEntry{
+			Timestamp:   time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC),
+			ProjectPath: project,
+			Model:       "claude-sonnet-4-5-20250929",
+			InputTokens: input,
+			Raw:         map[string]interface{}{"cache_read_input_tokens": cacheRead},
+		}
+	}
+	entries := []types.UsageEntry{
+		request("near", 1_000, 20_000),
+		request("near", 2_000, 168_000), // 170k of 200k
+		request("roomy", 1_000, 50_000),
+	}
+
+	sessions := New(nil).GenerateSessionReport(entries)
+	require.Len(t, sessions, 2)
+	byProject := map[string]types.SessionInfo{}
+	for _, session := range sessions {
+		byProject[session.ProjectPath] = session
+	}
+
+	near := byProject["near"]
+	assert.Equal(t, 170_000, near.PeakContextTokens)
+	assert.InDelta(t, 0.85, near.ContextUtilization, 1e-9)
+	assert.True(t, near.NearContextLimit)
+
+	roomy := byProject["roomy"]
+	assert.InDelta(t, 0.255, roomy.ContextUtilization, 1e-9)
+	assert.False(t, roomy.NearContextLimit)
+}
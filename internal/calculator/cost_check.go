@@ -0,0 +1,59 @@
+package calculator
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// CompareCosts recomputes from tokens the cost of every entry that logged one
+// (costUSD) and totals both per model, which shows stale pricing data or
+// logging bugs. entries must still carry their logged costs, i.e. not have
+// been through CalculateCosts in calculate or display mode.
+func (c *Calculator) CompareCosts(ctx context.Context, entries []types.UsageEntry) types.CostDiscrepancyReport {
+	var report types.CostDiscrepancyReport
+	byModel := make(map[string]*types.ModelCostDiscrepancy)
+
+	for _, entry := range entries {
+		if !entry.CostFromLog {
+			report.UnrecordedRequests++
+			continue
+		}
+
+		model, ok := byModel[entry.Model]
+		if !ok {
+			model = &types.ModelCostDiscrepancy{Model: entry.Model}
+			byModel[entry.Model] = model
+		}
+
+		computed := entry
+		computed.Cost, computed.APICost, computed.CacheCreateCost, computed.CacheReadCost = 0, 0, 0, 0
+		if !c.calculateSingleCost(ctx, &computed) {
+			model.Unpriced = true
+		}
+
+		model.Requests++
+		model.RecordedCost += entry.Cost
+		model.ComputedCost += computed.Cost
+	}
+
+	for _, model := range byModel {
+		model.Difference = model.ComputedCost - model.RecordedCost
+		report.Models = append(report.Models, *model)
+		report.Requests += model.Requests
+		report.RecordedCost += model.RecordedCost
+		report.ComputedCost += model.ComputedCost
+	}
+	report.Difference = report.ComputedCost - report.RecordedCost
+
+	sort.Slice(report.Models, func(i, j int) bool {
+		a, b := math.Abs(report.Models[i].Difference), math.Abs(report.Models[j].Difference)
+		if a != b {
+			return a > b
+		}
+		return report.Models[i].Model < report.Models[j].Model
+	})
+	return report
+}
@@ -0,0 +1,53 @@
+package calculator
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// partialPricing prices every model except unknown
+type partialPricing struct {
+	mockPricing
+	unknown string
+}
+
+func (p *partialPricing) GetModelPrice(ctx context.Context, model string) (float64, float64, float64, float64, error) {
+	if model == p.unknown {
+		return 0, 0, 0, 0, fmt.Errorf("no pricing for %s", model)
+	}
+	return p.mockPricing.GetModelPrice(ctx, model)
+}
+
+func TestCompareCosts(t *testing.T) {
+	pricing := &partialPricing{mockPricing: mockPricing{inputPrice: 0.01, outputPrice: 0.03}, unknown: "new-model"}
+	entries := []types.UsageEntry{
+		{Model: "sonnet", InputTokens: 100, OutputTokens: 50, Cost: 2.5, CostFromLog: true}, // Matches
+		{Model: "opus", InputTokens: 100, OutputTokens: 50, Cost: 1, CostFromLog: true},     // Logged $1.50 too low
+		{Model: "opus", InputTokens: 100, OutputTokens: 50},                                 // No costUSD
+		{Model: "new-model", InputTokens: 100, Cost: 0.5, CostFromLog: true},
+	}
+
+	report := New(pricing).CompareCosts(context.Background(), entries)
+
+	require.Len(t, report.Models, 3)
+	assert.Equal(t, "opus", report.Models[0].Model, "largest difference first")
+	assert.Equal(t, 1, report.Models[0].Requests)
+	assert.InDelta(t, 1.5, report.Models[0].Difference, 1e-9)
+	assert.Equal(t, "new-model", report.Models[1].Model)
+	assert.True(t, report.Models[1].Unpriced)
+	assert.InDelta(t, -0.5, report.Models[1].Difference, 1e-9)
+	assert.Equal(t, "sonnet", report.Models[2].Model)
+	assert.InDelta(t, 0, report.Models[2].Difference, 1e-9)
+
+	assert.Equal(t, 3, report.Requests)
+	assert.Equal(t, 1, report.UnrecordedRequests)
+	assert.InDelta(t, 4.0, report.RecordedCost, 1e-9)
+	assert.InDelta(t, 5.0, report.ComputedCost, 1e-9)
+	assert.InDelta(t, 1.0, report.Difference, 1e-9)
+	assert.InDelta(t, 1.0, entries[1].Cost, 1e-9, "entries are left untouched")
+}
@@ -0,0 +1,54 @@
+package calculator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// CostMode selects where entry costs come from, as --mode does in the
+// TypeScript ccusage
+type CostMode string
+
+const (
+	CostModeAuto      CostMode = "auto"      // costUSD from the log when present, otherwise computed from tokens
+	CostModeCalculate CostMode = "calculate" // Always computed from tokens, ignoring costUSD
+	CostModeDisplay   CostMode = "display"   // costUSD from the log only; entries without one cost nothing
+)
+
+// ParseCostMode validates a --mode value
+func ParseCostMode(value string) (CostMode, error) {
+	switch mode := CostMode(value); mode {
+	case CostModeAuto, CostModeCalculate, CostModeDisplay:
+		return mode, nil
+	}
+	return "", fmt.Errorf("invalid --mode %q, use auto, calculate or display", value)
+}
+
+// SetMode selects the cost source for later calculations ("" means auto)
+func (c *Calculator) SetMode(mode CostMode) {
+	if mode == "" {
+		mode = CostModeAuto
+	}
+	c.mode = mode
+}
+
+// applyCost sets an entry's cost according to the calculator's mode
+func (c *Calculator) applyCost(ctx context.Context, entry *types.UsageEntry) {
+	switch c.mode {
+	case CostModeCalculate:
+		// A logged cost must not survive when the model has no price
+		entry.Cost, entry.APICost, entry.CacheCreateCost, entry.CacheReadCost = 0, 0, 0, 0
+		c.calculateSingleCost(ctx, entry)
+	case CostModeDisplay:
+		if !entry.CostFromLog {
+			entry.Cost = 0
+		}
+	default:
+		if !entry.CostFromLog {
+			c.calculateSingleCost(ctx, entry)
+		}
+	}
+	c.applyCacheSavings(ctx, entry)
+}
@@ -0,0 +1,71 @@
+package calculator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCostModes(t *testing.T) {
+	pricing := &mockPricing{inputPrice: 0.01, outputPrice: 0.03}
+	newEntries := func() []types.UsageEntry {
+		return []types.UsageEntry{
+			{Model: "claude-sonnet-4-5-20250514", InputTokens: 100, OutputTokens: 50, Cost: 9, CostFromLog: true},
+			{Model: "claude-sonnet-4-5-20250514", InputTokens: 100, OutputTokens: 50, CostFromLog: true}, // costUSD: 0
+			{Model: "claude-sonnet-4-5-20250514", InputTokens: 100, OutputTokens: 50},
+		}
+	}
+
+	tests := []struct {
+		mode CostMode
+		want []float64
+	}{
+		{CostModeAuto, []float64{9, 0, 2.5}},
+		{CostModeCalculate, []float64{2.5, 2.5, 2.5}},
+		{CostModeDisplay, []float64{9, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			calc := New(pricing)
+			calc.SetMode(tt.mode)
+			entries, err := calc.CalculateCosts(context.Background(), newEntries())
+			require.NoError(t, err)
+			for i, want := range tt.want {
+				assert.InDelta(t, want, entries[i].Cost, 1e-9, "entry %d", i)
+			}
+		})
+	}
+}
+
+func TestParseCostMode(t *testing.T) {
+	mode, err := ParseCostMode("calculate")
+	require.NoError(t, err)
+	assert.Equal(t, CostModeCalculate, mode)
+
+	_, err = ParseCostMode("estimate")
+	assert.Error(t, err)
+}
+
+// warmingPricing records Warm calls
+type warmingPricing struct {
+	mockPricing
+	warmed int
+}
+
+func (w *warmingPricing) Warm(ctx context.Context) {
+	w.warmed++
+}
+
+func TestWarmPricing(t *testing.T) {
+	pricing := &warmingPricing{}
+	calc := New(pricing)
+	calc.SetMode(CostModeDisplay) // Cache savings still need the rates
+	calc.WarmPricing(context.Background())
+	assert.Equal(t, 1, pricing.warmed)
+
+	// Services that cannot warm up are left alone
+	New(&mockPricing{}).WarmPricing(context.Background())
+}
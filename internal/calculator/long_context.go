@@ -0,0 +1,64 @@
+package calculator
+
+import (
+	"context"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// LongContextThreshold is the prompt size (see ContextTokens) above which a
+// request is billed at the model's long-context rates
+const LongContextThreshold = 200_000
+
+// LongContextPricingService is implemented by pricing services that know the
+// higher rates some models charge for prompts above LongContextThreshold. ok
+// is false for models without such a tier.
+type LongContextPricingService interface {
+	GetLongContextPrice(ctx context.Context, model string) (inputPrice, outputPrice, cacheCreatePrice, cacheReadPrice float64, ok bool)
+}
+
+// DatedPricingService is implemented by pricing services whose rates change
+// over time (dated overrides); each request is priced at its timestamp
+type DatedPricingService interface {
+	GetModelPriceAt(ctx context.Context, model string, at time.Time) (inputPrice, outputPrice, cacheCreatePrice, cacheReadPrice float64, err error)
+	GetLongContextPriceAt(ctx context.Context, model string, at time.Time) (inputPrice, outputPrice, cacheCreatePrice, cacheReadPrice float64, ok bool)
+}
+
+// IsLongContext reports whether a request's prompt exceeds LongContextThreshold
+func IsLongContext(entry types.UsageEntry) bool {
+	return ContextTokens(entry) > LongContextThreshold
+}
+
+// entryPrices returns the per-token prices of an entry's request: the
+// model's long-context rates when its prompt exceeds LongContextThreshold
+// and the pricing service has them, since the whole request (output
+// included) is then billed at those rates, and the base rates otherwise.
+// Batch API requests get BatchDiscount off either. A DatedPricingService
+// gives the rates in effect at the request's timestamp.
+func (c *Calculator) entryPrices(ctx context.Context, entry types.UsageEntry) (inputPrice, outputPrice, cacheCreatePrice, cacheReadPrice float64, err error) {
+	dated, isDated := c.pricingService.(DatedPricingService)
+	found := false
+	if IsLongContext(entry) {
+		if isDated {
+			inputPrice, outputPrice, cacheCreatePrice, cacheReadPrice, found = dated.GetLongContextPriceAt(ctx, entry.Model, entry.Timestamp)
+		} else if tiered, ok := c.pricingService.(LongContextPricingService); ok {
+			inputPrice, outputPrice, cacheCreatePrice, cacheReadPrice, found = tiered.GetLongContextPrice(ctx, entry.Model)
+		}
+	}
+	if !found {
+		if isDated {
+			inputPrice, outputPrice, cacheCreatePrice, cacheReadPrice, err = dated.GetModelPriceAt(ctx, entry.Model, entry.Timestamp)
+		} else {
+			inputPrice, outputPrice, cacheCreatePrice, cacheReadPrice, err = c.pricingService.GetModelPrice(ctx, entry.Model)
+		}
+		if err != nil {
+			return 0, 0, 0, 0, err
+		}
+	}
+	if IsBatch(entry) {
+		factor := 1 - BatchDiscount
+		inputPrice, outputPrice, cacheCreatePrice, cacheReadPrice = inputPrice*factor, outputPrice*factor, cacheCreatePrice*factor, cacheReadPrice*factor
+	}
+	return inputPrice, outputPrice, cacheCreatePrice, cacheReadPrice, nil
+}
@@ -0,0 +1,90 @@
+package calculator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// tieredPricing doubles every rate of tieredModel above LongContextThreshold
+type tieredPricing struct {
+	mockPricing
+	tieredModel string
+}
+
+func (p *tieredPricing) GetLongContextPrice(ctx context.Context, model string) (float64, float64, float64, float64, bool) {
+	if model != p.tieredModel {
+		return 0, 0, 0, 0, false
+	}
+	return 2 * p.inputPrice, 2 * p.outputPrice, 2 * p.cacheCreatePrice, 2 * p.cacheReadPrice, true
+}
+
+func TestLongContextPricing(t *testing.T) {
+	pricing := &tieredPricing{
+		mockPricing: mockPricing{inputPrice: 0.001, outputPrice: 0.002, cacheCreatePrice: 0.0005, cacheReadPrice: 0.0001},
+		tieredModel: "sonnet",
+	}
+	calc := New(pricing)
+	calc.SetMode(CostModeCalculate)
+
+	prompt := func(model string, cacheRead int) types.UsageEntry {
+		return types.UsageEntry{
+			Model:        model,
+			InputTokens:  1000,
+			OutputTokens: 100,
+			Raw:          map[string]interface{}{"cache_read_input_tokens": cacheRead},
+		}
+	}
+	entries := []types.UsageEntry{
+		prompt("sonnet", LongContextThreshold-1000), // Exactly at the threshold
+		prompt("sonnet", LongContextThreshold),      // Above it
+		prompt("opus", LongContextThreshold),        // No long-context tier
+	}
+	_, err := calc.CalculateCosts(context.Background(), entries)
+	assert.NoError(t, err)
+
+	assert.False(t, IsLongContext(entries[0]))
+	base := 1000*0.001 + 100*0.002 + float64(LongContextThreshold-1000)*0.0001
+	assert.InDelta(t, base, entries[0].Cost, 1e-9)
+
+	assert.True(t, IsLongContext(entries[1]))
+	assert.InDelta(t, 2*(1000*0.001+100*0.002+float64(LongContextThreshold)*0.0001), entries[1].Cost, 1e-9,
+		"the whole request, output included, is billed at the long-context rates")
+	assert.InDelta(t, 2*(1000*0.001+100*0.002), entries[1].APICost, 1e-9)
+
+	assert.InDelta(t, 1000*0.001+100*0.002+float64(LongContextThreshold)*0.0001, entries[2].Cost, 1e-9)
+}
+
+// datedPricing doubles the rates of requests before changedAt
+type datedPricing struct {
+	mockPricing
+	changedAt time.Time
+}
+
+func (p *datedPricing) GetModelPriceAt(ctx context.Context, model string, at time.Time) (float64, float64, float64, float64, error) {
+	if at.Before(p.changedAt) {
+		return 2 * p.inputPrice, 2 * p.outputPrice, 0, 0, nil
+	}
+	return p.inputPrice, p.outputPrice, 0, 0, nil
+}
+
+func (p *datedPricing) GetLongContextPriceAt(ctx context.Context, model string, at time.Time) (float64, float64, float64, float64, bool) {
+	return 0, 0, 0, 0, false
+}
+
+func TestDatedPricing(t *testing.T) {
+	changedAt := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	calc := New(&datedPricing{mockPricing: mockPricing{inputPrice: 0.001, outputPrice: 0.002}, changedAt: changedAt})
+
+	entries := []types.UsageEntry{
+		{Model: "opus", InputTokens: 1000, OutputTokens: 100, Timestamp: changedAt.Add(-time.Minute)},
+		{Model: "opus", InputTokens: 1000, OutputTokens: 100, Timestamp: changedAt},
+	}
+	_, err := calc.CalculateCosts(context.Background(), entries)
+	assert.NoError(t, err)
+	assert.InDelta(t, 2*(1000*0.001+100*0.002), entries[0].Cost, 1e-9)
+	assert.InDelta(t, 1000*0.001+100*0.002, entries[1].Cost, 1e-9)
+}
@@ -0,0 +1,47 @@
+package calculator
+
+import (
+	"strings"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// Model families of ModelFamily
+const (
+	FamilyOpus   = "Opus"
+	FamilySonnet = "Sonnet"
+	FamilyHaiku  = "Haiku"
+	FamilyGPT    = "GPT"
+	FamilyOther  = "Other"
+)
+
+// ModelOther is the model name <synthetic> entries are listed under when the
+// loader keeps them (--include-synthetic); their family is Other
+const ModelOther = "other"
+
+// displayModel returns the model entries of model are listed under
+func displayModel(model string) string {
+	if model == types.SyntheticModel {
+		return ModelOther
+	}
+	return model
+}
+
+// ModelFamily rolls a model up to its family regardless of version and date
+// suffix: claude-opus-4-1-20250805 and claude-3-opus-20240229 are both Opus,
+// gpt-5-codex and o4-mini are GPT. Unrecognized models are Other.
+func ModelFamily(model string) string {
+	name := strings.ToLower(model)
+	switch {
+	case strings.Contains(name, "opus"):
+		return FamilyOpus
+	case strings.Contains(name, "sonnet"):
+		return FamilySonnet
+	case strings.Contains(name, "haiku"):
+		return FamilyHaiku
+	case strings.HasPrefix(name, "gpt-") || strings.HasPrefix(name, "codex-") ||
+		len(name) > 1 && name[0] == 'o' && name[1] >= '0' && name[1] <= '9':
+		return FamilyGPT
+	}
+	return FamilyOther
+}
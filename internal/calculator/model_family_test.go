@@ -0,0 +1,51 @@
+package calculator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelFamily(t *testing.T) {
+	for model, want := range map[string]string{
+		"claude-opus-4-1-20250805":   FamilyOpus,
+		"claude-3-opus-20240229":     FamilyOpus,
+		"claude-sonnet-4-5-20250929": FamilySonnet,
+		"claude-3-5-sonnet-20241022": FamilySonnet,
+		"claude-haiku-4-5":           FamilyHaiku,
+		"gpt-5-codex":                FamilyGPT,
+		"o4-mini":                    FamilyGPT,
+		"gemini-2.5-pro":             FamilyOther,
+		"<synthetic>":                FamilyOther,
+	} {
+		assert.Equal(t, want, ModelFamily(model), model)
+	}
+}
+
+func TestSummaryRollsUpModelFamilies(t *testing.T) {
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	entries := []types.UsageEntry{
+		{Timestamp: day.Add(time.Hour), Model: "claude-opus-4-20250514", OutputTokens: 10, TotalTokens: 10, Cost: 1},
+		{Timestamp: day.Add(time.Hour), Model: "claude-opus-4-1-20250805", OutputTokens: 20, TotalTokens: 20, Cost: 2},
+		{Timestamp: day.Add(time.Hour), Model: "claude-sonnet-4-5-20250929", OutputTokens: 5, TotalTokens: 5, Cost: 0.5},
+		{Timestamp: day.Add(time.Hour), Model: "<synthetic>"},
+	}
+
+	calc := New(nil)
+	calc.SetTimezone(time.UTC)
+	families := calc.GenerateDailyReport(entries, day).Summary.ModelFamilies
+
+	require.Len(t, families, 3)
+	assert.Equal(t, 1, families[FamilyOther].RequestCount, "kept <synthetic> entries are Other")
+	assert.Equal(t, 2, families[FamilyOpus].RequestCount)
+	assert.Equal(t, 30, families[FamilyOpus].TotalTokens)
+	assert.InDelta(t, 3.0, families[FamilyOpus].Cost, 1e-9)
+	assert.Equal(t, 1, families[FamilySonnet].RequestCount)
+
+	groups := Aggregate(entries, ModelFamilyKey())
+	assert.Equal(t, 2, groups[FamilyOpus].RequestCount)
+	assert.Equal(t, []string{"claude-opus-4-1-20250805", "claude-opus-4-20250514"}, groups[FamilyOpus].SortedModels())
+}
@@ -0,0 +1,74 @@
+package calculator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// Plan is a Claude subscription whose usage limits are estimated
+type Plan string
+
+const (
+	PlanPro   Plan = "pro"
+	PlanMax5  Plan = "max5"
+	PlanMax20 Plan = "max20"
+)
+
+// PlanLimits are the estimated limits of a plan. Anthropic does not publish
+// exact limits, so these are heuristics observed by the community: block
+// tokens count input and output tokens only (cache tokens are not limited
+// the same way), and a week is assumed to hold about ten full blocks.
+type PlanLimits struct {
+	Name        string
+	BlockTokens int
+	BlockCost   float64
+	WeeklyCost  float64
+}
+
+// PlanPresets are the limits of each Plan
+var PlanPresets = map[Plan]PlanLimits{
+	PlanPro:   {Name: "Pro", BlockTokens: 19_000, BlockCost: 18, WeeklyCost: 180},
+	PlanMax5:  {Name: "Max 5x", BlockTokens: 88_000, BlockCost: 35, WeeklyCost: 350},
+	PlanMax20: {Name: "Max 20x", BlockTokens: 220_000, BlockCost: 140, WeeklyCost: 1400},
+}
+
+// PlanWeek is the rolling window weekly plan usage is measured over
+const PlanWeek = 7 * 24 * time.Hour
+
+// ParsePlan validates a --plan value
+func ParsePlan(value string) (Plan, error) {
+	if _, ok := PlanPresets[Plan(value)]; ok {
+		return Plan(value), nil
+	}
+	return "", fmt.Errorf("invalid --plan %q, use pro, max5 or max20", value)
+}
+
+// PlanCapacity estimates how much of plan's limits is left: in block (nil
+// when no block is active, leaving a whole block) and in the rolling week
+// ending at now, from already cost-calculated entries
+func PlanCapacity(plan Plan, block *types.SessionBlock, entries []types.UsageEntry, now time.Time) types.PlanCapacity {
+	limits := PlanPresets[plan]
+	capacity := types.PlanCapacity{
+		Plan:            limits.Name,
+		BlockTokenLimit: limits.BlockTokens,
+		BlockCostLimit:  limits.BlockCost,
+		WeekCostLimit:   limits.WeeklyCost,
+	}
+	if block != nil {
+		capacity.BlockTokensUsed = block.TokenCounts.InputTokens + block.TokenCounts.OutputTokens
+		capacity.BlockCostUsed = block.CostUSD
+	}
+	weekStart := now.Add(-PlanWeek)
+	for _, entry := range entries {
+		if entry.Timestamp.After(weekStart) && !entry.Timestamp.After(now) {
+			capacity.WeekCostUsed += entry.Cost
+		}
+	}
+
+	capacity.BlockTokensRemaining = max(capacity.BlockTokenLimit-capacity.BlockTokensUsed, 0)
+	capacity.BlockCostRemaining = max(capacity.BlockCostLimit-capacity.BlockCostUsed, 0)
+	capacity.WeekCostRemaining = max(capacity.WeekCostLimit-capacity.WeekCostUsed, 0)
+	return capacity
+}
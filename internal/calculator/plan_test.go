@@ -0,0 +1,47 @@
+package calculator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePlan(t *testing.T) {
+	plan, err := ParsePlan("max5")
+	require.NoError(t, err)
+	assert.Equal(t, PlanMax5, plan)
+
+	_, err = ParsePlan("team")
+	assert.EqualError(t, err, `invalid --plan "team", use pro, max5 or max20`)
+}
+
+func TestPlanCapacity(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	entries := []types.UsageEntry{
+		{Timestamp: now.Add(-8 * 24 * time.Hour), Cost: 100}, // Before the week
+		{Timestamp: now.Add(-3 * 24 * time.Hour), Cost: 20},
+		{Timestamp: now.Add(-time.Hour), Cost: 10},
+	}
+	block := &types.SessionBlock{
+		TokenCounts: types.TokenCounts{InputTokens: 4_000, OutputTokens: 6_000, CacheReadInputTokens: 1_000_000},
+		CostUSD:     10,
+	}
+
+	capacity := PlanCapacity(PlanPro, block, entries, now)
+	assert.Equal(t, "Pro", capacity.Plan)
+	assert.Equal(t, 10_000, capacity.BlockTokensUsed, "cache tokens are not counted")
+	assert.Equal(t, 9_000, capacity.BlockTokensRemaining)
+	assert.InDelta(t, 8, capacity.BlockCostRemaining, 1e-9)
+	assert.InDelta(t, 30, capacity.WeekCostUsed, 1e-9)
+	assert.InDelta(t, 150, capacity.WeekCostRemaining, 1e-9)
+
+	block.CostUSD = 25
+	capacity = PlanCapacity(PlanPro, block, entries, now)
+	assert.Zero(t, capacity.BlockCostRemaining, "an exceeded limit leaves nothing")
+
+	capacity = PlanCapacity(PlanMax20, nil, nil, now)
+	assert.Equal(t, 220_000, capacity.BlockTokensRemaining, "without an active block a whole block is left")
+}
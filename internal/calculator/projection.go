@@ -0,0 +1,92 @@
+package calculator
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// ProjectionMethod selects the burn rate block projections extrapolate
+type ProjectionMethod string
+
+const (
+	ProjectionAverage ProjectionMethod = "average" // Average rate between the block's first and last request
+	ProjectionRecent  ProjectionMethod = "recent"  // Exponentially weighted per-minute rate, favouring the last minutes
+)
+
+// RecentBurnRateHalfLifeMinutes is how quickly older minutes lose weight in
+// the recent burn rate: a minute counts half as much as one this much newer
+const RecentBurnRateHalfLifeMinutes = 10
+
+// ProjectionIntervalZ is the number of standard deviations on each side of a
+// projection its low and high estimates span (a 90% interval)
+const ProjectionIntervalZ = 1.645
+
+// ParseProjectionMethod validates a --projection value
+func ParseProjectionMethod(value string) (ProjectionMethod, error) {
+	switch method := ProjectionMethod(value); method {
+	case ProjectionAverage, ProjectionRecent:
+		return method, nil
+	}
+	return "", fmt.Errorf("invalid --projection %q, use recent or average", value)
+}
+
+// recentBurnRate returns the exponentially weighted tokens and cost per minute
+// of a block's burn-rate series. Idle minutes up to now pull the rate down,
+// so a pause is reflected within minutes instead of being averaged away.
+func recentBurnRate(block types.SessionBlock) (tokensPerMinute, costPerMinute float64, ok bool) {
+	series := BurnRateSeries(block)
+	if len(series) < 2 {
+		return 0, 0, false
+	}
+
+	alpha := 1 - math.Pow(0.5, 1.0/RecentBurnRateHalfLifeMinutes)
+	tokensPerMinute, costPerMinute = float64(series[0].Tokens), series[0].CostUSD
+	for _, point := range series[1:] {
+		tokensPerMinute = alpha*float64(point.Tokens) + (1-alpha)*tokensPerMinute
+		costPerMinute = alpha*point.CostUSD + (1-alpha)*costPerMinute
+	}
+	return tokensPerMinute, costPerMinute, true
+}
+
+// setProjectionInterval sets the low and high estimates of a projection from
+// the spread of the block's per-minute usage. The remaining minutes are
+// treated as independent draws, so the interval widens with the square root
+// of the time left; it never drops below what the block has already used.
+func setProjectionInterval(projection *types.ProjectedUsage, block types.SessionBlock, currentTokens int) {
+	tokensSD, costSD := burnRateDeviation(BurnRateSeries(block))
+	spread := ProjectionIntervalZ * math.Sqrt(projection.RemainingMinutes)
+
+	tokenMargin := int(tokensSD * spread)
+	projection.LowTokens = max(projection.TotalTokens-tokenMargin, currentTokens)
+	projection.HighTokens = projection.TotalTokens + tokenMargin
+
+	costMargin := costSD * spread
+	projection.LowCost = math.Max(projection.TotalCost-costMargin, block.CostUSD)
+	projection.HighCost = projection.TotalCost + costMargin
+}
+
+// burnRateDeviation returns the sample standard deviation of the tokens and
+// cost per minute of a burn-rate series, zero with fewer than two minutes
+func burnRateDeviation(series []types.BurnRatePoint) (tokensSD, costSD float64) {
+	if len(series) < 2 {
+		return 0, 0
+	}
+
+	var tokensMean, costMean float64
+	for _, point := range series {
+		tokensMean += float64(point.Tokens)
+		costMean += point.CostUSD
+	}
+	n := float64(len(series))
+	tokensMean /= n
+	costMean /= n
+
+	var tokensVar, costVar float64
+	for _, point := range series {
+		tokensVar += math.Pow(float64(point.Tokens)-tokensMean, 2)
+		costVar += math.Pow(point.CostUSD-costMean, 2)
+	}
+	return math.Sqrt(tokensVar / (n - 1)), math.Sqrt(costVar / (n - 1))
+}
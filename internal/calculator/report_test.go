@@ -0,0 +1,38 @@
+package calculator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeriodReportsFollowDateKeys(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	at := func(ts string) types.UsageEntry {
+		timestamp, _ := time.Parse(time.RFC3339, ts)
+		// DateKey as the loader sets it for --timezone Asia/Tokyo
+		return types.UsageEntry{Timestamp: timestamp, DateKey: timestamp.In(tokyo).Format("2006-01-02"), Cost: 1}
+	}
+	entries := []types.UsageEntry{
+		at("2025-02-28T14:59:00Z"), // Feb 28 23:59 in Tokyo
+		at("2025-02-28T15:00:00Z"), // Mar 1 00:00 in Tokyo
+		at("2025-03-31T14:00:00Z"), // Mar 31 23:00 in Tokyo
+		at("2025-03-31T15:30:00Z"), // Apr 1 00:30 in Tokyo
+	}
+
+	calc := New(nil)
+	calc.SetTimezone(tokyo)
+
+	daily := calc.GenerateDailyReport(entries, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+	assert.Len(t, daily.Entries, 1)
+	assert.Equal(t, entries[1].Timestamp, daily.Entries[0].Timestamp)
+	assert.True(t, daily.StartTime.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, tokyo)))
+	assert.True(t, daily.EndTime.Equal(time.Date(2025, 3, 2, 0, 0, 0, 0, tokyo)))
+
+	monthly := calc.GenerateMonthlyReport(entries, 2025, 3)
+	assert.Len(t, monthly.Entries, 2)
+	assert.InDelta(t, 2.0, monthly.TotalCost, 1e-9)
+	assert.True(t, monthly.StartTime.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, tokyo)))
+}
@@ -374,3 +374,26 @@ func TestAggregateBySourceFile(t *testing.T) {
 	assert.Equal(t, 0.5, stats[1].Cost)
 	assert.Equal(t, 1, stats[1].EntryCount)
 }
+
+func TestGenerateConversationReportSplitsProjects(t *testing.T) {
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	entries := []types.UsageEntry{
+		{Timestamp: start, ProjectPath: "/p/app", SessionID: "conv-1", InputTokens: 10, Cost: 1},
+		{Timestamp: start.Add(30 * time.Minute), ProjectPath: "/p/app", SessionID: "conv-1", InputTokens: 10, Cost: 1},
+		{Timestamp: start.Add(time.Hour), ProjectPath: "/p/app", SessionID: "conv-2", InputTokens: 10, Cost: 1},
+		{Timestamp: start.Add(2 * time.Hour), ProjectPath: "/p/app"},
+	}
+
+	conversations := New(nil).GenerateConversationReport(entries)
+
+	require.Len(t, conversations, 3)
+	assert.Equal(t, "conv-1", conversations[0].SessionID)
+	assert.Equal(t, "/p/app", conversations[0].ProjectPath)
+	assert.Equal(t, 2, conversations[0].RequestCount)
+	assert.Equal(t, 30*time.Minute, conversations[0].Duration)
+	assert.Equal(t, "conv-2", conversations[1].SessionID)
+	assert.Equal(t, 1, conversations[1].RequestCount)
+	assert.Equal(t, ConversationUnknown, conversations[2].SessionID)
+
+	assert.Len(t, New(nil).GenerateSessionReport(entries), 1, "the session report merges the project's conversations")
+}
@@ -0,0 +1,47 @@
+package calculator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummaryEfficiencyMetrics(t *testing.T) {
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	entries := []types.UsageEntry{
+		{Timestamp: day.Add(time.Hour), InputTokens: 100, OutputTokens: 300, TotalTokens: 1400, Cost: 0.6},
+		{Timestamp: day.Add(2 * time.Hour), InputTokens: 300, OutputTokens: 200, TotalTokens: 600, Cost: 0.4},
+	}
+
+	calc := New(nil)
+	calc.SetTimezone(time.UTC)
+	summary := calc.GenerateDailyReport(entries, day).Summary
+
+	assert.InDelta(t, 1000, summary.AverageTokensPerRequest, 1e-9)
+	assert.InDelta(t, 1.25, summary.OutputInputRatio, 1e-9)
+	assert.InDelta(t, 2.0, summary.CostPer1KOutputTokens, 1e-9)
+
+	empty := New(nil).GenerateDailyReport(nil, day).Summary
+	assert.Zero(t, empty.AverageTokensPerRequest)
+	assert.Zero(t, empty.OutputInputRatio)
+	assert.Zero(t, empty.CostPer1KOutputTokens)
+}
+
+func TestModelBreakdown(t *testing.T) {
+	entries := []types.UsageEntry{
+		{Model: "claude-sonnet-4-20250514", TotalTokens: 3000, Cost: 1},
+		{Model: "claude-opus-4-1-20250805", TotalTokens: 1000, Cost: 4},
+		{Model: "claude-sonnet-4-20250514", TotalTokens: 2000, Cost: 0.5},
+		{Model: types.SyntheticModel, TotalTokens: 10},
+	}
+
+	breakdown := ModelBreakdown(entries)
+	assert.Equal(t, []types.ModelUsage{
+		{Model: "claude-opus-4-1-20250805", TotalTokens: 1000, Cost: 4, RequestCount: 1},
+		{Model: "claude-sonnet-4-20250514", TotalTokens: 5000, Cost: 1.5, RequestCount: 2},
+		{Model: ModelOther, TotalTokens: 10, RequestCount: 1},
+	}, breakdown)
+	assert.Empty(t, ModelBreakdown(nil))
+}
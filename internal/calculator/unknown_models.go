@@ -0,0 +1,38 @@
+package calculator
+
+import (
+	"context"
+	"sort"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// DefaultPricingReporter is implemented by pricing services that can tell
+// which models they price at generic default rates for lack of data
+type DefaultPricingReporter interface {
+	IsDefaultPriced(ctx context.Context, model string) bool
+}
+
+// UnknownModels returns the models of entries whose cost the calculator's
+// mode computes at default rates, so their costs are estimates, sorted. It
+// is nil when there are none or the pricing service cannot tell.
+func (c *Calculator) UnknownModels(ctx context.Context, entries []types.UsageEntry) []string {
+	reporter, ok := c.pricingService.(DefaultPricingReporter)
+	if !ok || c.mode == CostModeDisplay {
+		return nil
+	}
+
+	checked := make(map[string]bool)
+	var models []string
+	for _, entry := range entries {
+		if (c.mode != CostModeCalculate && entry.CostFromLog) || checked[entry.Model] {
+			continue
+		}
+		checked[entry.Model] = true
+		if reporter.IsDefaultPriced(ctx, entry.Model) {
+			models = append(models, entry.Model)
+		}
+	}
+	sort.Strings(models)
+	return models
+}
@@ -0,0 +1,52 @@
+package calculator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// defaultPricing is a mockPricing that knows only the models in known
+type defaultPricing struct {
+	mockPricing
+	known map[string]bool
+}
+
+func (p *defaultPricing) IsDefaultPriced(ctx context.Context, model string) bool {
+	return !p.known[model]
+}
+
+func TestUnknownModels(t *testing.T) {
+	pricing := &defaultPricing{known: map[string]bool{"claude-sonnet-4-5": true}}
+	entries := []types.UsageEntry{
+		{Model: "claude-sonnet-4-5"},
+		{Model: "mystery-b"},
+		{Model: "mystery-a"},
+		{Model: "mystery-a"},
+		{Model: "logged-model", CostFromLog: true},
+	}
+
+	calc := New(pricing)
+	assert.Equal(t, []string{"mystery-a", "mystery-b"}, calc.UnknownModels(context.Background(), entries))
+
+	// Logged costs are estimates only when recalculated
+	calc.SetMode(CostModeCalculate)
+	assert.Equal(t, []string{"logged-model", "mystery-a", "mystery-b"}, calc.UnknownModels(context.Background(), entries))
+	calc.SetMode(CostModeDisplay)
+	assert.Nil(t, calc.UnknownModels(context.Background(), entries))
+
+	// Reports list them in JSON
+	calc.SetMode(CostModeAuto)
+	day := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	for i := range entries {
+		entries[i].Timestamp = day
+	}
+	calc.SetTimezone(time.UTC)
+	assert.Equal(t, []string{"mystery-a", "mystery-b"}, calc.GenerateDailyReport(entries, day).UnknownModels)
+
+	// Services that cannot tell report nothing
+	assert.Nil(t, New(&mockPricing{}).UnknownModels(context.Background(), entries))
+}
@@ -0,0 +1,150 @@
+package calculator
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// HourOfDayProfile totals already cost-calculated entries by hour of the day
+// in the report timezone (see SetTimezone). All 24 hours are returned, idle
+// ones with zero usage, so the profile can be drawn as a histogram.
+func (c *Calculator) HourOfDayProfile(entries []types.UsageEntry) []types.HourUsage {
+	groups := Aggregate(entries, HourKey(c.location))
+	profile := make([]types.HourUsage, 24)
+	for hour := range profile {
+		profile[hour].Hour = hour
+		if group, ok := groups[fmt.Sprintf("%02d", hour)]; ok {
+			profile[hour].Requests = group.RequestCount
+			profile[hour].TotalTokens = group.TotalTokens
+			profile[hour].Cost = group.Cost
+		}
+	}
+	return profile
+}
+
+// Days of DayOfWeekUsage totals
+const (
+	DayWeekdays = "Weekdays"
+	DayWeekend  = "Weekend"
+)
+
+// DayOfWeekProfile totals already cost-calculated entries by day of the week,
+// Monday first, using the report dates of DailyKey. All seven days are
+// returned, idle ones with zero usage.
+func (c *Calculator) DayOfWeekProfile(entries []types.UsageEntry) []types.DayOfWeekUsage {
+	profile := make([]types.DayOfWeekUsage, 7)
+	for i := range profile {
+		day := time.Weekday((i + 1) % 7)
+		profile[i].Day = day.String()
+		profile[i].Weekend = isWeekend(day)
+	}
+
+	for date, group := range Aggregate(entries, DailyKey(c.location)) {
+		t, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			continue
+		}
+		day := &profile[(int(t.Weekday())+6)%7]
+		day.ActiveDays++
+		day.Requests += group.RequestCount
+		day.TotalTokens += group.TotalTokens
+		day.Cost += group.Cost
+	}
+	return profile
+}
+
+// SplitWeekend totals a DayOfWeekProfile into its weekdays and weekend
+func SplitWeekend(profile []types.DayOfWeekUsage) (weekdays, weekend types.DayOfWeekUsage) {
+	weekdays.Day = DayWeekdays
+	weekend.Day, weekend.Weekend = DayWeekend, true
+	for _, day := range profile {
+		total := &weekdays
+		if day.Weekend {
+			total = &weekend
+		}
+		total.ActiveDays += day.ActiveDays
+		total.Requests += day.Requests
+		total.TotalTokens += day.TotalTokens
+		total.Cost += day.Cost
+	}
+	return weekdays, weekend
+}
+
+// UsageStreaks measures how regularly already cost-calculated entries were
+// made, from the report dates of DailyKey: streaks of consecutive active days,
+// the most expensive Monday-to-Sunday week and the average number of hours
+// with usage per active day. The current streak counts back from now.
+func (c *Calculator) UsageStreaks(entries []types.UsageEntry, now time.Time) types.UsageStreaks {
+	dailyKey, hourKey := DailyKey(c.location), HourKey(c.location)
+	daily := Aggregate(entries, dailyKey)
+	hours := Aggregate(entries, func(entry types.UsageEntry) string {
+		if date := dailyKey(entry); date != "" {
+			return date + "T" + hourKey(entry)
+		}
+		return ""
+	})
+
+	var streaks types.UsageStreaks
+	dates := make([]string, 0, len(daily))
+	for date := range daily {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	run, runStart := 0, ""
+	var prev time.Time
+	weeks := make(map[string]*GroupTotals)
+	for _, date := range dates {
+		t, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			continue
+		}
+		streaks.ActiveDays++
+		if run > 0 && t.Equal(prev.AddDate(0, 0, 1)) {
+			run++
+		} else {
+			run, runStart = 1, date
+		}
+		prev = t
+		if run > streaks.LongestStreak {
+			streaks.LongestStreak = run
+			streaks.LongestStreakStart, streaks.LongestStreakEnd = runStart, date
+		}
+
+		week := t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7)).Format("2006-01-02")
+		if weeks[week] == nil {
+			weeks[week] = NewGroupTotals()
+		}
+		weeks[week].Merge(daily[date])
+	}
+	if streaks.ActiveDays == 0 {
+		return streaks
+	}
+	streaks.AvgActiveHours = float64(len(hours)) / float64(streaks.ActiveDays)
+
+	day := now.In(locationOrLocal(c.location))
+	if daily[day.Format("2006-01-02")] == nil {
+		day = day.AddDate(0, 0, -1) // Today may just not have started yet
+	}
+	for daily[day.Format("2006-01-02")] != nil {
+		streaks.CurrentStreak++
+		day = day.AddDate(0, 0, -1)
+	}
+
+	for week, group := range weeks {
+		if streaks.BusiestWeek == "" || group.Cost > streaks.BusiestWeekCost ||
+			(group.Cost == streaks.BusiestWeekCost && week < streaks.BusiestWeek) {
+			streaks.BusiestWeek = week
+			streaks.BusiestWeekTokens = group.TotalTokens
+			streaks.BusiestWeekCost = group.Cost
+		}
+	}
+	return streaks
+}
+
+func isWeekend(day time.Weekday) bool {
+	return day == time.Saturday || day == time.Sunday
+}
@@ -0,0 +1,108 @@
+package calculator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHourOfDayProfile(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	entries := []types.UsageEntry{
+		{Timestamp: time.Date(2025, 3, 1, 23, 30, 0, 0, time.UTC), InputTokens: 100, Cost: 1},
+		{Timestamp: time.Date(2025, 3, 2, 23, 10, 0, 0, time.UTC), InputTokens: 50, Cost: 0.5},
+		{Timestamp: time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC), InputTokens: 10, Cost: 0.1},
+	}
+
+	calc := New(nil)
+	calc.SetTimezone(time.UTC)
+	profile := calc.HourOfDayProfile(entries)
+	require.Len(t, profile, 24, "idle hours are included")
+	assert.Equal(t, 23, profile[23].Hour)
+	assert.Equal(t, 2, profile[23].Requests, "the same hour on different days adds up")
+	assert.Equal(t, 150, profile[23].TotalTokens)
+	assert.InDelta(t, 1.5, profile[23].Cost, 1e-9)
+	assert.Equal(t, 1, profile[9].Requests)
+	assert.Zero(t, profile[0].Requests)
+
+	calc.SetTimezone(tokyo)
+	profile = calc.HourOfDayProfile(entries)
+	assert.Equal(t, 2, profile[8].Requests, "23:00 UTC is 08:00 in Tokyo")
+	assert.Equal(t, 1, profile[18].Requests)
+}
+
+func TestDayOfWeekProfile(t *testing.T) {
+	entries := []types.UsageEntry{
+		// Saturday 1 March 2025, twice
+		{Timestamp: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC), InputTokens: 100, Cost: 1},
+		{Timestamp: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC), InputTokens: 100, Cost: 1},
+		// Monday 3 and Monday 10 March
+		{Timestamp: time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC), InputTokens: 10, Cost: 0.1},
+		{Timestamp: time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC), InputTokens: 30, Cost: 0.3},
+		// Sunday 2 March late evening UTC is Monday morning in Tokyo
+		{Timestamp: time.Date(2025, 3, 2, 22, 0, 0, 0, time.UTC), InputTokens: 5, Cost: 0.05},
+	}
+
+	calc := New(nil)
+	calc.SetTimezone(time.UTC)
+	profile := calc.DayOfWeekProfile(entries)
+	require.Len(t, profile, 7)
+	assert.Equal(t, "Monday", profile[0].Day, "weeks start on Monday")
+	assert.Equal(t, 2, profile[0].ActiveDays)
+	assert.Equal(t, 40, profile[0].TotalTokens)
+	assert.True(t, profile[5].Weekend)
+	assert.Equal(t, 1, profile[5].ActiveDays, "one date with two requests")
+	assert.Equal(t, 2, profile[5].Requests)
+	assert.Equal(t, 1, profile[6].Requests)
+
+	weekdays, weekend := SplitWeekend(profile)
+	assert.Equal(t, DayWeekdays, weekdays.Day)
+	assert.Equal(t, 2, weekdays.ActiveDays)
+	assert.InDelta(t, 0.4, weekdays.Cost, 1e-9)
+	assert.True(t, weekend.Weekend)
+	assert.Equal(t, 2, weekend.ActiveDays)
+	assert.Equal(t, 205, weekend.TotalTokens)
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	calc.SetTimezone(tokyo)
+	profile = calc.DayOfWeekProfile(entries)
+	assert.Equal(t, 2, profile[0].ActiveDays, "3 March was already active")
+	assert.Equal(t, 45, profile[0].TotalTokens)
+	assert.Zero(t, profile[6].Requests)
+}
+
+func TestUsageStreaks(t *testing.T) {
+	at := func(day, hour int) time.Time { return time.Date(2025, 3, day, hour, 0, 0, 0, time.UTC) }
+	entries := []types.UsageEntry{
+		// 1-3 March (Saturday to Monday), two hours on the 3rd
+		{Timestamp: at(1, 10), InputTokens: 100, Cost: 1},
+		{Timestamp: at(2, 10), InputTokens: 100, Cost: 1},
+		{Timestamp: at(3, 9), InputTokens: 100, Cost: 1},
+		{Timestamp: at(3, 15), InputTokens: 100, Cost: 1},
+		{Timestamp: at(3, 15), InputTokens: 100, Cost: 1},
+		// 9 and 10 March (Sunday, Monday)
+		{Timestamp: at(9, 10), InputTokens: 10, Cost: 0.1},
+		{Timestamp: at(10, 10), InputTokens: 10, Cost: 0.1},
+	}
+
+	calc := New(nil)
+	calc.SetTimezone(time.UTC)
+	streaks := calc.UsageStreaks(entries, at(11, 8))
+	assert.Equal(t, 5, streaks.ActiveDays)
+	assert.Equal(t, 3, streaks.LongestStreak)
+	assert.Equal(t, "2025-03-01", streaks.LongestStreakStart)
+	assert.Equal(t, "2025-03-03", streaks.LongestStreakEnd)
+	assert.Equal(t, 2, streaks.CurrentStreak, "an idle today does not break the streak")
+	assert.Equal(t, "2025-03-03", streaks.BusiestWeek, "the week from Monday 3 March")
+	assert.Equal(t, 310, streaks.BusiestWeekTokens, "Sunday 9 March ends that week")
+	assert.InDelta(t, 3.1, streaks.BusiestWeekCost, 1e-9)
+	assert.InDelta(t, 1.2, streaks.AvgActiveHours, 1e-9)
+
+	assert.Zero(t, calc.UsageStreaks(entries, at(12, 8)).CurrentStreak)
+	assert.Zero(t, calc.UsageStreaks(nil, at(12, 8)).ActiveDays)
+}
@@ -0,0 +1,173 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/calculator"
+	"github.com/sdpower/ccusage-go/internal/config"
+	"github.com/sdpower/ccusage-go/internal/loader"
+	"github.com/sdpower/ccusage-go/internal/monitor"
+	"github.com/sdpower/ccusage-go/internal/notify"
+	"github.com/spf13/cobra"
+)
+
+// alertFlags holds the alert flags of blocks --live and the alert command
+type alertFlags struct {
+	desktop    bool
+	notifyAt   []string
+	webhook    string
+	burnRate   float64
+	dailyCost  float64
+	warnAt     float64
+	criticalAt float64
+}
+
+func (f *alertFlags) register(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&f.desktop, "notify", false, "Show desktop notifications of alerts (needs osascript on macOS, notify-send on Linux)")
+	cmd.Flags().StringSliceVar(&f.notifyAt, "notify-at", nil, "Percentages of the token limit to alert at, and \"projection\" for a projection beyond it (default: --warn-at, --critical-at and projection)")
+	cmd.Flags().StringVar(&f.webhook, "alert-webhook", "", "POST alerts as JSON to this URL; Slack and Discord incoming webhooks get their message formats")
+	cmd.Flags().Float64Var(&f.burnRate, "alert-burn-rate", 0, "Alert when the active block burns more tokens per minute than this")
+	cmd.Flags().Float64Var(&f.dailyCost, "alert-daily-cost", 0, "Alert when today's cost exceeds this many dollars")
+	cmd.Flags().Float64Var(&f.warnAt, "warn-at", 0, "Percentage of the token limit at which usage is a warning: yellow bars and an alert (default: warn_at in the config, else 80)")
+	cmd.Flags().Float64Var(&f.criticalAt, "critical-at", 0, "Percentage of the token limit at which usage is critical: red bars and an alert (default: critical_at in the config, else 95)")
+}
+
+// thresholds returns the usage thresholds of the flags, falling back to cfg
+func (f *alertFlags) thresholds(cfg *config.Config) (calculator.UsageThresholds, error) {
+	warn, critical := f.warnAt, f.criticalAt
+	if warn == 0 {
+		warn = cfg.WarnAt
+	}
+	if critical == 0 {
+		critical = cfg.CriticalAt
+	}
+	return calculator.NewUsageThresholds(warn, critical)
+}
+
+// enabled reports whether alerts are delivered anywhere
+func (f *alertFlags) enabled() bool {
+	return f.desktop || f.webhook != ""
+}
+
+// alerts returns the alerts the flags select, at thresholds unless
+// --notify-at is given
+func (f *alertFlags) alerts(thresholds calculator.UsageThresholds) (monitor.Alerts, error) {
+	if f.burnRate < 0 || f.dailyCost < 0 {
+		return monitor.Alerts{}, fmt.Errorf("alert thresholds must not be negative")
+	}
+	notifyAt := f.notifyAt
+	if len(notifyAt) == 0 {
+		notifyAt = monitor.DefaultAlerts(thresholds)
+	}
+	alerts, err := monitor.ParseAlerts(notifyAt)
+	if err != nil {
+		return monitor.Alerts{}, err
+	}
+	alerts.BurnRate, alerts.DailyCost = f.burnRate, f.dailyCost
+	if f.desktop {
+		if err := notify.Available(); err != nil {
+			return monitor.Alerts{}, err
+		}
+		alerts.Desktop = true
+	}
+	if f.webhook != "" {
+		if alerts.Webhook, err = notify.NewWebhook(f.webhook); err != nil {
+			return monitor.Alerts{}, err
+		}
+	}
+	return alerts, nil
+}
+
+func NewAlertCommand() *cobra.Command {
+	var (
+		dataPath      string
+		tokenLimit    int
+		interval      time.Duration
+		sessionLength int
+		timezone      string
+		projection    string
+		loadFlags     loaderFlags
+		alertFlags    alertFlags
+	)
+
+	cmd := &cobra.Command{
+		Use:   "alert",
+		Short: "Watch usage in the background and send alerts when it crosses thresholds",
+		Long: `Check usage every --interval without a display, alerting when the active block
+reaches --notify-at shares of the token limit (by default --warn-at and
+--critical-at) or is projected to exceed it, burns
+tokens faster than --alert-burn-rate, or today's cost exceeds --alert-daily-cost.
+Each alert fires once per block (per day for the daily cost) and is posted to
+--alert-webhook and/or shown as a desktop notification (--notify).`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !alertFlags.enabled() {
+				return fmt.Errorf("alerts need --alert-webhook or --notify")
+			}
+			if interval < time.Second {
+				return fmt.Errorf("--interval must be at least 1s, got %s", interval)
+			}
+			if sessionLength <= 0 {
+				return fmt.Errorf("session length must be a positive number")
+			}
+			if tokenLimit < 0 {
+				return fmt.Errorf("--token-limit must not be negative, got %d", tokenLimit)
+			}
+			loc := time.Local
+			if timezone != "" {
+				var err error
+				if loc, err = time.LoadLocation(timezone); err != nil {
+					return fmt.Errorf("invalid timezone: %w", err)
+				}
+			}
+			projectionMethod, err := calculator.ParseProjectionMethod(projection)
+			if err != nil {
+				return err
+			}
+			if dataPath == "" {
+				dataPath = loadFlags.defaultDataPath()
+			}
+			// Validates the loading flags; the daemon sets up its own loader
+			if err := loadFlags.configure(loader.New(), dataPath); err != nil {
+				return err
+			}
+			thresholds, err := alertFlags.thresholds(loadFlags.config)
+			if err != nil {
+				return err
+			}
+			alerts, err := alertFlags.alerts(thresholds)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(os.Stderr, "Checking usage every %s; press Ctrl+C to stop\n", interval)
+			return monitor.RunAlertDaemon(cmd.Context(), monitor.AlertDaemonConfig{
+				DataPath:         dataPath,
+				TokenLimit:       tokenLimit,
+				Interval:         interval,
+				SessionLength:    sessionLength,
+				Timezone:         loc,
+				FileFilter:       loadFlags.filter,
+				IncludeSynthetic: loadFlags.synthetic,
+				CostMode:         loadFlags.costMode,
+				Projection:       projectionMethod,
+				Alerts:           alerts,
+				Log:              os.Stderr,
+				Pricing:          pricingOptions,
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&dataPath, "data-path", "", "Path to Claude data directory (comma-separated; ssh://, s3:// and gs:// URLs are mirrored locally, - reads standard input)")
+	cmd.Flags().IntVarP(&tokenLimit, "token-limit", "t", 0, "Token limit of a block for --notify-at (default: the most tokens of a past block)")
+	cmd.Flags().DurationVar(&interval, "interval", time.Minute, "Time between usage checks")
+	cmd.Flags().IntVarP(&sessionLength, "session-length", "n", calculator.DefaultSessionDurationHours, "Session block duration in hours")
+	cmd.Flags().StringVar(&timezone, "timezone", "", "Timezone of days for --alert-daily-cost and of times in alerts (e.g., America/New_York)")
+	cmd.Flags().StringVar(&projection, "projection", string(calculator.ProjectionAverage), "Burn rate of projections: average (since the block's first request) or recent (weighted toward the last minutes)")
+	alertFlags.register(cmd)
+	loadFlags.register(cmd)
+
+	return cmd
+}
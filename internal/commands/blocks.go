@@ -10,8 +10,8 @@ import (
 	"github.com/sdpower/ccusage-go/internal/calculator"
 	"github.com/sdpower/ccusage-go/internal/loader"
 	"github.com/sdpower/ccusage-go/internal/monitor"
+	"github.com/sdpower/ccusage-go/internal/notify"
 	"github.com/sdpower/ccusage-go/internal/output"
-	"github.com/sdpower/ccusage-go/internal/pricing"
 	"github.com/sdpower/ccusage-go/internal/types"
 	"github.com/spf13/cobra"
 )
@@ -32,6 +32,8 @@ func NewBlocksCommand() *cobra.Command {
 		format          string
 		dataPath        string
 		noColor         bool
+		loadFlags       loaderFlags
+		tableStyle      string
 		responsive      bool
 		timezone        string
 		since           string
@@ -39,6 +41,16 @@ func NewBlocksCommand() *cobra.Command {
 		live            bool
 		refreshInterval int
 		gradient        bool
+		blockAnchor     string
+		projection      string
+		gapThreshold    time.Duration
+		activeWithin    time.Duration
+		blockSource     string
+		plan            string
+		alertFlags      alertFlags
+		bell            bool
+		bellSound       string
+		snapshotDir     string
 	)
 
 	cmd := &cobra.Command{
@@ -48,7 +60,7 @@ func NewBlocksCommand() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Determine data path
 			if dataPath == "" {
-				dataPath = getDefaultDataPath()
+				dataPath = loadFlags.defaultDataPath()
 			}
 
 			// Parse timezone
@@ -61,10 +73,54 @@ func NewBlocksCommand() *cobra.Command {
 				}
 			}
 
+			if err := output.ValidateTableStyle(tableStyle); err != nil {
+				return err
+			}
+
 			// Validate session length
 			if sessionLength <= 0 {
 				return fmt.Errorf("session length must be a positive number")
 			}
+			if gapThreshold < 0 {
+				return fmt.Errorf("gap threshold must not be negative")
+			}
+			if activeWithin < 0 {
+				return fmt.Errorf("--active-within must not be negative")
+			}
+			anchor, err := calculator.ParseBlockAnchor(blockAnchor)
+			if err != nil {
+				return err
+			}
+			projectionMethod, err := calculator.ParseProjectionMethod(projection)
+			if err != nil {
+				return err
+			}
+			source, err := calculator.ParseBlockSource(blockSource)
+			if err != nil {
+				return err
+			}
+			var planPreset calculator.Plan
+			if plan != "" {
+				if planPreset, err = calculator.ParsePlan(plan); err != nil {
+					return err
+				}
+			}
+
+			if alertFlags.enabled() && !live {
+				return fmt.Errorf("--notify and --alert-webhook only apply to --live")
+			}
+			var liveBell *monitor.Bell
+			if bell || bellSound != "" {
+				if !live {
+					return fmt.Errorf("--bell and --bell-sound only apply to --live")
+				}
+				if bellSound != "" {
+					if err := notify.SoundAvailable(bellSound); err != nil {
+						return err
+					}
+				}
+				liveBell = &monitor.Bell{Sound: bellSound}
+			}
 
 			// Live monitoring mode
 			if live && format != "json" {
@@ -81,9 +137,30 @@ func NewBlocksCommand() *cobra.Command {
 				}
 				
 				// Initialize services for max token calculation
-				pricingService := pricing.NewService()
+				pricingService := newPricingService()
 				calc := calculator.New(pricingService)
 				dataLoader := loader.New()
+				if err := loadFlags.configure(dataLoader, dataPath); err != nil {
+					return err
+				}
+				thresholds, err := alertFlags.thresholds(loadFlags.config)
+				if err != nil {
+					return err
+				}
+				var alerts *monitor.Alerts
+				if alertFlags.enabled() {
+					selected, err := alertFlags.alerts(thresholds)
+					if err != nil {
+						return err
+					}
+					alerts = &selected
+				}
+				calc.SetMode(loadFlags.costMode)
+				calc.WarmPricing(cmd.Context())
+				calc.SetBlockAnchor(anchor, loc)
+				calc.SetGapThreshold(gapThreshold)
+				calc.SetActiveWindow(activeWithin)
+				calc.SetBlockSource(source)
 				
 				// Enable debug mode if DEBUG env var is set
 				if os.Getenv("DEBUG") != "" {
@@ -130,15 +207,45 @@ func NewBlocksCommand() *cobra.Command {
 					Timezone:        loc,
 					UseGradient:     gradient,
 					OptimizeMemory:  true, // Always enable memory optimization for live mode
+					NoCache:         loadFlags.noCache,
+					FileFilter:      loadFlags.filter,
+					IncludeSynthetic: loadFlags.synthetic,
+					CostMode:        loadFlags.costMode,
+					BlockAnchor:     anchor,
+					Projection:      projectionMethod,
+					GapThreshold:    gapThreshold,
+					ActiveWindow:    activeWithin,
+					BlockSource:     source,
+					Plan:            planPreset,
+					Alerts:          alerts,
+					Thresholds:      thresholds,
+					Bell:            liveBell,
+					SnapshotDir:     snapshotDir,
+					Pricing:         pricingOptions,
 				}
 				
 				return monitor.StartBlocksLiveMonitoring(config)
 			}
 
 			// Initialize services
-			pricingService := pricing.NewService()
+			pricingService := newPricingService()
 			calc := calculator.New(pricingService)
 			dataLoader := loader.New()
+			if err := loadFlags.configure(dataLoader, dataPath); err != nil {
+				return err
+			}
+			thresholds, err := alertFlags.thresholds(loadFlags.config)
+			if err != nil {
+				return err
+			}
+			calc.SetMode(loadFlags.costMode)
+			calc.WarmPricing(cmd.Context())
+			calc.SetBlockAnchor(anchor, loc)
+			calc.SetGapThreshold(gapThreshold)
+			calc.SetActiveWindow(activeWithin)
+			calc.SetBlockSource(source)
+			// filterEntriesByDateRange cuts days in UTC
+			dataLoader.SetDateRange(loaderDateRange(since, until, time.UTC))
 
 			// Load data
 			entries, err := dataLoader.LoadFromPath(cmd.Context(), dataPath)
@@ -170,6 +277,20 @@ func NewBlocksCommand() *cobra.Command {
 				return nil
 			}
 
+			// Plan capacity is measured against the active block, if any
+			var capacity *types.PlanCapacity
+			if planPreset != "" {
+				var activeBlock *types.SessionBlock
+				for i := range blocks {
+					if blocks[i].IsActive {
+						activeBlock = &blocks[i]
+						break
+					}
+				}
+				planCapacity := calculator.PlanCapacity(planPreset, activeBlock, entries, time.Now())
+				capacity = &planCapacity
+			}
+
 			// Calculate max tokens from ALL blocks before applying filters
 			maxTokensFromAll := calculator.GetMaxTokensFromBlocks(blocks)
 			if maxTokensFromAll > 0 && (tokenLimit == "max" || tokenLimit == "") {
@@ -221,7 +342,20 @@ func NewBlocksCommand() *cobra.Command {
 					NoColor:    noColor,
 					Responsive: responsive,
 				})
-				jsonData := formatBlocksAsJSON(blocks, actualTokenLimit)
+				jsonData := formatBlocksAsJSON(blocks, actualTokenLimit, thresholds, projectionMethod)
+				if capacity != nil {
+					jsonData["plan_capacity"] = capacity
+				}
+				var totalCost float64
+				for _, block := range blocks {
+					totalCost += block.CostUSD
+				}
+				if billed := output.BilledCost(totalCost); billed != nil {
+					jsonData["billing"] = billed
+				}
+				if unknown := calc.UnknownModels(cmd.Context(), entries); len(unknown) > 0 {
+					jsonData["unknown_models"] = unknown
+				}
 				outputStr, err = formatter.FormatJSON(jsonData)
 				if err != nil {
 					return fmt.Errorf("failed to format JSON: %w", err)
@@ -244,13 +378,18 @@ func NewBlocksCommand() *cobra.Command {
 				// Table output
 				if active && len(blocks) == 1 {
 					// Detailed active block view
-					outputStr = formatActiveBlockDetail(blocks[0], actualTokenLimit, noColor, loc)
+					outputStr = formatActiveBlockDetail(blocks[0], actualTokenLimit, thresholds, noColor, loc, projectionMethod)
 				} else {
 					// Table view for multiple blocks
 					tableFormatter := output.NewTableWriterFormatter(noColor)
+					tableFormatter.SetTableStyle(tableStyle)
 					tableFormatter.SetTimezone(loc)
+					tableFormatter.SetProjectionMethod(projectionMethod)
 					outputStr = tableFormatter.FormatBlocksReport(blocks, actualTokenLimit)
 				}
+				if capacity != nil {
+					outputStr += "\n" + output.FormatPlanCapacity(*capacity)
+				}
 			}
 
 			fmt.Print(outputStr)
@@ -261,10 +400,18 @@ func NewBlocksCommand() *cobra.Command {
 	cmd.Flags().BoolVarP(&active, "active", "a", false, "Show only active block with projections")
 	cmd.Flags().BoolVarP(&recent, "recent", "r", false, fmt.Sprintf("Show blocks from last %d days (including active)", DefaultRecentDays))
 	cmd.Flags().StringVarP(&tokenLimit, "token-limit", "t", "", "Token limit for quota warnings (e.g., 500000 or \"max\")")
+	cmd.Flags().StringVar(&plan, "plan", "", "Subscription plan (pro, max5, max20) to estimate the capacity left in the current block and week")
 	cmd.Flags().IntVarP(&sessionLength, "session-length", "n", calculator.DefaultSessionDurationHours, "Session block duration in hours")
+	cmd.Flags().DurationVar(&gapThreshold, "gap-threshold", 0, "Idle time that ends a block early, e.g. 90m (default: the session length); blocks still span --session-length")
+	cmd.Flags().DurationVar(&activeWithin, "active-within", 0, "Only count a block as active if its last request is this recent, e.g. 30m (default: the gap threshold)")
+	cmd.Flags().StringVar(&projection, "projection", string(calculator.ProjectionAverage), "Burn rate of projections: average (since the block's first request) or recent (weighted toward the last minutes)")
+	cmd.Flags().StringVar(&blockSource, "block-source", string(calculator.BlockSourceMerged), "Find blocks in: merged (all entries together), root (each data directory, e.g. one per synced machine) or file (each log file, i.e. terminal)")
+	cmd.Flags().StringVar(&blockAnchor, "block-anchor", string(calculator.BlockAnchorHour), "Block start: hour (first entry floored to the hour), exact (first entry) or fixed (wall-clock slots from midnight in --timezone)")
 	cmd.Flags().StringVarP(&format, "format", "f", "table", "Output format (table, json, csv)")
-	cmd.Flags().StringVar(&dataPath, "data-path", "", "Path to Claude data directory")
+	cmd.Flags().StringVar(&dataPath, "data-path", "", "Path to Claude data directory (comma-separated; ssh://, s3:// and gs:// URLs are mirrored locally, - reads standard input)")
 	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	loadFlags.register(cmd)
+	cmd.Flags().StringVar(&tableStyle, "table-style", output.TableStyleUnicode, "Table style (unicode, ascii, markdown, minimal, borderless)")
 	cmd.Flags().BoolVar(&responsive, "responsive", true, "Enable responsive table layout")
 	cmd.Flags().StringVar(&timezone, "timezone", "", "Timezone for date display (e.g., America/New_York)")
 	cmd.Flags().StringVar(&since, "since", "", "Start date filter (YYYY-MM-DD)")
@@ -272,12 +419,16 @@ func NewBlocksCommand() *cobra.Command {
 	cmd.Flags().BoolVar(&live, "live", false, "Live monitoring mode with real-time updates")
 	cmd.Flags().IntVar(&refreshInterval, "refresh-interval", 1, "Refresh interval in seconds for live mode (1-60)")
 	cmd.Flags().BoolVar(&gradient, "gradient", true, "Use gradient colors in progress bars (live mode)")
+	cmd.Flags().BoolVar(&bell, "bell", false, "Ring the terminal bell when the active block crosses --critical-at and when it ends (live mode)")
+	cmd.Flags().StringVar(&bellSound, "bell-sound", "", "Play this sound file instead of the terminal bell (implies --bell; needs afplay on macOS, paplay or aplay on Linux)")
+	cmd.Flags().StringVar(&snapshotDir, "snapshot-dir", "", "Directory the live monitor saves snapshots to when S is pressed (default: the working directory)")
+	alertFlags.register(cmd)
 
 	return cmd
 }
 
 // formatActiveBlockDetail formats detailed view of an active block
-func formatActiveBlockDetail(block types.SessionBlock, tokenLimit int, noColor bool, loc *time.Location) string {
+func formatActiveBlockDetail(block types.SessionBlock, tokenLimit int, thresholds calculator.UsageThresholds, noColor bool, loc *time.Location, method calculator.ProjectionMethod) string {
 	var output strings.Builder
 
 	// Title box
@@ -317,20 +468,22 @@ func formatActiveBlockDetail(block types.SessionBlock, tokenLimit int, noColor b
 	output.WriteString("Current Usage:\n")
 	output.WriteString(fmt.Sprintf("  Input Tokens:     %s\n", formatNumber(block.TokenCounts.InputTokens)))
 	output.WriteString(fmt.Sprintf("  Output Tokens:    %s\n", formatNumber(block.TokenCounts.OutputTokens)))
-	output.WriteString(fmt.Sprintf("  Total Cost:       $%.2f\n\n", block.CostUSD))
+	output.WriteString(fmt.Sprintf("  Total Cost:       %s\n\n", formatCost(block.CostUSD)))
 
 	// Burn rate
 	if burnRate := calculator.CalculateBurnRate(block); burnRate != nil {
 		output.WriteString("Burn Rate:\n")
 		output.WriteString(fmt.Sprintf("  Tokens/minute:    %s\n", formatNumber(int(burnRate.TokensPerMinute))))
-		output.WriteString(fmt.Sprintf("  Cost/hour:        $%.2f\n\n", burnRate.CostPerHour))
+		output.WriteString(fmt.Sprintf("  Cost/hour:        %s\n\n", formatCost(burnRate.CostPerHour)))
 	}
 
 	// Projections
-	if projection := calculator.ProjectBlockUsage(block); projection != nil {
+	if projection := calculator.ProjectBlockUsageWith(block, method); projection != nil {
 		output.WriteString("Projected Usage (if current rate continues):\n")
-		output.WriteString(fmt.Sprintf("  Total Tokens:     %s\n", formatNumber(projection.TotalTokens)))
-		output.WriteString(fmt.Sprintf("  Total Cost:       $%.2f\n\n", projection.TotalCost))
+		output.WriteString(fmt.Sprintf("  Total Tokens:     %s (90%% range %s - %s)\n",
+			formatNumber(projection.TotalTokens), formatNumber(projection.LowTokens), formatNumber(projection.HighTokens)))
+		output.WriteString(fmt.Sprintf("  Total Cost:       %s (90%% range %s - %s)\n\n",
+			formatCost(projection.TotalCost), formatCost(projection.LowCost), formatCost(projection.HighCost)))
 
 		// Token limit status
 		if tokenLimit > 0 {
@@ -345,7 +498,7 @@ func formatActiveBlockDetail(block types.SessionBlock, tokenLimit int, noColor b
 			if !noColor {
 				if percentUsed > 100 {
 					status = "\033[31mEXCEEDS LIMIT\033[0m"
-				} else if percentUsed > calculator.BlocksWarningThreshold*100 {
+				} else if percentUsed > thresholds.Warn {
 					status = "\033[33mWARNING\033[0m"
 				} else {
 					status = "\033[32mOK\033[0m"
@@ -353,7 +506,7 @@ func formatActiveBlockDetail(block types.SessionBlock, tokenLimit int, noColor b
 			} else {
 				if percentUsed > 100 {
 					status = "EXCEEDS LIMIT"
-				} else if percentUsed > calculator.BlocksWarningThreshold*100 {
+				} else if percentUsed > thresholds.Warn {
 					status = "WARNING"
 				} else {
 					status = "OK"
@@ -371,6 +524,12 @@ func formatActiveBlockDetail(block types.SessionBlock, tokenLimit int, noColor b
 	return output.String()
 }
 
+// formatCost formats a dollar amount as configured (see output.FormatCost);
+// formatActiveBlockDetail shadows the output package
+func formatCost(cost float64) string {
+	return output.FormatCost(cost)
+}
+
 // formatNumber formats a number with thousand separators
 func formatNumber(n int) string {
 	if n < 0 {
@@ -383,12 +542,12 @@ func formatNumber(n int) string {
 }
 
 // formatBlocksAsJSON converts blocks to JSON structure
-func formatBlocksAsJSON(blocks []types.SessionBlock, tokenLimit int) map[string]interface{} {
+func formatBlocksAsJSON(blocks []types.SessionBlock, tokenLimit int, thresholds calculator.UsageThresholds, method calculator.ProjectionMethod) map[string]interface{} {
 	blockData := []map[string]interface{}{}
 	
 	for _, block := range blocks {
 		burnRate := calculator.CalculateBurnRate(block)
-		projection := calculator.ProjectBlockUsage(block)
+		projection := calculator.ProjectBlockUsageWith(block, method)
 		
 		blockMap := map[string]interface{}{
 			"id":             block.ID,
@@ -404,19 +563,26 @@ func formatBlocksAsJSON(blocks []types.SessionBlock, tokenLimit int) map[string]
 			"models":         block.Models,
 		}
 		
+		if block.Source != "" {
+			blockMap["source"] = block.Source
+		}
 		if burnRate != nil {
 			blockMap["burn_rate"] = burnRate
 		}
+		if block.IsActive {
+			blockMap["burn_rate_series"] = calculator.BurnRateSeries(block)
+		}
 		
 		if projection != nil {
 			blockMap["projection"] = projection
+			blockMap["projection_method"] = method
 			
 			if tokenLimit > 0 {
 				percentUsed := float64(projection.TotalTokens) / float64(tokenLimit) * 100
 				status := "ok"
 				if percentUsed > 100 {
 					status = "exceeds"
-				} else if percentUsed > calculator.BlocksWarningThreshold*100 {
+				} else if percentUsed > thresholds.Warn {
 					status = "warning"
 				}
 				
@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/sdpower/ccusage-go/internal/cachedir"
+	"github.com/spf13/cobra"
+)
+
+// keptFiles are files in the cache directory that are not caches: the lines
+// --quarantine set aside (loader.DefaultQuarantinePath) and the monitor
+// daemon's status (monitor.DefaultStatusPath). cache clear keeps them
+// unless given --all.
+var keptFiles = []string{"quarantine.jsonl", "status.json"}
+
+func NewCacheCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the caches ccusage keeps between runs",
+	}
+	cmd.AddCommand(newCacheClearCommand())
+	return cmd
+}
+
+func newCacheClearCommand() *cobra.Command {
+	var all bool
+	cmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Delete the cached pricing, exchange rates, parse index and remote mirrors",
+		Long: `Delete the caches in the ccusage cache directory: $XDG_CACHE_HOME/ccusage,
+or ccusage under the platform's cache directory (~/.cache on Linux,
+~/Library/Caches on macOS). Caches left in ~/.cache/ccusage by earlier
+versions are deleted too. Everything in them is rebuilt or fetched again on
+the next run.
+
+The directory also holds files that are not caches: quarantine.jsonl, the
+lines --quarantine set aside for inspection, and status.json, written by
+monitor --daemon. They are kept unless --all is given.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := cachedir.Dir()
+			if err != nil {
+				return fmt.Errorf("cannot locate the cache directory: %w", err)
+			}
+			var keep []string
+			if !all {
+				keep = keptFiles
+			}
+			return clearCaches(cmd.OutOrStdout(), keep, dir, cachedir.Legacy())
+		},
+	}
+	cmd.Flags().BoolVar(&all, "all", false, "Also delete quarantine.jsonl and the monitor daemon's status.json")
+	return cmd
+}
+
+// clearCaches removes the cache directories that exist, skipping empty
+// paths, except for the files named in keep, and reports each one cleared
+func clearCaches(w io.Writer, keep []string, dirs ...string) error {
+	var cleared bool
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			continue
+		}
+		kept, err := clearCacheDir(dir, keep)
+		if err != nil {
+			return fmt.Errorf("failed to clear %s: %w", dir, err)
+		}
+		fmt.Fprintf(w, "Cleared %s\n", dir)
+		for _, path := range kept {
+			fmt.Fprintf(w, "Kept %s (delete it with --all)\n", path)
+		}
+		cleared = true
+	}
+	if !cleared {
+		fmt.Fprintln(w, "No caches to clear")
+	}
+	return nil
+}
+
+// clearCacheDir removes dir, or only its other entries when it holds files
+// named in keep, and returns the paths of the files kept
+func clearCacheDir(dir string, keep []string) ([]string, error) {
+	var kept []string
+	keptNames := make(map[string]bool)
+	for _, name := range keep {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			kept = append(kept, filepath.Join(dir, name))
+			keptNames[name] = true
+		}
+	}
+	if len(kept) == 0 {
+		return nil, os.RemoveAll(dir)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if keptNames[entry.Name()] {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			return nil, err
+		}
+	}
+	return kept, nil
+}
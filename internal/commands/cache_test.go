@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClearCaches(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "xdg", "ccusage")
+	legacy := filepath.Join(root, "home", ".cache", "ccusage")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "remote"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pricing.json"), []byte("{}"), 0o644))
+
+	var out bytes.Buffer
+	require.NoError(t, clearCaches(&out, keptFiles, dir, legacy, ""))
+	assert.Equal(t, "Cleared "+dir+"\n", out.String(), "missing directories are not reported")
+	assert.NoDirExists(t, dir)
+
+	out.Reset()
+	require.NoError(t, clearCaches(&out, keptFiles, dir, legacy))
+	assert.Equal(t, "No caches to clear\n", out.String())
+}
+
+func TestClearCachesKeepsQuarantineAndStatus(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "ccusage")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "remote", "host"), 0o755))
+	for _, name := range []string{"pricing.json", "index.db", "quarantine.jsonl", "status.json"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0o644))
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, clearCaches(&out, keptFiles, dir))
+	assert.Equal(t, "Cleared "+dir+"\n"+
+		"Kept "+filepath.Join(dir, "quarantine.jsonl")+" (delete it with --all)\n"+
+		"Kept "+filepath.Join(dir, "status.json")+" (delete it with --all)\n", out.String())
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"quarantine.jsonl", "status.json"}, names)
+
+	// --all deletes them too
+	out.Reset()
+	require.NoError(t, clearCaches(&out, nil, dir))
+	assert.Equal(t, "Cleared "+dir+"\n", out.String())
+	assert.NoDirExists(t, dir)
+}
@@ -0,0 +1,212 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/calculator"
+	"github.com/sdpower/ccusage-go/internal/loader"
+	"github.com/sdpower/ccusage-go/internal/output"
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/spf13/cobra"
+)
+
+func NewCommitsCommand() *cobra.Command {
+	var (
+		format      string
+		dataPath    string
+		noColor     bool
+		loadFlags   loaderFlags
+		tableStyle  string
+		timezone    string
+		repo        string
+		limit       int
+		allProjects bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "commits",
+		Short: "Show the usage that went into each git commit",
+		Long: `Read the commit times of a git repository (first-parent history of HEAD) and
+total the requests made from inside the repository between each commit and
+the one before it, answering how much a change cost in Claude usage. Usage
+since the last commit is shown as uncommitted.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := output.ValidateTableStyle(tableStyle); err != nil {
+				return err
+			}
+			if format != "table" && format != "json" {
+				return fmt.Errorf("invalid --format %q, use table or json", format)
+			}
+			if limit < 1 {
+				return fmt.Errorf("--limit must be at least 1, got %d", limit)
+			}
+
+			root, err := gitToplevel(cmd.Context(), repo)
+			if err != nil {
+				return err
+			}
+			// One extra commit marks where the oldest listed commit's work began
+			commits, err := gitCommits(cmd.Context(), root, limit+1)
+			if err != nil {
+				return err
+			}
+			var start time.Time
+			if len(commits) > limit {
+				start = commits[limit].CommittedAt
+				commits = commits[:limit]
+			}
+
+			// Determine data path
+			if dataPath == "" {
+				dataPath = loadFlags.defaultDataPath()
+			}
+
+			// Initialize services
+			pricingService := newPricingService()
+			calc := calculator.New(pricingService)
+			dataLoader := loader.New()
+			if err := loadFlags.configure(dataLoader, dataPath); err != nil {
+				return err
+			}
+			calc.SetMode(loadFlags.costMode)
+			calc.WarmPricing(cmd.Context())
+
+			loc := time.Local
+			if timezone != "" {
+				loc, err = time.LoadLocation(timezone)
+				if err != nil {
+					return fmt.Errorf("invalid timezone %s: %w", timezone, err)
+				}
+			}
+			now := time.Now()
+			dataLoader.SetDateRange(start, time.Time{})
+
+			entries, err := dataLoader.LoadFromPath(cmd.Context(), dataPath)
+			if err != nil {
+				return fmt.Errorf("failed to load usage data: %w", err)
+			}
+			if !allProjects {
+				entries = entriesInDirectory(entries, root)
+			}
+			entries, err = calc.CalculateCosts(cmd.Context(), entries)
+			if err != nil {
+				return fmt.Errorf("failed to calculate costs: %w", err)
+			}
+			rows := calc.UsageByCommit(entries, commits, start, now)
+
+			if format == "json" {
+				result, err := output.NewFormatter(output.FormatterOptions{Format: format}).FormatJSON(rows)
+				if err != nil {
+					return fmt.Errorf("failed to format report: %w", err)
+				}
+				fmt.Print(result)
+				return nil
+			}
+
+			tableFormatter := output.NewTableWriterFormatter(noColor)
+			tableFormatter.SetTableStyle(tableStyle)
+			tableFormatter.SetTimezone(loc)
+			fmt.Print(tableFormatter.FormatCommitUsage(rows))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "table", "Output format (table, json)")
+	cmd.Flags().StringVar(&dataPath, "data-path", "", "Path to Claude data directory (comma-separated; ssh://, s3:// and gs:// URLs are mirrored locally, - reads standard input)")
+	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	loadFlags.register(cmd)
+	cmd.Flags().StringVar(&tableStyle, "table-style", output.TableStyleUnicode, "Table style (unicode, ascii, markdown, minimal, borderless)")
+	cmd.Flags().StringVarP(&timezone, "timezone", "z", "", "Timezone of the commit times")
+	cmd.Flags().StringVar(&repo, "repo", ".", "Git repository whose commits usage is attributed to")
+	cmd.Flags().IntVarP(&limit, "limit", "n", 20, "Number of most recent commits to show")
+	cmd.Flags().BoolVar(&allProjects, "all-projects", false, "Count requests from every directory, not just from inside the repository")
+
+	return cmd
+}
+
+// gitFieldSeparator separates the fields of gitLogFormat
+const gitFieldSeparator = "\x1f"
+
+// gitLogFormat prints a commit's hash, committer time (Unix seconds) and subject
+const gitLogFormat = "%H%x1f%ct%x1f%s"
+
+// runGit runs git in dir and returns its standard output
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git: %w: %s", err, msg)
+		}
+		return "", fmt.Errorf("git: %w", err)
+	}
+	return stdout.String(), nil
+}
+
+// gitToplevel returns the root of the working tree containing dir
+func gitToplevel(ctx context.Context, dir string) (string, error) {
+	out, err := runGit(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// gitCommits returns up to limit commits of HEAD's first-parent history in
+// repo, newest first
+func gitCommits(ctx context.Context, repo string, limit int) ([]types.CommitUsage, error) {
+	out, err := runGit(ctx, repo, "log", "--first-parent", "-n", strconv.Itoa(limit), "--format="+gitLogFormat)
+	if err != nil {
+		return nil, err
+	}
+	return parseGitLog(out)
+}
+
+// parseGitLog parses the lines git log prints with gitLogFormat
+func parseGitLog(out string) ([]types.CommitUsage, error) {
+	var commits []types.CommitUsage
+	for _, line := range strings.Split(out, "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, gitFieldSeparator, 3)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected git log line %q", line)
+		}
+		seconds, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid commit time in git log line %q: %w", line, err)
+		}
+		commits = append(commits, types.CommitUsage{
+			Hash:        fields[0],
+			Subject:     fields[2],
+			CommittedAt: time.Unix(seconds, 0),
+		})
+	}
+	return commits, nil
+}
+
+// entriesInDirectory keeps the entries made from dir or a directory below it
+func entriesInDirectory(entries []types.UsageEntry, dir string) []types.UsageEntry {
+	dir = filepath.Clean(dir)
+	var kept []types.UsageEntry
+	for _, entry := range entries {
+		if entry.Cwd == "" {
+			continue
+		}
+		cwd := filepath.Clean(entry.Cwd)
+		if cwd == dir || strings.HasPrefix(cwd, dir+string(filepath.Separator)) {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
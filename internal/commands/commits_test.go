@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGitLog(t *testing.T) {
+	commits, err := parseGitLog("abc123\x1f1740823200\x1fAdd feature\x1fwith separator\n\ndef456\x1f1740819600\x1fInitial commit\n")
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+	assert.Equal(t, "abc123", commits[0].Hash)
+	assert.Equal(t, "Add feature\x1fwith separator", commits[0].Subject)
+	assert.True(t, commits[0].CommittedAt.Equal(time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)))
+
+	_, err = parseGitLog("abc123 no separators\n")
+	assert.Error(t, err)
+}
+
+func TestEntriesInDirectory(t *testing.T) {
+	entries := []types.UsageEntry{
+		{ID: "root", Cwd: "/src/app"},
+		{ID: "below", Cwd: "/src/app/web"},
+		{ID: "sibling", Cwd: "/src/app-old"},
+		{ID: "none"},
+	}
+	var ids []string
+	for _, entry := range entriesInDirectory(entries, "/src/app/") {
+		ids = append(ids, entry.ID)
+	}
+	assert.Equal(t, []string{"root", "below"}, ids)
+}
@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/calculator"
+	"github.com/sdpower/ccusage-go/internal/loader"
+	"github.com/sdpower/ccusage-go/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func NewCostCheckCommand() *cobra.Command {
+	var (
+		format     string
+		dataPath   string
+		noColor    bool
+		loadFlags  loaderFlags
+		tableStyle string
+		timezone   string
+		since      string
+		until      string
+		debug      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "cost-check",
+		Short: "Compare logged costs with costs computed from tokens",
+		Long: `Recompute the cost of every request that logged one (costUSD) from its tokens
+and current pricing, and report the difference per model. Large differences
+point at stale pricing data or logging bugs. --mode has no effect here.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := output.ValidateTableStyle(tableStyle); err != nil {
+				return err
+			}
+			if format != "table" && format != "json" {
+				return fmt.Errorf("invalid --format %q, use table or json", format)
+			}
+
+			// Determine data path
+			if dataPath == "" {
+				dataPath = loadFlags.defaultDataPath()
+			}
+
+			// Initialize services
+			pricingService := newPricingService()
+			calc := calculator.New(pricingService)
+			calc.WarmPricing(cmd.Context())
+			dataLoader := loader.New()
+			if err := loadFlags.configure(dataLoader, dataPath); err != nil {
+				return err
+			}
+
+			loc := time.Local
+			if timezone != "" {
+				var err error
+				loc, err = time.LoadLocation(timezone)
+				if err != nil {
+					return fmt.Errorf("invalid timezone %s: %w", timezone, err)
+				}
+				dataLoader.SetTimezone(loc)
+			}
+			dataLoader.SetDateRange(loaderDateRange(since, until, loc))
+
+			// Entries keep their logged costs: CalculateCosts is not applied
+			entries, err := dataLoader.LoadFromPath(cmd.Context(), dataPath)
+			if err != nil {
+				return fmt.Errorf("failed to load usage data: %w", err)
+			}
+			report := calc.CompareCosts(cmd.Context(), entries)
+			if debug {
+				for _, model := range report.Models {
+					report.Rates = append(report.Rates, effectiveRates(cmd.Context(), pricingService, model.Model))
+				}
+			}
+
+			if format == "json" {
+				result, err := output.NewFormatter(output.FormatterOptions{Format: format}).FormatJSON(report)
+				if err != nil {
+					return fmt.Errorf("failed to format report: %w", err)
+				}
+				fmt.Print(result)
+				return nil
+			}
+
+			tableFormatter := output.NewTableWriterFormatter(noColor)
+			tableFormatter.SetTableStyle(tableStyle)
+			fmt.Print(tableFormatter.FormatCostDiscrepancies(report))
+			fmt.Print(output.FormatRatesUsed(report.Rates))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "table", "Output format (table, json)")
+	cmd.Flags().StringVar(&dataPath, "data-path", "", "Path to Claude data directory (comma-separated; ssh://, s3:// and gs:// URLs are mirrored locally, - reads standard input)")
+	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	loadFlags.register(cmd)
+	cmd.Flags().StringVar(&tableStyle, "table-style", output.TableStyleUnicode, "Table style (unicode, ascii, markdown, minimal, borderless)")
+	cmd.Flags().StringVarP(&timezone, "timezone", "z", "", "Timezone for --since/--until dates")
+	cmd.Flags().StringVarP(&since, "since", "s", "", "Filter from date (YYYYMMDD format)")
+	cmd.Flags().StringVarP(&until, "until", "u", "", "Filter until date (YYYYMMDD format)")
+	cmd.Flags().BoolVar(&debug, "debug", false, "Explain computed costs: list the rates of each model in $/MTok")
+
+	return cmd
+}
@@ -2,13 +2,12 @@ package commands
 
 import (
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/sdpower/ccusage-go/internal/calculator"
 	"github.com/sdpower/ccusage-go/internal/loader"
 	"github.com/sdpower/ccusage-go/internal/output"
-	"github.com/sdpower/ccusage-go/internal/pricing"
-	"github.com/sdpower/ccusage-go/internal/types"
 	"github.com/spf13/cobra"
 )
 
@@ -18,11 +17,16 @@ func NewDailyCommand() *cobra.Command {
 		format     string
 		dataPath   string
 		noColor    bool
+		loadFlags  loaderFlags
+		tableStyle string
 		responsive bool
 		debug      bool
 		timezone   string
 		since      string
 		until      string
+		watch      time.Duration
+		groupBy    string
+		last       string
 	)
 
 	cmd := &cobra.Command{
@@ -30,6 +34,25 @@ func NewDailyCommand() *cobra.Command {
 		Short: "Generate daily usage report",
 		Long:  `Generate a daily usage report for Claude Code usage data.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := output.ValidateTableStyle(tableStyle); err != nil {
+				return err
+			}
+			if loadFlags.lowMemory && (format != "table" || date != "") {
+				return fmt.Errorf("--low-memory only supports the all-dates table output")
+			}
+			if loadFlags.keepRaw && format != "json" {
+				return fmt.Errorf("--keep-raw only applies to --format json")
+			}
+			if err := validateGroupBy(groupBy); err != nil {
+				return err
+			}
+			if groupBy != "" && (format != "table" || date != "") {
+				return fmt.Errorf("--group-by only supports the all-dates table output")
+			}
+			if len(loadFlags.plugins) > 0 && format == "csv" {
+				return fmt.Errorf("--plugin only supports table and json output")
+			}
+
 			// Parse date
 			var targetDate time.Time
 			var err error
@@ -42,10 +65,21 @@ func NewDailyCommand() *cobra.Command {
 					return fmt.Errorf("invalid date format, use YYYY-MM-DD: %w", err)
 				}
 			}
+			window, err := parseLastWindow(last, since, until)
+			if err != nil {
+				return err
+			}
+			if window > 0 && date != "" {
+				return fmt.Errorf("--last cannot be combined with --date")
+			}
+			watching, err := watchInterval(cmd, watch)
+			if err != nil {
+				return err
+			}
 
 			// Determine data path
 			if dataPath == "" {
-				dataPath = getDefaultDataPath()
+				dataPath = loadFlags.defaultDataPath()
 			}
 
 			// Load timezone if specified (BEFORE loading data)
@@ -59,91 +93,142 @@ func NewDailyCommand() *cobra.Command {
 			} else {
 				loc = time.Local
 			}
+			if date == "" {
+				targetDate = targetDate.In(loc) // Today in the report timezone
+			}
 
 			// Initialize services
-			pricingService := pricing.NewService()
+			pricingService := newPricingService()
 			calc := calculator.New(pricingService)
 			dataLoader := loader.New()
-			dataLoader.SetDebug(debug)
+			if err := loadFlags.configure(dataLoader, dataPath); err != nil {
+				return err
+			}
+			calc.SetMode(loadFlags.costMode)
+			calc.WarmPricing(cmd.Context())
+			calc.SetTimezone(loc)
+			// With --format json the loader stats go into the report metadata
+			// instead of stderr
+			dataLoader.SetDebug(debug && format != "json")
+			if debug && format != "json" {
+				defer debugPricing(os.Stderr, pricingService)
+			}
 			dataLoader.SetTimezone(loc) // Apply timezone to data loading (BEFORE loading data)
 
 			formatter := output.NewFormatter(output.FormatterOptions{
 				Format:     format,
 				NoColor:    noColor,
 				Responsive: responsive,
+				TableStyle: tableStyle,
 			})
 
-			// Load data
-			entries, err := dataLoader.LoadFromPath(cmd.Context(), dataPath)
-			if err != nil {
-				return fmt.Errorf("failed to load usage data: %w", err)
-			}
+			report := func() error {
+				// A --last window ends at the time of each render
+				start, end := reportDateRange(window, since, until, loc, time.Now())
+				dataLoader.SetDateRange(start, end)
+				plugins, err := loadFlags.newPlugins()
+				if err != nil {
+					return err
+				}
 
-			// Calculate costs
-			entries, err = calc.CalculateCosts(cmd.Context(), entries)
-			if err != nil {
-				return fmt.Errorf("failed to calculate costs: %w", err)
-			}
+				// The all-dates table only needs per-day totals, so entries are
+				// aggregated while loading instead of being kept in memory
+				if format == "table" && date == "" {
+					tableFormatter := output.NewTableWriterFormatter(noColor)
+					tableFormatter.SetTableStyle(tableStyle)
+					tableFormatter.SetTimezone(loc)
 
-			// For table format, use the tablewriter formatter
-			if format == "table" {
-				tableFormatter := output.NewTableWriterFormatter(noColor)
-				tableFormatter.SetTimezone(loc)
-				
-				// If no specific date, show all dates grouped
-				if date == "" {
-					// Convert since/until from YYYYMMDD to YYYY-MM-DD format
-					sinceDate := ""
-					untilDate := ""
-					if since != "" && len(since) == 8 {
-						sinceDate = fmt.Sprintf("%s-%s-%s", since[:4], since[4:6], since[6:8])
+					key := calculator.DailyKey(loc)
+					if groupBy != "" {
+						key = groupByKey(groupBy, loadFlags.config)
 					}
-					if until != "" && len(until) == 8 {
-						untilDate = fmt.Sprintf("%s-%s-%s", until[:4], until[4:6], until[6:8])
+					agg := calculator.NewGroupAggregator(calc, key)
+					if err := dataLoader.LoadAndAggregate(cmd.Context(), dataPath, nil, pluginAggregator(calc, agg, plugins)); err != nil {
+						return fmt.Errorf("failed to load usage data: %w", err)
 					}
-					output := tableFormatter.FormatDailyReportWithFilter(entries, sinceDate, untilDate)
-					fmt.Print(output)
-				} else {
-					// Filter entries for the target date
-					filteredEntries := []types.UsageEntry{}
-					startOfDay := time.Date(targetDate.Year(), targetDate.Month(), targetDate.Day(), 0, 0, 0, 0, targetDate.Location())
-					endOfDay := startOfDay.Add(24 * time.Hour)
-					
-					for _, entry := range entries {
-						// Include entries that are >= startOfDay and < endOfDay
-						if (entry.Timestamp.Equal(startOfDay) || entry.Timestamp.After(startOfDay)) && entry.Timestamp.Before(endOfDay) {
-							filteredEntries = append(filteredEntries, entry)
-						}
+					if groupBy != "" {
+						fmt.Print(formatGroupBy(tableFormatter, groupBy, agg.Groups))
+					} else {
+						// Entries outside --since/--until or --last were already dropped by the loader
+						fmt.Print(tableFormatter.FormatDailyGroups(agg.Groups, "", ""))
 					}
-					
-					output := tableFormatter.FormatDailyReport(filteredEntries)
-					fmt.Print(output)
+					return printPluginSections(tableFormatter, plugins)
 				}
-			} else {
-				// Generate report for JSON/CSV
-				report := calc.GenerateDailyReport(entries, targetDate)
-				
-				// Format and output
-				output, err := formatter.FormatUsageReport(report)
+
+				// Load data
+				entries, err := dataLoader.LoadFromPath(cmd.Context(), dataPath)
 				if err != nil {
-					return fmt.Errorf("failed to format report: %w", err)
+					return fmt.Errorf("failed to load usage data: %w", err)
 				}
+
+				// Calculate costs
+				entries, err = calc.CalculateCosts(cmd.Context(), entries)
+				if err != nil {
+					return fmt.Errorf("failed to calculate costs: %w", err)
+				}
+
+				// For table format, use the tablewriter formatter
+				if format == "table" {
+					tableFormatter := output.NewTableWriterFormatter(noColor)
+					tableFormatter.SetTableStyle(tableStyle)
+					tableFormatter.SetTimezone(loc)
 				
-				fmt.Print(output)
+					// Select the target date the same way as JSON/CSV (all-dates
+					// tables are handled above)
+					filteredEntries := calc.GenerateDailyReport(entries, targetDate).Entries
+					output := tableFormatter.FormatDailyReport(filteredEntries)
+					fmt.Print(output)
+					plugins.AddAll(filteredEntries)
+					return printPluginSections(tableFormatter, plugins)
+				} else {
+					// Generate report for JSON/CSV
+					report := calc.GenerateDailyReport(entries, targetDate)
+					if window > 0 {
+						report = calc.GenerateRollingReport(entries, start, end)
+					}
+					if debug {
+						report.Metadata = reportMetadata(dataLoader, pricingService)
+					}
+					plugins.AddAll(report.Entries)
+					if report.Sections, err = plugins.Sections(); err != nil {
+						return err
+					}
+				
+					// Format and output
+					output, err := formatter.FormatUsageReport(report)
+					if err != nil {
+						return fmt.Errorf("failed to format report: %w", err)
+					}
+				
+					fmt.Print(output)
+				}
+				return nil
+			}
+
+			if !watching {
+				return report()
 			}
-			return nil
+			return watchReport(cmd.Context(), dataPath, watch, report)
 		},
 	}
 
 	cmd.Flags().StringVarP(&date, "date", "d", "", "Date to generate report for (YYYY-MM-DD, defaults to today)")
 	cmd.Flags().StringVarP(&format, "format", "f", "table", "Output format (table, json, csv)")
-	cmd.Flags().StringVar(&dataPath, "data-path", "", "Path to Claude data directory")
+	cmd.Flags().StringVar(&dataPath, "data-path", "", "Path to Claude data directory (comma-separated; ssh://, s3:// and gs:// URLs are mirrored locally, - reads standard input)")
 	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	loadFlags.register(cmd)
+	loadFlags.registerLowMemory(cmd)
+	loadFlags.registerKeepRaw(cmd)
+	loadFlags.registerPlugins(cmd)
+	cmd.Flags().StringVar(&groupBy, "group-by", "", "Group the table by another dimension instead of date (agent: main conversation vs sub-agents, cwd: working directory, model-family: Opus/Sonnet/Haiku/GPT, account: logged account or data directory)")
+	cmd.Flags().StringVar(&tableStyle, "table-style", output.TableStyleUnicode, "Table style (unicode, ascii, markdown, minimal, borderless)")
 	cmd.Flags().BoolVar(&responsive, "responsive", true, "Enable responsive table layout")
 	cmd.Flags().BoolVar(&debug, "debug", false, "Show debug information")
 	cmd.Flags().StringVarP(&timezone, "timezone", "z", "", "Timezone for date grouping (e.g., UTC, America/New_York, Asia/Tokyo). Default: system timezone")
 	cmd.Flags().StringVarP(&since, "since", "s", "", "Filter from date (YYYYMMDD format)")
 	cmd.Flags().StringVarP(&until, "until", "u", "", "Filter until date (YYYYMMDD format)")
+	cmd.Flags().StringVar(&last, "last", "", "Report a rolling window ending now instead of calendar dates (e.g. 7d, 30d, 24h)")
+	registerWatch(cmd, &watch)
 
 	return cmd
 }
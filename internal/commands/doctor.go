@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sdpower/ccusage-go/internal/loader"
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/spf13/cobra"
+)
+
+func NewDoctorCommand() *cobra.Command {
+	var (
+		dataPath    string
+		parseErrors bool
+		maxMessages int
+		debug       bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check Claude data directories and usage files for problems",
+		Long: `Check that the Claude data directories exist and that their usage files parse cleanly.
+Lines that fail validation are skipped by the reports; use --parse-errors to list them.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if maxMessages < 0 {
+				return fmt.Errorf("--max-messages must not be negative, got %d", maxMessages)
+			}
+
+			// Determine data path
+			if dataPath == "" {
+				dataPath = getDefaultDataPath()
+			}
+
+			fmt.Println("Data directories:")
+			for _, root := range loader.SplitDataPaths(dataPath) {
+				if strings.Contains(root, "://") {
+					fmt.Printf("  • %s (remote)\n", root)
+				} else if _, err := os.Stat(root); err != nil {
+					fmt.Printf("  ✗ %s (not found)\n", root)
+				} else {
+					fmt.Printf("  ✓ %s\n", root)
+				}
+			}
+			fmt.Println()
+
+			dataLoader := loader.New()
+			dataLoader.SetMaxWorkers(defaultJobs)
+			dataLoader.SetDebug(debug)
+
+			report, err := dataLoader.ScanParseErrors(cmd.Context(), dataPath)
+			if err != nil {
+				return fmt.Errorf("failed to scan usage data: %w", err)
+			}
+
+			fmt.Printf("Usage files: %d (%s lines, %s invalid%s)\n",
+				report.Files, formatNumber(report.TotalLines), formatNumber(report.InvalidLines), invalidRate(report))
+			for _, err := range report.ReadErrors {
+				fmt.Printf("  ✗ %v\n", err)
+			}
+
+			if len(report.FileErrors) == 0 {
+				return nil
+			}
+			fmt.Printf("Files with parse errors: %d\n", len(report.FileErrors))
+			if !parseErrors {
+				fmt.Println("\nRun 'ccusage doctor --parse-errors' to list the offending lines.")
+				return nil
+			}
+
+			for _, file := range report.FileErrors {
+				printParseErrors(file, maxMessages)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&dataPath, "data-path", "", "Path to Claude data directory (comma-separated; ssh://, s3:// and gs:// URLs are mirrored locally, - reads standard input)")
+	cmd.Flags().BoolVar(&parseErrors, "parse-errors", false, "List files with parse failures and the offending line numbers")
+	cmd.Flags().IntVar(&maxMessages, "max-messages", 3, "Number of error messages to show per file with --parse-errors")
+	cmd.Flags().BoolVar(&debug, "debug", false, "Show debug information")
+
+	return cmd
+}
+
+// invalidRate formats the share of invalid lines as ", 1.23%", or "" when there are none
+func invalidRate(report *loader.ParseReport) string {
+	if report.InvalidLines == 0 || report.TotalLines == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", %.2f%%", float64(report.InvalidLines)/float64(report.TotalLines)*100)
+}
+
+// printParseErrors prints one file's invalid line numbers and its first maxMessages errors
+func printParseErrors(file types.FileValidationCount, maxMessages int) {
+	fmt.Printf("\n%s: %d of %d lines invalid\n", file.Path, file.InvalidLines, file.TotalLines)
+
+	lines := make([]string, 0, len(file.Errors))
+	for _, e := range file.Errors {
+		lines = append(lines, strconv.Itoa(e.Line))
+	}
+	if file.InvalidLines > len(file.Errors) {
+		lines = append(lines, "...")
+	}
+	fmt.Printf("  Lines: %s\n", strings.Join(lines, ", "))
+
+	for i, e := range file.Errors {
+		if i >= maxMessages {
+			break
+		}
+		fmt.Printf("  Line %d: %v\n", e.Line, e.Err)
+	}
+}
@@ -0,0 +1,237 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/config"
+	"github.com/sdpower/ccusage-go/internal/currency"
+	"github.com/sdpower/ccusage-go/internal/loader"
+	"github.com/sdpower/ccusage-go/internal/notify"
+	"github.com/sdpower/ccusage-go/internal/output"
+	"github.com/sdpower/ccusage-go/internal/usage"
+	"github.com/sdpower/ccusage-go/pkg/pricing"
+	"github.com/spf13/cobra"
+)
+
+// GlobalFlags holds the flags every command takes, which set up pricing
+// and the display currency
+type GlobalFlags struct {
+	offline     bool
+	pricingFile string
+	proxy       string
+	caBundle    string
+	currency    string
+	strict      bool
+	priceAsOf   string
+
+	pricingTimeout time.Duration
+	noNetwork      bool
+	pricingSource  string
+	billing        string
+}
+
+// pricingOptions configure the pricing services of the commands, set from
+// the global flags and the configuration file by GlobalFlags.Apply
+var pricingOptions pricing.Options
+
+// pricingServices are the services created by the command, whose unknown
+// models GlobalFlags.Check reports
+var pricingServices []*pricing.Service
+
+// newPricingService returns a pricing service configured by the global flags
+func newPricingService() *pricing.Service {
+	service := pricing.New(pricingOptions)
+	pricingServices = append(pricingServices, service)
+	return service
+}
+
+// unknownModels returns the models the command's pricing services found no
+// pricing for, sorted
+func unknownModels() []string {
+	seen := make(map[string]bool)
+	var models []string
+	for _, service := range pricingServices {
+		for _, model := range service.UnknownModels() {
+			if !seen[model] {
+				seen[model] = true
+				models = append(models, model)
+			}
+		}
+	}
+	sort.Strings(models)
+	return models
+}
+
+// Register adds the global flags to the root command
+func (g *GlobalFlags) Register(root *cobra.Command) {
+	root.PersistentFlags().BoolVarP(&g.offline, "offline", "O", false, "Use cached or embedded model pricing instead of fetching it from LiteLLM (no network access)")
+	root.PersistentFlags().StringVar(&g.pricingFile, "pricing-file", "", "JSON file of model prices (LiteLLM format) overriding or extending the fetched ones (default: pricing_file in the config)")
+	root.PersistentFlags().StringVar(&g.pricingSource, "pricing-source", "", "Where model prices are fetched from: litellm, or openrouter for usage billed through OpenRouter at its rates (default: pricing_source in the config, else litellm)")
+	root.PersistentFlags().StringVar(&g.proxy, "proxy", "", "Proxy URL for fetching pricing (default: proxy in the config, else HTTPS_PROXY/HTTP_PROXY)")
+	root.PersistentFlags().DurationVar(&g.pricingTimeout, "pricing-timeout", 0, "Give up fetching pricing after this long, retries included, and use cached or embedded prices (default: pricing_timeout in the config, else 15s)")
+	root.PersistentFlags().BoolVar(&g.noNetwork, "no-network", false, "Make no network connections: implies --offline, reads remote data paths from their last synced copies and skips usage limits")
+	root.PersistentFlags().StringVar(&g.caBundle, "ca-bundle", "", "PEM file of CA certificates to trust when fetching pricing, e.g. of a TLS-intercepting firewall (default: ca_bundle in the config)")
+	root.PersistentFlags().BoolVar(&g.strict, "strict-pricing", false, "Leave requests of models without pricing data uncosted and fail listing them, instead of applying generic default rates")
+	root.PersistentFlags().StringVar(&g.priceAsOf, "price-as-of", "", "Cost all usage at the rates in effect on this date (YYYY-MM-DD) instead of at each request's time; past rates come from dated entries of the pricing file")
+	root.PersistentFlags().StringVar(&g.billing, "billing", "", "How usage is paid for: api, or subscription to show costs as included in a Pro or Max plan ($0 actual / API-equivalent; JSON keeps both under \"billing\") (default: cost_display.billing in the config, else api)")
+	root.PersistentFlags().StringVar(&g.currency, "currency", "", "Show table costs in another currency, e.g. EUR, converted at ECB reference rates (default: cost_display.currency in the config, else USD)")
+}
+
+// Apply configures pricing and the display currency from the global flags,
+// falling back to the configuration file; run it before any command
+func (g *GlobalFlags) Apply() error {
+	offline := g.offline || g.noNetwork
+	opts := pricing.Options{Offline: offline, Strict: g.strict}
+	if g.priceAsOf != "" {
+		asOf, err := time.Parse("2006-01-02", g.priceAsOf)
+		if err != nil {
+			return fmt.Errorf("invalid --price-as-of %q, use YYYY-MM-DD", g.priceAsOf)
+		}
+		opts.AsOf = asOf
+	}
+	currency.SetOffline(offline)
+	loader.SetOffline(g.noNetwork)
+	usage.SetOffline(g.noNetwork)
+	if g.pricingTimeout < 0 {
+		return fmt.Errorf("invalid --pricing-timeout %s, use a positive duration", g.pricingTimeout)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	pricingFile, proxy, caBundle, code, billing := g.pricingFile, g.proxy, g.caBundle, g.currency, g.billing
+	if pricingFile == "" {
+		pricingFile = cfg.PricingFilePath()
+	}
+	if proxy == "" {
+		proxy = cfg.Proxy
+	}
+	if caBundle == "" {
+		caBundle = cfg.CABundlePath()
+	}
+	if code == "" {
+		code = cfg.CostDisplay.Currency
+	}
+	if billing == "" {
+		billing = cfg.CostDisplay.Billing
+	}
+	billingMode, err := output.ParseBilling(billing)
+	if err != nil {
+		return err
+	}
+	output.SetBilling(billingMode)
+	opts.Timeout = g.pricingTimeout
+	if opts.Timeout == 0 {
+		opts.Timeout = cfg.PricingFetchTimeout()
+	}
+
+	if err := applyPricingSource(&opts, cfg, g.pricingSource); err != nil {
+		return err
+	}
+	if pricingFile != "" {
+		if opts.Overrides, err = pricing.LoadOverrides(pricingFile); err != nil {
+			return err
+		}
+	}
+	var transport http.RoundTripper = noNetworkTransport{}
+	if !g.noNetwork {
+		// A run fetches each host about once: idle connections are not reused
+		t, err := pricing.NewTransportWith(pricing.TransportOptions{Proxy: proxy, CABundle: caBundle, DisableKeepAlives: true})
+		if err != nil {
+			return err
+		}
+		transport = t
+	}
+	opts.Transport = transport
+	pricingOptions = opts
+	currency.SetTransport(transport)
+	notify.SetTransport(transport)
+	if code != "" {
+		rate, err := currency.NewService().Rate(context.Background(), code)
+		if err != nil {
+			return err
+		}
+		output.SetCurrency(output.NewCurrency(rate.Code, rate.PerUSD))
+	}
+	return nil
+}
+
+// noNetworkTransport fails every request, guarding --no-network against
+// code paths that would fetch despite offline mode
+type noNetworkTransport struct{}
+
+func (noNetworkTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, fmt.Errorf("network access disabled by --no-network: %s", req.URL.Host)
+}
+
+// applyPricingSource sets the pricing backend of opts, source ("" means
+// pricing_source in the config), and where its data is fetched from:
+// $CCUSAGE_PRICING_URL or $CCUSAGE_PRICING_COMMIT, else pricing_url or
+// pricing_commit in the config
+func applyPricingSource(opts *pricing.Options, cfg *config.Config, source string) error {
+	if source == "" {
+		source = cfg.PricingSource
+	}
+	backend, err := pricing.ParseBackend(source)
+	if err != nil {
+		return err
+	}
+	opts.Backend = backend
+
+	pricingURL, commit := os.Getenv("CCUSAGE_PRICING_URL"), os.Getenv("CCUSAGE_PRICING_COMMIT")
+	if pricingURL == "" && commit == "" {
+		pricingURL, commit = cfg.PricingURL, cfg.PricingCommit
+	}
+	if commit == "" {
+		if pricingURL != "" {
+			if err := pricing.ValidateSourceURL(pricingURL); err != nil {
+				return err
+			}
+		}
+		opts.SourceURL = pricingURL
+		return nil
+	}
+	if pricingURL != "" {
+		return fmt.Errorf("set only one of the pricing URL and commit")
+	}
+	if backend != pricing.BackendLiteLLM {
+		return fmt.Errorf("a pricing commit pins LiteLLM pricing, it does not apply to %s", backend)
+	}
+	commitURL, err := pricing.CommitSourceURL(commit)
+	if err != nil {
+		return err
+	}
+	opts.SourceURL, opts.Pinned = commitURL, true
+	return nil
+}
+
+// Check reports the models priced during the command without pricing
+// data: a warning that their costs are estimates at default rates, or an
+// error under --strict-pricing; run it after the command
+func (g *GlobalFlags) Check() error {
+	return checkUnknownModels(os.Stderr, unknownModels(), g.strict)
+}
+
+func checkUnknownModels(w io.Writer, models []string, strict bool) error {
+	if len(models) == 0 {
+		return nil
+	}
+	quoted := make([]string, len(models))
+	for i, model := range models {
+		quoted[i] = fmt.Sprintf("%q", model)
+	}
+	list := strings.Join(quoted, ", ")
+	if strict {
+		return fmt.Errorf("no pricing for %s: their requests are left out of the costs (price them with --pricing-file)", list)
+	}
+	fmt.Fprintf(w, "Warning: no pricing for %s: their costs are estimates at default rates (price them with --pricing-file, see pricing list)\n", list)
+	return nil
+}
@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/config"
+	"github.com/sdpower/ccusage-go/internal/currency"
+	"github.com/sdpower/ccusage-go/internal/loader"
+	"github.com/sdpower/ccusage-go/internal/usage"
+	"github.com/sdpower/ccusage-go/pkg/pricing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyPricingSource(t *testing.T) {
+	var opts pricing.Options
+	assert.NoError(t, applyPricingSource(&opts, &config.Config{PricingCommit: "0123abcd"}, ""))
+	assert.Contains(t, opts.SourceURL, "0123abcd")
+	assert.True(t, opts.Pinned)
+	assert.Error(t, applyPricingSource(&opts, &config.Config{PricingCommit: "main"}, ""))
+	assert.Error(t, applyPricingSource(&opts, &config.Config{PricingURL: "https://mirror/prices.json", PricingCommit: "0123abcd"}, ""))
+	assert.Error(t, applyPricingSource(&opts, &config.Config{PricingURL: "not a url"}, ""))
+
+	// OpenRouter has no commits to pin
+	opts = pricing.Options{}
+	assert.NoError(t, applyPricingSource(&opts, &config.Config{PricingSource: "openrouter"}, ""))
+	assert.Equal(t, pricing.BackendOpenRouter, opts.Backend)
+	assert.Error(t, applyPricingSource(&opts, &config.Config{PricingCommit: "0123abcd"}, "openrouter"))
+	assert.Error(t, applyPricingSource(&opts, &config.Config{}, "anthropic"))
+
+	// The environment replaces the config
+	t.Setenv("CCUSAGE_PRICING_URL", "https://mirror.example.com/prices.json")
+	opts = pricing.Options{}
+	assert.NoError(t, applyPricingSource(&opts, &config.Config{PricingURL: "not a url"}, ""))
+	assert.Equal(t, "https://mirror.example.com/prices.json", opts.SourceURL)
+	assert.False(t, opts.Pinned)
+}
+
+func TestCheckStrictPricing(t *testing.T) {
+	pricingOptions = pricing.Options{Offline: true, Strict: true}
+	defer func() { pricingOptions, pricingServices = pricing.Options{}, nil }()
+	assert.NoError(t, (&GlobalFlags{strict: true}).Check())
+
+	_, _, _, _, err := newPricingService().GetModelPrice(context.Background(), "check-unknown-model")
+	assert.Error(t, err)
+	assert.ErrorContains(t, (&GlobalFlags{strict: true}).Check(), `"check-unknown-model"`)
+
+	// Services created elsewhere are not the command's
+	_, _, _, _, err = pricing.New(pricing.Options{Offline: true}).GetModelPrice(context.Background(), "other-unknown-model")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"check-unknown-model"}, unknownModels())
+
+	var warnings bytes.Buffer
+	assert.NoError(t, checkUnknownModels(&warnings, []string{"a", "b"}, false))
+	assert.Contains(t, warnings.String(), `no pricing for "a", "b": their costs are estimates`)
+	warnings.Reset()
+	assert.NoError(t, checkUnknownModels(&warnings, nil, false))
+	assert.Empty(t, warnings.String())
+}
+
+func TestNoNetwork(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	defer func() {
+		pricingOptions, pricingServices = pricing.Options{}, nil
+		currency.SetOffline(false)
+		currency.SetTransport(nil)
+		loader.SetOffline(false)
+		usage.SetOffline(false)
+	}()
+
+	assert.NoError(t, (&GlobalFlags{noNetwork: true}).Apply())
+	assert.Nil(t, usage.NewClient().GetUsage(context.Background()))
+	_, source := newPricingService().ModelPrice(context.Background(), "claude-sonnet-4-5")
+	assert.Equal(t, pricing.SourceEmbedded, source)
+
+	_, err := (&http.Client{Transport: noNetworkTransport{}}).Get("https://raw.githubusercontent.com/")
+	assert.ErrorContains(t, err, "network access disabled by --no-network")
+
+	assert.Error(t, (&GlobalFlags{pricingTimeout: -time.Second}).Apply())
+}
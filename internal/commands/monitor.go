@@ -14,13 +14,35 @@ func NewMonitorCommand() *cobra.Command {
 		interval   int
 		noColor    bool
 		continuous bool
+		daemon     bool
+		statusFile string
+		oneline    bool
+		once       bool
+		tokenLimit int
 	)
 
 	cmd := &cobra.Command{
 		Use:   "monitor",
 		Short: "Monitor Claude Code usage in real-time",
-		Long:  `Monitor Claude Code usage data in real-time with live dashboard.`,
+		Long: `Monitor Claude Code usage data in real-time with live dashboard.
+With --daemon, no dashboard is shown: the active block and today's usage are
+written to --status-file as JSON every --interval, for status bars, editors and
+other tools.
+With --oneline, a single line of the active block's share of the token limit,
+time left and burn rate, and today's cost is printed and updated in place; add
+--once for status bars, e.g.
+  tmux set -g status-right '#(ccusage monitor --oneline --once)'`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if interval < 1 {
+				return fmt.Errorf("--interval must be at least 1 second, got %d", interval)
+			}
+			if daemon && (oneline || once) {
+				return fmt.Errorf("--daemon cannot be combined with --oneline or --once")
+			}
+			if tokenLimit < 0 {
+				return fmt.Errorf("--token-limit must not be negative, got %d", tokenLimit)
+			}
+
 			// Determine data path
 			if dataPath == "" {
 				dataPath = getDefaultDataPath()
@@ -31,7 +53,13 @@ func NewMonitorCommand() *cobra.Command {
 				DataPath:   dataPath,
 				Interval:   time.Duration(interval) * time.Second,
 				NoColor:    noColor,
-				Continuous: continuous,
+				Continuous: continuous && !once,
+				Daemon:     daemon,
+				StatusFile: statusFile,
+				Oneline:    oneline,
+				Once:       once,
+				TokenLimit: tokenLimit,
+				Pricing:    pricingOptions,
 			})
 
 			// Start monitoring
@@ -44,10 +72,15 @@ func NewMonitorCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&dataPath, "data-path", "", "Path to Claude data directory")
+	cmd.Flags().StringVar(&dataPath, "data-path", "", "Path to Claude data directory (comma-separated; ssh://, s3:// and gs:// URLs are mirrored locally, - reads standard input)")
 	cmd.Flags().IntVar(&interval, "interval", 5, "Update interval in seconds")
 	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output")
 	cmd.Flags().BoolVar(&continuous, "continuous", true, "Run continuously")
+	cmd.Flags().BoolVar(&daemon, "daemon", false, "Run without a display, writing usage to --status-file every --interval")
+	cmd.Flags().BoolVar(&oneline, "oneline", false, "Print a single line of block usage, burn rate and today's cost, updated every --interval")
+	cmd.Flags().BoolVar(&once, "once", false, "Print once and exit, e.g. --oneline for tmux status bars")
+	cmd.Flags().IntVarP(&tokenLimit, "token-limit", "t", 0, "Token limit of a block for --oneline (default: the most tokens of a past block)")
+	cmd.Flags().StringVar(&statusFile, "status-file", "", "JSON file --daemon writes the active block and today's usage to (default: status.json in $XDG_CACHE_HOME/ccusage)")
 
 	return cmd
 }
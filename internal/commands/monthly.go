@@ -2,6 +2,7 @@ package commands
 
 import (
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -9,7 +10,6 @@ import (
 	"github.com/sdpower/ccusage-go/internal/calculator"
 	"github.com/sdpower/ccusage-go/internal/loader"
 	"github.com/sdpower/ccusage-go/internal/output"
-	"github.com/sdpower/ccusage-go/internal/pricing"
 	"github.com/spf13/cobra"
 )
 
@@ -19,11 +19,15 @@ func NewMonthlyCommand() *cobra.Command {
 		format     string
 		dataPath   string
 		noColor    bool
+		loadFlags  loaderFlags
+		tableStyle string
 		responsive bool
 		debug      bool
 		timezone   string
 		since      string
 		until      string
+		groupBy    string
+		last       string
 	)
 
 	cmd := &cobra.Command{
@@ -31,15 +35,30 @@ func NewMonthlyCommand() *cobra.Command {
 		Short: "Generate monthly usage report",
 		Long:  `Generate a monthly usage report for Claude Code usage data.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := output.ValidateTableStyle(tableStyle); err != nil {
+				return err
+			}
+			if loadFlags.lowMemory && format != "table" {
+				return fmt.Errorf("--low-memory only supports table output")
+			}
+			if loadFlags.keepRaw && format != "json" {
+				return fmt.Errorf("--keep-raw only applies to --format json")
+			}
+			if err := validateGroupBy(groupBy); err != nil {
+				return err
+			}
+			if groupBy != "" && format != "table" {
+				return fmt.Errorf("--group-by only supports table output")
+			}
+			if len(loadFlags.plugins) > 0 && format == "csv" {
+				return fmt.Errorf("--plugin only supports table and json output")
+			}
+
 			// Parse month
 			var year, monthNum int
 			var err error
 
-			if month == "" {
-				now := time.Now()
-				year = now.Year()
-				monthNum = int(now.Month())
-			} else {
+			if month != "" {
 				parts := strings.Split(month, "-")
 				if len(parts) != 2 {
 					return fmt.Errorf("invalid month format, use YYYY-MM")
@@ -59,10 +78,17 @@ func NewMonthlyCommand() *cobra.Command {
 					return fmt.Errorf("month must be between 1 and 12")
 				}
 			}
+			window, err := parseLastWindow(last, since, until)
+			if err != nil {
+				return err
+			}
+			if window > 0 && month != "" {
+				return fmt.Errorf("--last cannot be combined with --month")
+			}
 
 			// Determine data path
 			if dataPath == "" {
-				dataPath = getDefaultDataPath()
+				dataPath = loadFlags.defaultDataPath()
 			}
 
 			// Load timezone if specified (BEFORE loading data)
@@ -76,37 +102,64 @@ func NewMonthlyCommand() *cobra.Command {
 			} else {
 				loc = time.Local
 			}
+			if month == "" {
+				// Current month in the report timezone
+				now := time.Now().In(loc)
+				year = now.Year()
+				monthNum = int(now.Month())
+			}
 
 			// Initialize services
-			pricingService := pricing.NewService()
+			pricingService := newPricingService()
 			calc := calculator.New(pricingService)
 			dataLoader := loader.New()
-			dataLoader.SetDebug(debug)
+			if err := loadFlags.configure(dataLoader, dataPath); err != nil {
+				return err
+			}
+			calc.SetMode(loadFlags.costMode)
+			calc.WarmPricing(cmd.Context())
+			calc.SetTimezone(loc)
+			// With --format json the loader stats go into the report metadata
+			// instead of stderr
+			dataLoader.SetDebug(debug && format != "json")
+			if debug && format != "json" {
+				defer debugPricing(os.Stderr, pricingService)
+			}
+			start, end := reportDateRange(window, since, until, loc, time.Now())
+			dataLoader.SetDateRange(start, end)
 			dataLoader.SetTimezone(loc) // Apply timezone to data loading (BEFORE loading data)
+			plugins, err := loadFlags.newPlugins()
+			if err != nil {
+				return err
+			}
 
 			formatter := output.NewFormatter(output.FormatterOptions{
 				Format:     format,
 				NoColor:    noColor,
 				Responsive: responsive,
+				TableStyle: tableStyle,
 			})
 
-			// Load data
-			entries, err := dataLoader.LoadFromPath(cmd.Context(), dataPath)
-			if err != nil {
-				return fmt.Errorf("failed to load usage data: %w", err)
-			}
-
-			// Calculate costs
-			entries, err = calc.CalculateCosts(cmd.Context(), entries)
-			if err != nil {
-				return fmt.Errorf("failed to calculate costs: %w", err)
-			}
-
-			// For table format, use the tablewriter formatter
+			// The table only needs per-month totals, so entries are aggregated
+			// while loading instead of being kept in memory
 			if format == "table" {
 				tableFormatter := output.NewTableWriterFormatter(noColor)
+				tableFormatter.SetTableStyle(tableStyle)
 				tableFormatter.SetTimezone(loc)
-				
+
+				key := calculator.MonthlyKey(loc)
+				if groupBy != "" {
+					key = groupByKey(groupBy, loadFlags.config)
+				}
+				agg := calculator.NewGroupAggregator(calc, key)
+				if err := dataLoader.LoadAndAggregate(cmd.Context(), dataPath, nil, pluginAggregator(calc, agg, plugins)); err != nil {
+					return fmt.Errorf("failed to load usage data: %w", err)
+				}
+				if groupBy != "" {
+					fmt.Print(formatGroupBy(tableFormatter, groupBy, agg.Groups))
+					return printPluginSections(tableFormatter, plugins)
+				}
+
 				// Convert since/until from YYYYMM to YYYY-MM format for monthly filtering
 				sinceMonth := ""
 				untilMonth := ""
@@ -116,33 +169,62 @@ func NewMonthlyCommand() *cobra.Command {
 				if until != "" && len(until) == 6 {
 					untilMonth = fmt.Sprintf("%s-%s", until[:4], until[4:6])
 				}
-				output := tableFormatter.FormatMonthlyReportWithFilter(entries, sinceMonth, untilMonth)
-				fmt.Print(output)
-			} else {
-				// Generate report for JSON/CSV
-				report := calc.GenerateMonthlyReport(entries, year, monthNum)
-				
-				// Format and output
-				output, err := formatter.FormatUsageReport(report)
-				if err != nil {
-					return fmt.Errorf("failed to format report: %w", err)
-				}
-				
-				fmt.Print(output)
+				fmt.Print(tableFormatter.FormatMonthlyGroups(agg.Groups, sinceMonth, untilMonth))
+				return printPluginSections(tableFormatter, plugins)
 			}
+
+			// Load data
+			entries, err := dataLoader.LoadFromPath(cmd.Context(), dataPath)
+			if err != nil {
+				return fmt.Errorf("failed to load usage data: %w", err)
+			}
+
+			// Calculate costs
+			entries, err = calc.CalculateCosts(cmd.Context(), entries)
+			if err != nil {
+				return fmt.Errorf("failed to calculate costs: %w", err)
+			}
+
+			// Generate report for JSON/CSV
+			report := calc.GenerateMonthlyReport(entries, year, monthNum)
+			if window > 0 {
+				report = calc.GenerateRollingReport(entries, start, end)
+			}
+			if debug {
+				report.Metadata = reportMetadata(dataLoader, pricingService)
+			}
+			plugins.AddAll(report.Entries)
+			if report.Sections, err = plugins.Sections(); err != nil {
+				return err
+			}
+
+			// Format and output
+			output, err := formatter.FormatUsageReport(report)
+			if err != nil {
+				return fmt.Errorf("failed to format report: %w", err)
+			}
+
+			fmt.Print(output)
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVarP(&month, "month", "m", "", "Month to generate report for (YYYY-MM, defaults to current month)")
 	cmd.Flags().StringVarP(&format, "format", "f", "table", "Output format (table, json, csv)")
-	cmd.Flags().StringVar(&dataPath, "data-path", "", "Path to Claude data directory")
+	cmd.Flags().StringVar(&dataPath, "data-path", "", "Path to Claude data directory (comma-separated; ssh://, s3:// and gs:// URLs are mirrored locally, - reads standard input)")
 	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	loadFlags.register(cmd)
+	loadFlags.registerLowMemory(cmd)
+	loadFlags.registerKeepRaw(cmd)
+	loadFlags.registerPlugins(cmd)
+	cmd.Flags().StringVar(&groupBy, "group-by", "", "Group the table by another dimension instead of month (agent: main conversation vs sub-agents, cwd: working directory, model-family: Opus/Sonnet/Haiku/GPT, account: logged account or data directory)")
+	cmd.Flags().StringVar(&tableStyle, "table-style", output.TableStyleUnicode, "Table style (unicode, ascii, markdown, minimal, borderless)")
 	cmd.Flags().BoolVar(&responsive, "responsive", true, "Enable responsive table layout")
 	cmd.Flags().BoolVar(&debug, "debug", false, "Show debug information")
 	cmd.Flags().StringVarP(&timezone, "timezone", "z", "", "Timezone for date grouping (e.g., UTC, America/New_York, Asia/Tokyo). Default: system timezone")
 	cmd.Flags().StringVarP(&since, "since", "s", "", "Filter from month (YYYYMM format)")
 	cmd.Flags().StringVarP(&until, "until", "u", "", "Filter until month (YYYYMM format)")
+	cmd.Flags().StringVar(&last, "last", "", "Report a rolling window ending now instead of calendar months (e.g. 7d, 30d, 24h)")
 
 	return cmd
 }
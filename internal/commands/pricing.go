@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sdpower/ccusage-go/internal/output"
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/sdpower/ccusage-go/pkg/pricing"
+	"github.com/spf13/cobra"
+)
+
+func NewPricingCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pricing",
+		Short: "Inspect the model prices costs are calculated with",
+	}
+	cmd.AddCommand(newPricingListCommand())
+	return cmd
+}
+
+func newPricingListCommand() *cobra.Command {
+	var (
+		format     string
+		noColor    bool
+		tableStyle string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list [model...]",
+		Short: "Show the effective per-token rates of models and where they came from",
+		Long: `Show the input, output, cache creation and cache read rates costs are
+calculated with, in dollars per million tokens ($/MTok) and per token, and their source: an
+override (--pricing-file), LiteLLM (or OpenRouter with --pricing-source),
+the embedded prices or the default rates of unknown models. Without
+arguments, the models with override or embedded pricing are listed.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := output.ValidateTableStyle(tableStyle); err != nil {
+				return err
+			}
+			if format != "table" && format != "json" {
+				return fmt.Errorf("invalid --format %q, use table or json", format)
+			}
+
+			pricingService := newPricingService()
+			models := args
+			if len(models) == 0 {
+				models = pricingService.KnownModels()
+			}
+			rates := make([]types.ModelRates, 0, len(models))
+			for _, model := range models {
+				rates = append(rates, effectiveRates(cmd.Context(), pricingService, model))
+			}
+
+			if format == "json" {
+				result, err := output.NewFormatter(output.FormatterOptions{Format: format}).FormatJSON(rates)
+				if err != nil {
+					return fmt.Errorf("failed to format report: %w", err)
+				}
+				fmt.Print(result)
+				return nil
+			}
+
+			tableFormatter := output.NewTableWriterFormatter(noColor)
+			tableFormatter.SetTableStyle(tableStyle)
+			fmt.Print(tableFormatter.FormatModelRates(rates))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "table", "Output format (table, json)")
+	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	cmd.Flags().StringVar(&tableStyle, "table-style", output.TableStyleUnicode, "Table style (unicode, ascii, markdown, minimal, borderless)")
+
+	return cmd
+}
+
+// effectiveRates returns the pricing costs of model are calculated with
+func effectiveRates(ctx context.Context, pricingService *pricing.Service, model string) types.ModelRates {
+	modelPricing, source := pricingService.ModelPrice(ctx, model)
+	rates := modelRates(model, source, modelPricing)
+	if tier, ok := modelPricing.LongContext(); ok {
+		longContext := modelRates("", "", tier)
+		rates.LongContext = &longContext
+	}
+	return rates
+}
+
+// modelRates converts the pricing of model to a pricing list row
+func modelRates(model string, source pricing.Source, p pricing.ModelPricing) types.ModelRates {
+	return types.ModelRates{
+		Model:              model,
+		Source:             string(source),
+		Input:              p.InputCostPerToken,
+		Output:             p.OutputCostPerToken,
+		CacheCreate:        p.CacheCreationInputTokenCost,
+		CacheRead:          p.CacheReadInputTokenCost,
+		InputPerMTok:       pricing.PerMTok(p.InputCostPerToken),
+		OutputPerMTok:      pricing.PerMTok(p.OutputCostPerToken),
+		CacheCreatePerMTok: pricing.PerMTok(p.CacheCreationInputTokenCost),
+		CacheReadPerMTok:   pricing.PerMTok(p.CacheReadInputTokenCost),
+	}
+}
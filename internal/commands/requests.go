@@ -0,0 +1,183 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/calculator"
+	"github.com/sdpower/ccusage-go/internal/loader"
+	"github.com/sdpower/ccusage-go/internal/output"
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/spf13/cobra"
+)
+
+func NewRequestsCommand() *cobra.Command {
+	var (
+		dataPath  string
+		noColor   bool
+		loadFlags loaderFlags
+		timezone  string
+		limit     int
+		follow    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "requests",
+		Short: "List individual requests with their tokens and cost",
+		Long: `List the most recent requests with their token usage and cost.
+
+With --follow, keep running and print each new request as it is written to the
+session logs, like tail -f for spend.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if limit < 0 {
+				return fmt.Errorf("--limit must not be negative, got %d", limit)
+			}
+
+			// Determine data path
+			if dataPath == "" {
+				dataPath = loadFlags.defaultDataPath()
+			}
+			if follow {
+				for _, root := range loader.SplitDataPaths(dataPath) {
+					if loader.IsRemoteDataPath(root) {
+						return fmt.Errorf("--follow cannot watch remote data path %s", root)
+					}
+				}
+			}
+
+			// Initialize services
+			pricingService := newPricingService()
+			calc := calculator.New(pricingService)
+			dataLoader := loader.New()
+			if err := loadFlags.configure(dataLoader, dataPath); err != nil {
+				return err
+			}
+			calc.SetMode(loadFlags.costMode)
+			calc.WarmPricing(cmd.Context())
+
+			formatter := output.NewTableWriterFormatter(noColor)
+			formatter.SetProjectNamer(loadFlags.config)
+
+			// Set timezone if specified
+			if timezone != "" {
+				loc, err := time.LoadLocation(timezone)
+				if err != nil {
+					return fmt.Errorf("invalid timezone %s: %w", timezone, err)
+				}
+				dataLoader.SetTimezone(loc)
+				formatter.SetTimezone(loc)
+			}
+
+			// Start the tailer before the initial load so nothing written in between is missed
+			started := time.Now()
+			var watcher *loader.Watcher
+			if follow {
+				var err error
+				watcher, err = loader.NewWatcher(dataPath)
+				if err != nil {
+					return fmt.Errorf("failed to watch data directory: %w", err)
+				}
+				defer watcher.Close()
+			}
+
+			fmt.Print(formatter.FormatRequestHeader())
+			if limit > 0 {
+				entries, err := dataLoader.LoadFromPath(cmd.Context(), dataPath)
+				if err != nil {
+					return fmt.Errorf("failed to load usage data: %w", err)
+				}
+				entries = recentRequests(entries, started, limit)
+				entries, err = calc.CalculateCosts(cmd.Context(), entries)
+				if err != nil {
+					return fmt.Errorf("failed to calculate costs: %w", err)
+				}
+				for _, entry := range entries {
+					fmt.Print(formatter.FormatRequestLine(entry))
+				}
+			}
+
+			if !follow {
+				return nil
+			}
+			return followRequests(cmd.Context(), watcher, dataLoader.NewTailer(started), func(entries []types.UsageEntry) error {
+				entries, err := calc.CalculateCosts(cmd.Context(), entries)
+				if err != nil {
+					return fmt.Errorf("failed to calculate costs: %w", err)
+				}
+				for _, entry := range entries {
+					fmt.Print(formatter.FormatRequestLine(entry))
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&dataPath, "data-path", "", "Path to Claude data directory (comma-separated; ssh://, s3:// and gs:// URLs are mirrored locally, - reads standard input)")
+	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	loadFlags.register(cmd)
+	cmd.Flags().StringVarP(&timezone, "timezone", "z", "", "Timezone for timestamps (e.g., UTC, America/New_York, Asia/Tokyo)")
+	cmd.Flags().IntVarP(&limit, "limit", "n", 20, "Number of recent requests to list first (0 for none)")
+	cmd.Flags().BoolVar(&follow, "follow", false, "Keep running and print new requests as they are logged")
+
+	return cmd
+}
+
+// recentRequests returns the last limit requests logged before cutoff, oldest first.
+// Later requests are left to the follow tailer.
+func recentRequests(entries []types.UsageEntry, cutoff time.Time, limit int) []types.UsageEntry {
+	recent := make([]types.UsageEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Timestamp.Before(cutoff) {
+			recent = append(recent, entry)
+		}
+	}
+	sort.SliceStable(recent, func(i, j int) bool {
+		return recent[i].Timestamp.Before(recent[j].Timestamp)
+	})
+	if len(recent) > limit {
+		recent = recent[len(recent)-limit:]
+	}
+	return recent
+}
+
+// followRequests passes the requests appended to changed files to emit until interrupted
+func followRequests(ctx context.Context, watcher *loader.Watcher, tailer *loader.Tailer, emit func([]types.UsageEntry) error) error {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sigChan:
+			return nil
+		case err := <-watcher.Errors():
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		case paths := <-watcher.Changes():
+			var entries []types.UsageEntry
+			for _, path := range paths {
+				fileEntries, err := tailer.Read(path)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+					continue
+				}
+				entries = append(entries, fileEntries...)
+			}
+			if len(entries) == 0 {
+				continue
+			}
+			sort.SliceStable(entries, func(i, j int) bool {
+				return entries[i].Timestamp.Before(entries[j].Timestamp)
+			})
+			if err := emit(entries); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+		}
+	}
+}
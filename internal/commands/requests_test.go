@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecentRequestsKeepsLatestBeforeCutoff(t *testing.T) {
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	entries := []types.UsageEntry{
+		{ID: "c", Timestamp: base.Add(2 * time.Minute)},
+		{ID: "a", Timestamp: base},
+		{ID: "late", Timestamp: base.Add(time.Hour)},
+		{ID: "b", Timestamp: base.Add(time.Minute)},
+	}
+
+	recent := recentRequests(entries, base.Add(time.Hour), 2)
+
+	var ids []string
+	for _, e := range recent {
+		ids = append(ids, e.ID)
+	}
+	assert.Equal(t, []string{"b", "c"}, ids)
+}
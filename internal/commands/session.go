@@ -7,7 +7,6 @@ import (
 	"github.com/sdpower/ccusage-go/internal/calculator"
 	"github.com/sdpower/ccusage-go/internal/loader"
 	"github.com/sdpower/ccusage-go/internal/output"
-	"github.com/sdpower/ccusage-go/internal/pricing"
 	"github.com/spf13/cobra"
 )
 
@@ -16,12 +15,17 @@ func NewSessionCommand() *cobra.Command {
 		format      string
 		dataPath    string
 		noColor     bool
+		loadFlags   loaderFlags
+		tableStyle  string
 		responsive  bool
 		timezone    string
 		since       string
 		until       string
 		sessionID   string
 		sessionName string
+		last        string
+		groupBy     string
+		watch       time.Duration
 	)
 
 	cmd := &cobra.Command{
@@ -29,19 +33,41 @@ func NewSessionCommand() *cobra.Command {
 		Short: "Generate session usage report",
 		Long:  `Generate a session-based usage report for Claude Code usage data.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := output.ValidateTableStyle(tableStyle); err != nil {
+				return err
+			}
+			window, err := parseLastWindow(last, since, until)
+			if err != nil {
+				return err
+			}
+			if groupBy != "" && groupBy != groupByConversation {
+				return fmt.Errorf("invalid --group-by %q (supported: %s)", groupBy, groupByConversation)
+			}
+			watching, err := watchInterval(cmd, watch)
+			if err != nil {
+				return err
+			}
+
 			// Determine data path
 			if dataPath == "" {
-				dataPath = getDefaultDataPath()
+				dataPath = loadFlags.defaultDataPath()
 			}
 
 			// Initialize services
-			pricingService := pricing.NewService()
+			pricingService := newPricingService()
 			calc := calculator.New(pricingService)
 			dataLoader := loader.New()
+			if err := loadFlags.configure(dataLoader, dataPath); err != nil {
+				return err
+			}
+			calc.SetMode(loadFlags.costMode)
+			calc.WarmPricing(cmd.Context())
 
 			// Set timezone if specified
+			loc := time.Local
 			if timezone != "" {
-				loc, err := time.LoadLocation(timezone)
+				var err error
+				loc, err = time.LoadLocation(timezone)
 				if err != nil {
 					return fmt.Errorf("invalid timezone %s: %w", timezone, err)
 				}
@@ -49,77 +75,115 @@ func NewSessionCommand() *cobra.Command {
 			}
 
 			formatter := output.NewFormatter(output.FormatterOptions{
-				Format:     format,
-				NoColor:    noColor,
-				Responsive: responsive,
+				Format:       format,
+				NoColor:      noColor,
+				Responsive:   responsive,
+				TableStyle:   tableStyle,
+				ProjectNames: loadFlags.config,
 			})
 
-			// Load data
-			entries, err := dataLoader.LoadFromPath(cmd.Context(), dataPath)
-			if err != nil {
-				return fmt.Errorf("failed to load usage data: %w", err)
-			}
+			report := func() error {
+				// A --last window ends at the time of each render
+				dataLoader.SetDateRange(reportDateRange(window, since, until, loc, time.Now()))
 
-			// Apply date filters if specified
-			if since != "" || until != "" {
-				entries = filterEntriesByDate(entries, since, until)
-			}
+				// Load data
+				entries, err := dataLoader.LoadFromPath(cmd.Context(), dataPath)
+				if err != nil {
+					return fmt.Errorf("failed to load usage data: %w", err)
+				}
 
-			// Apply session filters
-			if sessionID != "" {
-				entries = filterEntriesBySessionID(entries, sessionID)
-			}
-			if sessionName != "" {
-				entries = filterEntriesBySessionName(entries, sessionName)
-			}
-			if (sessionID != "" || sessionName != "") && len(entries) == 0 {
-				fmt.Println("No entries found for the specified session filter")
-				return nil
-			}
+				// Apply date filters if specified
+				if since != "" || until != "" {
+					entries = filterEntriesByDate(entries, since, until)
+				}
 
-			// Calculate costs
-			entries, err = calc.CalculateCosts(cmd.Context(), entries)
-			if err != nil {
-				return fmt.Errorf("failed to calculate costs: %w", err)
-			}
+				// Apply session filters
+				if sessionID != "" {
+					entries = filterEntriesBySessionID(entries, sessionID)
+				}
+				if sessionName != "" {
+					entries = filterEntriesBySessionName(entries, sessionName)
+				}
+				if (sessionID != "" || sessionName != "") && len(entries) == 0 {
+					fmt.Println("No entries found for the specified session filter")
+					return nil
+				}
 
-			// Generate session report
-			sessions := calc.GenerateSessionReport(entries)
+				// Calculate costs
+				entries, err = calc.CalculateCosts(cmd.Context(), entries)
+				if err != nil {
+					return fmt.Errorf("failed to calculate costs: %w", err)
+				}
 
-			// Detail mode: show per-file breakdown when filtering by session
-			isFiltered := sessionID != "" || sessionName != ""
-			if isFiltered && format == "table" {
-				fileStats := calc.AggregateBySourceFile(entries)
-				tableFormatter := output.NewTableWriterFormatter(noColor)
-				if timezone != "" {
-					loc, _ := time.LoadLocation(timezone)
+				// One row per conversation instead of per project
+				if groupBy == groupByConversation {
+					conversations := calc.GenerateConversationReport(entries)
+					if format != "table" {
+						result, err := formatter.FormatSessionReport(conversations)
+						if err != nil {
+							return fmt.Errorf("failed to format report: %w", err)
+						}
+						fmt.Print(result)
+						return nil
+					}
+					tableFormatter := output.NewTableWriterFormatter(noColor)
+					tableFormatter.SetTableStyle(tableStyle)
+					tableFormatter.SetProjectNamer(loadFlags.config)
 					tableFormatter.SetTimezone(loc)
+					fmt.Print(tableFormatter.FormatConversationReport(conversations))
+					return nil
 				}
-				result := tableFormatter.FormatSessionDetailReport(sessions, fileStats)
+
+				// Generate session report
+				sessions := calc.GenerateSessionReport(entries)
+
+				// Detail mode: show per-file breakdown when filtering by session
+				isFiltered := sessionID != "" || sessionName != ""
+				if isFiltered && format == "table" {
+					fileStats := calc.AggregateBySourceFile(entries)
+					tableFormatter := output.NewTableWriterFormatter(noColor)
+					tableFormatter.SetTableStyle(tableStyle)
+					tableFormatter.SetProjectNamer(loadFlags.config)
+					if timezone != "" {
+						loc, _ := time.LoadLocation(timezone)
+						tableFormatter.SetTimezone(loc)
+					}
+					result := tableFormatter.FormatSessionDetailReport(sessions, fileStats)
+					fmt.Print(result)
+					return nil
+				}
+
+				// Format and output
+				result, err := formatter.FormatSessionReport(sessions)
+				if err != nil {
+					return fmt.Errorf("failed to format report: %w", err)
+				}
+
 				fmt.Print(result)
 				return nil
 			}
 
-			// Format and output
-			result, err := formatter.FormatSessionReport(sessions)
-			if err != nil {
-				return fmt.Errorf("failed to format report: %w", err)
+			if !watching {
+				return report()
 			}
-
-			fmt.Print(result)
-			return nil
+			return watchReport(cmd.Context(), dataPath, watch, report)
 		},
 	}
 
 	cmd.Flags().StringVarP(&format, "format", "f", "table", "Output format (table, json, csv)")
-	cmd.Flags().StringVar(&dataPath, "data-path", "", "Path to Claude data directory")
+	cmd.Flags().StringVar(&dataPath, "data-path", "", "Path to Claude data directory (comma-separated; ssh://, s3:// and gs:// URLs are mirrored locally, - reads standard input)")
 	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	loadFlags.register(cmd)
+	cmd.Flags().StringVar(&tableStyle, "table-style", output.TableStyleUnicode, "Table style (unicode, ascii, markdown, minimal, borderless)")
 	cmd.Flags().BoolVar(&responsive, "responsive", true, "Enable responsive table layout")
 	cmd.Flags().StringVarP(&timezone, "timezone", "z", "", "Timezone for date grouping")
 	cmd.Flags().StringVar(&since, "since", "", "Start date (YYYY-MM-DD)")
 	cmd.Flags().StringVar(&until, "until", "", "End date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&last, "last", "", "Only count usage in a rolling window ending now (e.g. 7d, 30d, 24h)")
 	cmd.Flags().StringVar(&sessionID, "session-id", "", "Filter by session UUID")
 	cmd.Flags().StringVar(&sessionName, "session-name", "", "Filter by session name (exact match)")
+	cmd.Flags().StringVar(&groupBy, "group-by", "", "Report each conversation (sessionId) separately instead of grouping by project (conversation)")
+	registerWatch(cmd, &watch)
 
 	return cmd
 }
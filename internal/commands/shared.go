@@ -1,40 +1,509 @@
 package commands
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/sdpower/ccusage-go/internal/calculator"
+	"github.com/sdpower/ccusage-go/internal/config"
+	"github.com/sdpower/ccusage-go/internal/loader"
+	"github.com/sdpower/ccusage-go/internal/output"
+	"github.com/sdpower/ccusage-go/internal/plugin"
 	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/sdpower/ccusage-go/pkg/pricing"
+	"github.com/spf13/cobra"
 )
 
+// loaderFlags holds the data loading flags shared by the report commands
+type loaderFlags struct {
+	provider     string
+	noCache      bool
+	jobs         int
+	strict       bool
+	maxErrorRate float64
+	include      []string
+	exclude      []string
+	lowMemory    bool
+	keepRaw      bool
+	mode         string
+	quarantine   bool
+	synthetic    bool
+	plugins      []string
+
+	// Set by configure
+	config   *config.Config
+	filter   *loader.FileFilter
+	costMode calculator.CostMode
+}
+
+// register adds the shared data loading flags to cmd
+func (f *loaderFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.provider, "provider", providerClaude, "Usage logs to read when --data-path is not set (claude, codex, all)")
+	cmd.Flags().BoolVar(&f.noCache, "no-cache", false, "Disable the persistent parse cache, dedupe store and timestamp index and re-read all files")
+	cmd.Flags().IntVarP(&f.jobs, "jobs", "j", defaultJobs, "Number of files to parse concurrently")
+	cmd.Flags().BoolVar(&f.strict, "strict", false, "Fail when more than --max-error-rate percent of lines fail validation")
+	cmd.Flags().Float64Var(&f.maxErrorRate, "max-error-rate", 0, "Percentage of invalid lines tolerated by --strict (0-100)")
+	cmd.Flags().StringArrayVar(&f.include, "include", nil, "Only read files matching this glob, e.g. 'projects/foo*' (repeatable)")
+	cmd.Flags().StringArrayVar(&f.exclude, "exclude", nil, "Skip files matching this glob, e.g. '*scratch*' (repeatable)")
+	cmd.Flags().BoolVar(&f.quarantine, "quarantine", false, "Append lines that fail to parse to quarantine.jsonl in the cache directory ($XDG_CACHE_HOME/ccusage)")
+	cmd.Flags().BoolVar(&f.synthetic, "include-synthetic", false, "Count <synthetic> model entries (skipped by default), listed under the model \"other\"")
+	cmd.Flags().StringVar(&f.mode, "mode", string(calculator.CostModeAuto), "Cost source: auto (logged costUSD, else computed from tokens), calculate (always from tokens), display (logged costUSD only)")
+}
+
+// registerLowMemory adds --low-memory to commands whose reports are built from
+// streamed totals (see loader.LoadAndAggregate)
+func (f *loaderFlags) registerLowMemory(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&f.lowMemory, "low-memory", false, "Spill parsed entries to temporary files instead of keeping them in memory (table output only)")
+}
+
+// registerPlugins adds --plugin to commands whose reports take plugin sections
+func (f *loaderFlags) registerPlugins(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&f.plugins, "plugin", nil, "Add the sections of a registered aggregator or of a command configured under \"plugins\" (repeatable)")
+}
+
+// newPlugins creates the --plugin aggregators for one report; call after configure
+func (f *loaderFlags) newPlugins() (*plugin.Set, error) {
+	return plugin.New(f.plugins, f.config.PluginCommands())
+}
+
+// pluginAggregator feeds each loaded entry, with its cost calculated, to agg
+// and the plugins
+func pluginAggregator(calc *calculator.Calculator, agg loader.Aggregator, plugins *plugin.Set) loader.Aggregator {
+	if plugins.Len() == 0 {
+		return agg
+	}
+	return loader.AggregatorFunc(func(entry types.UsageEntry) {
+		calc.CalculateCost(&entry)
+		agg.Add(entry)
+		plugins.Add(entry)
+	})
+}
+
+// printPluginSections prints the sections of the plugins below a table report
+func printPluginSections(f *output.TableWriterFormatter, plugins *plugin.Set) error {
+	sections, err := plugins.Sections()
+	if err != nil {
+		return err
+	}
+	fmt.Print(f.FormatReportSections(sections))
+	return nil
+}
+
+// registerKeepRaw adds --keep-raw to commands whose JSON output lists entries
+func (f *loaderFlags) registerKeepRaw(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&f.keepRaw, "keep-raw", false, "Include each entry's original log line as \"raw\" in JSON output")
+}
+
+// configure applies the shared data loading flags to a loader that will read dataPath
+func (f *loaderFlags) configure(dataLoader *loader.Loader, dataPath string) error {
+	if f.jobs < 1 {
+		return fmt.Errorf("--jobs must be at least 1, got %d", f.jobs)
+	}
+	switch f.provider {
+	case providerClaude, providerCodex, providerAll:
+	default:
+		return fmt.Errorf("invalid --provider %q, use claude, codex or all", f.provider)
+	}
+	if f.maxErrorRate < 0 || f.maxErrorRate > 100 {
+		return fmt.Errorf("--max-error-rate must be between 0 and 100, got %g", f.maxErrorRate)
+	}
+	costMode, err := calculator.ParseCostMode(f.mode)
+	if err != nil {
+		return err
+	}
+	f.costMode = costMode
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	f.config = cfg
+	decimals := output.DefaultCostFormat.Decimals
+	if cfg.CostDisplay.Decimals != nil {
+		decimals = *cfg.CostDisplay.Decimals
+	}
+	costFormat, err := output.NewCostFormat(decimals, cfg.CostDisplay.Rounding)
+	if err != nil {
+		return fmt.Errorf("invalid cost_display in %s: %w", cfg.Path(), err)
+	}
+	output.SetCostFormat(costFormat)
+	exclude := append(append(cfg.Exclude, cfg.IgnoreGlobs()...), f.exclude...)
+	filter, err := loader.NewFileFilter(append(cfg.Include, f.include...), exclude)
+	if err != nil {
+		return err
+	}
+	f.filter = filter
+
+	dataLoader.SetMaxWorkers(f.jobs)
+	dataLoader.SetFileFilter(filter)
+	dataLoader.SetLowMemory(f.lowMemory)
+	dataLoader.SetKeepRaw(f.keepRaw)
+	dataLoader.SetIncludeSynthetic(f.synthetic)
+	if f.strict {
+		dataLoader.SetStrict(f.maxErrorRate)
+	}
+
+	if f.quarantine {
+		quarantinePath, err := loader.DefaultQuarantinePath()
+		if err != nil {
+			return fmt.Errorf("cannot locate the quarantine file: %w", err)
+		}
+		dataLoader.SetQuarantine(loader.OpenQuarantine(quarantinePath))
+	}
+
+	if f.noCache {
+		return nil
+	}
+	cachePath, err := loader.DefaultParseCachePath()
+	if err != nil {
+		return nil // No home directory: run without the parse cache
+	}
+	if !f.lowMemory { // The parse cache is held in memory in full
+		dataLoader.SetParseCache(loader.OpenParseCache(cachePath))
+	}
+	if storePath, err := loader.DefaultDedupeStorePath(dataPath); err == nil {
+		dataLoader.SetDedupeStore(loader.OpenDedupeStore(storePath))
+	}
+	if indexPath, err := loader.DefaultTimestampIndexPath(); err == nil {
+		dataLoader.SetTimestampIndex(loader.OpenTimestampIndex(indexPath))
+	}
+	return nil
+}
+
+// loaderDateRange converts --since/--until values (YYYYMMDD, YYYY-MM-DD or
+// YYYYMM) into the half-open range, with days starting at midnight in loc,
+// that the loader uses to skip files and drop entries outside it. Values that
+// do not parse leave that side open.
+func loaderDateRange(since, until string, loc *time.Location) (time.Time, time.Time) {
+	var start, end time.Time
+	if t, _, ok := parseRangeDate(since, loc); ok {
+		start = t
+	}
+	if t, month, ok := parseRangeDate(until, loc); ok {
+		if month {
+			end = t.AddDate(0, 1, 0)
+		} else {
+			end = t.AddDate(0, 0, 1)
+		}
+	}
+	return start, end
+}
+
+// parseRangeDate parses a --since/--until value, reporting whether it names a whole month
+func parseRangeDate(value string, loc *time.Location) (t time.Time, month bool, ok bool) {
+	for _, layout := range []string{"20060102", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, false, true
+		}
+	}
+	if t, err := time.ParseInLocation("200601", value, loc); err == nil {
+		return t, true, true
+	}
+	return time.Time{}, false, false
+}
+
+// parseLastWindow parses a --last value: a number of days or weeks (7d, 2w)
+// or a duration (24h, 90m). An empty value means no rolling window. --last
+// replaces --since/--until, so combining them is rejected.
+func parseLastWindow(last, since, until string) (time.Duration, error) {
+	if last == "" {
+		return 0, nil
+	}
+	if since != "" || until != "" {
+		return 0, fmt.Errorf("--last cannot be combined with --since/--until")
+	}
+	var window time.Duration
+	if n, err := strconv.Atoi(last[:len(last)-1]); err == nil && strings.HasSuffix(last, "d") {
+		window = time.Duration(n) * 24 * time.Hour
+	} else if err == nil && strings.HasSuffix(last, "w") {
+		window = time.Duration(n) * 7 * 24 * time.Hour
+	} else if d, err := time.ParseDuration(last); err == nil {
+		window = d
+	}
+	if window <= 0 {
+		return 0, fmt.Errorf("invalid --last %q, use e.g. 7d, 30d or 24h", last)
+	}
+	return window, nil
+}
+
+// reportDateRange returns the range a report's entries are loaded from: the
+// --last window ending at now, or the --since/--until dates without one
+func reportDateRange(window time.Duration, since, until string, loc *time.Location, now time.Time) (time.Time, time.Time) {
+	if window > 0 {
+		return now.Add(-window), now
+	}
+	return loaderDateRange(since, until, loc)
+}
+
+// Values of --group-by
+const (
+	groupByAgent = "agent" // Main conversation vs sub-agents (Task tool)
+	groupByCwd   = "cwd"   // Working directory recorded with each request
+
+	groupByModelFamily = "model-family" // Opus, Sonnet, Haiku, GPT regardless of version
+	groupByAccount     = "account"      // Logged account, else the labeled data directory
+
+	groupByConversation = "conversation" // session: one row per sessionId
+)
+
+// validateGroupBy checks a --group-by value
+func validateGroupBy(groupBy string) error {
+	switch groupBy {
+	case "", groupByAgent, groupByCwd, groupByModelFamily, groupByAccount:
+		return nil
+	}
+	return fmt.Errorf("invalid --group-by %q (supported: %s, %s, %s, %s)", groupBy, groupByAgent, groupByCwd, groupByModelFamily, groupByAccount)
+}
+
+// groupByKey returns the grouping of a non-empty --group-by value, naming
+// accounts after the data directories in cfg
+func groupByKey(groupBy string, cfg *config.Config) calculator.KeyFunc {
+	switch groupBy {
+	case groupByAccount:
+		return calculator.AccountKey(cfg.AccountNames())
+	case groupByCwd:
+		return calculator.CwdKey()
+	case groupByModelFamily:
+		return calculator.ModelFamilyKey()
+	}
+	return calculator.AgentKey()
+}
+
+// formatGroupBy renders the groups of a non-empty --group-by value
+func formatGroupBy(f *output.TableWriterFormatter, groupBy string, groups map[string]*calculator.GroupTotals) string {
+	switch groupBy {
+	case groupByCwd:
+		return f.FormatCwdGroups(groups)
+	case groupByModelFamily:
+		return f.FormatModelFamilyGroups(groups)
+	case groupByAccount:
+		return f.FormatAccountGroups(groups)
+	}
+	return f.FormatAgentGroups(groups)
+}
+
+// Values of --provider
+const (
+	providerClaude = "claude"
+	providerCodex  = "codex"
+	providerAll    = "all"
+)
+
+// reportMetadata returns report metadata carrying the stats of the last load
+// of dataLoader and of pricingService, for --debug --format json
+func reportMetadata(dataLoader *loader.Loader, pricingService *pricing.Service) *types.ReportMetadata {
+	loaderStats, pricingStats := dataLoader.Stats(), pricingService.Stats()
+	return &types.ReportMetadata{Loader: &loaderStats, Pricing: &pricingStats}
+}
+
+// debugPricing prints how pricingService priced the models of a report, for
+// --debug with table or CSV output
+func debugPricing(w io.Writer, pricingService *pricing.Service) {
+	stats := pricingService.Stats()
+	load := string(stats.Load)
+	if load == "" {
+		load = "not loaded"
+	}
+	fmt.Fprintf(w, "Debug: Pricing from %s (%s): %d disk cache hits, %d fetches (%d failed, %dms)\n",
+		stats.Backend, load, stats.DiskCacheHits, stats.Fetches, stats.FetchErrors, stats.FetchLatencyMs)
+	fmt.Fprintf(w, "Debug: %d price lookups, %d memoized; by source:", stats.Lookups, stats.MemoHits)
+	for _, source := range []pricing.Source{pricing.SourceOverride, pricing.SourceLiteLLM, pricing.SourceOpenRouter, pricing.SourceEmbedded, pricing.SourceDefault} {
+		if count := stats.BySource[source]; count > 0 {
+			fmt.Fprintf(w, " %s %d", source, count)
+		}
+	}
+	fmt.Fprintln(w)
+	for _, model := range stats.Models {
+		fmt.Fprintf(w, "  %-10s %s\n", model.Source, model.Model)
+	}
+}
+
+// defaultDataPath returns the data directories of the selected --provider.
+// Files are parsed according to their content, so an explicit --data-path may
+// mix Claude Code and Codex logs regardless of --provider.
+func (f *loaderFlags) defaultDataPath() string {
+	switch f.provider {
+	case providerCodex:
+		return getDefaultCodexPath()
+	case providerAll:
+		return loader.JoinDataPaths([]string{getDefaultDataPath(), getDefaultCodexPath()})
+	default:
+		return getDefaultDataPath()
+	}
+}
+
+// defaultJobs is the default number of files parsed concurrently
+var defaultJobs = runtime.NumCPU()
+
+// clearScreen moves the cursor home and clears the terminal
+const clearScreen = "\033[H\033[2J"
+
+// registerWatch adds --watch [interval] to cmd. Bare, the report is
+// re-rendered when usage files change; --watch 30s also re-renders it every
+// 30s, which picks up remote data paths the file watcher cannot see.
+// It sets cmd.Args, as the flag parser leaves the interval of --watch 30s
+// as an argument.
+func registerWatch(cmd *cobra.Command, interval *time.Duration) {
+	cmd.Flags().DurationVarP(interval, "watch", "w", 0, "Clear and re-render the report whenever usage files change, and every interval when one is given (--watch 30s)")
+	cmd.Flags().Lookup("watch").NoOptDefVal = "0s"
+	cmd.Args = func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return nil
+		}
+		if len(args) > 1 || !cmd.Flags().Changed("watch") || *interval != 0 {
+			return fmt.Errorf("unexpected argument %q", args[0])
+		}
+		d, err := time.ParseDuration(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid --watch interval %q", args[0])
+		}
+		*interval = d
+		return nil
+	}
+}
+
+// watchInterval returns whether --watch was given and its interval
+func watchInterval(cmd *cobra.Command, interval time.Duration) (bool, error) {
+	if !cmd.Flags().Changed("watch") {
+		return false, nil
+	}
+	if interval < 0 {
+		return false, fmt.Errorf("--watch interval must be positive")
+	}
+	return true, nil
+}
+
+// watchReport renders the report, then re-renders it each time JSONL files
+// under dataPath change, and every interval when it is not 0, until
+// interrupted
+func watchReport(ctx context.Context, dataPath string, interval time.Duration, render func() error) error {
+	watcher, err := loader.NewWatcher(dataPath)
+	if err != nil {
+		return fmt.Errorf("failed to watch data directory: %w", err)
+	}
+	defer watcher.Close()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	for {
+		fmt.Print(clearScreen)
+		if err := render(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+
+		var tick <-chan time.Time // nil, never fires, without an interval
+		if interval > 0 {
+			tick = time.After(interval)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sigChan:
+			return nil
+		case <-watcher.Changes():
+		case <-tick:
+		}
+	}
+}
+
+// getDefaultDataPath returns the Claude data directories to read, comma-separated.
+// CLAUDE_CONFIG_DIR may itself list several directories.
 func getDefaultDataPath() string {
 	// Check environment variable first
 	if claudeConfigDir := os.Getenv("CLAUDE_CONFIG_DIR"); claudeConfigDir != "" {
 		return claudeConfigDir
 	}
 
-	// Default paths based on Claude Code configuration
+	// Default paths based on Claude Code configuration. On Windows the home
+	// directory is %USERPROFILE%.
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return "."
 	}
 
-	// Check ~/.claude/projects first
+	// Read both ~/.claude/projects and ~/.config/claude/projects when present
 	claudePath := filepath.Join(homeDir, ".claude", "projects")
-	if _, err := os.Stat(claudePath); err == nil {
-		return claudePath
+	configPath := filepath.Join(homeDir, ".config", "claude", "projects")
+
+	candidates := []string{claudePath, configPath}
+	// Under WSL, Claude Code may also run on the Windows side
+	for _, winHome := range wslWindowsHomes() {
+		candidates = append(candidates, filepath.Join(winHome, ".claude", "projects"))
 	}
 
-	// Check ~/.config/claude/projects
-	configPath := filepath.Join(homeDir, ".config", "claude", "projects")
-	if _, err := os.Stat(configPath); err == nil {
-		return configPath
+	var paths []string
+	for _, p := range candidates {
+		if _, err := os.Stat(p); err == nil {
+			paths = append(paths, p)
+		}
+	}
+	if len(paths) > 0 {
+		return loader.JoinDataPaths(paths)
 	}
 
 	// Fall back to ~/.claude/projects as default
 	return claudePath
 }
 
+// wslUsersDir is where WSL mounts the Windows user profiles
+var wslUsersDir = "/mnt/c/Users"
+
+// isWSL reports whether we run under the Windows Subsystem for Linux
+func isWSL() bool {
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		return true
+	}
+	release, err := os.ReadFile("/proc/sys/kernel/osrelease")
+	return err == nil && strings.Contains(strings.ToLower(string(release)), "microsoft")
+}
+
+// wslWindowsHomes returns the Windows user profile directories visible from
+// WSL, skipping the built-in profiles that never hold Claude data
+func wslWindowsHomes() []string {
+	if !isWSL() {
+		return nil
+	}
+	entries, err := os.ReadDir(wslUsersDir)
+	if err != nil {
+		return nil
+	}
+	var homes []string
+	for _, entry := range entries {
+		switch entry.Name() {
+		case "Public", "Default", "Default User", "All Users":
+			continue
+		}
+		if entry.IsDir() {
+			homes = append(homes, filepath.Join(wslUsersDir, entry.Name()))
+		}
+	}
+	return homes
+}
+
+// getDefaultCodexPath returns the OpenAI Codex CLI session log directory
+// ($CODEX_HOME/sessions, defaulting to ~/.codex/sessions)
+func getDefaultCodexPath() string {
+	if codexHome := os.Getenv("CODEX_HOME"); codexHome != "" {
+		return filepath.Join(codexHome, "sessions")
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "."
+	}
+	return filepath.Join(homeDir, ".codex", "sessions")
+}
+
 func filterEntriesBySessionID(entries []types.UsageEntry, sessionID string) []types.UsageEntry {
 	var filtered []types.UsageEntry
 	for _, entry := range entries {
@@ -0,0 +1,159 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/loader"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoaderDateRange(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	since, until := loaderDateRange("20250301", "2025-03-31", time.UTC)
+	assert.Equal(t, day(2025, 3, 1), since)
+	assert.Equal(t, day(2025, 4, 1), until)
+
+	since, until = loaderDateRange("202501", "202502", time.UTC)
+	assert.Equal(t, day(2025, 1, 1), since)
+	assert.Equal(t, day(2025, 3, 1), until, "a month bound covers the whole month")
+
+	tokyo := time.FixedZone("JST", 9*60*60)
+	since, until = loaderDateRange("20250301", "", tokyo)
+	assert.Equal(t, time.Date(2025, 2, 28, 15, 0, 0, 0, time.UTC), since.UTC(), "days start at midnight in the report timezone")
+	assert.True(t, until.IsZero())
+
+	since, until = loaderDateRange("", "not-a-date", time.UTC)
+	assert.True(t, since.IsZero())
+	assert.True(t, until.IsZero())
+}
+
+func TestDefaultDataPathIncludesWSLWindowsHome(t *testing.T) {
+	home := t.TempDir()
+	users := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".claude", "projects"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(users, "me", ".claude", "projects"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(users, "Public", ".claude", "projects"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(users, "other"), 0o755))
+
+	t.Setenv("HOME", home)
+	t.Setenv("CLAUDE_CONFIG_DIR", "")
+	t.Setenv("WSL_DISTRO_NAME", "Ubuntu")
+	saved := wslUsersDir
+	wslUsersDir = users
+	t.Cleanup(func() { wslUsersDir = saved })
+
+	assert.Equal(t, loader.JoinDataPaths([]string{
+		filepath.Join(home, ".claude", "projects"),
+		filepath.Join(users, "me", ".claude", "projects"),
+	}), getDefaultDataPath())
+}
+
+func TestParseLastWindow(t *testing.T) {
+	for value, want := range map[string]time.Duration{
+		"":    0,
+		"7d":  7 * 24 * time.Hour,
+		"2w":  14 * 24 * time.Hour,
+		"24h": 24 * time.Hour,
+		"90m": 90 * time.Minute,
+	} {
+		window, err := parseLastWindow(value, "", "")
+		require.NoError(t, err, value)
+		assert.Equal(t, want, window, value)
+	}
+
+	for _, value := range []string{"d", "0d", "-1h", "week"} {
+		_, err := parseLastWindow(value, "", "")
+		assert.Error(t, err, value)
+	}
+
+	_, err := parseLastWindow("7d", "20250301", "")
+	assert.Error(t, err, "--last replaces --since/--until")
+
+	now := time.Date(2025, 3, 10, 15, 30, 0, 0, time.UTC)
+	since, until := reportDateRange(24*time.Hour, "", "", time.UTC, now)
+	assert.Equal(t, now.Add(-24*time.Hour), since)
+	assert.Equal(t, now, until)
+}
+
+func TestWatchFlag(t *testing.T) {
+	parse := func(args ...string) (bool, time.Duration, error) {
+		var interval time.Duration
+		var watching bool
+		cmd := &cobra.Command{
+			RunE: func(cmd *cobra.Command, args []string) (err error) {
+				watching, err = watchInterval(cmd, interval)
+				return err
+			},
+			SilenceErrors: true,
+			SilenceUsage:  true,
+		}
+		registerWatch(cmd, &interval)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return watching, interval, err
+	}
+
+	watching, _, err := parse()
+	require.NoError(t, err)
+	assert.False(t, watching)
+
+	watching, interval, err := parse("--watch")
+	require.NoError(t, err)
+	assert.True(t, watching)
+	assert.Zero(t, interval, "a bare --watch only follows file changes")
+
+	watching, interval, err = parse("-w")
+	require.NoError(t, err)
+	assert.True(t, watching)
+	assert.Zero(t, interval)
+
+	watching, interval, err = parse("--watch=30s")
+	require.NoError(t, err)
+	assert.True(t, watching)
+	assert.Equal(t, 30*time.Second, interval)
+
+	// The interval may follow a bare --watch as a separate argument
+	watching, interval, err = parse("--watch", "30s")
+	require.NoError(t, err)
+	assert.True(t, watching)
+	assert.Equal(t, 30*time.Second, interval)
+
+	watching, interval, err = parse("-w", "1m")
+	require.NoError(t, err)
+	assert.True(t, watching)
+	assert.Equal(t, time.Minute, interval)
+
+	_, _, err = parse("--watch=-1s")
+	assert.Error(t, err)
+	_, _, err = parse("--watch", "-1s")
+	assert.Error(t, err)
+	_, _, err = parse("--watch", "soon")
+	assert.ErrorContains(t, err, `invalid --watch interval "soon"`)
+	_, _, err = parse("30s")
+	assert.ErrorContains(t, err, `unexpected argument "30s"`)
+	_, _, err = parse("--watch=10s", "30s")
+	assert.Error(t, err)
+}
+
+func TestWatchReportRerendersEveryInterval(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	renders := 0
+	err := watchReport(ctx, t.TempDir(), 10*time.Millisecond, func() error {
+		renders++
+		if renders == 3 {
+			cancel()
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, renders, "without file changes, the interval alone re-renders")
+}
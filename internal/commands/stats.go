@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/calculator"
+	"github.com/sdpower/ccusage-go/internal/loader"
+	"github.com/sdpower/ccusage-go/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func NewStatsCommand() *cobra.Command {
+	var (
+		format     string
+		dataPath   string
+		noColor    bool
+		loadFlags  loaderFlags
+		tableStyle string
+		timezone   string
+		since      string
+		until      string
+		last       string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show when and how regularly usage happens",
+		Long: `Total tokens and cost by hour of the day (in --timezone) and by day of the
+week across the selected date range, to see when most of the quota is used and
+how usage on weekdays compares with the weekend, followed by usage streaks
+(consecutive active days), the busiest week and active hours per day.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := output.ValidateTableStyle(tableStyle); err != nil {
+				return err
+			}
+			if format != "table" && format != "json" {
+				return fmt.Errorf("invalid --format %q, use table or json", format)
+			}
+			window, err := parseLastWindow(last, since, until)
+			if err != nil {
+				return err
+			}
+
+			// Determine data path
+			if dataPath == "" {
+				dataPath = loadFlags.defaultDataPath()
+			}
+
+			// Initialize services
+			pricingService := newPricingService()
+			calc := calculator.New(pricingService)
+			dataLoader := loader.New()
+			if err := loadFlags.configure(dataLoader, dataPath); err != nil {
+				return err
+			}
+			calc.SetMode(loadFlags.costMode)
+			calc.WarmPricing(cmd.Context())
+
+			loc := time.Local
+			if timezone != "" {
+				loc, err = time.LoadLocation(timezone)
+				if err != nil {
+					return fmt.Errorf("invalid timezone %s: %w", timezone, err)
+				}
+				dataLoader.SetTimezone(loc)
+			}
+			calc.SetTimezone(loc)
+			dataLoader.SetDateRange(reportDateRange(window, since, until, loc, time.Now()))
+
+			entries, err := dataLoader.LoadFromPath(cmd.Context(), dataPath)
+			if err != nil {
+				return fmt.Errorf("failed to load usage data: %w", err)
+			}
+			entries, err = calc.CalculateCosts(cmd.Context(), entries)
+			if err != nil {
+				return fmt.Errorf("failed to calculate costs: %w", err)
+			}
+			hours := calc.HourOfDayProfile(entries)
+			days := calc.DayOfWeekProfile(entries)
+			streaks := calc.UsageStreaks(entries, time.Now())
+
+			if format == "json" {
+				weekdays, weekend := calculator.SplitWeekend(days)
+				result, err := output.NewFormatter(output.FormatterOptions{Format: format}).FormatJSON(map[string]interface{}{
+					"hours":    hours,
+					"days":     days,
+					"weekdays": weekdays,
+					"weekend":  weekend,
+					"streaks":  streaks,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to format report: %w", err)
+				}
+				fmt.Print(result)
+				return nil
+			}
+
+			tableFormatter := output.NewTableWriterFormatter(noColor)
+			tableFormatter.SetTableStyle(tableStyle)
+			fmt.Print(tableFormatter.FormatHourOfDayProfile(hours))
+			fmt.Print(tableFormatter.FormatDayOfWeekProfile(days))
+			fmt.Print(tableFormatter.FormatUsageStreaks(streaks))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "table", "Output format (table, json)")
+	cmd.Flags().StringVar(&dataPath, "data-path", "", "Path to Claude data directory (comma-separated; ssh://, s3:// and gs:// URLs are mirrored locally, - reads standard input)")
+	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	loadFlags.register(cmd)
+	cmd.Flags().StringVar(&tableStyle, "table-style", output.TableStyleUnicode, "Table style (unicode, ascii, markdown, minimal, borderless)")
+	cmd.Flags().StringVarP(&timezone, "timezone", "z", "", "Timezone of the hours, days and --since/--until dates")
+	cmd.Flags().StringVarP(&since, "since", "s", "", "Filter from date (YYYYMMDD format)")
+	cmd.Flags().StringVarP(&until, "until", "u", "", "Filter until date (YYYYMMDD format)")
+	cmd.Flags().StringVar(&last, "last", "", "Only count usage in a rolling window ending now (e.g. 7d, 30d, 24h)")
+
+	return cmd
+}
@@ -9,7 +9,6 @@ import (
 	"github.com/sdpower/ccusage-go/internal/calculator"
 	"github.com/sdpower/ccusage-go/internal/loader"
 	"github.com/sdpower/ccusage-go/internal/output"
-	"github.com/sdpower/ccusage-go/internal/pricing"
 	"github.com/spf13/cobra"
 )
 
@@ -19,6 +18,7 @@ func NewWeeklyCommand() *cobra.Command {
 		format     string
 		dataPath   string
 		noColor    bool
+		loadFlags  loaderFlags
 		responsive bool
 	)
 
@@ -54,15 +54,24 @@ func NewWeeklyCommand() *cobra.Command {
 				}
 			}
 
+			if loadFlags.keepRaw && format != "json" {
+				return fmt.Errorf("--keep-raw only applies to --format json")
+			}
+
 			// Determine data path
 			if dataPath == "" {
-				dataPath = getDefaultDataPath()
+				dataPath = loadFlags.defaultDataPath()
 			}
 
 			// Initialize services
-			pricingService := pricing.NewService()
+			pricingService := newPricingService()
 			calc := calculator.New(pricingService)
 			dataLoader := loader.New()
+			if err := loadFlags.configure(dataLoader, dataPath); err != nil {
+				return err
+			}
+			calc.SetMode(loadFlags.costMode)
+			calc.WarmPricing(cmd.Context())
 
 			formatter := output.NewFormatter(output.FormatterOptions{
 				Format:     format,
@@ -98,8 +107,10 @@ func NewWeeklyCommand() *cobra.Command {
 
 	cmd.Flags().StringVarP(&week, "week", "w", "", "Week to generate report for (YYYY-WNN, defaults to current week)")
 	cmd.Flags().StringVarP(&format, "format", "f", "table", "Output format (table, json, csv)")
-	cmd.Flags().StringVar(&dataPath, "data-path", "", "Path to Claude data directory")
+	cmd.Flags().StringVar(&dataPath, "data-path", "", "Path to Claude data directory (comma-separated; ssh://, s3:// and gs:// URLs are mirrored locally, - reads standard input)")
 	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	loadFlags.register(cmd)
+	loadFlags.registerKeepRaw(cmd)
 	cmd.Flags().BoolVar(&responsive, "responsive", true, "Enable responsive table layout")
 
 	return cmd
@@ -0,0 +1,349 @@
+// Package config loads the optional user configuration file
+// (~/.config/ccusage/config.json) and project ignore list (~/.ccusageignore).
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Config is the user configuration. Every section is optional.
+type Config struct {
+	// ProjectNames overrides the display name derived from project directories.
+	// The first matching rule wins.
+	ProjectNames []ProjectNameRule `json:"project_names,omitempty"`
+
+	// Include and Exclude are file globs applied to every report, in addition
+	// to the --include and --exclude flags
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+
+	// IgnoreProjects lists project directories left out of every report,
+	// either as working directories ("/Users/me/tmp/*", "~/scratch") or as
+	// Claude project directory names ("-Users-me-scratch"). Globs are allowed.
+	// Load adds the lines of ~/.ccusageignore.
+	IgnoreProjects []string `json:"ignore_projects,omitempty"`
+
+	// Accounts names data directories for --group-by account, e.g.
+	// {"~/.claude": "me", "/mnt/alice/.claude": "alice"}
+	Accounts map[string]string `json:"accounts,omitempty"`
+
+	// CostDisplay sets how report tables show dollar amounts
+	CostDisplay CostDisplay `json:"cost_display,omitempty"`
+
+	// PricingFile overrides or extends model pricing like --pricing-file. A
+	// relative path is resolved against the configuration file's directory.
+	PricingFile string `json:"pricing_file,omitempty"`
+
+	// PricingURL fetches LiteLLM pricing from another location, e.g. an
+	// internal mirror; PricingCommit pins it to a LiteLLM commit instead, for
+	// reproducible reports. $CCUSAGE_PRICING_URL and $CCUSAGE_PRICING_COMMIT
+	// take precedence.
+	PricingURL    string `json:"pricing_url,omitempty"`
+	PricingCommit string `json:"pricing_commit,omitempty"`
+
+	// PricingSource is the default of --pricing-source: litellm or openrouter
+	PricingSource string `json:"pricing_source,omitempty"`
+
+	// Proxy and CABundle set how LiteLLM pricing is fetched, like --proxy and
+	// --ca-bundle; a relative CABundle is resolved like PricingFile
+	Proxy    string `json:"proxy,omitempty"`
+	CABundle string `json:"ca_bundle,omitempty"`
+
+	// PricingTimeout limits fetching LiteLLM pricing like --pricing-timeout,
+	// e.g. "5s"
+	PricingTimeout string `json:"pricing_timeout,omitempty"`
+
+	// WarnAt and CriticalAt are the defaults of --warn-at and --critical-at:
+	// the percentages of the token limit at which block usage turns yellow
+	// and red, and alerts fire
+	WarnAt     float64 `json:"warn_at,omitempty"`
+	CriticalAt float64 `json:"critical_at,omitempty"`
+
+	// Plugins maps --plugin names to external commands (argv) that receive
+	// report entries, e.g. {"team-rollup": ["python3", "~/bin/rollup.py"]}.
+	// See plugin.Exec for the protocol.
+	Plugins map[string][]string `json:"plugins,omitempty"`
+
+	path string
+}
+
+// CostDisplay is the precision of displayed costs. Decimals defaults to 2;
+// Rounding is nearest (default), up (no nonzero cost shows as $0.00) or down.
+// Currency is the default of --currency, Billing of --billing (api or
+// subscription).
+type CostDisplay struct {
+	Decimals *int   `json:"decimals,omitempty"`
+	Rounding string `json:"rounding,omitempty"`
+	Currency string `json:"currency,omitempty"`
+	Billing  string `json:"billing,omitempty"`
+}
+
+// ProjectNameRule maps a project to a display name. Path matches exactly
+// against the project directory path, its directory name (e.g.
+// "-Users-me-src-app") or the working directory it was created for
+// ("/Users/me/src/app").
+// Regex matches anywhere in the project directory path, and Name may refer to
+// its capture groups as $1, ${name}, ...
+type ProjectNameRule struct {
+	Path  string `json:"path,omitempty"`
+	Regex string `json:"regex,omitempty"`
+	Name  string `json:"name"`
+
+	re *regexp.Regexp
+}
+
+// DefaultPath returns the configuration file location: $CCUSAGE_CONFIG if set,
+// otherwise ccusage/config.json under $XDG_CONFIG_HOME (default ~/.config)
+func DefaultPath() (string, error) {
+	if p := os.Getenv("CCUSAGE_CONFIG"); p != "" {
+		return p, nil
+	}
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		configHome = filepath.Join(homeDir, ".config")
+	}
+	return filepath.Join(configHome, "ccusage", "config.json"), nil
+}
+
+// DefaultIgnorePath returns the location of the project ignore list, ~/.ccusageignore
+func DefaultIgnorePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".ccusageignore"), nil
+}
+
+// Load reads the configuration at the default path and adds the project
+// ignore list. Missing files yield an empty configuration.
+func Load() (*Config, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return &Config{}, nil // No home directory: nothing to load
+	}
+	cfg, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if ignorePath, err := DefaultIgnorePath(); err == nil {
+		patterns, err := LoadIgnoreFile(ignorePath)
+		if err != nil {
+			return nil, err
+		}
+		cfg.IgnoreProjects = append(cfg.IgnoreProjects, patterns...)
+	}
+	return cfg, nil
+}
+
+// LoadIgnoreFile reads a project ignore list: one IgnoreProjects pattern per
+// line, skipping blank lines and # comments. A missing file yields no patterns.
+func LoadIgnoreFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ignore list %s: %w", path, err)
+	}
+	var patterns []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, nil
+}
+
+// LoadFile reads and validates the configuration at path. A missing file
+// yields an empty configuration.
+func LoadFile(path string) (*Config, error) {
+	cfg := &Config{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Path returns the file the configuration was loaded from
+func (c *Config) Path() string {
+	return c.path
+}
+
+func (c *Config) validate() error {
+	for i := range c.ProjectNames {
+		rule := &c.ProjectNames[i]
+		if rule.Name == "" {
+			return fmt.Errorf("project_names[%d]: name is required", i)
+		}
+		if (rule.Path == "") == (rule.Regex == "") {
+			return fmt.Errorf("project_names[%d]: set exactly one of path or regex", i)
+		}
+		if rule.Regex != "" {
+			re, err := regexp.Compile(rule.Regex)
+			if err != nil {
+				return fmt.Errorf("project_names[%d]: %w", i, err)
+			}
+			rule.re = re
+		}
+	}
+	for name, args := range c.Plugins {
+		if len(args) == 0 || args[0] == "" {
+			return fmt.Errorf("plugins[%q]: command is required", name)
+		}
+	}
+	if c.PricingTimeout != "" {
+		if d, err := time.ParseDuration(c.PricingTimeout); err != nil || d <= 0 {
+			return fmt.Errorf("pricing_timeout: invalid duration %q, use e.g. 5s", c.PricingTimeout)
+		}
+	}
+	return nil
+}
+
+// PricingFilePath returns PricingFile resolved by resolvePath, "" when unset
+func (c *Config) PricingFilePath() string {
+	if c == nil {
+		return ""
+	}
+	return c.resolvePath(c.PricingFile)
+}
+
+// CABundlePath returns CABundle resolved by resolvePath, "" when unset
+func (c *Config) CABundlePath() string {
+	if c == nil {
+		return ""
+	}
+	return c.resolvePath(c.CABundle)
+}
+
+// PricingFetchTimeout returns PricingTimeout, 0 when unset
+func (c *Config) PricingFetchTimeout() time.Duration {
+	if c == nil {
+		return 0
+	}
+	d, _ := time.ParseDuration(c.PricingTimeout) // Checked by validate
+	return d
+}
+
+// resolvePath expands ~ in a configured file path and resolves a relative
+// one against the configuration file's directory
+func (c *Config) resolvePath(path string) string {
+	if path == "" {
+		return ""
+	}
+	path = expandHome(path)
+	if !filepath.IsAbs(path) && c.path != "" {
+		path = filepath.Join(filepath.Dir(c.path), path)
+	}
+	return path
+}
+
+// PluginCommands returns Plugins with ~ expanded in each command's arguments
+func (c *Config) PluginCommands() map[string][]string {
+	if c == nil {
+		return nil
+	}
+	commands := make(map[string][]string, len(c.Plugins))
+	for name, args := range c.Plugins {
+		expanded := make([]string, len(args))
+		for i, arg := range args {
+			expanded[i] = expandHome(arg)
+		}
+		commands[name] = expanded
+	}
+	return commands
+}
+
+// ProjectName returns the configured display name for a project directory
+// path, or false when no rule matches
+func (c *Config) ProjectName(projectPath string) (string, bool) {
+	if c == nil || projectPath == "" {
+		return "", false
+	}
+	dirName := filepath.Base(projectPath)
+	for _, rule := range c.ProjectNames {
+		if rule.re != nil {
+			if m := rule.re.FindStringSubmatchIndex(projectPath); m != nil {
+				return string(rule.re.ExpandString(nil, rule.Name, projectPath, m)), true
+			}
+			continue
+		}
+		if rule.Path == projectPath || rule.Path == dirName || encodeProjectDir(rule.Path) == dirName {
+			return rule.Name, true
+		}
+	}
+	return "", false
+}
+
+// AccountNames returns Accounts keyed by cleaned, slash-separated data
+// directory paths with ~ expanded, as calculator.DataRoot reports them
+func (c *Config) AccountNames() map[string]string {
+	if c == nil {
+		return nil
+	}
+	names := make(map[string]string, len(c.Accounts))
+	for dir, name := range c.Accounts {
+		names[filepath.ToSlash(filepath.Clean(expandHome(dir)))] = name
+	}
+	return names
+}
+
+// IgnoreGlobs returns IgnoreProjects as file globs for the loader's exclude
+// list, each matching everything below a projects/<directory>
+func (c *Config) IgnoreGlobs() []string {
+	if c == nil {
+		return nil
+	}
+	var globs []string
+	for _, pattern := range c.IgnoreProjects {
+		pattern = expandHome(pattern)
+		if strings.ContainsAny(pattern, `/\`) {
+			pattern = projectDirGlobUnsafe.ReplaceAllString(pattern, "-")
+		}
+		globs = append(globs, "projects/"+pattern)
+	}
+	return globs
+}
+
+// expandHome replaces a leading ~/ with the home directory
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(homeDir, path[2:])
+		}
+	}
+	return path
+}
+
+var projectDirUnsafe = regexp.MustCompile(`[^A-Za-z0-9-]`)
+
+// projectDirGlobUnsafe is projectDirUnsafe sparing glob metacharacters
+var projectDirGlobUnsafe = regexp.MustCompile(`[^A-Za-z0-9*?\[\]-]`)
+
+// encodeProjectDir returns the directory name Claude Code stores a working
+// directory's sessions under ("/Users/me/src/app" → "-Users-me-src-app")
+func encodeProjectDir(workDir string) string {
+	return projectDirUnsafe.ReplaceAllString(workDir, "-")
+}
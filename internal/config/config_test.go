@@ -0,0 +1,184 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLoadFileMissingIsEmpty(t *testing.T) {
+	cfg, err := LoadFile(filepath.Join(t.TempDir(), "missing.json"))
+	require.NoError(t, err)
+
+	_, ok := cfg.ProjectName("/home/me/.claude/projects/-Users-me-app")
+	assert.False(t, ok)
+}
+
+func TestProjectNameRules(t *testing.T) {
+	cfg, err := LoadFile(writeConfig(t, `{
+		"project_names": [
+			{"path": "/Users/me/work/api-server", "name": "API"},
+			{"path": "-Users-me-scratch", "name": "Scratch"},
+			{"regex": "-Users-me-src-(\\w+)$", "name": "src/$1"}
+		]
+	}`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		projectPath string
+		want        string
+		ok          bool
+	}{
+		{"/home/me/.claude/projects/-Users-me-work-api-server", "API", true},
+		{"/home/me/.claude/projects/-Users-me-scratch", "Scratch", true},
+		{"/home/me/.claude/projects/-Users-me-src-billing", "src/billing", true},
+		{"/home/me/.claude/projects/-Users-me-other", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := cfg.ProjectName(tt.projectPath)
+		assert.Equal(t, tt.ok, ok, tt.projectPath)
+		assert.Equal(t, tt.want, got, tt.projectPath)
+	}
+}
+
+func TestLoadFileRejectsInvalidRules(t *testing.T) {
+	for _, content := range []string{
+		`{"project_names": [{"path": "/a"}]}`,
+		`{"project_names": [{"name": "x"}]}`,
+		`{"project_names": [{"path": "/a", "regex": "a", "name": "x"}]}`,
+		`{"project_names": [{"regex": "(", "name": "x"}]}`,
+		`{"plugins": {"rollup": []}}`,
+		`{"project_names": `,
+	} {
+		_, err := LoadFile(writeConfig(t, content))
+		assert.Error(t, err, content)
+	}
+}
+
+func TestDefaultPathHonorsEnvironment(t *testing.T) {
+	t.Setenv("CCUSAGE_CONFIG", "")
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+	path, err := DefaultPath()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/tmp/xdg", "ccusage", "config.json"), path)
+
+	t.Setenv("CCUSAGE_CONFIG", "/etc/ccusage.json")
+	path, err = DefaultPath()
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/ccusage.json", path)
+}
+
+func TestLoadFileReadsFileGlobs(t *testing.T) {
+	cfg, err := LoadFile(writeConfig(t, `{"include": ["projects/work-*"], "exclude": ["*scratch*"]}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"projects/work-*"}, cfg.Include)
+	assert.Equal(t, []string{"*scratch*"}, cfg.Exclude)
+}
+
+func TestLoadIgnoreFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".ccusageignore")
+	require.NoError(t, os.WriteFile(path, []byte("# throwaway clones\n/Users/me/tmp/*\n\n  -Users-me-scratch  \n"), 0o644))
+
+	patterns, err := LoadIgnoreFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/Users/me/tmp/*", "-Users-me-scratch"}, patterns)
+
+	patterns, err = LoadIgnoreFile(filepath.Join(t.TempDir(), "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, patterns)
+}
+
+func TestIgnoreGlobs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg := &Config{IgnoreProjects: []string{
+		"/Users/me/src/experiments-*",
+		"-Users-me-scratch",
+		"~/clones/[ab]*",
+	}}
+	assert.Equal(t, []string{
+		"projects/-Users-me-src-experiments-*",
+		"projects/-Users-me-scratch",
+		"projects/" + encodeProjectDir(home) + "-clones-[ab]*",
+	}, cfg.IgnoreGlobs())
+}
+
+func TestCostDisplay(t *testing.T) {
+	cfg, err := LoadFile(writeConfig(t, `{"cost_display": {"decimals": 0, "rounding": "up"}}`))
+	require.NoError(t, err)
+	require.NotNil(t, cfg.CostDisplay.Decimals)
+	assert.Equal(t, 0, *cfg.CostDisplay.Decimals, "an explicit 0 is kept")
+	assert.Equal(t, "up", cfg.CostDisplay.Rounding)
+
+	cfg, err = LoadFile(writeConfig(t, `{}`))
+	require.NoError(t, err)
+	assert.Nil(t, cfg.CostDisplay.Decimals)
+}
+
+func TestAccountNames(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg, err := LoadFile(writeConfig(t, `{"accounts": {"~/.claude": "me", "/mnt/alice/.claude/": "alice"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		filepath.ToSlash(filepath.Join(home, ".claude")): "me",
+		"/mnt/alice/.claude":                             "alice",
+	}, cfg.AccountNames())
+
+	var none *Config
+	assert.Empty(t, none.AccountNames())
+}
+
+func TestPluginCommands(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg, err := LoadFile(writeConfig(t, `{"plugins": {"rollup": ["python3", "~/bin/rollup.py"]}}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"rollup": {"python3", filepath.Join(home, "bin", "rollup.py")},
+	}, cfg.PluginCommands())
+}
+
+func TestPricingPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	path := writeConfig(t, `{"pricing_file": "rates.json"}`)
+	cfg, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(filepath.Dir(path), "rates.json"), cfg.PricingFilePath())
+
+	cfg, err = LoadFile(writeConfig(t, `{"pricing_file": "~/rates.json"}`))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "rates.json"), cfg.PricingFilePath())
+
+	assert.Empty(t, (&Config{}).PricingFilePath())
+
+	path = writeConfig(t, `{"proxy": "http://proxy:8080", "ca_bundle": "ca.pem"}`)
+	cfg, err = LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "http://proxy:8080", cfg.Proxy)
+	assert.Equal(t, filepath.Join(filepath.Dir(path), "ca.pem"), cfg.CABundlePath())
+
+	cfg, err = LoadFile(writeConfig(t, `{"pricing_timeout": "3s"}`))
+	require.NoError(t, err)
+	assert.Equal(t, 3*time.Second, cfg.PricingFetchTimeout())
+	assert.Zero(t, (&Config{}).PricingFetchTimeout())
+	_, err = LoadFile(writeConfig(t, `{"pricing_timeout": "soon"}`))
+	assert.ErrorContains(t, err, "pricing_timeout")
+}
@@ -0,0 +1,238 @@
+// Package currency converts US dollar costs to other currencies with the
+// European Central Bank's daily reference rates.
+package currency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/cachedir"
+)
+
+// DefaultSourceURL is the ECB's euro reference rates of the last business day
+const DefaultSourceURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+
+// fetchTimeout limits the rates download
+const fetchTimeout = 5 * time.Second
+
+// Settings applied to every Service created afterwards
+var (
+	offline   bool
+	transport http.RoundTripper // nil means http.DefaultTransport
+)
+
+// SetOffline makes services created afterwards use the saved rates, however
+// old, or the fixed fallback rates without fetching them (--offline)
+func SetOffline(enabled bool) {
+	offline = enabled
+}
+
+// SetTransport makes services created afterwards fetch rates through t,
+// e.g. the proxy and CA bundle of the pricing fetch (nil restores the default)
+func SetTransport(t http.RoundTripper) {
+	transport = t
+}
+
+// Source tells where exchange rates came from
+type Source string
+
+const (
+	SourceECB    Source = "ecb"    // Fetched (or saved within cacheTTL) ECB rates
+	SourceCached Source = "cached" // Saved ECB rates older than cacheTTL
+	SourceFixed  Source = "fixed"  // Built-in fallback rates (fixedRates)
+)
+
+// Rate is the value of one US dollar in another currency
+type Rate struct {
+	Code   string  // ISO 4217 code, e.g. "EUR"
+	PerUSD float64 // Units of Code one dollar buys
+	Date   string  // Day the rate was published (YYYY-MM-DD)
+	Source Source
+}
+
+// Service looks up exchange rates, fetching them at most once per process
+type Service struct {
+	client   *http.Client
+	url      string
+	offline  bool
+	diskPath string        // ECB XML saved across runs ("" disables it)
+	cacheTTL time.Duration // Saved rates younger than this are not fetched again
+	warnings io.Writer
+
+	mu    sync.Mutex
+	rates *referenceRates
+}
+
+func NewService() *Service {
+	diskPath, _ := DefaultCachePath() // No home directory: fetch every run
+	return &Service{
+		client: &http.Client{
+			Timeout:   fetchTimeout,
+			Transport: transport,
+		},
+		url:      DefaultSourceURL,
+		offline:  offline,
+		diskPath: diskPath,
+		cacheTTL: 12 * time.Hour,
+		warnings: os.Stderr,
+	}
+}
+
+// DefaultCachePath returns where fetched rates are saved across runs,
+// exchange-rates.xml in the cache directory (see cachedir.Dir)
+func DefaultCachePath() (string, error) {
+	return cachedir.Path("exchange-rates.xml")
+}
+
+// Rate returns the value of one US dollar in the currency code (case
+// insensitive). Rates come from the ECB, else from the last saved copy,
+// else from fixedRates; a failed fetch prints one warning naming the fallback.
+func (s *Service) Rate(ctx context.Context, code string) (Rate, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "USD" {
+		return Rate{Code: code, PerUSD: 1, Source: SourceFixed}, nil
+	}
+
+	rates := s.load(ctx)
+	perEUR, exists := rates.perEUR[code]
+	if !exists && code != "EUR" {
+		return Rate{}, fmt.Errorf("unknown currency %q, use one of %s", code, strings.Join(rates.codes(), ", "))
+	}
+	if code == "EUR" {
+		perEUR = 1
+	}
+	return Rate{Code: code, PerUSD: perEUR / rates.perEUR["USD"], Date: rates.date, Source: rates.source}, nil
+}
+
+// load returns the rates of this run, reading or fetching them the first time
+func (s *Service) load(ctx context.Context) *referenceRates {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.rates != nil {
+		return s.rates
+	}
+
+	saved, savedAt, savedErr := s.readDiskCache()
+	switch {
+	case savedErr == nil && (s.offline || time.Since(savedAt) < s.cacheTTL):
+		saved.source = SourceECB
+		if time.Since(savedAt) >= s.cacheTTL {
+			saved.source = SourceCached
+		}
+		s.rates = saved
+	case s.offline:
+		s.rates = &fixedRates
+	default:
+		fetched, err := s.fetch(ctx)
+		if err == nil {
+			s.rates = fetched
+			break
+		}
+		s.rates = &fixedRates
+		if savedErr == nil {
+			saved.source = SourceCached
+			s.rates = saved
+		}
+		fmt.Fprintf(s.warnings, "Warning: failed to fetch exchange rates, using %s rates of %s: %v\n", s.rates.source, s.rates.date, err)
+	}
+	return s.rates
+}
+
+// fetch downloads the ECB rates and saves them for later runs
+func (s *Service) fetch(ctx context.Context) (*referenceRates, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("exchange rate server returned status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	rates, err := parseECB(data)
+	if err != nil {
+		return nil, err
+	}
+	rates.source = SourceECB
+	_ = s.writeDiskCache(data) // Best effort: the rates are fetched again next run
+	return rates, nil
+}
+
+// readDiskCache loads the saved ECB rates and the time they were fetched
+// (the file's modification time)
+func (s *Service) readDiskCache() (*referenceRates, time.Time, error) {
+	if s.diskPath == "" {
+		return nil, time.Time{}, errors.New("no exchange rate cache file")
+	}
+	info, err := os.Stat(s.diskPath)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	data, err := os.ReadFile(s.diskPath)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	rates, err := parseECB(data)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid exchange rate cache %s: %w", s.diskPath, err)
+	}
+	return rates, info.ModTime(), nil
+}
+
+// writeDiskCache saves fetched ECB rates for later runs
+func (s *Service) writeDiskCache(data []byte) error {
+	if s.diskPath == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(s.diskPath), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// Write to a temp file and rename so concurrent runs never read a partial file
+	tmp, err := os.CreateTemp(filepath.Dir(s.diskPath), filepath.Base(s.diskPath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create exchange rate cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write exchange rate cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write exchange rate cache: %w", err)
+	}
+	return os.Rename(tmp.Name(), s.diskPath)
+}
+
+// referenceRates are the euro's rates of one day
+type referenceRates struct {
+	date   string
+	perEUR map[string]float64 // Units of each currency one euro buys
+	source Source
+}
+
+// codes returns the currencies rates can convert to, sorted
+func (r *referenceRates) codes() []string {
+	codes := []string{"EUR"}
+	for code := range r.perEUR {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
@@ -0,0 +1,127 @@
+package currency
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testECB = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<Cube>
+		<Cube time='2026-10-15'>
+			<Cube currency='USD' rate='1.25'/>
+			<Cube currency='JPY' rate='175'/>
+			<Cube currency='GBP' rate='0.875'/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+// fakeTransport answers every request with body, or fails when it is empty
+type fakeTransport struct {
+	body     string
+	requests int
+}
+
+func (t *fakeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	if t.body == "" {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(t.body)), Request: req}, nil
+}
+
+func newTestService(t *testing.T, transport *fakeTransport) (*Service, *bytes.Buffer) {
+	t.Helper()
+	s := NewService()
+	s.client.Transport = transport
+	s.diskPath = filepath.Join(t.TempDir(), "exchange-rates.xml")
+	var warnings bytes.Buffer
+	s.warnings = &warnings
+	return s, &warnings
+}
+
+func TestParseECB(t *testing.T) {
+	rates, err := parseECB([]byte(testECB))
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-15", rates.date)
+	assert.Equal(t, map[string]float64{"USD": 1.25, "JPY": 175, "GBP": 0.875}, rates.perEUR)
+
+	_, err = parseECB([]byte(`<Envelope><Cube><Cube time="2026-10-15"><Cube currency="JPY" rate="175"/></Cube></Cube></Envelope>`))
+	assert.Error(t, err)
+	_, err = parseECB([]byte(`not xml`))
+	assert.Error(t, err)
+}
+
+func TestRate(t *testing.T) {
+	transport := &fakeTransport{body: testECB}
+	s, warnings := newTestService(t, transport)
+	ctx := context.Background()
+
+	rate, err := s.Rate(ctx, "jpy")
+	require.NoError(t, err)
+	assert.Equal(t, Rate{Code: "JPY", PerUSD: 140, Date: "2026-10-15", Source: SourceECB}, rate)
+
+	rate, err = s.Rate(ctx, "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, 0.8, rate.PerUSD)
+
+	rate, err = s.Rate(ctx, "USD")
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, rate.PerUSD)
+
+	_, err = s.Rate(ctx, "XYZ")
+	assert.ErrorContains(t, err, "GBP, JPY, USD")
+	assert.Equal(t, 1, transport.requests)
+	assert.Empty(t, warnings.String())
+
+	// A later run reuses the saved rates without fetching
+	next, _ := newTestService(t, transport)
+	next.diskPath = s.diskPath
+	rate, err = next.Rate(ctx, "GBP")
+	require.NoError(t, err)
+	assert.Equal(t, 0.7, rate.PerUSD)
+	assert.Equal(t, 1, transport.requests)
+}
+
+func TestRateFallback(t *testing.T) {
+	ctx := context.Background()
+
+	// Nothing saved: the fixed rates, with a warning
+	s, warnings := newTestService(t, &fakeTransport{})
+	rate, err := s.Rate(ctx, "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, SourceFixed, rate.Source)
+	assert.InDelta(t, 1/fixedRates.perEUR["USD"], rate.PerUSD, 1e-9)
+	assert.Contains(t, warnings.String(), "using fixed rates of 2025-10-01")
+
+	// Stale saved rates beat the fixed ones
+	stale, warnings := newTestService(t, &fakeTransport{})
+	require.NoError(t, os.WriteFile(stale.diskPath, []byte(testECB), 0o644))
+	old := time.Now().Add(-48 * time.Hour)
+	require.NoError(t, os.Chtimes(stale.diskPath, old, old))
+	rate, err = stale.Rate(ctx, "JPY")
+	require.NoError(t, err)
+	assert.Equal(t, Rate{Code: "JPY", PerUSD: 140, Date: "2026-10-15", Source: SourceCached}, rate)
+	assert.Contains(t, warnings.String(), "using cached rates of 2026-10-15")
+
+	// Offline never fetches and does not warn
+	transport := &fakeTransport{body: testECB}
+	offline, warnings := newTestService(t, transport)
+	offline.offline = true
+	rate, err = offline.Rate(ctx, "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, SourceFixed, rate.Source)
+	assert.Zero(t, transport.requests)
+	assert.Empty(t, warnings.String())
+}
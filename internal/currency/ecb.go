@@ -0,0 +1,85 @@
+package currency
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+)
+
+// ecbEnvelope is the eurofxref-daily.xml document:
+// <Cube><Cube time="..."><Cube currency="USD" rate="1.1645"/>...
+type ecbEnvelope struct {
+	Cube struct {
+		Days []struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string  `xml:"currency,attr"`
+				Rate     float64 `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+// parseECB reads the latest day of an ECB reference rates document
+func parseECB(data []byte) (*referenceRates, error) {
+	var envelope ecbEnvelope
+	if err := xml.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse ECB rates: %w", err)
+	}
+	if len(envelope.Cube.Days) == 0 {
+		return nil, errors.New("ECB rates list no day")
+	}
+
+	day := envelope.Cube.Days[0]
+	rates := &referenceRates{date: day.Time, perEUR: make(map[string]float64, len(day.Rates))}
+	for _, rate := range day.Rates {
+		if rate.Currency == "" || rate.Rate <= 0 {
+			return nil, fmt.Errorf("invalid ECB rate of %q: %g", rate.Currency, rate.Rate)
+		}
+		rates.perEUR[rate.Currency] = rate.Rate
+	}
+	if rates.perEUR["USD"] == 0 {
+		return nil, errors.New("ECB rates lack USD")
+	}
+	return rates, nil
+}
+
+// fixedRates are used when no ECB rates were ever fetched: approximate ECB
+// reference rates of early October 2025, so offline totals are close but
+// not exact
+var fixedRates = referenceRates{
+	date:   "2025-10-01",
+	source: SourceFixed,
+	perEUR: map[string]float64{
+		"USD": 1.17,
+		"JPY": 173.5,
+		"GBP": 0.873,
+		"CHF": 0.935,
+		"CAD": 1.633,
+		"AUD": 1.777,
+		"NZD": 2.017,
+		"CNY": 8.35,
+		"HKD": 9.11,
+		"SGD": 1.512,
+		"KRW": 1646,
+		"INR": 104.0,
+		"IDR": 19510,
+		"MYR": 4.93,
+		"PHP": 68.2,
+		"THB": 37.9,
+		"SEK": 11.02,
+		"NOK": 11.71,
+		"DKK": 7.464,
+		"PLN": 4.264,
+		"CZK": 24.33,
+		"HUF": 390.5,
+		"RON": 5.08,
+		"BGN": 1.9558,
+		"ISK": 143.0,
+		"TRY": 48.7,
+		"ILS": 3.88,
+		"ZAR": 20.3,
+		"BRL": 6.25,
+		"MXN": 21.55,
+	},
+}
@@ -0,0 +1,116 @@
+package loader
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// Aggregator receives deduplicated usage entries one at a time from LoadAndAggregate
+type Aggregator interface {
+	Add(entry types.UsageEntry)
+}
+
+// AggregatorFunc adapts a plain function to the Aggregator interface
+type AggregatorFunc func(entry types.UsageEntry)
+
+// Add calls f(entry)
+func (f AggregatorFunc) Add(entry types.UsageEntry) {
+	f(entry)
+}
+
+// LoadAndAggregate feeds every deduplicated entry under path to agg and then
+// discards it, so memory grows with the aggregator's groups rather than with
+// the size of the history. Files are parsed in batches of the worker count and
+// delivered in the same order as LoadFromPathWithOptions.
+//
+// SessionName is filled from custom titles found in the same or earlier files;
+// a title that only appears in a later file is not applied retroactively.
+// In low-memory mode (SetLowMemory) the dedupe keys are not kept in memory
+// either; see aggregateWithSpill.
+func (l *Loader) LoadAndAggregate(ctx context.Context, path string, options *LoaderOptions, agg Aggregator) error {
+	defer l.beginLoad()()
+
+	paths, err := l.resolveFiles(ctx, path, options)
+	if err != nil {
+		return err
+	}
+	defer l.saveCaches()
+
+	var calc CostCalculator
+	if options != nil {
+		calc = options.Calculator
+	}
+	if l.lowMemory {
+		return l.aggregateWithSpill(ctx, paths, calc, agg)
+	}
+
+	batchSize := l.maxWorkers
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	dedupeMap := make(map[string]bool)
+	sessionNames := make(map[string]string)
+	var firstErr error
+	var tally validationTally
+	loadedFiles, entryCount := 0, 0
+	progress := l.newProgressTracker(ctx, len(paths))
+
+	for start := 0; start < len(paths); start += batchSize {
+		end := start + batchSize
+		if end > len(paths) {
+			end = len(paths)
+		}
+
+		results, err := l.parseFiles(ctx, paths[start:end])
+		if err != nil {
+			return err
+		}
+
+		for _, res := range results {
+			if res.err != nil {
+				if firstErr == nil {
+					firstErr = res.err
+				}
+				progress.fileDone(res.path, res.err)
+				continue
+			}
+			loadedFiles++
+			tally.add(res.path, res.parsed)
+
+			// Custom titles take priority, so the first name seen for a session wins
+			for sid, name := range res.parsed.sessionNames {
+				if _, exists := sessionNames[sid]; !exists {
+					sessionNames[sid] = name
+				}
+			}
+
+			for _, entry := range l.dedupeParsedEntries(res.path, res.parsed, dedupeMap) {
+				if name, ok := sessionNames[entry.SessionID]; ok {
+					entry.SessionName = name
+				}
+				if calc != nil {
+					calc.CalculateCost(&entry)
+				}
+				agg.Add(entry)
+				progress.add(entry)
+				entryCount++
+			}
+			progress.fileDone(res.path, nil)
+		}
+	}
+
+	if l.debug {
+		fmt.Fprintf(os.Stderr, "Debug: Aggregated %d usage entries from %d files\n", entryCount, loadedFiles)
+	}
+	l.debugDuplicates()
+
+	l.recordTally(&tally)
+	if loadedFiles == 0 && firstErr != nil {
+		return fmt.Errorf("failed to load any files: %v", firstErr)
+	}
+	return l.checkValidation(&tally)
+}
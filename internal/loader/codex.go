@@ -0,0 +1,178 @@
+package loader
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// codexFallbackModel is used for Codex token counts logged before any turn_context
+const codexFallbackModel = "gpt-5"
+
+// isCodexRecord reports whether a decoded JSONL line comes from an OpenAI Codex
+// CLI session log (~/.codex/sessions/YYYY/MM/DD/rollout-*.jsonl). Codex lines
+// wrap their content in a "payload" object, which Claude Code logs never use.
+func isCodexRecord(raw map[string]interface{}) bool {
+	if _, ok := raw["type"].(string); !ok {
+		return false
+	}
+	_, ok := raw["payload"].(map[string]interface{})
+	return ok
+}
+
+// codexTokenUsage is a token_count usage block; input includes cached input
+type codexTokenUsage struct {
+	input       int
+	cachedInput int
+	output      int
+}
+
+func (u codexTokenUsage) sub(prev codexTokenUsage) codexTokenUsage {
+	return codexTokenUsage{
+		input:       u.input - prev.input,
+		cachedInput: u.cachedInput - prev.cachedInput,
+		output:      u.output - prev.output,
+	}
+}
+
+// codexSession tracks the state a Codex log carries between lines: the session
+// metadata, the model of the current turn and the running token totals
+type codexSession struct {
+	sessionID string
+	cwd       string
+	model     string
+	total     codexTokenUsage
+	hasTotal  bool
+}
+
+func newCodexSession(path string) *codexSession {
+	// rollout-2025-09-10T12-00-00-<uuid>.jsonl; session_meta normally overrides this
+	base := filepath.Base(path)
+	return &codexSession{sessionID: base[:len(base)-len(filepath.Ext(base))]}
+}
+
+// handle processes one Codex record, returning a usage entry for token_count
+// events that add tokens. Other record types only update session state.
+func (s *codexSession) handle(l *Loader, raw map[string]interface{}) (types.UsageEntry, bool, error) {
+	payload := raw["payload"].(map[string]interface{})
+
+	switch raw["type"] {
+	case "session_meta":
+		if id, ok := payload["id"].(string); ok && id != "" {
+			s.sessionID = id
+		}
+		if cwd, ok := payload["cwd"].(string); ok {
+			s.cwd = cwd
+		}
+		return types.UsageEntry{}, false, nil
+
+	case "turn_context":
+		if model, ok := payload["model"].(string); ok && model != "" {
+			s.model = model
+		}
+		return types.UsageEntry{}, false, nil
+
+	case "event_msg":
+		if payload["type"] != "token_count" {
+			return types.UsageEntry{}, false, nil
+		}
+	default:
+		return types.UsageEntry{}, false, nil
+	}
+
+	// token_count events carry running totals and, in newer CLI versions, the last turn's usage
+	info, ok := payload["info"].(map[string]interface{})
+	if !ok {
+		return types.UsageEntry{}, false, nil // Rate limit update without token info
+	}
+	total, hasTotal := parseCodexUsage(info["total_token_usage"])
+	last, hasLast := parseCodexUsage(info["last_token_usage"])
+
+	var usage codexTokenUsage
+	switch {
+	case hasTotal && s.hasTotal && total == s.total:
+		return types.UsageEntry{}, false, nil // Repeated event for the same turn
+	case hasLast:
+		usage = last
+	case hasTotal && s.hasTotal:
+		usage = total.sub(s.total)
+	case hasTotal:
+		usage = total
+	default:
+		return types.UsageEntry{}, false, fmt.Errorf("token_count event without usage")
+	}
+	if hasTotal {
+		s.total, s.hasTotal = total, true
+	}
+	if usage.input <= 0 && usage.output <= 0 {
+		return types.UsageEntry{}, false, nil
+	}
+
+	ts, err := parseCodexTimestamp(raw["timestamp"])
+	if err != nil {
+		return types.UsageEntry{}, false, err
+	}
+
+	model := s.model
+	if m, ok := info["model"].(string); ok && m != "" {
+		model = m
+	}
+	if model == "" {
+		model = codexFallbackModel
+	}
+
+	// Claude semantics: input excludes cache reads, which are reported separately
+	uncached := usage.input - usage.cachedInput
+	if uncached < 0 {
+		uncached = 0
+	}
+	entry := types.UsageEntry{
+		Timestamp:    ts,
+		ProjectPath:  s.cwd,
+		Cwd:          s.cwd,
+		Model:        model,
+		InputTokens:  uncached,
+		OutputTokens: usage.output,
+		TotalTokens:  uncached + usage.cachedInput + usage.output,
+		SessionID:    s.sessionID,
+		Raw:          map[string]interface{}{"cache_read_input_tokens": usage.cachedInput},
+	}
+	if l.timezone != nil {
+		entry.DateKey = ts.In(l.timezone).Format("2006-01-02")
+	}
+	return entry, true, nil
+}
+
+func parseCodexUsage(v interface{}) (codexTokenUsage, bool) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return codexTokenUsage{}, false
+	}
+	usage := codexTokenUsage{}
+	if n, ok := m["input_tokens"].(float64); ok {
+		usage.input = int(n)
+	}
+	if n, ok := m["cached_input_tokens"].(float64); ok {
+		usage.cachedInput = int(n)
+	}
+	// output_tokens already includes reasoning_output_tokens
+	if n, ok := m["output_tokens"].(float64); ok {
+		usage.output = int(n)
+	}
+	return usage, true
+}
+
+func parseCodexTimestamp(v interface{}) (time.Time, error) {
+	s, ok := v.(string)
+	if !ok || strings.TrimSpace(s) == "" {
+		return time.Time{}, fmt.Errorf("missing timestamp")
+	}
+	ts, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q: %w", s, err)
+	}
+	return ts, nil
+}
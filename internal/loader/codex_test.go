@@ -0,0 +1,74 @@
+package loader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const codexRollout = `{"timestamp":"2025-09-10T12:00:00.000Z","type":"session_meta","payload":{"id":"5973b6c0-94b8-487b-a530-2aeb6098ae0e","timestamp":"2025-09-10T12:00:00.000Z","cwd":"/home/me/src/app","originator":"codex_cli_rs","cli_version":"0.36.0"}}
+{"timestamp":"2025-09-10T12:00:01.000Z","type":"turn_context","payload":{"cwd":"/home/me/src/app","approval_policy":"on-request","model":"gpt-5-codex","effort":"medium"}}
+{"timestamp":"2025-09-10T12:00:02.000Z","type":"response_item","payload":{"type":"message","role":"user","content":[{"type":"input_text","text":"hi"}]}}
+{"timestamp":"2025-09-10T12:00:05.000Z","type":"event_msg","payload":{"type":"token_count","info":null,"rate_limits":{"primary":{"used_percent":1.0}}}}
+{"timestamp":"2025-09-10T12:00:06.000Z","type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":1200,"cached_input_tokens":1000,"output_tokens":80,"reasoning_output_tokens":30,"total_tokens":1280},"last_token_usage":{"input_tokens":1200,"cached_input_tokens":1000,"output_tokens":80,"reasoning_output_tokens":30,"total_tokens":1280},"model_context_window":272000}}}
+{"timestamp":"2025-09-10T12:00:06.500Z","type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":1200,"cached_input_tokens":1000,"output_tokens":80,"reasoning_output_tokens":30,"total_tokens":1280},"last_token_usage":{"input_tokens":1200,"cached_input_tokens":1000,"output_tokens":80,"reasoning_output_tokens":30,"total_tokens":1280},"model_context_window":272000}}}
+{"timestamp":"2025-09-10T12:01:00.000Z","type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":3000,"cached_input_tokens":2500,"output_tokens":200,"reasoning_output_tokens":50,"total_tokens":3200}}}}
+`
+
+func TestLoadCodexSessionLog(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	dayDir := filepath.Join(basePath, "sessions", "2025", "09", "10")
+	require.NoError(t, os.MkdirAll(dayDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dayDir, "rollout-2025-09-10T12-00-00-5973b6c0-94b8-487b-a530-2aeb6098ae0e.jsonl"), []byte(codexRollout), 0o644))
+
+	l := New()
+	entries, err := l.LoadFromPath(context.Background(), filepath.Join(basePath, "sessions"))
+	require.NoError(t, err)
+	require.Len(t, entries, 2, "info-less and repeated token_count events are skipped")
+
+	first := entries[0]
+	assert.Equal(t, "gpt-5-codex", first.Model)
+	assert.Equal(t, "5973b6c0-94b8-487b-a530-2aeb6098ae0e", first.SessionID)
+	assert.Equal(t, "/home/me/src/app", first.ProjectPath)
+	assert.Equal(t, 200, first.InputTokens, "cached input is reported as cache reads")
+	assert.Equal(t, 80, first.OutputTokens)
+	assert.Equal(t, 1000, first.Raw["cache_read_input_tokens"])
+	assert.Equal(t, 1280, first.TotalTokens)
+
+	// Without last_token_usage the turn is the difference between running totals
+	second := entries[1]
+	assert.Equal(t, 300, second.InputTokens)
+	assert.Equal(t, 120, second.OutputTokens)
+	assert.Equal(t, 1500, second.Raw["cache_read_input_tokens"])
+}
+
+func TestClaudeAndCodexLogsLoadTogether(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	addProjectFile(t, basePath, "project-a", "session.jsonl", []string{
+		createTestJSONLEntry(time.Date(2025, 9, 10, 11, 0, 0, 0, time.UTC), "claude-sonnet-4-5-20250514", 100, 10, "msg1", "req1"),
+	})
+	codexDir := filepath.Join(basePath, "codex")
+	require.NoError(t, os.MkdirAll(codexDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(codexDir, "rollout.jsonl"), []byte(codexRollout), 0o644))
+
+	l := New()
+	entries, err := l.LoadFromPath(context.Background(), JoinDataPaths([]string{basePath, codexDir}))
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	var models []string
+	for _, e := range entries {
+		models = append(models, e.Model)
+	}
+	assert.Equal(t, "claude-sonnet-4-5-20250514,gpt-5-codex,gpt-5-codex", strings.Join(models, ","))
+}
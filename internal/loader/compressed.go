@@ -0,0 +1,72 @@
+package loader
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Supported usage log extensions; archived logs may be gzip or zstd compressed
+const (
+	jsonlExt     = ".jsonl"
+	jsonlGzipExt = ".jsonl.gz"
+	jsonlZstdExt = ".jsonl.zst"
+)
+
+// isJSONLFile reports whether name is a plain or compressed JSONL usage log
+func isJSONLFile(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasSuffix(lower, jsonlExt) ||
+		strings.HasSuffix(lower, jsonlGzipExt) ||
+		strings.HasSuffix(lower, jsonlZstdExt)
+}
+
+// openJSONL opens a usage log, transparently decompressing .gz and .zst files
+func openJSONL(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return decompress(path, file)
+}
+
+// decompress wraps the contents of the log called name in a decompressor
+// when its extension calls for one. Closing the result also closes file.
+func decompress(name string, file io.ReadCloser) (io.ReadCloser, error) {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, jsonlGzipExt):
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		return &decompressReader{Reader: gz, closeFn: func() { gz.Close() }, file: file}, nil
+
+	case strings.HasSuffix(lower, jsonlZstdExt):
+		zr, err := zstd.NewReader(file, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		return &decompressReader{Reader: zr, closeFn: zr.Close, file: file}, nil
+
+	default:
+		return file, nil
+	}
+}
+
+// decompressReader closes both the decompressor and the underlying file
+type decompressReader struct {
+	io.Reader
+	closeFn func()
+	file    io.Closer
+}
+
+func (r *decompressReader) Close() error {
+	r.closeFn()
+	return r.file.Close()
+}
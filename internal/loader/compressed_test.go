@@ -0,0 +1,54 @@
+package loader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadCompressedJSONL(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	ts := time.Now().Add(-time.Hour)
+	addProjectFile(t, basePath, "project-a", "plain.jsonl", []string{
+		createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 100, 50, "msg1", "req1"),
+	})
+
+	projectDir := filepath.Join(basePath, "projects", "project-a")
+
+	var gzBuf bytes.Buffer
+	gz := gzip.NewWriter(&gzBuf)
+	_, err := gz.Write([]byte(createTestJSONLEntry(ts.Add(time.Minute), "claude-sonnet-4-5-20250514", 200, 100, "msg2", "req2") + "\n"))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "archived.jsonl.gz"), gzBuf.Bytes(), 0o644))
+
+	zw, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	zstdData := zw.EncodeAll([]byte(createTestJSONLEntry(ts.Add(2*time.Minute), "claude-sonnet-4-5-20250514", 300, 150, "msg3", "req3")+"\n"), nil)
+	require.NoError(t, zw.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "archived.jsonl.zst"), zstdData, 0o644))
+
+	// Unrelated compressed files are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "notes.txt.gz"), gzBuf.Bytes(), 0o644))
+
+	l := New()
+	entries, err := l.LoadFromPath(context.Background(), basePath)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	inputs := make([]int, 0, len(entries))
+	for _, e := range entries {
+		inputs = append(inputs, e.InputTokens)
+	}
+	assert.ElementsMatch(t, []int{100, 200, 300}, inputs)
+}
@@ -0,0 +1,85 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DataPathSeparator separates multiple data directories in --data-path and CLAUDE_CONFIG_DIR
+const DataPathSeparator = ","
+
+// SplitDataPaths splits a comma-separated list of data directories,
+// trimming whitespace and dropping empty and repeated entries. Entries may be
+// local paths or remote URLs such as ssh://host/path.
+func SplitDataPaths(path string) []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, p := range strings.Split(path, DataPathSeparator) {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		clean := p
+		if !IsRemoteDataPath(p) {
+			clean = filepath.Clean(p)
+		}
+		if seen[clean] {
+			continue
+		}
+		seen[clean] = true
+		paths = append(paths, clean)
+	}
+	return paths
+}
+
+// JoinDataPaths is the inverse of SplitDataPaths
+func JoinDataPaths(paths []string) string {
+	return strings.Join(paths, DataPathSeparator)
+}
+
+// resolveProjectsDir returns the projects subdirectory of basePath if it exists
+func resolveProjectsDir(basePath string) string {
+	projectsPath := filepath.Join(basePath, "projects")
+	if _, err := os.Stat(projectsPath); err == nil {
+		return projectsPath
+	}
+	return basePath
+}
+
+// pathSegments splits a path at both / and \, so Windows paths (including
+// ones read on another platform) split the same way as Unix ones
+func pathSegments(path string) []string {
+	return strings.Split(strings.ReplaceAll(path, `\`, "/"), "/")
+}
+
+// pathPrefix returns the part of path made of its first n segments, keeping
+// the original separators
+func pathPrefix(path string, segments []string, n int) string {
+	if n == 0 {
+		return ""
+	}
+	length := n - 1
+	for _, segment := range segments[:n] {
+		length += len(segment)
+	}
+	return path[:length]
+}
+
+// uniqueFiles removes repeated file paths (e.g. overlapping data directories) preserving order
+func uniqueFiles(files []string) []string {
+	seen := make(map[string]bool, len(files))
+	unique := files[:0]
+	for _, f := range files {
+		key := f
+		if abs, err := filepath.Abs(f); err == nil {
+			key = abs
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, f)
+	}
+	return unique
+}
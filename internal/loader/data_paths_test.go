@@ -0,0 +1,55 @@
+package loader
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitDataPaths(t *testing.T) {
+	assert.Equal(t, []string{"/a", "/b"}, SplitDataPaths("/a, /b/,,/a"))
+	assert.Equal(t, []string{"/only"}, SplitDataPaths("/only"))
+	assert.Empty(t, SplitDataPaths(" , "))
+	assert.Equal(t, []string{"ssh://devbox/~/.claude", "/a"}, SplitDataPaths("ssh://devbox/~/.claude, /a"))
+}
+
+func TestLoadFromMultipleDataPaths(t *testing.T) {
+	first, cleanupFirst := setupTestProject(t)
+	defer cleanupFirst()
+	second, cleanupSecond := setupTestProject(t)
+	defer cleanupSecond()
+
+	ts := time.Now().Add(-time.Hour)
+	addProjectFile(t, first, "project-a", "a.jsonl", []string{
+		createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 100, 50, "msg1", "req1"),
+	})
+	addProjectFile(t, second, "project-b", "b.jsonl", []string{
+		createTestJSONLEntry(ts.Add(time.Minute), "claude-sonnet-4-5-20250514", 200, 100, "msg2", "req2"),
+		// Same request also logged in the first directory
+		createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 100, 50, "msg1", "req1"),
+	})
+
+	l := New()
+	missing := filepath.Join(first, "does-not-exist")
+	entries, err := l.LoadFromPath(context.Background(), JoinDataPaths([]string{first, missing, second, first}))
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "entries should be merged and deduplicated across directories")
+
+	_, err = l.LoadFromPath(context.Background(), missing)
+	assert.Error(t, err)
+}
+
+func TestExtractProjectPathAcceptsBothSeparators(t *testing.T) {
+	l := New()
+
+	assert.Equal(t, "/home/me/.claude/projects/-home-me-app",
+		l.extractProjectPath("/home/me/.claude/projects/-home-me-app/session.jsonl"))
+	assert.Equal(t, `C:\Users\me\.claude\projects\C--Users-me-app`,
+		l.extractProjectPath(`C:\Users\me\.claude\projects\C--Users-me-app\session.jsonl`))
+	assert.Equal(t, `D:\logs\app`,
+		l.extractProjectPath(`D:\logs\app\2025\03\01\session.jsonl`))
+}
@@ -0,0 +1,211 @@
+package loader
+
+import (
+	"encoding/gob"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/sdpower/ccusage-go/internal/cachedir"
+)
+
+// dedupeStoreVersion is bumped whenever the on-disk layout or key hashing changes
+const dedupeStoreVersion = 1
+
+// dedupeStoreData is the on-disk form: parallel slices of key hashes and the
+// index of the file that first contained each key
+type dedupeStoreData struct {
+	Version int
+	Files   []string
+	Keys    []uint64
+	Owners  []uint32
+}
+
+// DedupeStore remembers which file first contained each messageId:requestId
+// across runs. A later run keeps an entry only if its key is new or belongs to
+// the same file, so duplicates are caught even when the owning file is not
+// loaded again (e.g. filtered out by ModifiedWithin in the live monitor).
+//
+// Keys are stored as 64-bit FNV-1a hashes to keep the file small. Ownership is
+// only meaningful for one set of data directories, so use one store per data path.
+type DedupeStore struct {
+	path      string
+	mu        sync.Mutex
+	owners    map[uint64]uint32 // key hash → index into files
+	files     []string
+	fileIndex map[string]uint32
+	exists    map[uint32]bool // Whether an owning file still exists, checked once per store
+	dirty     bool
+}
+
+// DefaultDedupeStorePath returns the dedupe store location for dataPath
+// (dedupe-<hash>.db in the cache directory)
+func DefaultDedupeStorePath(dataPath string) (string, error) {
+	h := fnv.New64a()
+	h.Write([]byte(JoinDataPaths(SplitDataPaths(dataPath))))
+	return cachedir.Path(fmt.Sprintf("dedupe-%016x.db", h.Sum64()))
+}
+
+// OpenDedupeStore loads the dedupe store at path. A missing, unreadable or
+// outdated file yields an empty store rather than an error.
+func OpenDedupeStore(path string) *DedupeStore {
+	s := &DedupeStore{
+		path:      path,
+		owners:    make(map[uint64]uint32),
+		fileIndex: make(map[string]uint32),
+		exists:    make(map[uint32]bool),
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return s
+	}
+	defer file.Close()
+
+	var data dedupeStoreData
+	if err := gob.NewDecoder(file).Decode(&data); err != nil || data.Version != dedupeStoreVersion || len(data.Keys) != len(data.Owners) {
+		s.dirty = true // Rewrite corrupt or outdated store on save
+		return s
+	}
+
+	s.files = data.Files
+	for i, f := range s.files {
+		s.fileIndex[f] = uint32(i)
+	}
+	for i, key := range data.Keys {
+		if int(data.Owners[i]) < len(s.files) {
+			s.owners[key] = data.Owners[i]
+		}
+	}
+	return s
+}
+
+// SetDedupeStore enables cross-run deduplication for subsequent loads
+func (l *Loader) SetDedupeStore(store *DedupeStore) {
+	l.dedupeStore = store
+}
+
+// Len returns the number of remembered keys
+func (s *DedupeStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.owners)
+}
+
+// claim reports whether an entry with key read from path should be kept,
+// recording path as the key's owner when the key is new. A key whose owner no
+// longer exists (a renamed project directory, or a log rotated to .gz) passes
+// to path instead of dropping the entry.
+func (s *DedupeStore) claim(key, path string) bool {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	sum := h.Sum64()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx, ok := s.fileIndex[path]
+	if !ok {
+		idx = uint32(len(s.files))
+		s.files = append(s.files, path)
+		s.fileIndex[path] = idx
+	}
+
+	if owner, exists := s.owners[sum]; exists && (owner == idx || s.ownerExists(owner)) {
+		return owner == idx
+	}
+	s.owners[sum] = idx
+	s.dirty = true
+	return true
+}
+
+// ownerExists reports whether the file at index owner is still on disk.
+// The caller holds s.mu.
+func (s *DedupeStore) ownerExists(owner uint32) bool {
+	exists, ok := s.exists[owner]
+	if !ok {
+		_, err := os.Stat(s.files[owner])
+		exists = !os.IsNotExist(err)
+		s.exists[owner] = exists
+	}
+	return exists
+}
+
+// Save writes the store to disk if anything changed. Keys owned by files that
+// no longer exist are dropped so their duplicates elsewhere can take over.
+func (s *DedupeStore) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Compact the file table, keeping only existing files that own keys
+	used := make([]bool, len(s.files))
+	for _, owner := range s.owners {
+		used[owner] = true
+	}
+	remap := make([]int, len(s.files))
+	var files []string
+	for i, f := range s.files {
+		remap[i] = -1
+		if !used[i] {
+			continue
+		}
+		if _, err := os.Stat(f); os.IsNotExist(err) {
+			s.dirty = true
+			continue
+		}
+		remap[i] = len(files)
+		files = append(files, f)
+	}
+	if !s.dirty {
+		return nil
+	}
+
+	data := dedupeStoreData{
+		Version: dedupeStoreVersion,
+		Files:   files,
+		Keys:    make([]uint64, 0, len(s.owners)),
+		Owners:  make([]uint32, 0, len(s.owners)),
+	}
+	for key, owner := range s.owners {
+		if remap[owner] < 0 {
+			delete(s.owners, key)
+			continue
+		}
+		data.Keys = append(data.Keys, key)
+		data.Owners = append(data.Owners, uint32(remap[owner]))
+		s.owners[key] = uint32(remap[owner])
+	}
+	s.files = files
+	s.exists = make(map[uint32]bool)
+	s.fileIndex = make(map[string]uint32, len(files))
+	for i, f := range files {
+		s.fileIndex[f] = uint32(i)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// Write to a temp file and rename so concurrent runs never read a partial store
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create dedupe store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := gob.NewEncoder(tmp).Encode(&data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to encode dedupe store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write dedupe store: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace dedupe store: %w", err)
+	}
+
+	s.dirty = false
+	return nil
+}
@@ -0,0 +1,173 @@
+package loader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDedupeStoreCatchesDuplicatesAcrossRuns(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	ts := time.Now().Add(-2 * time.Hour)
+	older := addProjectFile(t, basePath, "project-a", "older.jsonl", []string{
+		createTestJSONLEntry(ts.Add(-time.Hour), "claude-sonnet-4-5-20250514", 50, 5, "msg0", "req0"),
+		createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 100, 10, "msg1", "req1"),
+	})
+	newer := addProjectFile(t, basePath, "project-a", "newer.jsonl", []string{
+		createTestJSONLEntry(ts.Add(time.Hour), "claude-sonnet-4-5-20250514", 200, 20, "msg2", "req2"),
+		// Resumed conversation repeats the earlier request
+		createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 100, 10, "msg1", "req1"),
+	})
+	storePath := filepath.Join(t.TempDir(), "dedupe.db")
+
+	first := New()
+	first.SetDedupeStore(OpenDedupeStore(storePath))
+	expected, err := first.LoadFromPath(context.Background(), basePath)
+	require.NoError(t, err)
+	require.Len(t, expected, 3)
+
+	// Repeated full loads are unaffected by the remembered keys
+	second := New()
+	second.SetDedupeStore(OpenDedupeStore(storePath))
+	entries, err := second.LoadFromPath(context.Background(), basePath)
+	require.NoError(t, err)
+	assert.Equal(t, expected, entries)
+
+	// Loading only the newer file still drops the copy owned by the older one
+	store := OpenDedupeStore(storePath)
+	assert.Equal(t, 3, store.Len())
+	incremental := New()
+	incremental.SetDedupeStore(store)
+	entries, _, err = incremental.loadFile(newer)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, 200, entries[0].InputTokens)
+	assert.Equal(t, 1, incremental.Stats().DuplicatesAcrossRuns)
+	assert.Equal(t, map[string]int{newer: 1}, incremental.Stats().DuplicatesByFile)
+
+	// Once the owning file is gone the remaining copy is counted again
+	require.NoError(t, os.Remove(older))
+	require.NoError(t, store.Save())
+	afterDelete := New()
+	afterDelete.SetDedupeStore(OpenDedupeStore(storePath))
+	entries, _, err = afterDelete.loadFile(newer)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestDedupeStoreKeepsRequestsOwnedByFilteredRun(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	ts := time.Now().Add(-2 * time.Hour)
+	addProjectFile(t, basePath, "-a", "a.jsonl", []string{
+		createTestJSONLEntry(ts.Add(-time.Hour), "claude-sonnet-4-5-20250514", 50, 5, "msg0", "req0"),
+		createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 100, 10, "msg1", "req1"),
+	})
+	addProjectFile(t, basePath, "-b", "b.jsonl", []string{
+		createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 100, 10, "msg1", "req1"),
+		createTestJSONLEntry(ts.Add(time.Hour), "claude-sonnet-4-5-20250514", 200, 20, "msg2", "req2"),
+	})
+
+	for _, lowMemory := range []bool{false, true} {
+		t.Run(fmt.Sprintf("lowMemory=%v", lowMemory), func(t *testing.T) {
+			storePath := filepath.Join(t.TempDir(), "dedupe.db")
+
+			// A filtered run makes the later file the owner of the shared request
+			filter, err := NewFileFilter([]string{"projects/-b/*"}, nil)
+			require.NoError(t, err)
+			filtered := New()
+			filtered.SetFileFilter(filter)
+			filtered.SetDedupeStore(OpenDedupeStore(storePath))
+			entries, err := filtered.LoadFromPath(context.Background(), basePath)
+			require.NoError(t, err)
+			require.Len(t, entries, 2)
+
+			// A full run then keeps one copy of it rather than dropping both
+			full := New()
+			full.SetLowMemory(lowMemory)
+			full.SetDedupeStore(OpenDedupeStore(storePath))
+			entries = nil
+			err = full.LoadAndAggregate(context.Background(), basePath, nil, AggregatorFunc(func(entry types.UsageEntry) {
+				entries = append(entries, entry)
+			}))
+			require.NoError(t, err)
+			assert.Len(t, entries, 3)
+			assert.Equal(t, 1, full.Stats().DuplicatesRemoved)
+		})
+	}
+}
+
+func TestDedupeStoreReleasesKeysOfMovedFiles(t *testing.T) {
+	lines := func(ts time.Time) []string {
+		return []string{
+			createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 100, 10, "msg1", "req1"),
+			createTestJSONLEntry(ts.Add(time.Minute), "claude-sonnet-4-5-20250514", 200, 20, "msg2", "req2"),
+		}
+	}
+	tests := []struct {
+		name string
+		move func(t *testing.T, basePath, path string)
+	}{
+		{"renamed project directory", func(t *testing.T, basePath, path string) {
+			require.NoError(t, os.Rename(filepath.Dir(path), filepath.Join(basePath, "projects", "-renamed")))
+		}},
+		{"log rotated to gzip", func(t *testing.T, basePath, path string) {
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			_, err = gz.Write(data)
+			require.NoError(t, err)
+			require.NoError(t, gz.Close())
+			require.NoError(t, os.WriteFile(path+".gz", buf.Bytes(), 0o644))
+			require.NoError(t, os.Remove(path))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			basePath, cleanup := setupTestProject(t)
+			defer cleanup()
+			path := addProjectFile(t, basePath, "-a", "session.jsonl", lines(time.Now().Add(-time.Hour)))
+			storePath := filepath.Join(t.TempDir(), "dedupe.db")
+
+			first := New()
+			first.SetDedupeStore(OpenDedupeStore(storePath))
+			entries, err := first.LoadFromPath(context.Background(), basePath)
+			require.NoError(t, err)
+			require.Len(t, entries, 2)
+
+			// The new copy takes over the keys of the one that is gone
+			tt.move(t, basePath, path)
+			moved := New()
+			moved.SetDedupeStore(OpenDedupeStore(storePath))
+			entries, err = moved.LoadFromPath(context.Background(), basePath)
+			require.NoError(t, err)
+			assert.Len(t, entries, 2)
+			assert.Zero(t, moved.Stats().DuplicatesAcrossRuns)
+		})
+	}
+}
+
+func TestDedupeStorePathDependsOnDataPath(t *testing.T) {
+	a, err := DefaultDedupeStorePath("/data/a")
+	require.NoError(t, err)
+	b, err := DefaultDedupeStorePath("/data/b")
+	require.NoError(t, err)
+	same, err := DefaultDedupeStorePath(" /data/a ,")
+	require.NoError(t, err)
+
+	assert.NotEqual(t, a, b)
+	assert.Equal(t, a, same)
+}
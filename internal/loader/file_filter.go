@@ -0,0 +1,128 @@
+package loader
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// FileFilter scopes file discovery with glob patterns. A pattern matches a
+// file when it matches any run of consecutive path segments, so
+// "projects/foo*" selects every file below a matching project directory and
+// "*scratch*" any path with a segment containing "scratch". Segments use
+// path.Match syntax, "**" matches any number of segments, and a leading "/"
+// anchors the pattern at the filesystem root.
+type FileFilter struct {
+	include [][]string
+	exclude [][]string
+}
+
+// NewFileFilter builds a filter keeping files that match at least one include
+// pattern (all files when there are none) and no exclude pattern
+func NewFileFilter(include, exclude []string) (*FileFilter, error) {
+	f := &FileFilter{}
+	var err error
+	if f.include, err = compileGlobs(include); err != nil {
+		return nil, err
+	}
+	if f.exclude, err = compileGlobs(exclude); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func compileGlobs(patterns []string) ([][]string, error) {
+	var globs [][]string
+	for _, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+		pattern = filepath.ToSlash(pattern)
+		if len(pattern) > 1 {
+			pattern = strings.TrimSuffix(pattern, "/") // "scratch/" means the directory
+		}
+		segments := strings.Split(pattern, "/")
+		for _, segment := range segments {
+			if _, err := path.Match(segment, ""); err != nil {
+				return nil, fmt.Errorf("invalid glob %q: %w", pattern, err)
+			}
+		}
+		globs = append(globs, segments)
+	}
+	return globs, nil
+}
+
+// SetFileFilter restricts the files read by later loads (nil reads everything)
+func (l *Loader) SetFileFilter(filter *FileFilter) {
+	l.fileFilter = filter
+}
+
+// Allows reports whether file passes the filter. A nil filter allows everything.
+func (f *FileFilter) Allows(file string) bool {
+	if f == nil {
+		return true
+	}
+	segments := strings.Split(filepath.ToSlash(file), "/")
+	for _, glob := range f.exclude {
+		if matchGlob(glob, segments) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, glob := range f.include {
+		if matchGlob(glob, segments) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterFiles drops the files rejected by the loader's file filter
+func (l *Loader) filterFiles(files []string) []string {
+	if l.fileFilter == nil {
+		return files
+	}
+	kept := files[:0]
+	for _, file := range files {
+		if l.fileFilter.Allows(file) {
+			kept = append(kept, file)
+		}
+	}
+	return kept
+}
+
+// matchGlob reports whether glob matches consecutive segments of a path
+func matchGlob(glob, segments []string) bool {
+	if glob[0] == "" { // Leading "/": anchored at the root, whose segment is also ""
+		return segments[0] == "" && matchPrefix(glob[1:], segments[1:])
+	}
+	for i := range segments {
+		if matchPrefix(glob, segments[i:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPrefix reports whether glob matches the leading segments
+func matchPrefix(glob, segments []string) bool {
+	if len(glob) == 0 {
+		return true
+	}
+	if glob[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchPrefix(glob[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	ok, _ := path.Match(glob[0], segments[0])
+	return ok && matchPrefix(glob[1:], segments[1:])
+}
@@ -0,0 +1,67 @@
+package loader
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileFilterGlobs(t *testing.T) {
+	const file = "/home/me/.claude/projects/-Users-me-src-foo-api/s1.jsonl"
+
+	tests := []struct {
+		include []string
+		exclude []string
+		allowed bool
+	}{
+		{nil, nil, true},
+		{[]string{"projects/*foo*"}, nil, true},
+		{[]string{"projects/*bar*"}, nil, false},
+		{[]string{"projects/*bar*", "*.jsonl"}, nil, true},
+		{[]string{".claude/**/s1.jsonl"}, nil, true},
+		{[]string{"/home/me/.claude/"}, nil, true},
+		{[]string{"/me/.claude"}, nil, false},
+		{nil, []string{"*-src-foo-*"}, false},
+		{[]string{"projects/*foo*"}, []string{"s1.jsonl"}, false},
+	}
+	for _, tt := range tests {
+		filter, err := NewFileFilter(tt.include, tt.exclude)
+		require.NoError(t, err)
+		assert.Equal(t, tt.allowed, filter.Allows(file), "include %v exclude %v", tt.include, tt.exclude)
+	}
+
+	var none *FileFilter
+	assert.True(t, none.Allows(file))
+
+	_, err := NewFileFilter([]string{"projects/[foo"}, nil)
+	assert.Error(t, err)
+}
+
+func TestLoadAppliesFileFilter(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	ts := time.Now().Add(-time.Hour)
+	addProjectFile(t, basePath, "work-api", "a.jsonl", []string{
+		createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 100, 50, "msg1", "req1"),
+	})
+	addProjectFile(t, basePath, "work-scratch", "b.jsonl", []string{
+		createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 200, 100, "msg2", "req2"),
+	})
+	addProjectFile(t, basePath, "personal", "c.jsonl", []string{
+		createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 300, 150, "msg3", "req3"),
+	})
+
+	filter, err := NewFileFilter([]string{"projects/work-*"}, []string{"*scratch*"})
+	require.NoError(t, err)
+	l := New()
+	l.SetFileFilter(filter)
+
+	entries, err := l.LoadFromPath(context.Background(), basePath)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, 100, entries[0].InputTokens)
+}
@@ -0,0 +1,95 @@
+package loader
+
+import (
+	"bytes"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+	"strings"
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// fingerprintFile returns the CRC-32C of the first size bytes of path and
+// whether they end with a newline
+func fingerprintFile(path string, size int64) (uint32, bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, false, err
+	}
+	defer file.Close()
+	return fingerprintPrefix(file, size)
+}
+
+// fingerprintPrefix hashes the next size bytes of r, leaving r positioned after them
+func fingerprintPrefix(r io.Reader, size int64) (uint32, bool, error) {
+	h := &lastByteHash{Hash32: crc32.New(castagnoli)}
+	if _, err := io.CopyN(h, r, size); err != nil {
+		return 0, false, err
+	}
+	return h.Sum32(), h.last == '\n', nil
+}
+
+// lastByteHash remembers the final byte written through it
+type lastByteHash struct {
+	hash.Hash32
+	last byte
+}
+
+func (h *lastByteHash) Write(p []byte) (int, error) {
+	if len(p) > 0 {
+		h.last = p[len(p)-1]
+	}
+	return h.Hash32.Write(p)
+}
+
+// resumeFromCache reuses the cached parse of a file whose size or mtime
+// changed, as long as the bytes parsed last time are intact: a touched file is
+// served from the cache as is, and a file that was only appended to has just
+// its new lines parsed. It reports false when the file must be parsed in full.
+func (l *Loader) resumeFromCache(path string, info os.FileInfo) (*parsedFile, bool) {
+	cached := l.parseCache.get(path)
+	if cached == nil || info.Size() < cached.Size || !strings.HasSuffix(strings.ToLower(path), jsonlExt) {
+		return nil, false
+	}
+	grew := info.Size() > cached.Size
+	if grew && (cached.Codex || !cached.EndsLine) {
+		// Codex session context is not cached, and a partial last line would be split
+		return nil, false
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, false
+	}
+	defer file.Close()
+
+	sum, _, err := fingerprintPrefix(file, cached.Size)
+	if err != nil || sum != cached.Checksum {
+		return nil, false
+	}
+	parsed := cached.toParsed(path, l.timezone)
+	if !grew {
+		return parsed, true
+	}
+
+	// The file is positioned right after the cached prefix
+	scanner := newBufferedLines(io.LimitReader(file, info.Size()-cached.Size))
+	lp := l.newLineParser(path)
+	lp.detected = true // Only Claude Code logs are resumed
+	lineNum := cached.LastLine
+	for scanner.Scan() {
+		lineNum++
+		if err := scanner.LineErr(); err != nil {
+			lp.skipLine(lineNum, err, parsed)
+			continue
+		}
+		lp.parseLine(bytes.TrimSpace(scanner.Bytes()), lineNum, parsed)
+	}
+	if scanner.Err() != nil {
+		return nil, false
+	}
+	parsed.lastLine = lineNum
+	return parsed, true
+}
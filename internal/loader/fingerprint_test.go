@@ -0,0 +1,116 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCacheResumesTouchedAndAppendedFiles(t *testing.T) {
+	model := "claude-sonnet-4-5-20250514"
+	ts := time.Now().Add(-time.Hour)
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	appendLines(t, path, createTestJSONLEntry(ts, model, 100, 50, "msg1", "req1")+"\n\n")
+
+	l := New()
+	l.SetParseCache(OpenParseCache(filepath.Join(t.TempDir(), "index.db")))
+	read := func() *parsedFile {
+		parsed, err := l.readFileEntries(path)
+		require.NoError(t, err)
+		return parsed
+	}
+	stats := func() [3]int {
+		hits, resumed, misses := l.parseCache.Stats()
+		return [3]int{hits, resumed, misses}
+	}
+
+	first := read()
+	require.Len(t, first.entries, 1)
+	assert.Equal(t, [3]int{0, 0, 1}, stats())
+
+	// Touching the file changes its mtime but not its content
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, later, later))
+	assert.Equal(t, first.entries, read().entries)
+	assert.Equal(t, [3]int{0, 1, 1}, stats())
+
+	// Appended lines are parsed on top of the cached prefix, numbering lines on from it
+	appendLines(t, path, createTestJSONLEntry(ts.Add(time.Minute), model, 10, 5, "msg2", "req2")+"\n{broken\n")
+	appended := read()
+	assert.Equal(t, [3]int{0, 2, 1}, stats())
+	require.Len(t, appended.entries, 2)
+	assert.Equal(t, 10, appended.entries[1].InputTokens)
+	assert.Equal(t, 3, appended.lines)
+	require.Len(t, appended.parseErrors, 1)
+	assert.Equal(t, 4, appended.parseErrors[0].Line)
+
+	// A rewrite of the same size is detected by the checksum and parsed in full
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	data[len(data)-3] = 'X' // "{broken" → "{brokXn"
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+	require.NoError(t, os.Chtimes(path, later.Add(time.Minute), later.Add(time.Minute)))
+	read()
+	assert.Equal(t, [3]int{0, 2, 2}, stats())
+}
+
+func TestParseCacheReparsesAfterPartialLastLine(t *testing.T) {
+	model := "claude-sonnet-4-5-20250514"
+	ts := time.Now().Add(-time.Hour)
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	line := createTestJSONLEntry(ts, model, 100, 50, "msg1", "req1")
+
+	// The writer was mid-line when the file was first parsed
+	appendLines(t, path, line[:30])
+
+	l := New()
+	l.SetParseCache(OpenParseCache(filepath.Join(t.TempDir(), "index.db")))
+	parsed, err := l.readFileEntries(path)
+	require.NoError(t, err)
+	assert.Empty(t, parsed.entries)
+
+	appendLines(t, path, line[30:]+"\n")
+	parsed, err = l.readFileEntries(path)
+	require.NoError(t, err)
+	require.Len(t, parsed.entries, 1)
+	assert.Zero(t, parsed.invalidLines)
+	_, resumed, misses := l.parseCache.Stats()
+	assert.Equal(t, 0, resumed)
+	assert.Equal(t, 2, misses)
+}
+
+func TestParseCacheStopsAtStatSizeWhenFileGrowsDuringParse(t *testing.T) {
+	model := "claude-sonnet-4-5-20250514"
+	ts := time.Now().Add(-time.Hour)
+
+	for _, useMmap := range []bool{false, true} {
+		path := filepath.Join(t.TempDir(), "session.jsonl")
+		appendLines(t, path, createTestJSONLEntry(ts, model, 100, 50, "msg1", "req1")+"\n")
+
+		l := New()
+		l.SetUseMmap(useMmap)
+		l.SetParseCache(OpenParseCache(filepath.Join(t.TempDir(), "index.db")))
+
+		// The session writes a request between the stat and the parse
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		appendLines(t, path, createTestJSONLEntry(ts.Add(time.Minute), model, 10, 5, "msg2", "req2")+"\n")
+		parsed, err := l.parseAndCache(path, info)
+		require.NoError(t, err)
+		require.Len(t, parsed.entries, 1, "mmap=%v", useMmap)
+
+		// The next load parses the appended request once, on top of the cached prefix
+		parsed, err = l.readFileEntries(path)
+		require.NoError(t, err)
+		require.Len(t, parsed.entries, 2, "mmap=%v", useMmap)
+		assert.Equal(t, 10, parsed.entries[1].InputTokens)
+		assert.Equal(t, 2, parsed.lines)
+		assert.Equal(t, 2, parsed.lastLine)
+		_, resumed, _ := l.parseCache.Stats()
+		assert.Equal(t, 1, resumed)
+	}
+}
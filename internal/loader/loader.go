@@ -1,10 +1,11 @@
 package loader
 
 import (
-	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
@@ -30,9 +31,29 @@ type LoaderOptions struct {
 }
 
 type Loader struct {
-	maxWorkers int
-	debug      bool
-	timezone   *time.Location
+	maxWorkers   int
+	debug        bool
+	timezone     *time.Location
+	parseCache   *ParseCache
+	dedupeStore  *DedupeStore
+	useMmap      bool
+	fileFilter   *FileFilter // Include/exclude globs applied during discovery
+	strict       bool    // Fail loads whose invalid line rate exceeds maxErrorRate
+	maxErrorRate float64 // Percent
+
+	remoteCacheDir string // Where remote data directories are mirrored ("" = default)
+
+	since          time.Time // Date range used to skip files during discovery (zero = open)
+	until          time.Time
+	timestampIndex *TimestampIndex
+	lowMemory      bool // Spill entries to disk in LoadAndAggregate
+	stats          types.LoaderStats
+	progress       chan<- LoadProgress // Per-file updates (nil = none)
+	keepRaw        bool                // Keep each entry's log line in RawJSON
+	quarantine     *Quarantine         // Receives lines that fail to parse (nil = none)
+	streamed       map[string]Source   // Files of the last resolve read through a Source, not from disk
+
+	includeSynthetic bool // Keep <synthetic> model entries
 }
 
 func New() *Loader {
@@ -51,6 +72,33 @@ func (l *Loader) SetTimezone(timezone *time.Location) {
 	l.timezone = timezone
 }
 
+// SetUseMmap reads uncompressed JSONL files through a memory mapping instead
+// of a buffered scanner, which avoids per-line copies on large histories
+func (l *Loader) SetUseMmap(enabled bool) {
+	l.useMmap = enabled
+}
+
+// SetKeepRaw keeps the original log line of every entry in RawJSON, so JSON
+// output can carry fields the loader does not model (request IDs, service
+// tiers, stop reasons). Cached parses do not store the lines, so files are
+// re-read instead of being served from the parse cache.
+func (l *Loader) SetKeepRaw(enabled bool) {
+	l.keepRaw = enabled
+}
+
+// SetIncludeSynthetic keeps entries of the <synthetic> model, which are
+// dropped by default (like the TypeScript version), so totals match the sums
+// of the raw logs
+func (l *Loader) SetIncludeSynthetic(enabled bool) {
+	l.includeSynthetic = enabled
+}
+
+// dropSynthetic reports whether entry is a <synthetic> model entry the loader
+// leaves out. Parsing keeps them, so one parse cache serves both settings.
+func (l *Loader) dropSynthetic(entry types.UsageEntry) bool {
+	return entry.Model == types.SyntheticModel && !l.includeSynthetic
+}
+
 // SetMaxWorkers sets the maximum number of concurrent file read workers
 // This is useful for reducing CPU usage in live monitoring mode
 func (l *Loader) SetMaxWorkers(workers int) {
@@ -66,31 +114,104 @@ func (l *Loader) LoadFromPath(ctx context.Context, path string) ([]types.UsageEn
 
 // LoadFromPathWithOptions loads usage data with optional filters
 func (l *Loader) LoadFromPathWithOptions(ctx context.Context, path string, options *LoaderOptions) ([]types.UsageEntry, error) {
-	// Check if path exists
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		if l.debug {
-			fmt.Fprintf(os.Stderr, "Debug: Path does not exist: %s\n", path)
-		}
-		return nil, fmt.Errorf("path does not exist: %s", path)
+	defer l.beginLoad()()
+
+	paths, err := l.resolveFiles(ctx, path, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Use LoadParallelWithOptions if stream processing is enabled
+	var entries []types.UsageEntry
+	if options != nil && options.StreamProcessing {
+		entries, err = l.LoadParallelWithOptions(ctx, paths, options)
+	} else {
+		entries, err = l.LoadParallel(ctx, paths)
 	}
 	
-	// Look for JSONL files in projects subdirectory
-	projectsPath := filepath.Join(path, "projects")
-	if _, err := os.Stat(projectsPath); err == nil {
-		path = projectsPath
+	if l.debug {
+		fmt.Fprintf(os.Stderr, "Debug: Loaded %d usage entries\n", len(entries))
+		if options != nil && options.StreamProcessing {
+			fmt.Fprintf(os.Stderr, "Debug: Stream processing enabled - costs calculated during loading\n")
+		}
+		
+		// Count valid entries (any entry with timestamp is valid)
+		validCount := 0
+		for _, e := range entries {
+			if !e.Timestamp.IsZero() {
+				validCount++
+			}
+		}
+		fmt.Fprintf(os.Stderr, "Debug: %d entries have valid timestamps\n", validCount)
 	}
+	l.debugDuplicates()
+
+	l.saveCaches()
 	
-	// Find files with optional filtering
+	return entries, err
+}
+
+// resolveFiles lists the JSONL files under path (comma-separated data directories)
+// after applying options, sorted by earliest timestamp
+func (l *Loader) resolveFiles(ctx context.Context, path string, options *LoaderOptions) ([]string, error) {
+	// Multiple data directories may be given as a comma-separated list
 	var paths []string
-	var err error
-	if options != nil && (options.OnlyActiveSession || options.ModifiedWithin > 0) {
-		paths, err = l.findJSONLFilesWithFilter(path, options)
-	} else {
-		paths, err = l.findJSONLFiles(path)
+	var roots []string
+	l.streamed = make(map[string]Source)
+	for _, root := range SplitDataPaths(path) {
+		source, err := l.openSource(ctx, root)
+		if err != nil {
+			return nil, err
+		}
+		dir, ok := source.(*DirSource)
+		if !ok {
+			// Other sources are only listed and opened through the interface
+			found, err := source.ListFiles(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list files of %s: %w", root, err)
+			}
+			for _, name := range found {
+				l.streamed[name] = source
+			}
+			roots = append(roots, root)
+			paths = append(paths, found...)
+			continue
+		}
+		root = dir.Root
+
+		// Check if path exists
+		if _, err := os.Stat(root); os.IsNotExist(err) {
+			if l.debug {
+				fmt.Fprintf(os.Stderr, "Debug: Path does not exist: %s\n", root)
+			}
+			continue
+		}
+
+		// Look for JSONL files in projects subdirectory
+		root = resolveProjectsDir(root)
+		roots = append(roots, root)
+
+		// Find files with optional filtering
+		var found []string
+		if options != nil && (options.OnlyActiveSession || options.ModifiedWithin > 0) {
+			found, err = l.findJSONLFilesWithFilter(root, options)
+		} else {
+			found, err = dir.ListFiles(ctx)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to find JSONL files: %w", err)
+		}
+		paths = append(paths, found...)
 	}
-	if err != nil {
-		return nil, fmt.Errorf("failed to find JSONL files: %w", err)
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("path does not exist: %s", path)
 	}
+	path = JoinDataPaths(roots)
+
+	// The same file may be reachable from overlapping data directories
+	paths = uniqueFiles(paths)
+	l.stats.FilesScanned = len(paths)
+	paths = l.filterFiles(paths)
 
 	// Apply MaxFiles limit if specified
 	if options != nil && options.MaxFiles > 0 && len(paths) > options.MaxFiles {
@@ -118,6 +239,11 @@ func (l *Loader) LoadFromPathWithOptions(ctx context.Context, path string, optio
 		return nil, types.ErrDataNotFound
 	}
 
+	// Files outside --since/--until are skipped only after the existence check,
+	// so an empty range yields an empty report rather than "no data"
+	paths = l.pruneByDateRange(paths)
+	l.stats.FilesSkipped = l.stats.FilesScanned - len(paths)
+
 	// Sort files by earliest timestamp (like TypeScript version)
 	sortedPaths, err := l.sortFilesByTimestamp(paths)
 	if err != nil {
@@ -133,46 +259,59 @@ func (l *Loader) LoadFromPathWithOptions(ctx context.Context, path string, optio
 		}
 	}
 
-	// Use LoadParallelWithOptions if stream processing is enabled
-	var entries []types.UsageEntry
-	if options != nil && options.StreamProcessing {
-		entries, err = l.LoadParallelWithOptions(ctx, paths, options)
-	} else {
-		entries, err = l.LoadParallel(ctx, paths)
+	return paths, nil
+}
+
+// saveCaches persists the parse cache, dedupe store and timestamp index, if enabled
+func (l *Loader) saveCaches() {
+	if l.parseCache != nil {
+		if l.debug {
+			hits, resumed, misses := l.parseCache.Stats()
+			fmt.Fprintf(os.Stderr, "Debug: Parse cache: %d files reused, %d resumed after appends, %d files parsed\n", hits, resumed, misses)
+		}
+		if err := l.parseCache.Save(); err != nil && l.debug {
+			fmt.Fprintf(os.Stderr, "Debug: Failed to save parse cache: %v\n", err)
+		}
 	}
-	
-	if l.debug {
-		fmt.Fprintf(os.Stderr, "Debug: Loaded %d usage entries\n", len(entries))
-		if options != nil && options.StreamProcessing {
-			fmt.Fprintf(os.Stderr, "Debug: Stream processing enabled - costs calculated during loading\n")
+	if l.dedupeStore != nil {
+		if err := l.dedupeStore.Save(); err != nil && l.debug {
+			fmt.Fprintf(os.Stderr, "Debug: Failed to save dedupe store: %v\n", err)
 		}
-		
-		// Count valid entries (any entry with timestamp is valid)
-		validCount := 0
-		for _, e := range entries {
-			if !e.Timestamp.IsZero() {
-				validCount++
+	}
+	if l.timestampIndex != nil {
+		if err := l.timestampIndex.Save(); err != nil && l.debug {
+			fmt.Fprintf(os.Stderr, "Debug: Failed to save timestamp index: %v\n", err)
+		}
+	}
+	if l.quarantine != nil {
+		// Quarantine is opt-in, so its outcome is reported without --debug
+		if n := l.quarantine.Len(); n > 0 {
+			if err := l.quarantine.Save(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			} else {
+				fmt.Fprintf(os.Stderr, "Quarantined %d unparseable lines in %s\n", n, l.quarantine.Path())
 			}
 		}
-		fmt.Fprintf(os.Stderr, "Debug: %d entries have valid timestamps\n", validCount)
 	}
-	
-	return entries, err
 }
 
 func (l *Loader) LoadParallel(ctx context.Context, paths []string) ([]types.UsageEntry, error) {
 	return l.LoadParallelWithOptions(ctx, paths, nil)
 }
 
-func (l *Loader) LoadParallelWithOptions(ctx context.Context, paths []string, options *LoaderOptions) ([]types.UsageEntry, error) {
-	type result struct {
-		entries      []types.UsageEntry
-		sessionNames map[string]string
-		err          error
-	}
+// fileResult is the outcome of parsing one file
+type fileResult struct {
+	path   string
+	parsed *parsedFile
+	err    error
+}
 
-	jobs := make(chan string, len(paths))
-	results := make(chan result, len(paths))
+// parseFiles parses paths concurrently with up to maxWorkers workers.
+// Results are returned in the order of paths.
+func (l *Loader) parseFiles(ctx context.Context, paths []string) ([]fileResult, error) {
+	// Each worker writes only its own slot
+	results := make([]fileResult, len(paths))
+	jobs := make(chan int)
 
 	var wg sync.WaitGroup
 	workers := l.maxWorkers
@@ -180,92 +319,108 @@ func (l *Loader) LoadParallelWithOptions(ctx context.Context, paths []string, op
 		workers = len(paths)
 	}
 
-	// Global deduplication map shared across all files
-	var dedupeMutex sync.Mutex
-	globalDedupeMap := make(map[string]bool)
-
 	// Start workers
 	for i := 0; i < workers; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			for path := range jobs {
-				select {
-				case <-ctx.Done():
-					return
-				default:
-					entries, sessionNames, err := l.loadFileWithGlobalDedupe(path, &dedupeMutex, globalDedupeMap)
-
-					// Stream processing: calculate costs immediately if enabled
-					if options != nil && options.StreamProcessing && options.Calculator != nil && err == nil {
-						for i := range entries {
-							options.Calculator.CalculateCost(&entries[i])
-							// Clear most Raw data after cost calculation to save memory
-							// Keep only cache token fields that are needed for aggregation
-							if entries[i].Raw != nil {
-								cacheData := make(map[string]interface{})
-								if cc, exists := entries[i].Raw["cache_creation_input_tokens"]; exists {
-									cacheData["cache_creation_input_tokens"] = cc
-								}
-								if cr, exists := entries[i].Raw["cache_read_input_tokens"]; exists {
-									cacheData["cache_read_input_tokens"] = cr
-								}
-								if resetTime, exists := entries[i].Raw["usage_limit_reset_time"]; exists {
-									cacheData["usage_limit_reset_time"] = resetTime
-								}
-								if len(cacheData) > 0 {
-									entries[i].Raw = cacheData
-								} else {
-									entries[i].Raw = nil
-								}
-							}
-						}
-					}
-
-					results <- result{entries: entries, sessionNames: sessionNames, err: err}
-				}
+			for idx := range jobs {
+				parsed, err := l.readFileEntries(paths[idx])
+				results[idx] = fileResult{path: paths[idx], parsed: parsed, err: err}
 			}
 		}()
 	}
 
 	// Send jobs
-	go func() {
-		defer close(jobs)
-		for _, path := range paths {
-			select {
-			case <-ctx.Done():
-				return
-			case jobs <- path:
-			}
+sendLoop:
+	for idx := range paths {
+		select {
+		case <-ctx.Done():
+			break sendLoop
+		case jobs <- idx:
 		}
-	}()
+	}
+	close(jobs)
+	wg.Wait()
 
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (l *Loader) LoadParallelWithOptions(ctx context.Context, paths []string, options *LoaderOptions) ([]types.UsageEntry, error) {
+	results, err := l.parseFiles(ctx, paths)
+	if err != nil {
+		return nil, err
+	}
 
 	var allEntries []types.UsageEntry
 	var errors []error
 	globalSessionNames := make(map[string]string)
 
-	for res := range results {
+	// Merge in file order so deduplication and session naming do not depend on
+	// worker scheduling (first occurrence in timestamp-sorted files wins)
+	globalDedupeMap := make(map[string]bool)
+	var tally validationTally
+	progress := l.newProgressTracker(ctx, len(paths))
+	for _, res := range results {
 		if res.err != nil {
 			errors = append(errors, res.err)
-		} else {
-			allEntries = append(allEntries, res.entries...)
-			// Merge per-file session name maps (custom-title takes priority)
-			for sid, name := range res.sessionNames {
-				if _, exists := globalSessionNames[sid]; !exists {
-					globalSessionNames[sid] = name
+			progress.fileDone(res.path, res.err)
+			continue
+		}
+		tally.add(res.path, res.parsed)
+
+		entries := l.dedupeParsedEntries(res.path, res.parsed, globalDedupeMap)
+
+		// Stream processing: calculate costs immediately if enabled
+		if options != nil && options.StreamProcessing && options.Calculator != nil {
+			for i := range entries {
+				options.Calculator.CalculateCost(&entries[i])
+				// Clear most Raw data after cost calculation to save memory
+				// Keep only cache token fields that are needed for aggregation
+				if entries[i].Raw != nil {
+					cacheData := make(map[string]interface{})
+					if cc, exists := entries[i].Raw["cache_creation_input_tokens"]; exists {
+						cacheData["cache_creation_input_tokens"] = cc
+					}
+					if cr, exists := entries[i].Raw["cache_read_input_tokens"]; exists {
+						cacheData["cache_read_input_tokens"] = cr
+					}
+					if resetTime, exists := entries[i].Raw["usage_limit_reset_time"]; exists {
+						cacheData["usage_limit_reset_time"] = resetTime
+					}
+					if len(cacheData) > 0 {
+						entries[i].Raw = cacheData
+					} else {
+						entries[i].Raw = nil
+					}
 				}
 			}
 		}
+
+		for _, entry := range entries {
+			progress.add(entry)
+		}
+		progress.fileDone(res.path, nil)
+
+		allEntries = append(allEntries, entries...)
+		// Merge per-file session name maps (custom-title takes priority)
+		for sid, name := range res.parsed.sessionNames {
+			if _, exists := globalSessionNames[sid]; !exists {
+				globalSessionNames[sid] = name
+			}
+		}
 	}
 
+	l.recordTally(&tally)
 	if len(errors) > 0 && len(allEntries) == 0 {
 		return nil, fmt.Errorf("failed to load any files: %v", errors[0])
 	}
+	if err := l.checkValidation(&tally); err != nil {
+		return nil, err
+	}
 
 	// Global backfill: apply session names across all entries
 	for i := range allEntries {
@@ -283,10 +438,6 @@ func (l *Loader) loadFile(path string) ([]types.UsageEntry, map[string]string, e
 	return l.loadFileWithDedupe(path, dedupeMap)
 }
 
-func (l *Loader) loadFileWithGlobalDedupe(path string, dedupeMutex *sync.Mutex, globalDedupeMap map[string]bool) ([]types.UsageEntry, map[string]string, error) {
-	return l.loadFileWithDedupe(path, globalDedupeMap, dedupeMutex)
-}
-
 // clearRawData removes Raw data from entries to save memory
 func clearRawData(entries []types.UsageEntry) {
 	for i := range entries {
@@ -294,145 +445,301 @@ func clearRawData(entries []types.UsageEntry) {
 	}
 }
 
-func (l *Loader) loadFileWithDedupe(path string, dedupeMap map[string]bool, dedupeMutex ...*sync.Mutex) ([]types.UsageEntry, map[string]string, error) {
-	file, err := os.Open(path)
+func (l *Loader) loadFileWithDedupe(path string, dedupeMap map[string]bool) ([]types.UsageEntry, map[string]string, error) {
+	parsed, err := l.readFileEntries(path)
 	if err != nil {
-		return nil, nil, types.LoaderError{Path: path, Err: err}
+		return nil, nil, err
 	}
-	defer file.Close()
-
-	// Extract project path from file path
-	// File path format: /path/to/claude/projects/project-name/YYYY/MM/DD/file.jsonl
-	projectPath := l.extractProjectPath(path)
+	return l.dedupeParsedEntries(path, parsed, dedupeMap), parsed.sessionNames, nil
+}
 
+// dedupeParsedEntries returns the entries of parsed whose messageId:requestId
+// has not been seen yet, recording new keys in dedupeMap (like TypeScript).
+// With a dedupe store, keys first seen in another file on an earlier run are
+// dropped as well. Entries outside the date range are dropped after their key
+// is recorded, so which copy of a request wins does not depend on the range.
+func (l *Loader) dedupeParsedEntries(path string, parsed *parsedFile, dedupeMap map[string]bool) []types.UsageEntry {
 	var entries []types.UsageEntry
-	scanner := bufio.NewScanner(file)
-	
-	// Increase buffer size to handle very long lines (like TypeScript version)
-	buf := make([]byte, 0, 64*1024)  // Start with 64KB
-	scanner.Buffer(buf, 1024*1024)  // Allow up to 1MB per line
-	
-	lineNum := 0
-	parseErrors := 0
-	firstError := ""
-	sessionNameMap := make(map[string]string)
-
-	for scanner.Scan() {
-		lineNum++
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
+	for i, entry := range parsed.entries {
+		if l.dropSynthetic(entry) {
 			continue
 		}
-
-		var raw map[string]interface{}
-		if err := json.Unmarshal([]byte(line), &raw); err != nil {
-			parseErrors++
-			if firstError == "" && l.debug {
-				firstError = fmt.Sprintf("Line %d: JSON parse error: %v", lineNum, err)
+		uniqueHash := parsed.dedupeKeys[i]
+		if uniqueHash != "" {
+			if dedupeMap[uniqueHash] {
+				l.recordDuplicate(path, false)
+				continue // Skip duplicate
 			}
-			continue // Skip malformed JSON lines
+			if l.dedupeStore != nil && !l.dedupeStore.claim(uniqueHash, path) {
+				l.recordDuplicate(path, true)
+				continue // Duplicate of an entry from another file seen on an earlier run
+			}
+			// Only a kept copy is recorded, so the owning file's copy survives
+			// when another file of this load was rejected by the store first
+			dedupeMap[uniqueHash] = true
+		}
+		if !l.inDateRange(entry.Timestamp) {
+			continue
 		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
 
-		// Intercept custom-title and agent-name entries for session name mapping
-		if typeStr, ok := raw["type"].(string); ok {
-			if typeStr == "custom-title" {
-				if title, ok := raw["customTitle"].(string); ok {
-					if sid, ok := raw["sessionId"].(string); ok {
-						sessionNameMap[sid] = title
-					}
-				}
-				continue
-			}
-			if typeStr == "agent-name" {
-				if name, ok := raw["agentName"].(string); ok {
-					if sid, ok := raw["sessionId"].(string); ok {
-						if _, exists := sessionNameMap[sid]; !exists {
-							sessionNameMap[sid] = name
-						}
-					}
-				}
-				continue
+// readFileEntries returns the parsed contents of a file, served from the
+// persistent parse cache when the file is unchanged since it was last parsed
+func (l *Loader) readFileEntries(path string) (*parsedFile, error) {
+	if _, ok := l.streamed[path]; ok || (l.parseCache == nil && l.timestampIndex == nil) {
+		return l.parseFile(path, -1)
+	}
+
+	// Stat before reading; only the bytes up to this size are parsed and cached
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, types.LoaderError{Path: path, Err: err}
+	}
+	if l.parseCache != nil && !l.keepRaw {
+		if parsed, ok := l.parseCache.lookup(path, info, l.timezone); ok && !l.needsQuarantine(parsed) {
+			if l.timestampIndex != nil {
+				l.timestampIndex.record(path, info, parsed)
 			}
+			return parsed, nil
 		}
+	}
+	return l.parseAndCache(path, info)
+}
 
-		// Try to parse entry according to TypeScript schema rules
-		entry, err := l.parseEntry(raw, projectPath)
-		entry.SourceFile = path
+// parseAndCache parses the first info.Size() bytes of a file the parse cache
+// cannot serve as is, and caches the result. Lines appended after info was
+// taken are left to the next load rather than cached past the fingerprinted
+// size, where resuming would parse them a second time.
+func (l *Loader) parseAndCache(path string, info os.FileInfo) (*parsedFile, error) {
+	// Changed files that were only touched or appended to reuse the cached prefix
+	var parsed *parsedFile
+	var err error
+	resumed := false
+	if l.parseCache != nil && !l.keepRaw {
+		parsed, resumed = l.resumeFromCache(path, info)
+		if resumed && l.needsQuarantine(parsed) {
+			resumed = false
+		}
+	}
+	if !resumed {
+		parsed, err = l.parseFile(path, info.Size())
 		if err != nil {
-			// TypeScript version would skip this line silently
-			// Only count as parse error if it's an actual JSON structure we expect to handle
-			if l.shouldCountAsParseError(err, raw) {
-				parseErrors++
-				if firstError == "" && l.debug {
-					firstError = fmt.Sprintf("Line %d: Entry parse error: %v", lineNum, err)
-				}
-			}
-			continue // Skip entries that fail to parse
+			return nil, err
 		}
+	}
+	if l.parseCache != nil {
+		l.parseCache.countParse(resumed)
+		l.parseCache.store(path, info, parsed)
+	}
+	if l.timestampIndex != nil {
+		l.timestampIndex.record(path, info, parsed)
+	}
+	return parsed, nil
+}
 
-		// Skip entries with zero timestamp (invalid date)
-		if entry.Timestamp.IsZero() || entry.Timestamp.Year() < 2020 {
-			continue
+// parseFile reads and parses the usage entries of the first limit bytes of a
+// JSONL file (all of it when limit is negative) without deduplication
+func (l *Loader) parseFile(path string, limit int64) (*parsedFile, error) {
+	var scanner lineReader
+	var file io.Closer
+	var err error
+	if _, ok := l.streamed[path]; ok {
+		var r io.ReadCloser
+		if r, err = l.openFile(path); err == nil {
+			scanner, file = newBufferedLines(r), r
 		}
-		
-		// Skip synthetic model entries (matches TypeScript behavior)
-		if entry.Model == "<synthetic>" {
+	} else {
+		scanner, file, err = openLineReader(path, l.useMmap, limit)
+	}
+	if err != nil {
+		return nil, types.LoaderError{Path: path, Err: err}
+	}
+	defer file.Close()
+
+	parsed := &parsedFile{sessionNames: make(map[string]string)}
+	lp := l.newLineParser(path)
+
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+		if err := scanner.LineErr(); err != nil {
+			lp.skipLine(lineNum, err, parsed)
 			continue
 		}
-		
-		// Implement deduplication based on message ID and request ID (like TypeScript)
-		uniqueHash := l.createUniqueHash(raw)
-		if uniqueHash != "" {
-			// Use mutex if provided (for global dedupe)
-			if len(dedupeMutex) > 0 && dedupeMutex[0] != nil {
-				dedupeMutex[0].Lock()
-				if dedupeMap[uniqueHash] {
-					dedupeMutex[0].Unlock()
-					continue // Skip duplicate
-				}
-				dedupeMap[uniqueHash] = true
-				dedupeMutex[0].Unlock()
-			} else {
-				// Local dedupe without mutex
-				if dedupeMap[uniqueHash] {
-					continue // Skip duplicate
-				}
-				dedupeMap[uniqueHash] = true
+		lp.parseLine(bytes.TrimSpace(scanner.Bytes()), lineNum, parsed)
+	}
+	parsed.lastLine = lineNum
+	parsed.codex = lp.codex != nil
+
+	if l.debug && parsed.invalidLines > 0 {
+		fmt.Fprintf(os.Stderr, "Debug: File %s had %d parse errors\n", filepath.Base(path), parsed.invalidLines)
+		first := parsed.parseErrors[0]
+		fmt.Fprintf(os.Stderr, "  First error: Line %d: %v\n", first.Line, first.Err)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, types.LoaderError{Path: path, Err: err}
+	}
+
+	return parsed, nil
+}
+
+// lineParser turns the lines of one file into usage entries, carrying the
+// per-file state (format detection, Codex session context) between lines.
+// parseFile and Tailer share it.
+type lineParser struct {
+	loader      *Loader
+	path        string
+	projectPath string
+	sessionID   string        // Session of entries without a sessionId, from the file name
+	sidechain   bool          // The file is a sub-agent log
+	codex       *codexSession // Non-nil once the file is detected as a Codex CLI log
+	detected    bool
+}
+
+func (l *Loader) newLineParser(path string) *lineParser {
+	// Extract project path from file path
+	// File path format: /path/to/claude/projects/project-name/YYYY/MM/DD/file.jsonl
+	return &lineParser{
+		loader:      l,
+		path:        path,
+		projectPath: l.extractProjectPath(path),
+		sessionID:   sessionIDFromPath(path),
+		sidechain:   isSubagentLog(path),
+	}
+}
+
+// skipLine records a line that could not be read at all. Unlike malformed
+// lines it is also reported on stderr, since it may well hold a usage entry.
+func (p *lineParser) skipLine(lineNum int, err error, parsed *parsedFile) {
+	parsed.lines++
+	parsed.recordParseError(lineNum, err)
+	p.loader.quarantine.add(p.path, lineNum, nil, err)
+	fmt.Fprintf(os.Stderr, "Warning: %s:%d: %v\n", p.path, lineNum, err)
+}
+
+// rejectLine records a line that failed to parse and quarantines it
+func (p *lineParser) rejectLine(lineNum int, line []byte, err error, parsed *parsedFile) {
+	parsed.recordParseError(lineNum, err)
+	p.loader.quarantine.add(p.path, lineNum, line, err)
+}
+
+// parseLine parses one trimmed line, appending its entry (if any) to parsed
+func (p *lineParser) parseLine(line []byte, lineNum int, parsed *parsedFile) {
+	if len(line) == 0 {
+		return
+	}
+	parsed.lines++
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(line, &raw); err != nil {
+		p.rejectLine(lineNum, line, fmt.Errorf("JSON parse error: %w", err), parsed)
+		return // Skip malformed JSON lines
+	}
+
+	// The first record tells Codex CLI session logs apart from Claude Code logs
+	if !p.detected {
+		p.detected = true
+		if isCodexRecord(raw) {
+			p.codex = newCodexSession(p.path)
+		}
+	}
+	if p.codex != nil {
+		entry, ok, err := p.codex.handle(p.loader, raw)
+		if err != nil {
+			p.rejectLine(lineNum, line, fmt.Errorf("codex entry parse error: %w", err), parsed)
+		} else if ok {
+			entry.SourceFile = p.path
+			if p.loader.keepRaw {
+				entry.RawJSON = append(json.RawMessage(nil), line...)
 			}
+			parsed.entries = append(parsed.entries, entry)
+			parsed.dedupeKeys = append(parsed.dedupeKeys, "") // Codex turns have no request IDs
 		}
+		return
+	}
 
-		// For stream processing, we can clear most of Raw data after parsing
-		// Keep only cache token fields if they exist
-		if entry.Raw != nil {
-			cacheData := make(map[string]interface{})
-			if cc, ok := entry.Raw["cache_creation_input_tokens"]; ok {
-				cacheData["cache_creation_input_tokens"] = cc
-			}
-			if cr, ok := entry.Raw["cache_read_input_tokens"]; ok {
-				cacheData["cache_read_input_tokens"] = cr
+	// Intercept custom-title and agent-name entries for session name mapping
+	if typeStr, ok := raw["type"].(string); ok {
+		if typeStr == "custom-title" {
+			if title, ok := raw["customTitle"].(string); ok {
+				if sid, ok := raw["sessionId"].(string); ok {
+					parsed.sessionNames[sid] = title
+				}
 			}
-			if len(cacheData) > 0 {
-				entry.Raw = cacheData
-			} else {
-				entry.Raw = nil
+			return
+		}
+		if typeStr == "agent-name" {
+			if name, ok := raw["agentName"].(string); ok {
+				if sid, ok := raw["sessionId"].(string); ok {
+					if _, exists := parsed.sessionNames[sid]; !exists {
+						parsed.sessionNames[sid] = name
+					}
+				}
 			}
+			return
 		}
-		
-		entries = append(entries, entry)
 	}
 
-	if l.debug && parseErrors > 0 {
-		fmt.Fprintf(os.Stderr, "Debug: File %s had %d parse errors\n", filepath.Base(path), parseErrors)
-		if firstError != "" {
-			fmt.Fprintf(os.Stderr, "  First error: %s\n", firstError)
+	// Try to parse entry according to TypeScript schema rules
+	entry, err := p.loader.parseEntry(raw, p.projectPath)
+	entry.SourceFile = p.path
+	if entry.SessionID == "" {
+		entry.SessionID = p.sessionID
+	}
+	if p.sidechain {
+		entry.IsSidechain = true // Older sub-agent logs lack isSidechain
+	}
+	if err != nil {
+		// TypeScript version would skip this line silently
+		// Only count as parse error if it's an actual JSON structure we expect to handle
+		if p.loader.shouldCountAsParseError(err, raw) {
+			p.rejectLine(lineNum, line, fmt.Errorf("entry parse error: %w", err), parsed)
 		}
+		return // Skip entries that fail to parse
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, nil, types.LoaderError{Path: path, Err: err}
+	// Skip entries with a missing or implausible timestamp, counting them so
+	// the loss shows up in parse error reports and the loader stats
+	if entry.Timestamp.IsZero() || entry.Timestamp.Year() < 2020 {
+		parsed.badTimestamps++
+		p.rejectLine(lineNum, line, fmt.Errorf("invalid timestamp: %v", raw["timestamp"]), parsed)
+		return
 	}
 
-	return entries, sessionNameMap, nil
+	// Synthetic model entries are kept here and dropped after parsing unless
+	// included (see dropSynthetic)
+
+	// Keep the dedupe key; duplicates are dropped by the caller across files
+	uniqueHash := p.loader.createUniqueHash(raw)
+
+	// For stream processing, we can clear most of Raw data after parsing
+	// Keep only cache token fields if they exist
+	if entry.Raw != nil {
+		cacheData := make(map[string]interface{})
+		if cc, ok := entry.Raw["cache_creation_input_tokens"]; ok {
+			cacheData["cache_creation_input_tokens"] = cc
+		}
+		if cr, ok := entry.Raw["cache_read_input_tokens"]; ok {
+			cacheData["cache_read_input_tokens"] = cr
+		}
+		if len(cacheData) > 0 {
+			entry.Raw = cacheData
+		} else {
+			entry.Raw = nil
+		}
+	}
+	if p.loader.keepRaw {
+		// line may point into a reused read buffer or a mapping
+		entry.RawJSON = append(json.RawMessage(nil), line...)
+	}
+	
+	parsed.entries = append(parsed.entries, entry)
+	parsed.dedupeKeys = append(parsed.dedupeKeys, uniqueHash)
 }
 
 func (l *Loader) parseEntry(raw map[string]interface{}, filePath string) (types.UsageEntry, error) {
@@ -446,36 +753,9 @@ func (l *Loader) parseEntry(raw map[string]interface{}, filePath string) (types.
 		entry.ID = id
 	}
 
-	// Parse timestamp - try multiple formats
-	if ts, ok := raw["timestamp"].(string); ok {
-		// Try multiple time formats
-		formats := []string{
-			time.RFC3339,
-			time.RFC3339Nano,
-			"2006-01-02T15:04:05.999999999Z07:00",
-			"2006-01-02T15:04:05Z",
-			"2006-01-02T15:04:05.999Z",
-		}
-		
-		var parsedTime time.Time
-		var parseErr error
-		for _, format := range formats {
-			parsedTime, parseErr = time.Parse(format, ts)
-			if parseErr == nil {
-				entry.Timestamp = parsedTime
-				break
-			}
-		}
-		
-		// If all formats fail, try parsing as Unix timestamp
-		if parseErr != nil {
-			if tsFloat, ok := raw["timestamp"].(float64); ok {
-				entry.Timestamp = time.Unix(int64(tsFloat), 0)
-			}
-		}
-	} else if tsFloat, ok := raw["timestamp"].(float64); ok {
-		// Handle numeric timestamp
-		entry.Timestamp = time.Unix(int64(tsFloat), 0)
+	// Parse timestamp (RFC 3339 with any offset, space-separated, or Unix epoch)
+	if ts, ok := parseTimestamp(raw["timestamp"]); ok {
+		entry.Timestamp = ts
 	}
 
 	// Apply timezone conversion and set DateKey (matching TypeScript's formatDate)
@@ -505,8 +785,10 @@ func (l *Loader) parseEntry(raw map[string]interface{}, filePath string) (types.
 
 	if cost, ok := raw["cost"].(float64); ok {
 		entry.Cost = cost
+		entry.CostFromLog = true
 	} else if costUSD, ok := raw["costUSD"].(float64); ok {
 		entry.Cost = costUSD
+		entry.CostFromLog = true
 	}
 
 	if sessionID, ok := raw["session_id"].(string); ok {
@@ -517,6 +799,23 @@ func (l *Loader) parseEntry(raw map[string]interface{}, filePath string) (types.
 		entry.BlockType = blockType
 	}
 
+	// Sub-agent (Task tool) requests are logged as sidechains
+	if sidechain, ok := raw["isSidechain"].(bool); ok {
+		entry.IsSidechain = sidechain
+	}
+	if agentID, ok := raw["agentId"].(string); ok {
+		entry.AgentID = agentID
+	}
+	if cwd, ok := raw["cwd"].(string); ok {
+		entry.Cwd = cwd
+	}
+	// Merged team exports label each line with who made the request
+	if account, ok := raw["account"].(string); ok && account != "" {
+		entry.Account = account
+	} else if userID, ok := raw["userId"].(string); ok {
+		entry.Account = userID
+	}
+
 	// Parse cache-related fields (for flat structure)
 	if cacheCreate, ok := raw["cache_creation_input_tokens"].(float64); ok {
 		if entry.Raw == nil {
@@ -560,24 +859,6 @@ func (l *Loader) createUniqueHash(raw map[string]interface{}) string {
 	return messageID + ":" + requestID
 }
 
-func (l *Loader) findJSONLFiles(basePath string) ([]string, error) {
-	var files []string
-
-	err := filepath.Walk(basePath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil // Continue walking, ignore inaccessible files
-		}
-
-		if !info.IsDir() && strings.HasSuffix(strings.ToLower(path), ".jsonl") {
-			files = append(files, path)
-		}
-
-		return nil
-	})
-
-	return files, err
-}
-
 // findJSONLFilesWithFilter finds JSONL files with optional time-based filtering
 func (l *Loader) findJSONLFilesWithFilter(basePath string, options *LoaderOptions) ([]string, error) {
 	var files []string
@@ -638,7 +919,7 @@ func (l *Loader) findProjectDirectories(basePath string) ([]string, error) {
 	
 	// Collect all subdirectories (these are project directories in flat structure)
 	for _, entry := range entries {
-		if entry.IsDir() {
+		if isDirEntry(basePath, entry) {
 			projectPath := filepath.Join(basePath, entry.Name())
 			projectDirs = append(projectDirs, projectPath)
 		}
@@ -659,7 +940,7 @@ func (l *Loader) shouldSkipProject(projectPath string, cutoffTime time.Time) boo
 	hasJSONL := false
 	
 	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(strings.ToLower(entry.Name()), ".jsonl") {
+		if !entry.IsDir() && isJSONLFile(entry.Name()) {
 			hasJSONL = true
 			info, err := entry.Info()
 			if err != nil {
@@ -693,7 +974,7 @@ func (l *Loader) collectProjectFiles(projectPath string, cutoffTime time.Time, a
 			continue // Skip subdirectories in flat structure
 		}
 		
-		if !strings.HasSuffix(strings.ToLower(entry.Name()), ".jsonl") {
+		if !isJSONLFile(entry.Name()) {
 			continue // Skip non-JSONL files
 		}
 		
@@ -816,35 +1097,33 @@ func (l *Loader) sortFilesByTimestamp(files []string) ([]string, error) {
 }
 
 func (l *Loader) getEarliestTimestamp(filePath string) (time.Time, error) {
-	file, err := os.Open(filePath)
+	file, err := l.openFile(filePath)
 	if err != nil {
 		return time.Time{}, err
 	}
 	defer file.Close()
 	
-	scanner := bufio.NewScanner(file)
+	scanner := newBufferedLines(file)
 	var earliestTime time.Time
 	
 	// Scan first few lines to find earliest timestamp
 	lineCount := 0
 	for scanner.Scan() && lineCount < 100 { // Only check first 100 lines for performance
 		lineCount++
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
 			continue
 		}
 		
 		var raw map[string]interface{}
-		if err := json.Unmarshal([]byte(line), &raw); err != nil {
+		if err := json.Unmarshal(line, &raw); err != nil {
 			continue
 		}
 		
 		// Try to parse timestamp
-		if ts, ok := raw["timestamp"].(string); ok {
-			if parsedTime, err := time.Parse(time.RFC3339, ts); err == nil {
-				if earliestTime.IsZero() || parsedTime.Before(earliestTime) {
-					earliestTime = parsedTime
-				}
+		if parsedTime, ok := parseTimestamp(raw["timestamp"]); ok {
+			if earliestTime.IsZero() || parsedTime.Before(earliestTime) {
+				earliestTime = parsedTime
 			}
 		}
 	}
@@ -907,6 +1186,13 @@ func (l *Loader) validateUsageData(raw map[string]interface{}, entry *types.Usag
 		entry.Raw["cache_creation_input_tokens"] = int(cacheCreate)
 	}
 	
+	// cache_creation splits it by cache TTL; 1-hour writes are billed higher
+	if cacheCreation, ok := usage["cache_creation"].(map[string]interface{}); ok {
+		if hour, ok := cacheCreation["ephemeral_1h_input_tokens"].(float64); ok {
+			entry.CacheCreation1hTokens = int(hour)
+		}
+	}
+	
 	// cache_read_input_tokens is optional
 	if cacheRead, ok := usage["cache_read_input_tokens"].(float64); ok {
 		if entry.Raw == nil {
@@ -915,11 +1201,18 @@ func (l *Loader) validateUsageData(raw map[string]interface{}, entry *types.Usag
 		entry.Raw["cache_read_input_tokens"] = int(cacheRead)
 	}
 	
+	// service_tier is optional; batch requests are billed at a discount
+	if tier, ok := usage["service_tier"].(string); ok {
+		entry.ServiceTier = tier
+	}
+	
 	// costUSD is optional
 	if cost, ok := raw["costUSD"].(float64); ok {
 		entry.Cost = cost
+		entry.CostFromLog = true
 	} else if cost, ok := raw["cost"].(float64); ok {
 		entry.Cost = cost
+		entry.CostFromLog = true
 	}
 	
 	// sessionId is optional (various field names)
@@ -935,55 +1228,37 @@ func (l *Loader) validateUsageData(raw map[string]interface{}, entry *types.Usag
 func (l *Loader) extractProjectPath(filePath string) string {
 	// Extract project path from file path
 	// File path format: /path/to/claude/projects/project-name/YYYY/MM/DD/file.jsonl
-	// We want to return the full path including project-name
-	
+	// We want to return the full path including project-name.
+	// Both separators are recognized, so Windows paths work on any platform.
+
 	// Remove the filename first
-	dir := filepath.Dir(filePath)
-	parts := strings.Split(dir, string(os.PathSeparator))
-	
+	dir := filePath[:strings.LastIndexAny(filePath, `/\`)+1]
+	dir = strings.TrimRight(dir, `/\`)
+	if dir == "" {
+		return filepath.Dir(filePath)
+	}
+	parts := pathSegments(dir)
+
 	// Find "projects" directory and include everything up to and including the project
 	for i := 0; i < len(parts); i++ {
 		if parts[i] == "projects" && i+1 < len(parts) {
-			// Check if the structure after projects looks like project/YYYY/MM/DD
-			// If so, we want to include the project directory
-			if i+4 < len(parts) {
-				// Check if parts[i+2], parts[i+3], parts[i+4] look like YYYY/MM/DD
-				possibleYear := parts[i+2]
-				possibleMonth := parts[i+3]
-				possibleDay := parts[i+4]
-				
-				if isNumeric(possibleYear) && len(possibleYear) == 4 &&
-				   isNumeric(possibleMonth) && len(possibleMonth) <= 2 &&
-				   isNumeric(possibleDay) && len(possibleDay) <= 2 {
-					// This looks like the expected structure
-					// Return path up to and including the project directory
-					projectPath := strings.Join(parts[:i+2], string(os.PathSeparator))
-					return projectPath
-				}
-			}
-			// Otherwise just return up to the project directory
-			projectPath := strings.Join(parts[:i+2], string(os.PathSeparator))
-			return projectPath
+			return pathPrefix(dir, parts, i+2)
 		}
 	}
-	
-	// If no "projects" directory, look for common project patterns
-	// Remove date structure from the end if present (YYYY/MM/DD)
+
+	// If no "projects" directory, remove date structure from the end if present (YYYY/MM/DD)
 	if len(parts) >= 3 {
-		// Check last 3 parts for date pattern
 		possibleYear := parts[len(parts)-3]
 		possibleMonth := parts[len(parts)-2]
 		possibleDay := parts[len(parts)-1]
-		
+
 		if isNumeric(possibleYear) && len(possibleYear) == 4 &&
-		   isNumeric(possibleMonth) && len(possibleMonth) <= 2 &&
-		   isNumeric(possibleDay) && len(possibleDay) <= 2 {
-			// Remove date parts to get project directory
-			projectPath := strings.Join(parts[:len(parts)-3], string(os.PathSeparator))
-			return projectPath
+			isNumeric(possibleMonth) && len(possibleMonth) <= 2 &&
+			isNumeric(possibleDay) && len(possibleDay) <= 2 {
+			return pathPrefix(dir, parts, len(parts)-3)
 		}
 	}
-	
+
 	// Fallback: return the directory path as is
 	return dir
 }
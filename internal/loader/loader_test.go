@@ -0,0 +1,64 @@
+package loader
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallelLoadIsDeterministic(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	ts := time.Now().Add(-24 * time.Hour)
+	for i := 0; i < 20; i++ {
+		addProjectFile(t, basePath, fmt.Sprintf("project-%02d", i), "session.jsonl", []string{
+			createTestJSONLEntry(ts.Add(time.Duration(i)*time.Minute), "claude-sonnet-4-5-20250514", i+1, 1, fmt.Sprintf("msg%d", i), fmt.Sprintf("req%d", i)),
+			// Every file repeats the first request; only the earliest file may keep it
+			createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 1000, 1, "msg-shared", "req-shared"),
+		})
+	}
+
+	single := New()
+	expected, err := single.LoadFromPath(context.Background(), basePath)
+	require.NoError(t, err)
+	require.Len(t, expected, 21)
+
+	for run := 0; run < 5; run++ {
+		parallel := New()
+		parallel.SetMaxWorkers(8)
+		entries, err := parallel.LoadFromPath(context.Background(), basePath)
+		require.NoError(t, err)
+		assert.Equal(t, expected, entries, "parallel load should match single-worker order and dedupe choice")
+	}
+}
+
+func TestLoadAndAggregateMatchesLoadFromPath(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	ts := time.Now().Add(-24 * time.Hour)
+	for i := 0; i < 6; i++ {
+		addProjectFile(t, basePath, fmt.Sprintf("project-%02d", i), "session.jsonl", []string{
+			createTestJSONLEntry(ts.Add(time.Duration(i)*time.Minute), "claude-sonnet-4-5-20250514", i+1, 1, fmt.Sprintf("msg%d", i), fmt.Sprintf("req%d", i)),
+			createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 1000, 1, "msg-shared", "req-shared"),
+		})
+	}
+
+	expected, err := New().LoadFromPath(context.Background(), basePath)
+	require.NoError(t, err)
+
+	l := New()
+	l.SetMaxWorkers(2) // force several batches
+	var streamed []types.UsageEntry
+	err = l.LoadAndAggregate(context.Background(), basePath, nil, AggregatorFunc(func(entry types.UsageEntry) {
+		streamed = append(streamed, entry)
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, expected, streamed)
+}
@@ -0,0 +1,203 @@
+package loader
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+// maxLineSize is the longest JSONL line the readers parse. Longer lines are
+// skipped and reported rather than aborting the rest of the file. It is a
+// variable so tests can lower it.
+var maxLineSize = 256 * 1024 * 1024
+
+// errLineTooLong is the parse error recorded for lines over maxLineSize
+var errLineTooLong = errors.New("line longer than 256 MiB skipped")
+
+// lineReader yields the lines of a usage log. The slice returned by Bytes is
+// only valid until the next call to Scan. A non-nil LineErr means the current
+// line could not be read; Bytes is then empty and reading goes on with the
+// next line.
+type lineReader interface {
+	Scan() bool
+	Bytes() []byte
+	LineErr() error
+	Err() error
+}
+
+// openLineReader opens path for line-by-line reading, stopping after limit
+// bytes of the file when limit is not negative. With useMmap, plain JSONL
+// files are memory-mapped; compressed files always use a buffered reader.
+func openLineReader(path string, useMmap bool, limit int64) (lineReader, io.Closer, error) {
+	lower := strings.ToLower(path)
+	if useMmap && strings.HasSuffix(lower, jsonlExt) {
+		m, err := openMmapLines(path, limit)
+		if err != nil {
+			return nil, nil, err
+		}
+		return m, m, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var raw io.ReadCloser = file
+	if limit >= 0 {
+		raw = limitedFile{Reader: io.LimitReader(file, limit), Closer: file}
+	}
+	r, err := decompress(path, raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	return newBufferedLines(r), r, nil
+}
+
+// limitedFile reads the start of a file and closes the whole of it
+type limitedFile struct {
+	io.Reader
+	io.Closer
+}
+
+// bufferedLines reads lines through a 64KB buffer. Lines that do not fit are
+// collected in a separate buffer that grows as needed, so huge tool results
+// do not stop the file from being read.
+type bufferedLines struct {
+	r       *bufio.Reader
+	buf     []byte // Holds lines longer than the reader's buffer
+	line    []byte
+	lineErr error
+	err     error
+}
+
+func newBufferedLines(r io.Reader) *bufferedLines {
+	return &bufferedLines{r: bufio.NewReaderSize(r, 64*1024)}
+}
+
+func (b *bufferedLines) Scan() bool {
+	if b.err != nil {
+		return false
+	}
+	b.line, b.lineErr = nil, nil
+
+	chunk, err := b.r.ReadSlice('\n')
+	line := chunk // Most lines fit the reader's buffer and are used in place
+	if err == bufio.ErrBufferFull {
+		b.buf = append(b.buf[:0], chunk...)
+		for err == bufio.ErrBufferFull {
+			chunk, err = b.r.ReadSlice('\n')
+			if b.lineErr != nil {
+				continue // Drain the rest of an overlong line
+			}
+			if len(b.buf)+len(chunk) > maxLineSize+1 {
+				b.lineErr = errLineTooLong
+				b.buf = nil
+				continue
+			}
+			b.buf = append(b.buf, chunk...)
+		}
+		line = b.buf
+	}
+
+	if err != nil && err != io.EOF {
+		b.err = err
+		return false
+	}
+	if err == io.EOF && len(line) == 0 && b.lineErr == nil {
+		return false
+	}
+	if b.lineErr == nil {
+		b.line = bytes.TrimSuffix(bytes.TrimSuffix(line, []byte("\n")), []byte("\r"))
+	}
+	return true
+}
+
+func (b *bufferedLines) Bytes() []byte {
+	return b.line
+}
+
+func (b *bufferedLines) LineErr() error {
+	return b.lineErr
+}
+
+func (b *bufferedLines) Err() error {
+	return b.err
+}
+
+// mmapLines iterates over the lines of a memory-mapped file without copying them
+type mmapLines struct {
+	data    []byte
+	unmap   func() error
+	pos     int
+	line    []byte
+	lineErr error
+	err     error
+}
+
+func openMmapLines(path string, limit int64) (*mmapLines, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() // The mapping stays valid after the descriptor is closed
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	size := info.Size()
+	if limit >= 0 && limit < size {
+		size = limit
+	}
+	m := &mmapLines{unmap: func() error { return nil }}
+	if size > 0 {
+		m.data, m.unmap, err = mmapFile(file, int(size))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (m *mmapLines) Scan() bool {
+	if m.err != nil || m.pos >= len(m.data) {
+		return false
+	}
+	rest := m.data[m.pos:]
+	end := bytes.IndexByte(rest, '\n')
+	if end < 0 {
+		end = len(rest)
+		m.pos = len(m.data)
+	} else {
+		m.pos += end + 1
+	}
+	m.line, m.lineErr = nil, nil
+	if end > maxLineSize {
+		m.lineErr = errLineTooLong
+		return true
+	}
+	m.line = bytes.TrimSuffix(rest[:end], []byte("\r"))
+	return true
+}
+
+func (m *mmapLines) Bytes() []byte {
+	return m.line
+}
+
+func (m *mmapLines) LineErr() error {
+	return m.lineErr
+}
+
+func (m *mmapLines) Err() error {
+	return m.err
+}
+
+// Close unmaps the file; lines returned earlier must not be used afterwards
+func (m *mmapLines) Close() error {
+	m.data, m.line = nil, nil
+	return m.unmap()
+}
@@ -0,0 +1,109 @@
+package loader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMmapReaderMatchesScanner(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	ts := time.Now().Add(-time.Hour)
+	addProjectFile(t, basePath, "project-a", "session.jsonl", []string{
+		createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 100, 50, "msg1", "req1"),
+		"",
+		"{not json",
+		createTestJSONLEntry(ts.Add(time.Minute), "claude-sonnet-4-5-20250514", 200, 100, "msg2", "req2"),
+	})
+
+	projectDir := filepath.Join(basePath, "projects", "project-a")
+	// CRLF line endings and a missing trailing newline
+	crlf := createTestJSONLEntry(ts.Add(2*time.Minute), "claude-opus-4-1-20250805", 300, 150, "msg3", "req3") + "\r\n" +
+		createTestJSONLEntry(ts.Add(3*time.Minute), "claude-opus-4-1-20250805", 400, 200, "msg4", "req4")
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "crlf.jsonl"), []byte(crlf), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "empty.jsonl"), nil, 0o644))
+
+	expected, err := New().LoadFromPath(context.Background(), basePath)
+	require.NoError(t, err)
+	require.Len(t, expected, 4)
+
+	l := New()
+	l.SetUseMmap(true)
+	entries, err := l.LoadFromPath(context.Background(), basePath)
+	require.NoError(t, err)
+	assert.Equal(t, expected, entries)
+}
+
+func TestOverlongLinesAreSkipped(t *testing.T) {
+	defer func(size int) { maxLineSize = size }(maxLineSize)
+	maxLineSize = 100 * 1024 // Longer than the 64KB read buffer
+
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	ts := time.Now().Add(-time.Hour)
+	model := "claude-sonnet-4-5-20250514"
+	// Lines of just under and over the limit, padded with a huge tool result
+	padded := func(minute int, msgID string, size int) string {
+		line := createTestJSONLEntry(ts.Add(time.Duration(minute)*time.Minute), model, 100, 10, msgID, "req-"+msgID)
+		return line[:len(line)-1] + `,"toolUseResult":"` + strings.Repeat("x", size-len(line)-19) + `"}`
+	}
+	long := padded(1, "long", maxLineSize)
+	require.Len(t, long, maxLineSize)
+	path := addProjectFile(t, basePath, "project-a", "session.jsonl", []string{
+		createTestJSONLEntry(ts, model, 100, 10, "msg1", "req1"),
+		long,
+		padded(2, "huge", maxLineSize+1),
+		createTestJSONLEntry(ts.Add(3*time.Minute), model, 100, 10, "msg2", "req2"),
+	})
+
+	for _, useMmap := range []bool{false, true} {
+		l := New()
+		l.SetUseMmap(useMmap)
+		parsed, err := l.parseFile(path, -1)
+		require.NoError(t, err, "useMmap=%v", useMmap)
+		require.Len(t, parsed.entries, 3, "useMmap=%v", useMmap)
+		assert.Equal(t, time.Minute, parsed.entries[1].Timestamp.Sub(parsed.entries[0].Timestamp), "the line at the limit is kept")
+		assert.Equal(t, 4, parsed.lines)
+		require.Len(t, parsed.parseErrors, 1)
+		assert.Equal(t, 3, parsed.parseErrors[0].Line)
+		assert.ErrorIs(t, parsed.parseErrors[0].Err, errLineTooLong)
+	}
+}
+
+func BenchmarkParseFile(b *testing.B) {
+	path := filepath.Join(b.TempDir(), "history.jsonl")
+	var sb strings.Builder
+	ts := time.Now().Add(-24 * time.Hour)
+	for i := 0; i < 20000; i++ {
+		sb.WriteString(createTestJSONLEntry(ts.Add(time.Duration(i)*time.Second), "claude-sonnet-4-5-20250514", i, i, fmt.Sprintf("msg%d", i), fmt.Sprintf("req%d", i)))
+		sb.WriteByte('\n')
+	}
+	require.NoError(b, os.WriteFile(path, []byte(sb.String()), 0o644))
+
+	for _, mode := range []struct {
+		name    string
+		useMmap bool
+	}{{"scanner", false}, {"mmap", true}} {
+		b.Run(mode.name, func(b *testing.B) {
+			l := New()
+			l.SetUseMmap(mode.useMmap)
+			b.SetBytes(int64(sb.Len()))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := l.parseFile(path, -1); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
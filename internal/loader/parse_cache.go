@@ -0,0 +1,356 @@
+package loader
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/cachedir"
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// parseCacheVersion is bumped whenever the cached entry layout or parsing rules change
+const parseCacheVersion = 15
+
+// parsedFile holds the parsed, not yet deduplicated contents of a JSONL file
+type parsedFile struct {
+	entries       []types.UsageEntry
+	dedupeKeys    []string // messageId:requestId per entry ("" when not deduplicable)
+	sessionNames  map[string]string
+	lines         int                // Non-empty lines read
+	invalidLines  int                // Lines that failed JSON or schema validation
+	badTimestamps int                // Usage entries dropped for a missing or invalid timestamp (also invalid lines)
+	parseErrors   []types.ParseError // The first maxRecordedParseErrors failures
+	lastLine      int                // Number of the last line read, including blank ones
+	codex         bool               // Parsed as a Codex CLI session log
+}
+
+// maxRecordedParseErrors caps the per-file parse errors kept for reporting
+const maxRecordedParseErrors = 100
+
+// recordParseError counts an invalid line, keeping its details if under the cap
+func (p *parsedFile) recordParseError(line int, err error) {
+	p.invalidLines++
+	if len(p.parseErrors) < maxRecordedParseErrors {
+		p.parseErrors = append(p.parseErrors, types.ParseError{Line: line, Err: err})
+	}
+}
+
+// cachedEntry is the compact on-disk form of a parsed usage entry
+type cachedEntry struct {
+	DedupeKey        string
+	ID               string
+	Timestamp        time.Time
+	ProjectPath      string
+	Model            string
+	SessionID        string
+	BlockType        string
+	IsSidechain      bool
+	AgentID          string
+	Cwd              string
+	ServiceTier      string
+	Account          string
+	InputTokens      int
+	OutputTokens     int
+	CacheCreation    int
+	CacheRead        int
+	CacheCreation1h  int
+	HasCacheCreation bool
+	HasCacheRead     bool
+	Cost             float64
+	CostFromLog      bool
+}
+
+// cachedFile is the cached parse result of a single file, valid while size
+// and mtime match, or while the fingerprinted prefix is unchanged (see resumeFromCache)
+type cachedFile struct {
+	ModTime       int64 // UnixNano
+	Size          int64
+	Checksum      uint32 // CRC-32C of the first Size bytes
+	EndsLine      bool   // The first Size bytes end with a newline
+	LastLine      int
+	Codex         bool
+	Entries       []cachedEntry
+	SessionNames  map[string]string
+	Lines         int
+	InvalidLines  int
+	BadTimestamps int
+	ParseErrors   []cachedParseError
+}
+
+// cachedParseError is the on-disk form of a recorded parse error
+type cachedParseError struct {
+	Line    int
+	Message string
+}
+
+type parseCacheData struct {
+	Version int
+	Files   map[string]*cachedFile // absolute file path → parse result
+}
+
+// ParseCache persists per-file parse results between invocations so that
+// unchanged JSONL files do not need to be re-read and re-parsed
+type ParseCache struct {
+	path    string
+	mu      sync.Mutex
+	files   map[string]*cachedFile
+	seen    map[string]bool
+	dirty   bool
+	hits    int
+	resumed int
+	misses  int
+}
+
+// DefaultParseCachePath returns the default parse cache location (index.db in the cache directory)
+func DefaultParseCachePath() (string, error) {
+	return cachedir.Path("index.db")
+}
+
+// OpenParseCache loads the parse cache stored at path. A missing, unreadable or
+// outdated cache file yields an empty cache rather than an error.
+func OpenParseCache(path string) *ParseCache {
+	c := &ParseCache{
+		path:  path,
+		files: make(map[string]*cachedFile),
+		seen:  make(map[string]bool),
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return c
+	}
+	defer file.Close()
+
+	var data parseCacheData
+	if err := gob.NewDecoder(file).Decode(&data); err != nil || data.Version != parseCacheVersion {
+		c.dirty = true // Rewrite corrupt or outdated cache on save
+		return c
+	}
+	if data.Files != nil {
+		c.files = data.Files
+	}
+	return c
+}
+
+// SetParseCache enables the persistent parse cache for subsequent loads
+func (l *Loader) SetParseCache(cache *ParseCache) {
+	l.parseCache = cache
+}
+
+// Stats returns the number of files served from the cache, the number resumed
+// from a cached prefix and the number parsed in full
+func (c *ParseCache) Stats() (hits, resumed, misses int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.resumed, c.misses
+}
+
+// countParse records how a file missing from the cache was read
+func (c *ParseCache) countParse(resumed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if resumed {
+		c.resumed++
+	} else {
+		c.misses++
+	}
+}
+
+func (c *ParseCache) lookup(path string, info os.FileInfo, timezone *time.Location) (*parsedFile, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.seen[path] = true
+	cached, ok := c.files[path]
+	if !ok || cached.Size != info.Size() || cached.ModTime != info.ModTime().UnixNano() {
+		return nil, false
+	}
+	c.hits++
+	return cached.toParsed(path, timezone), true
+}
+
+// get returns the cached result for path regardless of its fingerprint
+func (c *ParseCache) get(path string) *cachedFile {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.files[path]
+}
+
+func (cached *cachedFile) toParsed(path string, timezone *time.Location) *parsedFile {
+	parsed := &parsedFile{
+		entries:       make([]types.UsageEntry, len(cached.Entries)),
+		dedupeKeys:    make([]string, len(cached.Entries)),
+		sessionNames:  make(map[string]string, len(cached.SessionNames)),
+		lines:         cached.Lines,
+		invalidLines:  cached.InvalidLines,
+		badTimestamps: cached.BadTimestamps,
+		lastLine:      cached.LastLine,
+		codex:         cached.Codex,
+	}
+	for _, pe := range cached.ParseErrors {
+		parsed.parseErrors = append(parsed.parseErrors, types.ParseError{Line: pe.Line, Err: errors.New(pe.Message)})
+	}
+	for i, ce := range cached.Entries {
+		parsed.entries[i] = ce.toUsageEntry(path, timezone)
+		parsed.dedupeKeys[i] = ce.DedupeKey
+	}
+	for sid, name := range cached.SessionNames {
+		parsed.sessionNames[sid] = name
+	}
+	return parsed
+}
+
+func (c *ParseCache) store(path string, info os.FileInfo, parsed *parsedFile) {
+	cached := &cachedFile{
+		ModTime:       info.ModTime().UnixNano(),
+		Size:          info.Size(),
+		LastLine:      parsed.lastLine,
+		Codex:         parsed.codex,
+		Entries:       make([]cachedEntry, len(parsed.entries)),
+		SessionNames:  make(map[string]string, len(parsed.sessionNames)),
+		Lines:         parsed.lines,
+		InvalidLines:  parsed.invalidLines,
+		BadTimestamps: parsed.badTimestamps,
+	}
+	for _, pe := range parsed.parseErrors {
+		cached.ParseErrors = append(cached.ParseErrors, cachedParseError{Line: pe.Line, Message: pe.Err.Error()})
+	}
+	for i, entry := range parsed.entries {
+		cached.Entries[i] = newCachedEntry(entry, parsed.dedupeKeys[i])
+	}
+	for sid, name := range parsed.sessionNames {
+		cached.SessionNames[sid] = name
+	}
+	if sum, endsLine, err := fingerprintFile(path, info.Size()); err == nil {
+		cached.Checksum, cached.EndsLine = sum, endsLine
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.files[path] = cached
+	c.seen[path] = true
+	c.dirty = true
+}
+
+// Save writes the cache to disk if anything changed. Entries for files that
+// were not visited and no longer exist are dropped.
+func (c *ParseCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for path := range c.files {
+		if c.seen[path] {
+			continue
+		}
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			delete(c.files, path)
+			c.dirty = true
+		}
+	}
+	if !c.dirty {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// Write to a temp file and rename so concurrent runs never read a partial cache
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	data := parseCacheData{Version: parseCacheVersion, Files: c.files}
+	if err := gob.NewEncoder(tmp).Encode(&data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("failed to replace cache: %w", err)
+	}
+
+	c.dirty = false
+	return nil
+}
+
+func newCachedEntry(entry types.UsageEntry, dedupeKey string) cachedEntry {
+	ce := cachedEntry{
+		DedupeKey:    dedupeKey,
+		ID:           entry.ID,
+		Timestamp:    entry.Timestamp,
+		ProjectPath:  entry.ProjectPath,
+		Model:        entry.Model,
+		SessionID:    entry.SessionID,
+		BlockType:    entry.BlockType,
+		IsSidechain:  entry.IsSidechain,
+		AgentID:      entry.AgentID,
+		Cwd:          entry.Cwd,
+		ServiceTier:  entry.ServiceTier,
+		Account:      entry.Account,
+		InputTokens:  entry.InputTokens,
+		OutputTokens: entry.OutputTokens,
+		Cost:         entry.Cost,
+		CostFromLog:  entry.CostFromLog,
+
+		CacheCreation1h: entry.CacheCreation1hTokens,
+	}
+	if cc, ok := entry.Raw["cache_creation_input_tokens"].(int); ok {
+		ce.CacheCreation = cc
+		ce.HasCacheCreation = true
+	}
+	if cr, ok := entry.Raw["cache_read_input_tokens"].(int); ok {
+		ce.CacheRead = cr
+		ce.HasCacheRead = true
+	}
+	return ce
+}
+
+func (ce cachedEntry) toUsageEntry(path string, timezone *time.Location) types.UsageEntry {
+	entry := types.UsageEntry{
+		ID:           ce.ID,
+		Timestamp:    ce.Timestamp,
+		ProjectPath:  ce.ProjectPath,
+		Model:        ce.Model,
+		InputTokens:  ce.InputTokens,
+		OutputTokens: ce.OutputTokens,
+		TotalTokens:  ce.InputTokens + ce.OutputTokens + ce.CacheCreation + ce.CacheRead,
+		Cost:         ce.Cost,
+		CostFromLog:  ce.CostFromLog,
+		SessionID:    ce.SessionID,
+		BlockType:    ce.BlockType,
+		IsSidechain:  ce.IsSidechain,
+		AgentID:      ce.AgentID,
+		Cwd:          ce.Cwd,
+		ServiceTier:  ce.ServiceTier,
+		Account:      ce.Account,
+		SourceFile:   path,
+
+		CacheCreation1hTokens: ce.CacheCreation1h,
+	}
+
+	// DateKey depends on the requested timezone, so it is derived on every load
+	if timezone != nil {
+		entry.DateKey = ce.Timestamp.In(timezone).Format("2006-01-02")
+	}
+
+	if ce.HasCacheCreation || ce.HasCacheRead {
+		entry.Raw = make(map[string]interface{}, 2)
+		if ce.HasCacheCreation {
+			entry.Raw["cache_creation_input_tokens"] = ce.CacheCreation
+		}
+		if ce.HasCacheRead {
+			entry.Raw["cache_read_input_tokens"] = ce.CacheRead
+		}
+	}
+	return entry
+}
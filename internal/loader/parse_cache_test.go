@@ -0,0 +1,182 @@
+package loader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCacheReusesUnchangedFiles(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	sessionID := "11111111-2222-3333-4444-555555555555"
+	ts := time.Now().Add(-time.Hour)
+	addProjectFile(t, basePath, "project-a", sessionID+".jsonl", []string{
+		createCustomTitleLine(sessionID, "cached-session"),
+		createTestJSONLEntryWithSessionID(ts, "claude-sonnet-4-5-20250514", 100, 50, "msg1", "req1", sessionID),
+		createTestJSONLEntryWithSessionID(ts, "claude-sonnet-4-5-20250514", 100, 50, "msg1", "req1", sessionID), // duplicate
+	})
+	fileB := addProjectFile(t, basePath, "project-b", "b.jsonl", []string{
+		createTestJSONLEntry(ts.Add(time.Minute), "claude-opus-4-1-20250805", 10, 5, "msg2", "req2"),
+	})
+
+	cachePath := filepath.Join(basePath, "cache", "index.db")
+
+	// First run parses everything and writes the cache
+	l := New()
+	l.SetParseCache(OpenParseCache(cachePath))
+	first, err := l.LoadFromPath(context.Background(), basePath)
+	require.NoError(t, err)
+	require.Len(t, first, 2)
+	hits, resumed, misses := l.parseCache.Stats()
+	assert.Equal(t, 0, hits)
+	assert.Equal(t, 0, resumed)
+	assert.Equal(t, 2, misses)
+	require.FileExists(t, cachePath)
+
+	// Second run serves both files from the cache with identical results
+	l = New()
+	l.SetParseCache(OpenParseCache(cachePath))
+	second, err := l.LoadFromPath(context.Background(), basePath)
+	require.NoError(t, err)
+	hits, _, misses = l.parseCache.Stats()
+	assert.Equal(t, 2, hits)
+	assert.Equal(t, 0, misses)
+	assert.ElementsMatch(t, first, second)
+
+	// Appending to a file re-reads only that file, parsing just the new line
+	f, err := os.OpenFile(fileB, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = f.WriteString(createTestJSONLEntry(ts.Add(2*time.Minute), "claude-opus-4-1-20250805", 20, 10, "msg3", "req3") + "\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	l = New()
+	l.SetParseCache(OpenParseCache(cachePath))
+	third, err := l.LoadFromPath(context.Background(), basePath)
+	require.NoError(t, err)
+	assert.Len(t, third, 3)
+	hits, resumed, misses = l.parseCache.Stats()
+	assert.Equal(t, 1, hits)
+	assert.Equal(t, 1, resumed)
+	assert.Equal(t, 0, misses)
+}
+
+func TestParseCacheRoundTripsEntryFields(t *testing.T) {
+	ts := time.Now().Add(-time.Hour)
+	line := createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 100, 10, "msg1", "req1")
+
+	tests := []struct {
+		name  string
+		line  string
+		check func(t *testing.T, entry types.UsageEntry)
+	}{
+		{
+			name: "working directory",
+			line: strings.Replace(line, "{", `{"cwd":"/home/me/app/backend",`, 1),
+			check: func(t *testing.T, entry types.UsageEntry) {
+				assert.Equal(t, "/home/me/app/backend", entry.Cwd)
+			},
+		},
+		{
+			name: "batch service tier",
+			line: strings.Replace(line, `"output_tokens":10`, `"output_tokens":10,"service_tier":"batch"`, 1),
+			check: func(t *testing.T, entry types.UsageEntry) {
+				assert.Equal(t, "batch", entry.ServiceTier)
+			},
+		},
+		{
+			name: "account",
+			line: strings.Replace(line, "{", `{"account":"alice",`, 1),
+			check: func(t *testing.T, entry types.UsageEntry) {
+				assert.Equal(t, "alice", entry.Account)
+			},
+		},
+		{
+			name: "account from user ID",
+			line: strings.Replace(line, "{", `{"userId":"bob",`, 1),
+			check: func(t *testing.T, entry types.UsageEntry) {
+				assert.Equal(t, "bob", entry.Account)
+			},
+		},
+		{
+			name: "1-hour cache writes",
+			line: strings.Replace(line, `"output_tokens":10`, `"output_tokens":10,"cache_creation_input_tokens":500,`+
+				`"cache_creation":{"ephemeral_5m_input_tokens":200,"ephemeral_1h_input_tokens":300}`, 1),
+			check: func(t *testing.T, entry types.UsageEntry) {
+				assert.Equal(t, 300, entry.CacheCreation1hTokens)
+				assert.Equal(t, 500, entry.Raw["cache_creation_input_tokens"])
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			basePath := t.TempDir()
+			projectDir := filepath.Join(basePath, "projects", "-home-me-app")
+			require.NoError(t, os.MkdirAll(projectDir, 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(projectDir, "session.jsonl"), []byte(tt.line+"\n"), 0o644))
+			cachePath := filepath.Join(t.TempDir(), "index.db")
+
+			// The first load parses the file, the second reads it back from the saved cache
+			var loads [2][]types.UsageEntry
+			for i := range loads {
+				l := New()
+				l.SetParseCache(OpenParseCache(cachePath))
+				entries, err := l.LoadFromPath(context.Background(), basePath)
+				require.NoError(t, err)
+				require.Len(t, entries, 1)
+				tt.check(t, entries[0])
+				hits, _, _ := l.parseCache.Stats()
+				assert.Equal(t, i, hits)
+				loads[i] = entries
+			}
+			assert.Equal(t, loads[0], loads[1])
+		})
+	}
+}
+
+func TestParseCacheIgnoresCorruptFile(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "index.db")
+	require.NoError(t, os.WriteFile(cachePath, []byte("not a cache"), 0o644))
+
+	cache := OpenParseCache(cachePath)
+	assert.Empty(t, cache.files)
+	require.NoError(t, cache.Save())
+	assert.NotNil(t, OpenParseCache(cachePath).files)
+}
+
+func TestKeepRawBypassesParseCache(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	line := createTestJSONLEntry(time.Now().Add(-time.Hour), "claude-sonnet-4-5-20250514", 100, 50, "msg1", "req1")
+	addProjectFile(t, basePath, "project-a", "a.jsonl", []string{line})
+	cachePath := filepath.Join(basePath, "cache", "index.db")
+
+	l := New()
+	l.SetParseCache(OpenParseCache(cachePath))
+	entries, err := l.LoadFromPath(context.Background(), basePath)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Nil(t, entries[0].RawJSON)
+
+	// The file is cached now, but the cached copy has no log lines
+	l = New()
+	l.SetParseCache(OpenParseCache(cachePath))
+	l.SetKeepRaw(true)
+	entries, err = l.LoadFromPath(context.Background(), basePath)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.JSONEq(t, line, string(entries[0].RawJSON))
+	hits, _, _ := l.parseCache.Stats()
+	assert.Equal(t, 0, hits)
+}
@@ -0,0 +1,47 @@
+package loader
+
+import (
+	"context"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// ParseReport summarizes the lines that failed validation under a data path
+type ParseReport struct {
+	Files        int // Files read successfully
+	TotalLines   int // Non-empty lines across those files
+	InvalidLines int
+	FileErrors   []types.FileValidationCount // Files with invalid lines, worst first
+	ReadErrors   []error                     // Files that could not be read at all
+}
+
+// ScanParseErrors reads every usage file under path and reports which lines
+// failed JSON or schema validation. Normal loads skip such lines silently.
+func (l *Loader) ScanParseErrors(ctx context.Context, path string) (*ParseReport, error) {
+	paths, err := l.resolveFiles(ctx, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer l.saveCaches()
+
+	results, err := l.parseFiles(ctx, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	var tally validationTally
+	report := &ParseReport{}
+	for _, res := range results {
+		if res.err != nil {
+			report.ReadErrors = append(report.ReadErrors, res.err)
+			continue
+		}
+		report.Files++
+		tally.add(res.path, res.parsed)
+	}
+
+	report.TotalLines = tally.total
+	report.InvalidLines = tally.invalid
+	report.FileErrors = tally.worstFiles()
+	return report, nil
+}
@@ -0,0 +1,60 @@
+package loader
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timestampLayouts are the string timestamp formats accepted in usage logs.
+// Fractional seconds are optional in each; layouts without an offset are UTC.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// parseTimestamp reads an entry timestamp: an RFC 3339 string with any
+// offset, the same with a space instead of the "T" or without an offset, or
+// a Unix epoch in seconds, milliseconds, microseconds or nanoseconds given as
+// a number or a numeric string
+func parseTimestamp(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case string:
+		v = strings.TrimSpace(v)
+		for _, layout := range timestampLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, true
+			}
+		}
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return epochTime(n)
+		}
+	case float64:
+		return epochTime(v)
+	}
+	return time.Time{}, false
+}
+
+// epochTime converts a Unix timestamp, telling its unit from its magnitude
+// (seconds stay below 1e11 until the year 5138)
+func epochTime(n float64) (time.Time, bool) {
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return time.Time{}, false
+	}
+	switch abs := math.Abs(n); {
+	case abs >= 1e17:
+		return time.Unix(0, int64(n)), true
+	case abs >= 1e14:
+		return time.UnixMicro(int64(n)), true
+	case abs >= 1e11:
+		return time.UnixMilli(int64(n)), true
+	default:
+		sec, frac := math.Modf(n)
+		return time.Unix(int64(sec), int64(frac*1e9)), true
+	}
+}
@@ -0,0 +1,74 @@
+package loader
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	want := time.Date(2025, 3, 10, 6, 30, 15, 0, time.UTC)
+	wantMillis := want.Add(123 * time.Millisecond)
+
+	tests := []struct {
+		value interface{}
+		want  time.Time
+	}{
+		{"2025-03-10T06:30:15Z", want},
+		{"2025-03-10T06:30:15.123Z", wantMillis},
+		{"2025-03-10T15:30:15+09:00", want},
+		{"2025-03-10T15:30:15.123+0900", wantMillis},
+		{"2025-03-10 06:30:15", want},
+		{"2025-03-10 06:30:15.123", wantMillis},
+		{"2025-03-10 01:30:15-05:00", want},
+		{"2025-03-10T06:30:15", want},
+		{float64(want.Unix()), want},
+		{float64(wantMillis.UnixMilli()), wantMillis},
+		{float64(wantMillis.UnixMicro()), wantMillis},
+		{fmt.Sprint(wantMillis.UnixMilli()), wantMillis},
+		{" 2025-03-10T06:30:15Z ", want},
+	}
+	for _, tt := range tests {
+		got, ok := parseTimestamp(tt.value)
+		require.True(t, ok, "%v", tt.value)
+		assert.True(t, tt.want.Equal(got), "%v: got %v, want %v", tt.value, got, tt.want)
+	}
+
+	for _, value := range []interface{}{nil, "", "yesterday", "2025-13-01T00:00:00Z", true} {
+		_, ok := parseTimestamp(value)
+		assert.False(t, ok, "%v", value)
+	}
+}
+
+func TestBadTimestampsAreCounted(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	ts := time.Now().Add(-time.Hour)
+	model := "claude-sonnet-4-5-20250514"
+	withTimestamp := func(value string, msgID string) string {
+		return fmt.Sprintf(`{"timestamp":%s,"requestId":"req-%s","message":{"id":"%s","model":"%s","usage":{"input_tokens":100,"output_tokens":10}}}`,
+			value, msgID, msgID, model)
+	}
+	addProjectFile(t, basePath, "project-a", "session.jsonl", []string{
+		createTestJSONLEntry(ts, model, 100, 10, "msg1", "req1"),
+		withTimestamp(fmt.Sprint(ts.UnixMilli()), "epoch"),
+		withTimestamp(`"`+ts.Format("2006-01-02 15:04:05")+`"`, "spaced"),
+		withTimestamp(`"not a time"`, "garbled"),
+		withTimestamp(`null`, "missing"),
+		`{"type":"summary","summary":"no timestamp needed"}`,
+	})
+
+	l := New()
+	entries, err := l.LoadFromPath(context.Background(), basePath)
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+
+	stats := l.Stats()
+	assert.Equal(t, 2, stats.BadTimestamps)
+	assert.Equal(t, 2, stats.ParseErrors)
+}
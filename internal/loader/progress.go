@@ -0,0 +1,105 @@
+package loader
+
+import (
+	"context"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// PartialTotals sums the deduplicated usage loaded so far. Cost is only
+// filled when the load calculates costs (a Calculator in LoaderOptions).
+type PartialTotals struct {
+	Entries             int
+	InputTokens         int
+	OutputTokens        int
+	CacheCreationTokens int
+	CacheReadTokens     int
+	Cost                float64
+}
+
+// add folds one entry into the totals
+func (t *PartialTotals) add(entry types.UsageEntry) {
+	t.Entries++
+	t.InputTokens += entry.InputTokens
+	t.OutputTokens += entry.OutputTokens
+	if cc, ok := entry.Raw["cache_creation_input_tokens"].(int); ok {
+		t.CacheCreationTokens += cc
+	}
+	if cr, ok := entry.Raw["cache_read_input_tokens"].(int); ok {
+		t.CacheReadTokens += cr
+	}
+	t.Cost += entry.Cost
+}
+
+// LoadProgress is sent on the progress channel after each file of a load
+type LoadProgress struct {
+	Path        string
+	FilesLoaded int           // Files processed so far, including failed ones
+	FilesTotal  int           // Files the load will process
+	Err         error         // Why Path could not be read (nil on success)
+	File        PartialTotals // Usage contributed by Path
+	Total       PartialTotals // Usage of all files processed so far
+}
+
+// SetProgress makes LoadFromPath and LoadAndAggregate send a LoadProgress on
+// ch after each file, in the order files are merged, so a caller can show
+// "N of M files loaded" and interim totals during a long scan. Sends block
+// until ch is read or the load's context is done; the loader never closes
+// ch. In low-memory mode entries are only deduplicated once every file has
+// been parsed, so the per-file totals stay empty and one last update carries
+// the final totals. A nil ch disables progress updates.
+func (l *Loader) SetProgress(ch chan<- LoadProgress) {
+	l.progress = ch
+}
+
+// progressTracker accumulates the totals reported by SetProgress
+type progressTracker struct {
+	ch     chan<- LoadProgress
+	ctx    context.Context
+	total  int
+	loaded int
+	file   PartialTotals
+	sum    PartialTotals
+}
+
+// newProgressTracker returns a tracker for a load of total files, or nil
+// when progress is not requested; a nil tracker ignores all calls
+func (l *Loader) newProgressTracker(ctx context.Context, total int) *progressTracker {
+	if l.progress == nil {
+		return nil
+	}
+	return &progressTracker{ch: l.progress, ctx: ctx, total: total}
+}
+
+// add counts an entry of the file being merged
+func (p *progressTracker) add(entry types.UsageEntry) {
+	if p == nil {
+		return
+	}
+	p.file.add(entry)
+	p.sum.add(entry)
+}
+
+// fileDone reports the file being merged and starts the next one
+func (p *progressTracker) fileDone(path string, err error) {
+	if p == nil {
+		return
+	}
+	p.loaded++
+	p.send(LoadProgress{Path: path, Err: err, File: p.file})
+	p.file = PartialTotals{}
+}
+
+// send delivers an update unless the load has been cancelled
+func (p *progressTracker) send(update LoadProgress) {
+	if p == nil {
+		return
+	}
+	update.FilesLoaded = p.loaded
+	update.FilesTotal = p.total
+	update.Total = p.sum
+	select {
+	case p.ch <- update:
+	case <-p.ctx.Done():
+	}
+}
@@ -0,0 +1,88 @@
+package loader
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressReportsEachFile(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	ts := time.Now().Add(-24 * time.Hour)
+	model := "claude-sonnet-4-5-20250514"
+	addProjectFile(t, basePath, "project-a", "session.jsonl", []string{
+		createTestJSONLEntry(ts, model, 100, 10, "msg1", "req1"),
+		createTestJSONLEntry(ts, model, 1000, 1, "msg-shared", "req-shared"),
+	})
+	addProjectFile(t, basePath, "project-b", "session.jsonl", []string{
+		createTestJSONLEntry(ts.Add(time.Minute), model, 200, 20, "msg2", "req2"),
+		createTestJSONLEntry(ts.Add(time.Minute), model, 1000, 1, "msg-shared", "req-shared"),
+	})
+
+	load := func(lowMemory bool) []LoadProgress {
+		ch := make(chan LoadProgress)
+		var updates []LoadProgress
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for update := range ch {
+				updates = append(updates, update)
+			}
+		}()
+
+		l := New()
+		l.SetLowMemory(lowMemory)
+		l.SetProgress(ch)
+		err := l.LoadAndAggregate(context.Background(), basePath, nil, AggregatorFunc(func(types.UsageEntry) {}))
+		close(ch)
+		<-done
+		require.NoError(t, err)
+		return updates
+	}
+
+	updates := load(false)
+	require.Len(t, updates, 2)
+	assert.Equal(t, 1, updates[0].FilesLoaded)
+	assert.Equal(t, 2, updates[0].FilesTotal)
+	assert.Equal(t, PartialTotals{Entries: 2, InputTokens: 1100, OutputTokens: 11}, updates[0].File)
+	assert.Equal(t, 2, updates[1].FilesLoaded)
+	assert.Equal(t, PartialTotals{Entries: 1, InputTokens: 200, OutputTokens: 20}, updates[1].File, "duplicates are not counted twice")
+	assert.Equal(t, PartialTotals{Entries: 3, InputTokens: 1300, OutputTokens: 31}, updates[1].Total)
+
+	updates = load(true)
+	require.Len(t, updates, 3, "one update per file plus the final totals")
+	assert.Equal(t, PartialTotals{}, updates[1].Total)
+	assert.Equal(t, 2, updates[2].FilesLoaded)
+	assert.Equal(t, PartialTotals{Entries: 3, InputTokens: 1300, OutputTokens: 31}, updates[2].Total)
+}
+
+func TestProgressStopsWhenCancelled(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	addProjectFile(t, basePath, "project-a", "session.jsonl", []string{
+		createTestJSONLEntry(time.Now().Add(-time.Hour), "claude-sonnet-4-5-20250514", 100, 10, "msg1", "req1"),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	l := New()
+	l.SetProgress(make(chan LoadProgress)) // never read
+	errc := make(chan error, 1)
+	go func() {
+		_, err := l.LoadFromPath(ctx, basePath)
+		errc <- err
+	}()
+	cancel()
+
+	select {
+	case <-errc:
+	case <-time.After(5 * time.Second):
+		t.Fatal("load blocked on an unread progress channel after cancellation")
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
 
 	"github.com/sdpower/ccusage-go/internal/types"
@@ -48,16 +47,22 @@ func (ic *IncrementalCache) Update(
 ) (entries []types.UsageEntry, changed bool, err error) {
 	ic.dirty = false
 
-	// Resolve projects path
-	projectsPath := filepath.Join(basePath, "projects")
-	if _, statErr := os.Stat(projectsPath); statErr == nil {
-		basePath = projectsPath
-	}
-
-	// Phase 1: Find all project directories
-	projectDirs, err := l.findProjectDirectories(basePath)
-	if err != nil {
-		return nil, false, fmt.Errorf("failed to find project directories: %w", err)
+	// Phase 1: Find all project directories across every data directory
+	var projectDirs []string
+	for _, root := range SplitDataPaths(basePath) {
+		source, err := l.openSource(context.Background(), root)
+		if err != nil {
+			return nil, false, err
+		}
+		dir, ok := source.(*DirSource)
+		if !ok {
+			return nil, false, fmt.Errorf("cannot watch data path %s: it is not a directory", root)
+		}
+		dirs, err := l.findProjectDirectories(resolveProjectsDir(dir.Root))
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to find project directories: %w", err)
+		}
+		projectDirs = append(projectDirs, dirs...)
 	}
 
 	cutoffTime := time.Now().Add(-modifiedWithin)
@@ -89,7 +94,7 @@ func (ic *IncrementalCache) Update(
 		// Collect current JSONL files with their state
 		currentFiles := make(map[string]FileState)
 		for _, de := range dirEntries {
-			if de.IsDir() || !strings.HasSuffix(strings.ToLower(de.Name()), ".jsonl") {
+			if de.IsDir() || !isJSONLFile(de.Name()) || !l.fileFilter.Allows(filepath.Join(projectDir, de.Name())) {
 				continue
 			}
 			info, err := de.Info()
@@ -0,0 +1,160 @@
+package loader
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/cachedir"
+)
+
+// QuarantineRecord is one line of the quarantine file: a log line that failed
+// to parse, with where it came from and why it was rejected
+type QuarantineRecord struct {
+	QuarantinedAt time.Time `json:"quarantined_at"`
+	File          string    `json:"file"`
+	Line          int       `json:"line"`
+	Error         string    `json:"error"`
+	Raw           string    `json:"raw,omitempty"` // Empty for lines too long to keep
+}
+
+// Quarantine collects the lines that fail to parse and appends them to a
+// JSONL file on Save, so data problems can be inspected and reported instead
+// of being dropped invisibly. A line already in the file is not added again,
+// which keeps repeated full re-reads (e.g. with --no-cache) from piling up
+// copies.
+type Quarantine struct {
+	path    string
+	mu      sync.Mutex
+	seen    map[uint64]bool // Hashes of file, line number and text already quarantined
+	pending []QuarantineRecord
+}
+
+// DefaultQuarantinePath returns the quarantine file location
+// (quarantine.jsonl in the cache directory)
+func DefaultQuarantinePath() (string, error) {
+	return cachedir.Path("quarantine.jsonl")
+}
+
+// OpenQuarantine prepares the quarantine file at path, reading the records it
+// already holds. A missing or unreadable file starts an empty quarantine.
+func OpenQuarantine(path string) *Quarantine {
+	q := &Quarantine{path: path, seen: make(map[uint64]bool)}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return q
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		var record QuarantineRecord
+		if len(line) > 0 && json.Unmarshal(line, &record) == nil {
+			q.seen[quarantineKey(record.File, record.Line, record.Raw)] = true
+		}
+		if err != nil {
+			return q
+		}
+	}
+}
+
+// SetQuarantine makes later loads hand the lines that fail to parse to q,
+// which is saved when the load finishes (nil disables it). Cached files with
+// invalid lines are re-read, since the parse cache does not keep their text.
+func (l *Loader) SetQuarantine(q *Quarantine) {
+	l.quarantine = q
+}
+
+// needsQuarantine reports whether a cached parse has invalid lines that must
+// be read again to reach the quarantine
+func (l *Loader) needsQuarantine(parsed *parsedFile) bool {
+	return l.quarantine != nil && parsed.invalidLines > 0
+}
+
+// quarantineKey identifies a quarantined line
+func quarantineKey(path string, line int, raw string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write([]byte(strconv.Itoa(line)))
+	h.Write([]byte{0})
+	h.Write([]byte(raw))
+	return h.Sum64()
+}
+
+// add records a rejected line unless it is already quarantined
+func (q *Quarantine) add(path string, line int, raw []byte, err error) {
+	if q == nil {
+		return
+	}
+	text := string(raw)
+	key := quarantineKey(path, line, text)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.seen[key] {
+		return
+	}
+	q.seen[key] = true
+	q.pending = append(q.pending, QuarantineRecord{
+		QuarantinedAt: time.Now().UTC(),
+		File:          path,
+		Line:          line,
+		Error:         err.Error(),
+		Raw:           text,
+	})
+}
+
+// Len returns the number of lines quarantined since the last Save
+func (q *Quarantine) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// Path returns the quarantine file location
+func (q *Quarantine) Path() string {
+	return q.path
+}
+
+// Save appends the lines quarantined since the last Save to the file
+func (q *Quarantine) Save() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.pending) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(q.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create quarantine directory: %w", err)
+	}
+	file, err := os.OpenFile(q.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open quarantine file: %w", err)
+	}
+	buf := bufio.NewWriter(file)
+	enc := json.NewEncoder(buf)
+	for i := range q.pending {
+		if err := enc.Encode(&q.pending[i]); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to write quarantine file: %w", err)
+		}
+	}
+	if err := buf.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write quarantine file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write quarantine file: %w", err)
+	}
+	q.pending = nil
+	return nil
+}
@@ -0,0 +1,53 @@
+package loader
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuarantineKeepsBadLinesOnce(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	file := addProjectFile(t, basePath, "project-a", "session.jsonl", []string{
+		createTestJSONLEntry(time.Now().Add(-time.Hour), "claude-sonnet-4-5-20250514", 100, 50, "msg1", "req1"),
+		`{"timestamp": "2026-10-06T10:00:00Z", "message": {"usage":`,
+	})
+	cachePath := filepath.Join(t.TempDir(), "index.db")
+	quarantinePath := filepath.Join(t.TempDir(), "quarantine.jsonl")
+
+	// A plain cached load first, so the second run must re-read the cached file
+	l := New()
+	l.SetParseCache(OpenParseCache(cachePath))
+	_, err := l.LoadFromPath(context.Background(), basePath)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		l = New()
+		l.SetParseCache(OpenParseCache(cachePath))
+		l.SetQuarantine(OpenQuarantine(quarantinePath))
+		entries, err := l.LoadFromPath(context.Background(), basePath)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	}
+
+	data, err := os.ReadFile(quarantinePath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 1, "the second load must not quarantine the line again")
+
+	var record QuarantineRecord
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	assert.Equal(t, file, record.File)
+	assert.Equal(t, 2, record.Line)
+	assert.Contains(t, record.Error, "JSON parse error")
+	assert.Equal(t, `{"timestamp": "2026-10-06T10:00:00Z", "message": {"usage":`, record.Raw)
+}
@@ -0,0 +1,149 @@
+package loader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sdpower/ccusage-go/internal/cachedir"
+)
+
+// IsRemoteDataPath reports whether a data directory is a URL (e.g. ssh://host/path)
+// rather than a local path
+func IsRemoteDataPath(root string) bool {
+	return strings.Contains(root, "://")
+}
+
+// DefaultRemoteCacheDir returns where remote data directories are mirrored (remote in the cache directory)
+func DefaultRemoteCacheDir() (string, error) {
+	return cachedir.Path("remote")
+}
+
+// offline is applied to every loader (see SetOffline)
+var offline bool
+
+// SetOffline makes loaders read remote data directories from their mirrors
+// as last synced, without connecting to the remote side (--no-network)
+func SetOffline(enabled bool) {
+	offline = enabled
+}
+
+// SetRemoteCacheDir overrides where remote data directories are mirrored
+func (l *Loader) SetRemoteCacheDir(dir string) {
+	l.remoteCacheDir = dir
+}
+
+// mirrorRemote mirrors a remote data directory into the remote cache and
+// returns the local copy, which the rest of the loader then works on. Only
+// changed files are transferred.
+func (l *Loader) mirrorRemote(ctx context.Context, root string) (string, error) {
+	u, err := url.Parse(root)
+	if err != nil {
+		return "", fmt.Errorf("invalid data path %s: %w", root, err)
+	}
+
+	cacheDir := l.remoteCacheDir
+	if cacheDir == "" {
+		if cacheDir, err = DefaultRemoteCacheDir(); err != nil {
+			return "", err
+		}
+	}
+	host := strings.ReplaceAll(u.Host, ":", "_")
+	if u.User != nil && u.User.Username() != "" {
+		host = u.User.Username() + "@" + host
+	}
+	mirror := filepath.Join(cacheDir, u.Scheme, host, filepath.FromSlash(strings.TrimPrefix(u.Path, "/")))
+	if offline {
+		if _, err := os.Stat(mirror); err != nil {
+			return "", fmt.Errorf("no local copy of %s to read without network access", root)
+		}
+		return mirror, nil
+	}
+
+	switch u.Scheme {
+	case "ssh":
+		err = l.syncSSH(ctx, u, mirror)
+	case "s3", "gs":
+		err = l.syncObjectStore(ctx, u, mirror)
+	default:
+		return "", fmt.Errorf("unsupported data path scheme %q in %s", u.Scheme, root)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to sync %s: %w", root, err)
+	}
+	return mirror, nil
+}
+
+// remoteManifestName is the file in a mirror directory recording the size and
+// modification time of each mirrored file, so unchanged files are not
+// transferred again
+const remoteManifestName = ".ccusage-manifest.json"
+
+// remoteFile is what the manifest knows of a remote file
+type remoteFile struct {
+	Size    int64 `json:"size"`
+	ModTime int64 `json:"mtime"` // Unix seconds
+}
+
+type remoteManifest map[string]remoteFile // slash-separated relative path → file
+
+func loadRemoteManifest(mirror string) remoteManifest {
+	manifest := make(remoteManifest)
+	data, err := os.ReadFile(filepath.Join(mirror, remoteManifestName))
+	if err != nil {
+		return manifest
+	}
+	if json.Unmarshal(data, &manifest) != nil {
+		// Manifests of earlier versions only held sizes. Keeping their files
+		// without a time fetches each one again but still removes deleted ones.
+		var sizes map[string]int64
+		manifest = make(remoteManifest)
+		if json.Unmarshal(data, &sizes) == nil {
+			for name, size := range sizes {
+				manifest[name] = remoteFile{Size: size}
+			}
+		}
+	}
+	return manifest
+}
+
+func (m remoteManifest) save(mirror string) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(mirror, remoteManifestName), data, 0o600)
+}
+
+// changedFiles compares a remote listing with the manifest, returning files to
+// fetch and removing mirrored files that no longer exist remotely. A file
+// rewritten at the same size is told apart by its modification time.
+func (m remoteManifest) changedFiles(mirror string, remote map[string]remoteFile) []string {
+	var changed []string
+	for name, file := range remote {
+		if old, ok := m[name]; !ok || old != file {
+			changed = append(changed, name)
+		}
+	}
+	for name := range m {
+		if _, ok := remote[name]; !ok {
+			os.Remove(filepath.Join(mirror, filepath.FromSlash(name)))
+			delete(m, name)
+		}
+	}
+	return changed
+}
+
+// mirrorPath maps a relative remote file name into the mirror, rejecting names
+// that would escape it
+func mirrorPath(mirror, name string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("unsafe file name %q", name)
+	}
+	return filepath.Join(mirror, clean), nil
+}
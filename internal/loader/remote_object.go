@@ -0,0 +1,63 @@
+package loader
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Object store CLIs; tests substitute local stand-ins
+var (
+	awsCommand    = "aws"
+	gcloudCommand = "gcloud"
+)
+
+// gcsExcludePattern skips everything but usage logs (gcloud rsync only supports exclusions)
+const gcsExcludePattern = `^(?!.*\.jsonl(\.gz|\.zst)?$).*`
+
+// syncObjectStore mirrors the usage logs under s3://bucket/prefix or
+// gs://bucket/prefix into mirror using the provider's CLI, which handles
+// credentials, listing and transferring only new or changed objects
+func (l *Loader) syncObjectStore(ctx context.Context, u *url.URL, mirror string) error {
+	if u.Host == "" {
+		return fmt.Errorf("missing bucket")
+	}
+	if err := os.MkdirAll(mirror, 0o755); err != nil {
+		return err
+	}
+	source := u.Scheme + "://" + u.Host + "/" + strings.TrimPrefix(u.Path, "/")
+
+	var name string
+	var args []string
+	switch u.Scheme {
+	case "s3":
+		name = awsCommand
+		args = []string{"s3", "sync", source, mirror, "--delete", "--only-show-errors",
+			"--exclude", "*",
+			"--include", "*" + jsonlExt, "--include", "*" + jsonlGzipExt, "--include", "*" + jsonlZstdExt}
+	case "gs":
+		name = gcloudCommand
+		args = []string{"storage", "rsync", source, mirror, "--recursive",
+			"--delete-unmatched-destination-objects", "--exclude", gcsExcludePattern, "--quiet"}
+	default:
+		return fmt.Errorf("unsupported object store %q", u.Scheme)
+	}
+
+	if l.debug {
+		fmt.Fprintf(os.Stderr, "Debug: Syncing %s to %s\n", source, mirror)
+	}
+	cmd := exec.CommandContext(ctx, name, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %w: %s", name, err, msg)
+		}
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package loader
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sshCommand is the ssh client binary; tests substitute a local stand-in
+var sshCommand = "ssh"
+
+// syncSSH mirrors the usage logs under ssh://[user@]host[:port]/path into mirror.
+// Files are listed with find and stat, and changed files are fetched in a
+// single tar stream, so only common POSIX tools are needed on the remote side.
+func (l *Loader) syncSSH(ctx context.Context, u *url.URL, mirror string) error {
+	if u.Hostname() == "" {
+		return fmt.Errorf("missing host")
+	}
+	root := remoteShellPath(u.Path)
+
+	listing, err := runSSH(ctx, u, "cd "+root+" && "+statFormat+" && find . -type f "+
+		`\( -name '*`+jsonlExt+`' -o -name '*`+jsonlGzipExt+`' -o -name '*`+jsonlZstdExt+`' \) -exec stat "$fmt" {} +`, nil)
+	if err != nil {
+		return err
+	}
+	remote := parseStatListing(listing)
+
+	if err := os.MkdirAll(mirror, 0o755); err != nil {
+		return err
+	}
+	manifest := loadRemoteManifest(mirror)
+	changed := manifest.changedFiles(mirror, remote)
+	if l.debug {
+		fmt.Fprintf(os.Stderr, "Debug: %s: %d remote files, %d changed\n", u.Redacted(), len(remote), len(changed))
+	}
+
+	if len(changed) > 0 {
+		sort.Strings(changed)
+		if err := fetchSSH(ctx, u, root, changed, mirror, manifest); err != nil {
+			return err
+		}
+	}
+	return manifest.save(mirror)
+}
+
+// statFormat sets $fmt to the stat option printing "size mtime name", for
+// GNU and busybox stat (-c) or BSD and macOS stat (-f)
+const statFormat = `if stat -c %s . >/dev/null 2>&1; then fmt='-c%s %Y %n'; else fmt='-f%z %m %N'; fi`
+
+// fetchSSH streams the files named by changed, relative to root, from the host
+// of u into mirror as a tar archive, so no more than one file is buffered
+func fetchSSH(ctx context.Context, u *url.URL, root string, changed []string, mirror string, manifest remoteManifest) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	cmd, host := sshCommandFor(ctx, u, "cd "+root+" && tar -cf - -T -")
+	cmd.Stdin = strings.NewReader(strings.Join(changed, "\n") + "\n")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return sshError(host, err, &stderr)
+	}
+
+	if err := extractTar(stdout, mirror, manifest); err != nil {
+		cancel() // Stop the transfer rather than reading the rest of the archive
+		cmd.Wait()
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	io.Copy(io.Discard, stdout) // Padding after the end of the archive
+	if err := cmd.Wait(); err != nil {
+		return sshError(host, err, &stderr)
+	}
+	return nil
+}
+
+// sshCommandFor returns the ssh command running a shell command on the host
+// of u, and the host as shown in errors
+func sshCommandFor(ctx context.Context, u *url.URL, command string) (*exec.Cmd, string) {
+	args := []string{"-o", "BatchMode=yes"}
+	if port := u.Port(); port != "" {
+		args = append(args, "-p", port)
+	}
+	host := u.Hostname()
+	if u.User != nil && u.User.Username() != "" {
+		host = u.User.Username() + "@" + host
+	}
+	args = append(args, host, command)
+	return exec.CommandContext(ctx, sshCommand, args...), host
+}
+
+// runSSH runs a shell command on the host of u and returns its stdout
+func runSSH(ctx context.Context, u *url.URL, command string, stdin io.Reader) ([]byte, error) {
+	cmd, host := sshCommandFor(ctx, u, command)
+	cmd.Stdin = stdin
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, sshError(host, err, &stderr)
+	}
+	return out, nil
+}
+
+// sshError describes a failed ssh command with what it wrote to stderr
+func sshError(host string, err error, stderr *bytes.Buffer) error {
+	if msg := strings.TrimSpace(stderr.String()); msg != "" {
+		return fmt.Errorf("ssh %s: %w: %s", host, err, msg)
+	}
+	return fmt.Errorf("ssh %s: %w", host, err)
+}
+
+// remoteShellPath quotes a URL path for the remote shell. A leading /~ refers
+// to the remote home directory, e.g. ssh://devbox/~/.claude.
+func remoteShellPath(p string) string {
+	if p == "" || p == "/~" {
+		return `"$HOME"`
+	}
+	if strings.HasPrefix(p, "/~/") {
+		return `"$HOME"/` + shellQuote(strings.TrimPrefix(p, "/~/"))
+	}
+	return shellQuote(p)
+}
+
+// shellQuote single-quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// parseStatListing parses stat output ("1234 1700000000 ./projects/x/y.jsonl")
+// into relative file names with their sizes and modification times
+func parseStatListing(out []byte) map[string]remoteFile {
+	files := make(map[string]remoteFile)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.SplitN(strings.TrimSpace(scanner.Text()), " ", 3)
+		if len(fields) != 3 || !strings.HasPrefix(fields[2], "./") {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		modTime, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		files[path.Clean(fields[2])] = remoteFile{Size: size, ModTime: modTime}
+	}
+	return files
+}
+
+// extractTar writes the regular files of a tar stream into mirror and records
+// their sizes and modification times
+func extractTar(r io.Reader, mirror string, manifest remoteManifest) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(hdr.Name)
+		dest, err := mirrorPath(mirror, name)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return err
+		}
+		f, err := os.Create(dest)
+		if err != nil {
+			return err
+		}
+		n, err := io.Copy(f, tr)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", dest, err)
+		}
+		manifest[name] = remoteFile{Size: n, ModTime: hdr.ModTime.Unix()}
+	}
+}
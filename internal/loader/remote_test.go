@@ -0,0 +1,174 @@
+package loader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSSH stands in for the ssh client: it drops the options and host and runs
+// the remote command with the local shell
+const fakeSSH = `#!/bin/sh
+while [ "$1" = "-o" ] || [ "$1" = "-p" ]; do shift 2; done
+shift
+exec sh -c "$1"
+`
+
+func TestLoadFromSSHDataPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake ssh client needs a POSIX shell")
+	}
+
+	binDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "ssh"), []byte(fakeSSH), 0o755))
+	oldSSH := sshCommand
+	sshCommand = filepath.Join(binDir, "ssh")
+	defer func() { sshCommand = oldSSH }()
+
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+	ts := time.Now().Add(-time.Hour)
+	file := addProjectFile(t, basePath, "project-a", "session.jsonl", []string{
+		createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 100, 10, "msg1", "req1"),
+	})
+	stale := addProjectFile(t, basePath, "project-b", "old.jsonl", []string{
+		createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 50, 5, "msg0", "req0"),
+	})
+
+	cacheDir := t.TempDir()
+	dataPath := "ssh://devbox:2222" + filepath.ToSlash(basePath)
+
+	l := New()
+	l.SetRemoteCacheDir(cacheDir)
+	entries, err := l.LoadFromPath(context.Background(), dataPath)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	mirrored := filepath.Join(cacheDir, "ssh", "devbox_2222", filepath.FromSlash(basePath[1:]), "projects", "project-a", "session.jsonl")
+	_, err = os.Stat(mirrored)
+	require.NoError(t, err)
+
+	// Appended lines are fetched again and deleted remote files disappear from the mirror
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = f.WriteString(createTestJSONLEntry(ts.Add(time.Minute), "claude-sonnet-4-5-20250514", 200, 20, "msg2", "req2") + "\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	require.NoError(t, os.Remove(stale))
+
+	entries, err = l.LoadFromPath(context.Background(), dataPath)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, 100, entries[0].InputTokens)
+	assert.Equal(t, 200, entries[1].InputTokens)
+
+	// A file rewritten at the same size is fetched again for its new mtime
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	rewritten := strings.Replace(string(data), `"input_tokens":100`, `"input_tokens":300`, 1)
+	require.Len(t, rewritten, len(data))
+	require.NoError(t, os.WriteFile(file, []byte(rewritten), 0o644))
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(file, later, later))
+	entries, err = l.LoadFromPath(context.Background(), dataPath)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, 300, entries[0].InputTokens)
+
+	// Offline, the mirror is read as last synced without running ssh
+	SetOffline(true)
+	defer SetOffline(false)
+	sshCommand = filepath.Join(binDir, "missing-ssh")
+	entries, err = l.LoadFromPath(context.Background(), dataPath)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+	_, err = l.LoadFromPath(context.Background(), "ssh://otherbox"+filepath.ToSlash(basePath))
+	assert.ErrorContains(t, err, "no local copy")
+}
+
+func TestRemoteShellPath(t *testing.T) {
+	assert.Equal(t, `"$HOME"`, remoteShellPath("/~"))
+	assert.Equal(t, `"$HOME"/'.claude'`, remoteShellPath("/~/.claude"))
+	assert.Equal(t, `'/srv/it'\''s'`, remoteShellPath("/srv/it's"))
+}
+
+func TestParseStatListing(t *testing.T) {
+	files := parseStatListing([]byte("1234 1700000000 ./projects/a/session.jsonl\n" +
+		"56 1700000060 ./projects/my app/b.jsonl.gz\n" +
+		"stat: cannot stat './gone.jsonl': No such file or directory\n"))
+	assert.Equal(t, map[string]remoteFile{
+		"projects/a/session.jsonl":   {Size: 1234, ModTime: 1700000000},
+		"projects/my app/b.jsonl.gz": {Size: 56, ModTime: 1700000060},
+	}, files)
+}
+
+func TestRemoteManifestReadsSizeOnlyManifest(t *testing.T) {
+	mirror := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(mirror, remoteManifestName), []byte(`{"projects/a.jsonl":10,"projects/gone.jsonl":5}`), 0o600))
+	gone := filepath.Join(mirror, "projects", "gone.jsonl")
+	require.NoError(t, os.MkdirAll(filepath.Dir(gone), 0o755))
+	require.NoError(t, os.WriteFile(gone, []byte("x"), 0o644))
+
+	manifest := loadRemoteManifest(mirror)
+	assert.Equal(t, remoteManifest{"projects/a.jsonl": {Size: 10}, "projects/gone.jsonl": {Size: 5}}, manifest)
+
+	// Files without a recorded time are fetched again, and deleted ones still leave the mirror
+	changed := manifest.changedFiles(mirror, map[string]remoteFile{"projects/a.jsonl": {Size: 10, ModTime: 1700000000}})
+	assert.Equal(t, []string{"projects/a.jsonl"}, changed)
+	assert.NoFileExists(t, gone)
+}
+
+// fakeObjectCLI records its arguments and copies FAKE_SYNC_SOURCE into the
+// destination, standing in for `aws s3 sync` and `gcloud storage rsync`
+const fakeObjectCLI = `#!/bin/sh
+echo "$@" > "$FAKE_SYNC_ARGS"
+cp -R "$FAKE_SYNC_SOURCE"/. "$4"
+`
+
+func TestLoadFromObjectStoreDataPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake object store client needs a POSIX shell")
+	}
+
+	binDir := t.TempDir()
+	fake := filepath.Join(binDir, "cli")
+	require.NoError(t, os.WriteFile(fake, []byte(fakeObjectCLI), 0o755))
+	oldAWS, oldGcloud := awsCommand, gcloudCommand
+	awsCommand, gcloudCommand = fake, fake
+	defer func() { awsCommand, gcloudCommand = oldAWS, oldGcloud }()
+
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+	addProjectFile(t, basePath, "project-a", "session.jsonl", []string{
+		createTestJSONLEntry(time.Now().Add(-time.Hour), "claude-sonnet-4-5-20250514", 100, 10, "msg1", "req1"),
+	})
+	argsFile := filepath.Join(t.TempDir(), "args")
+	t.Setenv("FAKE_SYNC_SOURCE", basePath)
+	t.Setenv("FAKE_SYNC_ARGS", argsFile)
+
+	for _, tc := range []struct {
+		dataPath string
+		wantArgs string
+	}{
+		{"s3://team-usage/laptops/alice", "s3 sync s3://team-usage/laptops/alice "},
+		{"gs://team-usage/laptops/alice", "storage rsync gs://team-usage/laptops/alice "},
+	} {
+		cacheDir := t.TempDir()
+		l := New()
+		l.SetRemoteCacheDir(cacheDir)
+		entries, err := l.LoadFromPath(context.Background(), tc.dataPath)
+		require.NoError(t, err, tc.dataPath)
+		assert.Len(t, entries, 1)
+
+		args, err := os.ReadFile(argsFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(args), tc.wantArgs+filepath.Join(cacheDir, tc.dataPath[:2], "team-usage", "laptops", "alice"))
+	}
+}
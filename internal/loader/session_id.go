@@ -0,0 +1,42 @@
+package loader
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// uuidPattern matches the conversation UUIDs Claude Code names its logs after
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// sessionIDFromPath returns the conversation UUID a log file belongs to, for
+// entries that do not carry a sessionId. Claude Code writes a conversation to
+// <uuid>.jsonl and its sub-agents to <uuid>/subagents/*.jsonl. Other file
+// names yield "".
+func sessionIDFromPath(path string) string {
+	base := filepath.Base(path)
+	for _, ext := range []string{jsonlGzipExt, jsonlZstdExt, jsonlExt} {
+		if strings.HasSuffix(strings.ToLower(base), ext) {
+			base = base[:len(base)-len(ext)]
+			break
+		}
+	}
+	if uuidPattern.MatchString(base) {
+		return base
+	}
+
+	// Sub-agent logs sit one or two directories below the conversation's
+	dir := filepath.Dir(path)
+	for i := 0; i < 2; i++ {
+		if name := filepath.Base(dir); uuidPattern.MatchString(name) {
+			return name
+		}
+		dir = filepath.Dir(dir)
+	}
+	return ""
+}
+
+// isSubagentLog reports whether path is a sub-agent log, <uuid>/subagents/*.jsonl
+func isSubagentLog(path string) bool {
+	return filepath.Base(filepath.Dir(path)) == "subagents"
+}
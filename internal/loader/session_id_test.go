@@ -0,0 +1,121 @@
+package loader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionIDFromPath(t *testing.T) {
+	const id = "0b7f3c2e-5d1a-4e8b-9c6f-2a4d8e1b3f70"
+	projects := filepath.Join("home", "me", ".claude", "projects", "-Users-me-src-app")
+
+	tests := map[string]string{
+		filepath.Join(projects, id+".jsonl"):                          id,
+		filepath.Join(projects, id+".jsonl.gz"):                       id,
+		filepath.Join(projects, id+".JSONL.ZST"):                      id,
+		filepath.Join(projects, id, "subagents", "agent-a1b2.jsonl"):  id,
+		filepath.Join(projects, id, "agent-a1b2.jsonl"):               id,
+		filepath.Join(projects, "session.jsonl"):                      "",
+		filepath.Join(projects, "0b7f3c2e-not-a-uuid.jsonl"):          "",
+		filepath.Join(id, "projects", "-Users-me-src-app", "a.jsonl"): "",
+	}
+	for path, want := range tests {
+		assert.Equal(t, want, sessionIDFromPath(path), path)
+	}
+}
+
+func TestSessionIDFallsBackToFileName(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	const id = "0b7f3c2e-5d1a-4e8b-9c6f-2a4d8e1b3f70"
+	ts := time.Now().Add(-time.Hour)
+	model := "claude-sonnet-4-5-20250514"
+	projectDir := filepath.Join(basePath, "projects", "project-a")
+	require.NoError(t, os.MkdirAll(filepath.Join(projectDir, id, "subagents"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, id+".jsonl"), []byte(
+		createTestJSONLEntry(ts, model, 100, 10, "msg1", "req1")+"\n"+
+			createTestJSONLEntryWithSessionID(ts, model, 200, 20, "msg2", "req2", "explicit")+"\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, id, "subagents", "agent-1.jsonl"), []byte(
+		createTestJSONLEntry(ts, model, 300, 30, "msg3", "req3")+"\n"), 0o644))
+	addProjectFile(t, basePath, "project-b", "session.jsonl", []string{
+		createTestJSONLEntry(ts, model, 400, 40, "msg4", "req4"),
+	})
+
+	entries, err := New().LoadFromPath(context.Background(), basePath)
+	require.NoError(t, err)
+	sessions := make(map[int]string)
+	for _, e := range entries {
+		sessions[e.InputTokens] = e.SessionID
+	}
+	assert.Equal(t, map[int]string{100: id, 200: "explicit", 300: id, 400: ""}, sessions)
+}
+
+func TestSidechainEntriesAreMarked(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	const id = "0b7f3c2e-5d1a-4e8b-9c6f-2a4d8e1b3f70"
+	ts := time.Now().Add(-time.Hour)
+	model := "claude-sonnet-4-5-20250514"
+	projectDir := filepath.Join(basePath, "projects", "project-a")
+	require.NoError(t, os.MkdirAll(filepath.Join(projectDir, id, "subagents"), 0o755))
+	sidechain := strings.Replace(createTestJSONLEntry(ts, model, 200, 20, "msg2", "req2"),
+		"{", `{"isSidechain":true,"agentId":"a1b2",`, 1)
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, id+".jsonl"), []byte(
+		createTestJSONLEntry(ts, model, 100, 10, "msg1", "req1")+"\n"+sidechain+"\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, id, "subagents", "agent-c3d4.jsonl"), []byte(
+		createTestJSONLEntry(ts, model, 300, 30, "msg3", "req3")+"\n"), 0o644))
+
+	// The second load is served from the parse cache
+	for i := 0; i < 2; i++ {
+		entries, err := New().LoadFromPath(context.Background(), basePath)
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		for _, e := range entries {
+			switch e.InputTokens {
+			case 100:
+				assert.False(t, e.IsSidechain)
+			case 200:
+				assert.True(t, e.IsSidechain)
+				assert.Equal(t, "a1b2", e.AgentID)
+			case 300:
+				assert.True(t, e.IsSidechain, "entries under subagents/ are sub-agent usage")
+			}
+		}
+	}
+}
+
+func TestIncludeSynthetic(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	ts := time.Now().Add(-time.Hour)
+	projectDir := filepath.Join(basePath, "projects", "-home-me-app")
+	require.NoError(t, os.MkdirAll(projectDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "session.jsonl"), []byte(
+		createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 100, 10, "msg1", "req1")+"\n"+
+			createTestJSONLEntry(ts, types.SyntheticModel, 0, 0, "msg2", "req2")+"\n"), 0o644))
+
+	// Both settings are served from the same parse cache
+	for _, include := range []bool{false, true, false, true} {
+		l := New()
+		l.SetIncludeSynthetic(include)
+		entries, err := l.LoadFromPath(context.Background(), basePath)
+		require.NoError(t, err)
+		if include {
+			require.Len(t, entries, 2)
+		} else {
+			require.Len(t, entries, 1)
+			assert.NotEqual(t, types.SyntheticModel, entries[0].Model)
+		}
+	}
+}
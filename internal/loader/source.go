@@ -0,0 +1,171 @@
+package loader
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sync"
+)
+
+// Source supplies the usage log files of one data path. Sources let new
+// backends be added without changing how files are parsed, deduplicated and
+// aggregated.
+type Source interface {
+	// ListFiles returns the names of the usage logs (.jsonl, optionally
+	// .gz/.zst compressed). Names must be unique across all data paths of a
+	// load, e.g. by prefixing them with the source's URL.
+	ListFiles(ctx context.Context) ([]string, error)
+
+	// Open returns the contents of a file named by ListFiles, compressed as
+	// its name says
+	Open(name string) (io.ReadCloser, error)
+}
+
+// SourceFactory creates the Source for a data path URL
+type SourceFactory func(ctx context.Context, l *Loader, root string) (Source, error)
+
+var (
+	sourceMu        sync.RWMutex
+	sourceFactories = make(map[string]SourceFactory)
+)
+
+// RegisterSource makes data paths with the given URL scheme (scheme://...)
+// load through factory, replacing any earlier registration. Built in are
+// ssh, s3 and gs, which are mirrored into a local directory, and stdin
+// (also written "-").
+func RegisterSource(scheme string, factory SourceFactory) {
+	sourceMu.Lock()
+	defer sourceMu.Unlock()
+	sourceFactories[scheme] = factory
+}
+
+func init() {
+	for _, scheme := range []string{"ssh", "s3", "gs"} {
+		RegisterSource(scheme, mirrorSource)
+	}
+	RegisterSource("stdin", func(ctx context.Context, l *Loader, root string) (Source, error) {
+		return NewReaderSource(stdinName, os.Stdin), nil
+	})
+}
+
+// stdinName is the file name of the log read from standard input
+const stdinName = "<stdin>"
+
+// openSource returns the Source for one data path. Paths that are not URLs
+// are local directories.
+func (l *Loader) openSource(ctx context.Context, root string) (Source, error) {
+	if root == "-" {
+		root = "stdin://"
+	}
+	if !IsRemoteDataPath(root) {
+		return NewDirSource(root), nil
+	}
+
+	u, err := url.Parse(root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data path %s: %w", root, err)
+	}
+	sourceMu.RLock()
+	factory, ok := sourceFactories[u.Scheme]
+	sourceMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported data path scheme %q in %s", u.Scheme, root)
+	}
+	return factory(ctx, l, root)
+}
+
+// DirSource reads the logs under a local data directory, or under its
+// projects subdirectory when there is one. The loader opens these files
+// itself, so they also get memory mapping, the parse cache and the
+// timestamp index.
+type DirSource struct {
+	Root string
+}
+
+// NewDirSource returns the Source for a local data directory
+func NewDirSource(root string) *DirSource {
+	return &DirSource{Root: root}
+}
+
+// ListFiles returns the paths of the logs below the directory. Symlinked
+// project directories are followed; inaccessible entries are ignored.
+func (s *DirSource) ListFiles(ctx context.Context) ([]string, error) {
+	var files []string
+	walkTree(resolveProjectsDir(s.Root), func(path string, info os.FileInfo) {
+		if !info.IsDir() && isJSONLFile(path) {
+			files = append(files, path)
+		}
+	})
+	return files, ctx.Err()
+}
+
+// Open opens a log by path
+func (s *DirSource) Open(name string) (io.ReadCloser, error) {
+	return os.Open(name)
+}
+
+// readerSource serves a single log read from a stream
+type readerSource struct {
+	name string
+	r    io.Reader
+	once sync.Once
+	data []byte
+	err  error
+}
+
+// NewReaderSource returns a Source holding one log file called name with the
+// contents of r. r is read in full on first use and kept in memory, since
+// the loader may open the file more than once.
+func NewReaderSource(name string, r io.Reader) Source {
+	return &readerSource{name: name, r: r}
+}
+
+func (s *readerSource) read() error {
+	s.once.Do(func() {
+		s.data, s.err = io.ReadAll(s.r)
+	})
+	return s.err
+}
+
+func (s *readerSource) ListFiles(ctx context.Context) ([]string, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	return []string{s.name}, nil
+}
+
+func (s *readerSource) Open(name string) (io.ReadCloser, error) {
+	if name != s.name {
+		return nil, os.ErrNotExist
+	}
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(s.data)), nil
+}
+
+// mirrorSource is the factory of remote data paths: the remote directory is
+// mirrored locally and then read like any local directory
+func mirrorSource(ctx context.Context, l *Loader, root string) (Source, error) {
+	mirror, err := l.mirrorRemote(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	return NewDirSource(mirror), nil
+}
+
+// openFile opens a listed file, through its Source unless it is a local file
+func (l *Loader) openFile(name string) (io.ReadCloser, error) {
+	src, ok := l.streamed[name]
+	if !ok {
+		return openJSONL(name)
+	}
+	r, err := src.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return decompress(name, r)
+}
@@ -0,0 +1,88 @@
+package loader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySource serves logs held in memory
+type memorySource map[string][]byte
+
+func (s memorySource) ListFiles(ctx context.Context) ([]string, error) {
+	var names []string
+	for name := range s {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (s memorySource) Open(name string) (io.ReadCloser, error) {
+	data, ok := s[name]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func TestRegisteredSourceIsLoaded(t *testing.T) {
+	ts := time.Now().Add(-time.Hour)
+	model := "claude-sonnet-4-5-20250514"
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, err := gz.Write([]byte(createTestJSONLEntry(ts, model, 200, 20, "msg2", "req2") + "\n"))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	source := memorySource{
+		"mem://box/projects/app/a.jsonl": []byte(createTestJSONLEntry(ts, model, 100, 10, "msg1", "req1") + "\n" +
+			createTestJSONLEntry(ts, model, 100, 10, "msg1", "req1") + "\n"),
+		"mem://box/projects/app/b.jsonl.gz": compressed.Bytes(),
+	}
+	RegisterSource("mem", func(ctx context.Context, l *Loader, root string) (Source, error) {
+		assert.Equal(t, "mem://box", root)
+		return source, nil
+	})
+
+	entries, err := New().LoadFromPath(context.Background(), "mem://box")
+	require.NoError(t, err)
+	tokens := 0
+	for _, e := range entries {
+		tokens += e.InputTokens
+	}
+	assert.Len(t, entries, 2, "duplicates are dropped across sources as well")
+	assert.Equal(t, 300, tokens)
+}
+
+func TestStdinDataPath(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	stdin := os.Stdin
+	os.Stdin = r
+	defer func() { os.Stdin = stdin }()
+
+	go func() {
+		defer w.Close()
+		io.WriteString(w, createTestJSONLEntry(time.Now().Add(-time.Hour), "claude-sonnet-4-5-20250514", 100, 10, "msg1", "req1")+"\n")
+	}()
+
+	entries, err := New().LoadFromPath(context.Background(), "-")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, stdinName, entries[0].SourceFile)
+}
+
+func TestUnknownSourceScheme(t *testing.T) {
+	_, err := New().LoadFromPath(context.Background(), "ftp://host/logs")
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), `unsupported data path scheme "ftp"`), err.Error())
+}
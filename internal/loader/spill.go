@@ -0,0 +1,242 @@
+package loader
+
+import (
+	"bufio"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// spillBuckets is the number of temporary files entries are partitioned into
+// in low-memory mode; only one bucket is held in memory at a time
+const spillBuckets = 64
+
+// spilledEntry is an entry parked on disk until its bucket is deduplicated
+type spilledEntry struct {
+	File  uint32 // Index of the source file, which also orders duplicates
+	Seq   uint32 // Position within the file
+	Entry cachedEntry
+}
+
+// spillBucket is one temporary file of spilled entries
+type spillBucket struct {
+	file *os.File
+	buf  *bufio.Writer
+	enc  *gob.Encoder
+}
+
+// SetLowMemory makes LoadAndAggregate spill parsed entries to temporary files
+// and deduplicate them one partition at a time, instead of keeping every
+// dedupe key of the history in memory. Entries reach the aggregator in no
+// particular order.
+func (l *Loader) SetLowMemory(enabled bool) {
+	l.lowMemory = enabled
+}
+
+// aggregateWithSpill is LoadAndAggregate for low-memory mode. The first pass
+// parses files in batches and writes each entry to the bucket chosen by its
+// dedupe key, so all copies of a request land in the same bucket. The second
+// pass reads the buckets back one at a time, keeps the first copy of each
+// request in file order and feeds it to agg.
+func (l *Loader) aggregateWithSpill(ctx context.Context, paths []string, calc CostCalculator, agg Aggregator) error {
+	dir, err := os.MkdirTemp("", "ccusage-spill-")
+	if err != nil {
+		return fmt.Errorf("failed to create spill directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	buckets := make([]*spillBucket, spillBuckets)
+	defer func() {
+		for _, b := range buckets {
+			if b != nil {
+				b.file.Close()
+			}
+		}
+	}()
+
+	batchSize := l.maxWorkers
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	// Titles are collected for the whole history first, so unlike the
+	// streaming path they also apply to entries from earlier files
+	sessionNames := make(map[string]string)
+	var firstErr error
+	var tally validationTally
+	loadedFiles := 0
+	progress := l.newProgressTracker(ctx, len(paths))
+
+	for start := 0; start < len(paths); start += batchSize {
+		end := start + batchSize
+		if end > len(paths) {
+			end = len(paths)
+		}
+
+		results, err := l.parseFiles(ctx, paths[start:end])
+		if err != nil {
+			return err
+		}
+
+		for i, res := range results {
+			if res.err != nil {
+				if firstErr == nil {
+					firstErr = res.err
+				}
+				progress.fileDone(res.path, res.err)
+				continue
+			}
+			loadedFiles++
+			tally.add(res.path, res.parsed)
+
+			// Custom titles take priority, so the first name seen for a session wins
+			for sid, name := range res.parsed.sessionNames {
+				if _, exists := sessionNames[sid]; !exists {
+					sessionNames[sid] = name
+				}
+			}
+
+			fileIdx := uint32(start + i)
+			for seq, entry := range res.parsed.entries {
+				if l.dropSynthetic(entry) {
+					continue
+				}
+				key := res.parsed.dedupeKeys[seq]
+				b, err := l.spillBucketFor(dir, buckets, key, fileIdx)
+				if err != nil {
+					return err
+				}
+				spilled := spilledEntry{File: fileIdx, Seq: uint32(seq), Entry: newCachedEntry(entry, key)}
+				if err := b.enc.Encode(&spilled); err != nil {
+					return fmt.Errorf("failed to spill entries: %w", err)
+				}
+			}
+			progress.fileDone(res.path, nil)
+		}
+	}
+
+	if progress != nil {
+		next := agg
+		agg = AggregatorFunc(func(entry types.UsageEntry) {
+			next.Add(entry)
+			progress.add(entry)
+		})
+	}
+
+	entryCount := 0
+	for _, b := range buckets {
+		if b == nil {
+			continue
+		}
+		n, err := l.drainSpillBucket(b, paths, sessionNames, calc, agg)
+		if err != nil {
+			return err
+		}
+		entryCount += n
+	}
+
+	progress.send(LoadProgress{})
+
+	if l.debug {
+		fmt.Fprintf(os.Stderr, "Debug: Aggregated %d usage entries from %d files (low-memory mode)\n", entryCount, loadedFiles)
+	}
+	l.debugDuplicates()
+
+	l.recordTally(&tally)
+	if loadedFiles == 0 && firstErr != nil {
+		return fmt.Errorf("failed to load any files: %v", firstErr)
+	}
+	return l.checkValidation(&tally)
+}
+
+// spillBucketFor returns (creating it on first use) the bucket for an entry.
+// Entries without a dedupe key are spread by file.
+func (l *Loader) spillBucketFor(dir string, buckets []*spillBucket, key string, fileIdx uint32) (*spillBucket, error) {
+	n := uint64(fileIdx)
+	if key != "" {
+		h := fnv.New64a()
+		h.Write([]byte(key))
+		n = h.Sum64()
+	}
+	idx := n % spillBuckets
+	if buckets[idx] != nil {
+		return buckets[idx], nil
+	}
+
+	file, err := os.Create(filepath.Join(dir, fmt.Sprintf("bucket-%02d.gob", idx)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create spill file: %w", err)
+	}
+	buf := bufio.NewWriter(file)
+	buckets[idx] = &spillBucket{file: file, buf: buf, enc: gob.NewEncoder(buf)}
+	return buckets[idx], nil
+}
+
+// drainSpillBucket deduplicates one bucket and feeds its entries to agg
+func (l *Loader) drainSpillBucket(b *spillBucket, paths []string, sessionNames map[string]string, calc CostCalculator, agg Aggregator) (int, error) {
+	if err := b.buf.Flush(); err != nil {
+		return 0, fmt.Errorf("failed to write spill file: %w", err)
+	}
+	if _, err := b.file.Seek(0, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("failed to read spill file: %w", err)
+	}
+
+	var spilled []spilledEntry
+	dec := gob.NewDecoder(bufio.NewReader(b.file))
+	for {
+		var se spilledEntry
+		if err := dec.Decode(&se); err == io.EOF {
+			break
+		} else if err != nil {
+			return 0, fmt.Errorf("failed to read spill file: %w", err)
+		}
+		spilled = append(spilled, se)
+	}
+
+	// The first copy in file order wins, as in the in-memory path
+	sort.Slice(spilled, func(i, j int) bool {
+		if spilled[i].File != spilled[j].File {
+			return spilled[i].File < spilled[j].File
+		}
+		return spilled[i].Seq < spilled[j].Seq
+	})
+
+	seen := make(map[string]bool)
+	count := 0
+	for _, se := range spilled {
+		path := paths[se.File]
+		if key := se.Entry.DedupeKey; key != "" {
+			if seen[key] {
+				l.recordDuplicate(path, false)
+				continue
+			}
+			if l.dedupeStore != nil && !l.dedupeStore.claim(key, path) {
+				l.recordDuplicate(path, true)
+				continue // Duplicate of an entry from another file seen on an earlier run
+			}
+			// Only a kept copy is recorded, as in dedupeParsedEntries
+			seen[key] = true
+		}
+
+		if !l.inDateRange(se.Entry.Timestamp) {
+			continue
+		}
+		entry := se.Entry.toUsageEntry(path, l.timezone)
+		if name, ok := sessionNames[entry.SessionID]; ok {
+			entry.SessionName = name
+		}
+		if calc != nil {
+			calc.CalculateCost(&entry)
+		}
+		agg.Add(entry)
+		count++
+	}
+	return count, nil
+}
@@ -0,0 +1,45 @@
+package loader
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLowMemoryAggregateMatchesInMemory(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	ts := time.Now().Add(-24 * time.Hour)
+	for i := 0; i < 6; i++ {
+		sessionID := fmt.Sprintf("session-%d", i)
+		addProjectFile(t, basePath, fmt.Sprintf("project-%02d", i), "session.jsonl", []string{
+			createTestJSONLEntryWithSessionID(ts.Add(time.Duration(i)*time.Minute), "claude-sonnet-4-5-20250514", i+1, 1, fmt.Sprintf("msg%d", i), fmt.Sprintf("req%d", i), sessionID),
+			createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 1000, 1, "msg-shared", "req-shared"),
+			createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 1000, 1, "msg-shared", "req-shared"),
+			createCustomTitleLine(sessionID, fmt.Sprintf("title-%d", i)),
+		})
+	}
+
+	collect := func(lowMemory bool) []types.UsageEntry {
+		l := New()
+		l.SetMaxWorkers(2) // force several batches
+		l.SetLowMemory(lowMemory)
+		var entries []types.UsageEntry
+		err := l.LoadAndAggregate(context.Background(), basePath, nil, AggregatorFunc(func(entry types.UsageEntry) {
+			entries = append(entries, entry)
+		}))
+		require.NoError(t, err)
+		return entries
+	}
+
+	inMemory := collect(false)
+	spilled := collect(true)
+	require.Len(t, inMemory, 7, "six own requests plus one shared request")
+	assert.ElementsMatch(t, inMemory, spilled)
+}
@@ -0,0 +1,73 @@
+package loader
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// Stats returns the telemetry of the most recent LoadFromPath or
+// LoadAndAggregate call
+func (l *Loader) Stats() types.LoaderStats {
+	return l.stats
+}
+
+// beginLoad resets the stats and returns a func that records the wall time
+// of the load when it finishes
+func (l *Loader) beginLoad() func() {
+	l.stats = types.LoaderStats{}
+	start := time.Now()
+	return func() {
+		l.stats.WallTimeMs = time.Since(start).Milliseconds()
+	}
+}
+
+// recordTally copies the line counts of a finished load into the stats
+func (l *Loader) recordTally(t *validationTally) {
+	l.stats.LinesParsed = t.total
+	l.stats.ParseErrors = t.invalid
+	l.stats.BadTimestamps = t.badTimestamps
+}
+
+// recordDuplicate counts an entry of path dropped as a copy of an earlier
+// request; acrossRuns marks copies caught by the dedupe store
+func (l *Loader) recordDuplicate(path string, acrossRuns bool) {
+	l.stats.DuplicatesRemoved++
+	if acrossRuns {
+		l.stats.DuplicatesAcrossRuns++
+	}
+	if l.stats.DuplicatesByFile == nil {
+		l.stats.DuplicatesByFile = make(map[string]int)
+	}
+	l.stats.DuplicatesByFile[path]++
+}
+
+// debugDuplicatesShown is the number of files debugDuplicates lists
+const debugDuplicatesShown = 10
+
+// debugDuplicates prints the duplicates removed by the load, with the files
+// that held the most copies, when debugging
+func (l *Loader) debugDuplicates() {
+	if !l.debug || l.stats.DuplicatesRemoved == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Debug: Removed %d duplicate entries (%d seen in another file on an earlier run) from %d files\n",
+		l.stats.DuplicatesRemoved, l.stats.DuplicatesAcrossRuns, len(l.stats.DuplicatesByFile))
+
+	files := make([]string, 0, len(l.stats.DuplicatesByFile))
+	for file := range l.stats.DuplicatesByFile {
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if l.stats.DuplicatesByFile[files[i]] != l.stats.DuplicatesByFile[files[j]] {
+			return l.stats.DuplicatesByFile[files[i]] > l.stats.DuplicatesByFile[files[j]]
+		}
+		return files[i] < files[j]
+	})
+	for _, file := range files[:min(len(files), debugDuplicatesShown)] {
+		fmt.Fprintf(os.Stderr, "  %6d  %s\n", l.stats.DuplicatesByFile[file], file)
+	}
+}
@@ -0,0 +1,65 @@
+package loader
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsCountFilesLinesAndDuplicates(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	ts := time.Now().Add(-time.Hour)
+	fileA := addProjectFile(t, basePath, "project-a", "session.jsonl", []string{
+		createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 100, 10, "msg1", "req1"),
+		createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 100, 10, "msg1", "req1"),
+		"{truncated",
+	})
+	fileB := addProjectFile(t, basePath, "project-b", "session.jsonl", []string{
+		createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 100, 10, "msg1", "req1"),
+		createTestJSONLEntry(ts.Add(time.Minute), "claude-sonnet-4-5-20250514", 100, 10, "msg2", "req2"),
+	})
+	addProjectFile(t, basePath, "scratch", "session.jsonl", []string{
+		createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 100, 10, "msg3", "req3"),
+	})
+
+	filter, err := NewFileFilter(nil, []string{"scratch"})
+	require.NoError(t, err)
+	want := types.LoaderStats{
+		FilesScanned:      3,
+		FilesSkipped:      1,
+		LinesParsed:       5,
+		ParseErrors:       1,
+		DuplicatesRemoved: 2,
+		DuplicatesByFile:  map[string]int{fileA: 1, fileB: 1},
+	}
+
+	for _, lowMemory := range []bool{false, true} {
+		l := New()
+		l.SetFileFilter(filter)
+		l.SetLowMemory(lowMemory)
+		err := l.LoadAndAggregate(context.Background(), basePath, nil, AggregatorFunc(func(types.UsageEntry) {}))
+		require.NoError(t, err)
+		stats := l.Stats()
+		assert.GreaterOrEqual(t, stats.WallTimeMs, int64(0))
+		stats.WallTimeMs = 0
+		assert.Equal(t, want, stats, "lowMemory=%v", lowMemory)
+	}
+
+	// Stats are reset by every load
+	l := New()
+	l.SetFileFilter(filter)
+	for run := 0; run < 2; run++ {
+		entries, err := l.LoadFromPath(context.Background(), basePath)
+		require.NoError(t, err)
+		assert.Len(t, entries, 2)
+		stats := l.Stats()
+		stats.WallTimeMs = 0
+		assert.Equal(t, want, stats)
+	}
+}
@@ -0,0 +1,73 @@
+package loader
+
+import (
+	"sort"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// maxReportedFiles caps the per-file breakdown in strict mode errors
+const maxReportedFiles = 10
+
+// SetStrict makes loads fail with a types.StrictValidationError when more than
+// maxErrorRate percent of non-empty lines fail validation, instead of silently
+// skipping them
+func (l *Loader) SetStrict(maxErrorRate float64) {
+	l.strict = true
+	l.maxErrorRate = maxErrorRate
+}
+
+// validationTally accumulates line counts across the files of one load
+type validationTally struct {
+	invalid       int
+	total         int
+	badTimestamps int
+	files         []types.FileValidationCount
+}
+
+func (t *validationTally) add(path string, parsed *parsedFile) {
+	t.total += parsed.lines
+	t.invalid += parsed.invalidLines
+	t.badTimestamps += parsed.badTimestamps
+	if parsed.invalidLines > 0 {
+		t.files = append(t.files, types.FileValidationCount{
+			Path:         path,
+			InvalidLines: parsed.invalidLines,
+			TotalLines:   parsed.lines,
+			Errors:       parsed.parseErrors,
+		})
+	}
+}
+
+// checkValidation returns a StrictValidationError if strict mode is on and the error rate is exceeded
+func (l *Loader) checkValidation(t *validationTally) error {
+	if !l.strict || t.invalid == 0 {
+		return nil
+	}
+	if float64(t.invalid)/float64(t.total)*100 <= l.maxErrorRate {
+		return nil
+	}
+
+	files := t.worstFiles()
+	more := 0
+	if len(files) > maxReportedFiles {
+		more = len(files) - maxReportedFiles
+		files = files[:maxReportedFiles]
+	}
+	return types.StrictValidationError{
+		InvalidLines: t.invalid,
+		TotalLines:   t.total,
+		MaxErrorRate: l.maxErrorRate,
+		Files:        files,
+		MoreFiles:    more,
+	}
+}
+
+// worstFiles returns the files with invalid lines, most invalid lines first
+func (t *validationTally) worstFiles() []types.FileValidationCount {
+	files := append([]types.FileValidationCount(nil), t.files...)
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].InvalidLines > files[j].InvalidLines
+	})
+	return files
+}
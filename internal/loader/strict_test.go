@@ -0,0 +1,85 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrictModeFailsAboveErrorRate(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	ts := time.Now().Add(-time.Hour)
+	addProjectFile(t, basePath, "project-a", "good.jsonl", []string{
+		createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 100, 10, "msg1", "req1"),
+		createTestJSONLEntry(ts.Add(time.Minute), "claude-sonnet-4-5-20250514", 100, 10, "msg2", "req2"),
+		createTestJSONLEntry(ts.Add(2*time.Minute), "claude-sonnet-4-5-20250514", 100, 10, "msg3", "req3"),
+	})
+	badFile := addProjectFile(t, basePath, "project-b", "bad.jsonl", []string{
+		createTestJSONLEntry(ts.Add(3*time.Minute), "claude-sonnet-4-5-20250514", 100, 10, "msg4", "req4"),
+		"{truncated",
+	})
+
+	// Without strict mode the broken line is skipped
+	entries, err := New().LoadFromPath(context.Background(), basePath)
+	require.NoError(t, err)
+	assert.Len(t, entries, 4)
+
+	strict := New()
+	strict.SetStrict(0)
+	_, err = strict.LoadFromPath(context.Background(), basePath)
+	var validationErr types.StrictValidationError
+	require.True(t, errors.As(err, &validationErr), "expected StrictValidationError, got %v", err)
+	assert.Equal(t, 1, validationErr.InvalidLines)
+	assert.Equal(t, 5, validationErr.TotalLines)
+	require.Len(t, validationErr.Files, 1)
+	assert.Equal(t, badFile, validationErr.Files[0].Path)
+	assert.Contains(t, err.Error(), "bad.jsonl: 1 of 2 lines invalid")
+
+	// One bad line in five is 20%, within a 25% limit
+	tolerant := New()
+	tolerant.SetStrict(25)
+	entries, err = tolerant.LoadFromPath(context.Background(), basePath)
+	require.NoError(t, err)
+	assert.Len(t, entries, 4)
+}
+
+func TestScanParseErrorsReportsLineNumbers(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	ts := time.Now().Add(-time.Hour)
+	badFile := addProjectFile(t, basePath, "project-a", "bad.jsonl", []string{
+		createTestJSONLEntry(ts, "claude-sonnet-4-5-20250514", 100, 10, "msg1", "req1"),
+		"{truncated",
+		"",
+		`{"timestamp": "not a time"}`,
+	})
+	cachePath := filepath.Join(t.TempDir(), "index.db")
+
+	// The second scan is served from the parse cache and must report the same lines
+	for run := 0; run < 2; run++ {
+		l := New()
+		l.SetParseCache(OpenParseCache(cachePath))
+		report, err := l.ScanParseErrors(context.Background(), basePath)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, report.Files)
+		assert.Equal(t, 3, report.TotalLines)
+		assert.Equal(t, 2, report.InvalidLines)
+		require.Len(t, report.FileErrors, 1)
+		file := report.FileErrors[0]
+		assert.Equal(t, badFile, file.Path)
+		require.Len(t, file.Errors, 2)
+		assert.Equal(t, 2, file.Errors[0].Line)
+		assert.Contains(t, file.Errors[0].Err.Error(), "JSON parse error")
+		assert.Equal(t, 4, file.Errors[1].Line)
+	}
+}
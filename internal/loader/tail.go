@@ -0,0 +1,124 @@
+package loader
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// Tailer follows JSONL files like `tail -f`: it remembers a byte offset per
+// file and parses only the complete lines appended since the previous read.
+// A file is read from the start the first time it is seen (so Codex session
+// context and resumed-session history are known), but only entries stamped at
+// or after the tailer's start time are returned.
+type Tailer struct {
+	loader *Loader
+	since  time.Time
+	files  map[string]*tailedFile
+	seen   map[string]bool   // Dedupe keys already returned
+	names  map[string]string // Session names seen so far
+}
+
+type tailedFile struct {
+	parser  *lineParser
+	offset  int64 // Bytes consumed, always at a line boundary
+	lineNum int
+}
+
+// NewTailer creates a Tailer returning entries stamped at or after since
+func (l *Loader) NewTailer(since time.Time) *Tailer {
+	return &Tailer{
+		loader: l,
+		since:  since,
+		files:  make(map[string]*tailedFile),
+		seen:   make(map[string]bool),
+		names:  make(map[string]string),
+	}
+}
+
+// Read returns the new usage entries appended to path since the previous Read.
+// A trailing line without a newline is left for the next call, since the
+// writer may still be in the middle of it. Truncated files are re-read.
+func (t *Tailer) Read(path string) ([]types.UsageEntry, error) {
+	if !strings.HasSuffix(strings.ToLower(path), jsonlExt) {
+		return nil, nil // Compressed archives are not appended to
+	}
+	if !t.loader.fileFilter.Allows(path) {
+		return nil, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			delete(t.files, path)
+			return nil, nil
+		}
+		return nil, types.LoaderError{Path: path, Err: err}
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, types.LoaderError{Path: path, Err: err}
+	}
+
+	tf := t.files[path]
+	if tf == nil || info.Size() < tf.offset {
+		tf = &tailedFile{parser: t.loader.newLineParser(path)}
+		t.files[path] = tf
+	}
+	if info.Size() == tf.offset {
+		return nil, nil
+	}
+
+	if _, err := file.Seek(tf.offset, io.SeekStart); err != nil {
+		return nil, types.LoaderError{Path: path, Err: err}
+	}
+	parsed := &parsedFile{sessionNames: make(map[string]string)}
+	reader := bufio.NewReaderSize(io.LimitReader(file, info.Size()-tf.offset), 64*1024)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			if len(line) > maxLineSize {
+				return nil, types.LoaderError{Path: path, Err: bufio.ErrTooLong}
+			}
+			break // Incomplete (or no) trailing line: wait for the rest
+		}
+		if err != nil {
+			return nil, types.LoaderError{Path: path, Err: err}
+		}
+		tf.offset += int64(len(line))
+		tf.lineNum++
+		tf.parser.parseLine(bytes.TrimSpace(line), tf.lineNum, parsed)
+	}
+
+	for sid, name := range parsed.sessionNames {
+		t.names[sid] = name
+	}
+
+	var entries []types.UsageEntry
+	for i, entry := range parsed.entries {
+		if t.loader.dropSynthetic(entry) {
+			continue
+		}
+		if key := parsed.dedupeKeys[i]; key != "" {
+			if t.seen[key] {
+				continue
+			}
+			t.seen[key] = true
+		}
+		if entry.Timestamp.Before(t.since) {
+			continue
+		}
+		if name, ok := t.names[entry.SessionID]; ok {
+			entry.SessionName = name
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,70 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func appendLines(t *testing.T, path string, content string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = f.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+}
+
+func TestTailerReadsOnlyNewRequests(t *testing.T) {
+	start := time.Now().Truncate(time.Second)
+	model := "claude-sonnet-4-5-20250514"
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+
+	// History written before following started is skipped, including its dedupe keys
+	appendLines(t, path,
+		createTestJSONLEntryWithSessionID(start.Add(-time.Hour), model, 100, 50, "msg1", "req1", "s1")+"\n"+
+			createCustomTitleLine("s1", "refactor")+"\n")
+
+	tailer := New().NewTailer(start)
+	entries, err := tailer.Read(path)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// A half-written line waits for its newline
+	line := createTestJSONLEntryWithSessionID(start.Add(time.Second), model, 10, 5, "msg2", "req2", "s1")
+	appendLines(t, path, line[:20])
+	entries, err = tailer.Read(path)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	appendLines(t, path, line[20:]+"\n"+
+		createTestJSONLEntryWithSessionID(start.Add(time.Second), model, 10, 5, "msg2", "req2", "s1")+"\n")
+	entries, err = tailer.Read(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "duplicate streaming lines count once")
+	assert.Equal(t, 10, entries[0].InputTokens)
+	assert.Equal(t, "refactor", entries[0].SessionName)
+	assert.Equal(t, path, entries[0].SourceFile)
+
+	entries, err = tailer.Read(path)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// A truncated (rewritten) file is read again from the start
+	require.NoError(t, os.WriteFile(path, []byte(
+		createTestJSONLEntry(start.Add(2*time.Second), model, 7, 3, "msg3", "req3")+"\n"), 0o644))
+	entries, err = tailer.Read(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, 7, entries[0].InputTokens)
+
+	// Deleted files are forgotten
+	require.NoError(t, os.Remove(path))
+	entries, err = tailer.Read(path)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
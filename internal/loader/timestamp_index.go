@@ -0,0 +1,213 @@
+package loader
+
+import (
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/cachedir"
+)
+
+// timestampIndexVersion is bumped whenever the on-disk format changes
+const timestampIndexVersion = 1
+
+// dateRangeSlack widens the requested date range before pruning files, so
+// reports whose days are cut in another timezone never lose an entry.
+// Pruning only skips work; the commands still filter entries by date.
+const dateRangeSlack = 24 * time.Hour
+
+// fileSpan records the first and last entry timestamps of a file version
+type fileSpan struct {
+	ModTime int64 // UnixNano
+	Size    int64
+	First   int64 // UnixNano of the earliest entry (0 when the file has none)
+	Last    int64 // UnixNano of the latest entry
+}
+
+type timestampIndexData struct {
+	Version int
+	Files   map[string]fileSpan // absolute file path → entry time span
+}
+
+// TimestampIndex persists the entry time span of every parsed file so that
+// date-filtered reports can skip files entirely outside the requested range
+// without reading them. It is far smaller than the parse cache and is loaded
+// in full on every run.
+type TimestampIndex struct {
+	path  string
+	mu    sync.Mutex
+	files map[string]fileSpan
+	dirty bool
+}
+
+// DefaultTimestampIndexPath returns the default index location (timestamps.db in the cache directory)
+func DefaultTimestampIndexPath() (string, error) {
+	return cachedir.Path("timestamps.db")
+}
+
+// OpenTimestampIndex loads the index stored at path. A missing, unreadable or
+// outdated index yields an empty one rather than an error.
+func OpenTimestampIndex(path string) *TimestampIndex {
+	idx := &TimestampIndex{path: path, files: make(map[string]fileSpan)}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return idx
+	}
+	defer file.Close()
+
+	var data timestampIndexData
+	if err := gob.NewDecoder(file).Decode(&data); err != nil || data.Version != timestampIndexVersion {
+		idx.dirty = true // Rewrite corrupt or outdated index on save
+		return idx
+	}
+	if data.Files != nil {
+		idx.files = data.Files
+	}
+	return idx
+}
+
+// SetTimestampIndex enables the persistent timestamp index for subsequent loads
+func (l *Loader) SetTimestampIndex(index *TimestampIndex) {
+	l.timestampIndex = index
+}
+
+// SetDateRange limits loads to entries in [since, until). A zero bound leaves
+// that side open. Files are skipped when their modification time or indexed
+// entry span shows they fall entirely outside the range (widened by a day on
+// each side); entries of the remaining files outside the range are dropped
+// right after deduplication, before cost calculation.
+func (l *Loader) SetDateRange(since, until time.Time) {
+	l.since = since
+	l.until = until
+}
+
+// inDateRange reports whether ts falls within the loader's date range
+func (l *Loader) inDateRange(ts time.Time) bool {
+	if !l.since.IsZero() && ts.Before(l.since) {
+		return false
+	}
+	return l.until.IsZero() || ts.Before(l.until)
+}
+
+// lookup returns the span recorded for the current version of a file
+func (idx *TimestampIndex) lookup(path string, info os.FileInfo) (fileSpan, bool) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	span, ok := idx.files[path]
+	if !ok || span.Size != info.Size() || span.ModTime != info.ModTime().UnixNano() {
+		return fileSpan{}, false
+	}
+	return span, true
+}
+
+// record stores the entry span of a freshly read file version
+func (idx *TimestampIndex) record(path string, info os.FileInfo, parsed *parsedFile) {
+	span := fileSpan{ModTime: info.ModTime().UnixNano(), Size: info.Size()}
+	for _, entry := range parsed.entries {
+		ts := entry.Timestamp.UnixNano()
+		if span.First == 0 || ts < span.First {
+			span.First = ts
+		}
+		if ts > span.Last {
+			span.Last = ts
+		}
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if old, ok := idx.files[path]; ok && old == span {
+		return
+	}
+	idx.files[path] = span
+	idx.dirty = true
+}
+
+// Save writes the index to disk if anything changed, dropping files that no longer exist
+func (idx *TimestampIndex) Save() error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for path := range idx.files {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			delete(idx.files, path)
+			idx.dirty = true
+		}
+	}
+	if !idx.dirty {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(idx.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// Write to a temp file and rename so concurrent runs never read a partial index
+	tmp, err := os.CreateTemp(filepath.Dir(idx.path), filepath.Base(idx.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create timestamp index: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	data := timestampIndexData{Version: timestampIndexVersion, Files: idx.files}
+	if err := gob.NewEncoder(tmp).Encode(&data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to encode timestamp index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write timestamp index: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), idx.path); err != nil {
+		return fmt.Errorf("failed to replace timestamp index: %w", err)
+	}
+
+	idx.dirty = false
+	return nil
+}
+
+// pruneByDateRange drops files that cannot hold entries in the loader's date range
+func (l *Loader) pruneByDateRange(files []string) []string {
+	if l.since.IsZero() && l.until.IsZero() {
+		return files
+	}
+	var since, until int64
+	if !l.since.IsZero() {
+		since = l.since.Add(-dateRangeSlack).UnixNano()
+	}
+	if !l.until.IsZero() {
+		until = l.until.Add(dateRangeSlack).UnixNano()
+	}
+
+	kept := files[:0]
+	pruned := 0
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			kept = append(kept, file) // Let the reader report the problem
+			continue
+		}
+		// Entries are written after they happen, so a file last modified
+		// before the range cannot contain entries inside it
+		if since != 0 && info.ModTime().UnixNano() < since {
+			pruned++
+			continue
+		}
+		if l.timestampIndex != nil {
+			if span, ok := l.timestampIndex.lookup(file, info); ok && span.First != 0 {
+				if (since != 0 && span.Last < since) || (until != 0 && span.First >= until) {
+					pruned++
+					continue
+				}
+			}
+		}
+		kept = append(kept, file)
+	}
+
+	if l.debug && pruned > 0 {
+		fmt.Fprintf(os.Stderr, "Debug: Skipped %d files outside the date range\n", pruned)
+	}
+	return kept
+}
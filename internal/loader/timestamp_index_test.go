@@ -0,0 +1,124 @@
+package loader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateRangePrunesFilesOutsideRange(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+	indexPath := filepath.Join(t.TempDir(), "timestamps.db")
+
+	model := "claude-sonnet-4-5-20250514"
+	jan := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	recent := time.Now().Add(-time.Hour)
+
+	// Untouched since January: skipped by modification time alone
+	untouched := addProjectFile(t, basePath, "project-a", "old.jsonl", []string{
+		createTestJSONLEntry(jan, model, 100, 50, "msg1", "req1"),
+	})
+	require.NoError(t, os.Chtimes(untouched, jan, jan))
+	// Recently copied but holding only January entries: skipped once indexed
+	copied := addProjectFile(t, basePath, "project-a", "copied.jsonl", []string{
+		createTestJSONLEntry(jan.Add(time.Hour), model, 200, 100, "msg2", "req2"),
+	})
+	current := addProjectFile(t, basePath, "project-b", "current.jsonl", []string{
+		createTestJSONLEntry(recent, model, 300, 150, "msg3", "req3"),
+	})
+
+	// Entries outside the range are dropped either way; the stats show which
+	// files were skipped without being read
+	load := func() ([]string, int) {
+		l := New()
+		l.SetTimestampIndex(OpenTimestampIndex(indexPath))
+		l.SetDateRange(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{})
+		entries, err := l.LoadFromPath(context.Background(), basePath)
+		require.NoError(t, err)
+		var files []string
+		for _, e := range entries {
+			files = append(files, e.SourceFile)
+		}
+		return files, l.Stats().FilesSkipped
+	}
+
+	files, skipped := load()
+	assert.Equal(t, []string{current}, files)
+	assert.Equal(t, 1, skipped, "unindexed files are read")
+	files, skipped = load()
+	assert.Equal(t, []string{current}, files)
+	assert.Equal(t, 2, skipped, "indexed spans outside the range are skipped")
+
+	// Appending in-range entries changes the file, so it is read again
+	f, err := os.OpenFile(copied, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = f.WriteString(createTestJSONLEntry(recent, model, 1, 1, "msg4", "req4") + "\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	files, skipped = load()
+	assert.ElementsMatch(t, []string{copied, current}, files)
+	assert.Equal(t, 1, skipped)
+}
+
+func TestDateRangeWithNoMatchingFilesIsEmpty(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	jan := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	file := addProjectFile(t, basePath, "project-a", "old.jsonl", []string{
+		createTestJSONLEntry(jan, "claude-sonnet-4-5-20250514", 100, 50, "msg1", "req1"),
+	})
+	require.NoError(t, os.Chtimes(file, jan, jan))
+
+	l := New()
+	l.SetDateRange(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{})
+	entries, err := l.LoadFromPath(context.Background(), basePath)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestDateRangeDropsEntriesAfterDedupe(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	model := "claude-sonnet-4-5-20250514"
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	addProjectFile(t, basePath, "project-a", "session.jsonl", []string{
+		createTestJSONLEntry(day.Add(-time.Minute), model, 100, 10, "msg1", "req1"),
+		createTestJSONLEntry(day.Add(time.Hour), model, 200, 20, "msg2", "req2"),
+		createTestJSONLEntry(day.Add(24*time.Hour), model, 300, 30, "msg3", "req3"),
+	})
+	// A later copy of req1 that falls inside the range must not resurface
+	addProjectFile(t, basePath, "project-b", "session.jsonl", []string{
+		createTestJSONLEntry(day.Add(time.Minute), model, 100, 10, "msg1", "req1"),
+	})
+
+	newLoader := func() *Loader {
+		l := New()
+		l.SetDateRange(day, day.AddDate(0, 0, 1))
+		return l
+	}
+
+	entries, err := newLoader().LoadFromPath(context.Background(), basePath)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, 200, entries[0].InputTokens)
+
+	for _, lowMemory := range []bool{false, true} {
+		l := newLoader()
+		l.SetLowMemory(lowMemory)
+		var tokens []int
+		err := l.LoadAndAggregate(context.Background(), basePath, nil, AggregatorFunc(func(e types.UsageEntry) {
+			tokens = append(tokens, e.InputTokens)
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, []int{200}, tokens, "lowMemory=%v", lowMemory)
+	}
+}
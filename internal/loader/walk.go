@@ -0,0 +1,72 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// maxWalkDepth is how many directory levels below a data directory are searched
+const maxWalkDepth = 16
+
+// walkTree calls fn for root and everything below it, like filepath.Walk, but
+// follows symlinks: some users keep project directories on other volumes and
+// link them into ~/.claude/projects. Paths are reported under the link, not
+// its target. Every real directory is visited once, so symlink cycles end,
+// and the walk stops maxWalkDepth levels below root. Unreadable entries and
+// broken links are skipped.
+func walkTree(root string, fn func(path string, info os.FileInfo)) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return
+	}
+	visited := make(map[string]bool)
+
+	var walk func(path string, info os.FileInfo, depth int)
+	walk = func(path string, info os.FileInfo, depth int) {
+		if !info.IsDir() {
+			fn(path, info)
+			return
+		}
+		real, err := filepath.EvalSymlinks(path)
+		if err != nil || visited[real] {
+			return
+		}
+		visited[real] = true
+		fn(path, info)
+		if depth >= maxWalkDepth {
+			return
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return
+		}
+		for _, entry := range entries {
+			child := filepath.Join(path, entry.Name())
+			var info os.FileInfo
+			if entry.Type()&os.ModeSymlink != 0 {
+				info, err = os.Stat(child)
+			} else {
+				info, err = entry.Info()
+			}
+			if err != nil {
+				continue
+			}
+			walk(child, info, depth+1)
+		}
+	}
+	walk(root, info, 0)
+}
+
+// isDirEntry reports whether entry, read from dir, is a directory or a
+// symlink to one
+func isDirEntry(dir string, entry os.DirEntry) bool {
+	if entry.IsDir() {
+		return true
+	}
+	if entry.Type()&os.ModeSymlink == 0 {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(dir, entry.Name()))
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,71 @@
+package loader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindJSONLFilesFollowsSymlinkedProjects(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+	projectsDir := filepath.Join(basePath, "projects")
+
+	ts := time.Now().Add(-time.Hour)
+	model := "claude-sonnet-4-5-20250514"
+	local := addProjectFile(t, basePath, "project-a", "session.jsonl", []string{
+		createTestJSONLEntry(ts, model, 100, 10, "msg1", "req1"),
+	})
+
+	// A project kept on another volume and linked in
+	volume := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(volume, "project-b"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(volume, "project-b", "session.jsonl"),
+		[]byte(createTestJSONLEntry(ts, model, 200, 20, "msg2", "req2")+"\n"), 0o644))
+	require.NoError(t, os.Symlink(filepath.Join(volume, "project-b"), filepath.Join(projectsDir, "project-b")))
+	linked := filepath.Join(projectsDir, "project-b", "session.jsonl")
+
+	// Cycles back to the projects directory and into the linked project
+	require.NoError(t, os.Symlink(projectsDir, filepath.Join(projectsDir, "project-a", "loop")))
+	require.NoError(t, os.Symlink(filepath.Join(volume, "project-b"), filepath.Join(volume, "project-b", "self")))
+	// Broken links are ignored
+	require.NoError(t, os.Symlink(filepath.Join(volume, "missing"), filepath.Join(projectsDir, "gone")))
+
+	files, err := NewDirSource(projectsDir).ListFiles(context.Background())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{local, linked}, files, "each file is found once, under the link")
+
+	// The active-session scan lists project directories itself
+	entries, err := New().LoadFromPathWithOptions(context.Background(), basePath, &LoaderOptions{ModifiedWithin: 24 * time.Hour})
+	require.NoError(t, err)
+	var sources []string
+	for _, e := range entries {
+		sources = append(sources, e.SourceFile)
+	}
+	assert.ElementsMatch(t, []string{local, linked}, sources)
+}
+
+func TestWalkTreeStopsAtMaxDepth(t *testing.T) {
+	root := t.TempDir()
+	dir := root
+	for i := 0; i < maxWalkDepth+2; i++ {
+		dir = filepath.Join(dir, "d")
+	}
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+
+	deepest := 0
+	walkTree(root, func(path string, info os.FileInfo) {
+		rel, err := filepath.Rel(root, path)
+		require.NoError(t, err)
+		if depth := len(strings.Split(rel, string(filepath.Separator))); rel != "." && depth > deepest {
+			deepest = depth
+		}
+	})
+	assert.Equal(t, maxWalkDepth, deepest)
+}
@@ -0,0 +1,190 @@
+package loader
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultWatchDebounce coalesces bursts of writes (Claude appends line by line) into one notification
+const DefaultWatchDebounce = 200 * time.Millisecond
+
+// Watcher reports changes to JSONL files under a data directory using fsnotify.
+// fsnotify is not recursive, so every directory below the base path is watched
+// and newly created directories are added as they appear.
+type Watcher struct {
+	fsw      *fsnotify.Watcher
+	debounce time.Duration
+	changes  chan []string
+	errors   chan error
+	done     chan struct{}
+	once     sync.Once
+}
+
+// NewWatcher starts watching basePath (or its projects subdirectory) for JSONL changes.
+// basePath may list several data directories separated by commas.
+func NewWatcher(basePath string) (*Watcher, error) {
+	return NewWatcherWithDebounce(basePath, DefaultWatchDebounce)
+}
+
+// NewWatcherWithDebounce is NewWatcher with a custom coalescing window
+func NewWatcherWithDebounce(basePath string, debounce time.Duration) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+
+	w := &Watcher{
+		fsw:      fsw,
+		debounce: debounce,
+		changes:  make(chan []string, 1),
+		errors:   make(chan error, 1),
+		done:     make(chan struct{}),
+	}
+
+	for _, root := range SplitDataPaths(basePath) {
+		if IsRemoteDataPath(root) {
+			continue // Remote directories cannot be watched; they are re-synced on each load
+		}
+		root = resolveProjectsDir(root)
+		if err := w.addTree(root); err != nil {
+			fsw.Close()
+			return nil, fmt.Errorf("failed to watch %s: %w", root, err)
+		}
+	}
+
+	go w.run()
+	return w, nil
+}
+
+// Changes delivers the sorted list of JSONL files changed since the previous notification
+func (w *Watcher) Changes() <-chan []string {
+	return w.changes
+}
+
+// Errors delivers non-fatal watcher errors
+func (w *Watcher) Errors() <-chan error {
+	return w.errors
+}
+
+// Close stops watching and releases the underlying file descriptors
+func (w *Watcher) Close() error {
+	var err error
+	w.once.Do(func() {
+		close(w.done)
+		err = w.fsw.Close()
+	})
+	return err
+}
+
+// addTree registers root and all of its subdirectories, following symlinks
+// like findJSONLFiles
+func (w *Watcher) addTree(root string) error {
+	var err error
+	walkTree(root, func(path string, info os.FileInfo) {
+		if info.IsDir() && err == nil {
+			err = w.fsw.Add(path)
+		}
+	})
+	return err
+}
+
+func (w *Watcher) run() {
+	pending := make(map[string]bool)
+	var timer *time.Timer
+	var timerC <-chan time.Time
+
+	for {
+		select {
+		case <-w.done:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					// New project or subagent directory: watch it and pick up files written before the watch was added
+					w.addTree(event.Name)
+					collectJSONLFiles(event.Name, pending)
+				}
+			}
+			if isJSONLFile(event.Name) && event.Op != fsnotify.Chmod {
+				pending[event.Name] = true
+			}
+			if len(pending) > 0 && timer == nil {
+				timer = time.NewTimer(w.debounce)
+				timerC = timer.C
+			}
+
+		case <-timerC:
+			timer, timerC = nil, nil
+			paths := make([]string, 0, len(pending))
+			for path := range pending {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+			pending = make(map[string]bool)
+			w.deliver(paths)
+
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			select {
+			case w.errors <- err:
+			default: // Drop errors nobody is reading
+			}
+		}
+	}
+}
+
+// deliver sends paths, merging with a notification the consumer has not picked up yet
+func (w *Watcher) deliver(paths []string) {
+	for {
+		select {
+		case w.changes <- paths:
+			return
+		case <-w.done:
+			return
+		default:
+		}
+		// Channel is full: take the unread batch and merge it into this one
+		select {
+		case prev := <-w.changes:
+			paths = mergeSorted(prev, paths)
+		default:
+		}
+	}
+}
+
+func collectJSONLFiles(root string, into map[string]bool) {
+	walkTree(root, func(path string, info os.FileInfo) {
+		if !info.IsDir() && isJSONLFile(path) {
+			into[path] = true
+		}
+	})
+}
+
+func mergeSorted(a, b []string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	merged := make([]string, 0, len(a)+len(b))
+	for _, list := range [][]string{a, b} {
+		for _, path := range list {
+			if !seen[path] {
+				seen[path] = true
+				merged = append(merged, path)
+			}
+		}
+	}
+	sort.Strings(merged)
+	return merged
+}
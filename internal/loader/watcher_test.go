@@ -0,0 +1,52 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func waitForChanges(t *testing.T, w *Watcher) []string {
+	t.Helper()
+	select {
+	case paths := <-w.Changes():
+		return paths
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for watcher notification")
+		return nil
+	}
+}
+
+func TestWatcherReportsJSONLChanges(t *testing.T) {
+	basePath, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	existing := addProjectFile(t, basePath, "project-a", "a.jsonl", []string{
+		createTestJSONLEntry(time.Now(), "claude-sonnet-4-5-20250514", 100, 50, "msg1", "req1"),
+	})
+
+	w, err := NewWatcherWithDebounce(basePath, 20*time.Millisecond)
+	require.NoError(t, err)
+	defer w.Close()
+
+	// Appending to an existing file
+	f, err := os.OpenFile(existing, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = f.WriteString(createTestJSONLEntry(time.Now(), "claude-sonnet-4-5-20250514", 10, 5, "msg2", "req2") + "\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	assert.Equal(t, []string{existing}, waitForChanges(t, w))
+
+	// Non-JSONL files are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(basePath, "projects", "project-a", "notes.txt"), []byte("x"), 0o644))
+
+	// Files in a newly created project directory are picked up
+	created := addProjectFile(t, basePath, "project-b", "b.jsonl", []string{
+		createTestJSONLEntry(time.Now(), "claude-sonnet-4-5-20250514", 1, 1, "msg3", "req3"),
+	})
+	assert.Contains(t, waitForChanges(t, w), created)
+}
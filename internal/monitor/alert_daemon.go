@@ -0,0 +1,84 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/calculator"
+	"github.com/sdpower/ccusage-go/internal/loader"
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/sdpower/ccusage-go/pkg/pricing"
+)
+
+// AlertDaemonConfig configures RunAlertDaemon
+type AlertDaemonConfig struct {
+	DataPath         string
+	TokenLimit       int           // Of a block (0 = the most tokens of a past block)
+	Interval         time.Duration // Between checks
+	SessionLength    int
+	Timezone         *time.Location
+	FileFilter       *loader.FileFilter          // Include/exclude globs (nil reads everything)
+	IncludeSynthetic bool                        // Keep <synthetic> model entries
+	CostMode         calculator.CostMode         // Cost source ("" = auto)
+	Projection       calculator.ProjectionMethod // Burn rate of the projection ("" = average)
+	Alerts           Alerts
+	Log              io.Writer       // Alerts sent and delivery failures
+	Pricing          pricing.Options // Of the service pricing usage
+}
+
+// RunAlertDaemon checks usage every interval without a display, delivering
+// the alerts it calls for, until ctx is done
+func RunAlertDaemon(ctx context.Context, config AlertDaemonConfig) error {
+	calc := calculator.New(pricing.New(config.Pricing))
+	calc.SetMode(config.CostMode)
+	calc.WarmPricing(ctx)
+	dataLoader := loader.New()
+	dataLoader.SetMaxWorkers(3) // Runs for hours next to the user's work
+	dataLoader.SetFileFilter(config.FileFilter)
+	dataLoader.SetIncludeSynthetic(config.IncludeSynthetic)
+	cache := loader.NewIncrementalCache()
+	tracker := &blockAlerts{alerts: config.Alerts}
+
+	tokenLimit := config.TokenLimit
+	if tokenLimit == 0 && (len(config.Alerts.Percents) > 0 || config.Alerts.Projection) {
+		entries, err := dataLoader.LoadFromPath(ctx, config.DataPath)
+		if err != nil {
+			return fmt.Errorf("failed to load usage data: %w", err)
+		}
+		tokenLimit = calculator.GetMaxTokensFromBlocks(calc.IdentifySessionBlocks(entries, config.SessionLength))
+		fmt.Fprintf(config.Log, "Token limit: %s, the most tokens of a past block\n", formatNumber(tokenLimit))
+	}
+
+	for {
+		entries, _, err := cache.Update(dataLoader, calc, config.DataPath, 24*time.Hour)
+		if err != nil {
+			fmt.Fprintf(config.Log, "Warning: failed to load usage data: %v\n", err)
+		} else {
+			blocks := calc.IdentifySessionBlocks(entries, config.SessionLength)
+			now := time.Now()
+			status := currentStatus(activeBlock(blocks), entries, tokenLimit, config.Projection, now, config.Timezone)
+			for _, alert := range tracker.check(status) {
+				fmt.Fprintf(config.Log, "%s Alert: %s: %s\n", now.In(config.Timezone).Format("2006-01-02 15:04:05"), alert.Title, alert.Message)
+				config.Alerts.deliver(ctx, alert, config.Log)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(config.Interval):
+		}
+	}
+}
+
+// activeBlock returns the active block of blocks, or nil
+func activeBlock(blocks []types.SessionBlock) *types.SessionBlock {
+	for i := range blocks {
+		if blocks[i].IsActive {
+			return &blocks[i]
+		}
+	}
+	return nil
+}
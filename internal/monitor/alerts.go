@@ -0,0 +1,206 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/calculator"
+	"github.com/sdpower/ccusage-go/internal/notify"
+	"github.com/sdpower/ccusage-go/internal/output"
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// AlertProjection is the --notify-at value alerting when the projected
+// usage of a block exceeds the token limit
+const AlertProjection = "projection"
+
+// DefaultAlerts returns the default --notify-at values: the warning and
+// critical shares of the token limit, and a projection beyond it
+func DefaultAlerts(thresholds calculator.UsageThresholds) []string {
+	return []string{
+		strconv.FormatFloat(thresholds.Warn, 'f', -1, 64),
+		strconv.FormatFloat(thresholds.Critical, 'f', -1, 64),
+		AlertProjection,
+	}
+}
+
+// Alert events, the "event" of webhook payloads
+const (
+	EventBlockUsage = "block_usage" // Block tokens reached a share of the token limit
+	EventProjection = "projection"  // Projected block tokens exceed the token limit
+	EventBurnRate   = "burn_rate"   // Tokens per minute of the block exceed a rate
+	EventDailyCost  = "daily_cost"  // Today's cost exceeds an amount
+)
+
+// Alerts selects the alerts of the live monitor and the alert daemon
+type Alerts struct {
+	Percents   []float64 // Shares of the token limit, ascending
+	Projection bool      // Projected usage exceeds the token limit
+	BurnRate   float64   // Tokens per minute (0 = none)
+	DailyCost  float64   // Dollars spent today (0 = none)
+
+	Desktop bool            // Show desktop notifications
+	Webhook *notify.Webhook // Post to a webhook (nil = none)
+}
+
+// ParseAlerts validates --notify-at values: percentages of the token limit
+// and AlertProjection
+func ParseAlerts(values []string) (Alerts, error) {
+	var alerts Alerts
+	for _, value := range values {
+		value = strings.TrimSuffix(strings.TrimSpace(value), "%")
+		if value == AlertProjection {
+			alerts.Projection = true
+			continue
+		}
+		percent, err := strconv.ParseFloat(value, 64)
+		if err != nil || percent <= 0 {
+			return Alerts{}, fmt.Errorf("invalid --notify-at %q, use percentages of the token limit or %s", value, AlertProjection)
+		}
+		alerts.Percents = append(alerts.Percents, percent)
+	}
+	sort.Float64s(alerts.Percents)
+	return alerts, nil
+}
+
+// deliver sends alert to the desktop and the webhook, reporting failures
+// to errs (nil discards them)
+func (a Alerts) deliver(ctx context.Context, alert notify.Alert, errs io.Writer) {
+	if a.Desktop {
+		if err := notify.Send(alert.Title, alert.Message); err != nil && errs != nil {
+			fmt.Fprintf(errs, "Warning: %v\n", err)
+		}
+	}
+	if a.Webhook != nil {
+		if err := a.Webhook.Post(ctx, alert); err != nil && errs != nil {
+			fmt.Fprintf(errs, "Warning: %v\n", err)
+		}
+	}
+}
+
+// usageStatus is what alerts are checked against
+type usageStatus struct {
+	block      *types.SessionBlock // Active block (nil = none)
+	tokenLimit int                 // Of a block (0 = unknown)
+	projection *types.ProjectedUsage
+	burnRate   *types.BurnRate
+	todayCost  float64
+	now        time.Time
+	loc        *time.Location
+}
+
+// blockAlerts tracks the alerts already sent, so that each fires once per
+// block, or per day for the daily cost
+type blockAlerts struct {
+	alerts     Alerts
+	blockStart time.Time
+	percent    float64 // Highest threshold alerted for the block
+	projected  bool
+	burning    bool
+	costDay    string // Day the daily cost was alerted (YYYY-MM-DD)
+}
+
+// check returns the alerts status calls for. Crossing several thresholds at
+// once, e.g. on starting the monitor, only alerts the highest.
+func (a *blockAlerts) check(status usageStatus) []notify.Alert {
+	var alerts []notify.Alert
+	if day := status.now.In(status.loc).Format("2006-01-02"); a.alerts.DailyCost > 0 && status.todayCost >= a.alerts.DailyCost && a.costDay != day {
+		a.costDay = day
+		alerts = append(alerts, notify.Alert{
+			Event:     EventDailyCost,
+			Title:     fmt.Sprintf("Claude usage today passed %s", output.FormatCost(a.alerts.DailyCost)),
+			Message:   fmt.Sprintf("%s spent on %s", output.FormatCost(status.todayCost), day),
+			Value:     status.todayCost,
+			Threshold: a.alerts.DailyCost,
+			Time:      status.now,
+		})
+	}
+
+	block := status.block
+	if block == nil {
+		return alerts
+	}
+	if !block.StartTime.Equal(a.blockStart) {
+		*a = blockAlerts{alerts: a.alerts, blockStart: block.StartTime, costDay: a.costDay}
+	}
+	ends := block.EndTime.In(status.loc).Format("03:04 PM")
+
+	if burnRate := status.burnRate; a.alerts.BurnRate > 0 && !a.burning && burnRate != nil && burnRate.TokensPerMinute >= a.alerts.BurnRate {
+		a.burning = true
+		alerts = append(alerts, notify.Alert{
+			Event:     EventBurnRate,
+			Title:     fmt.Sprintf("Claude burn rate over %s tokens/min", formatNumber(int(a.alerts.BurnRate))),
+			Message:   fmt.Sprintf("%s tokens/min (%s/hour) in the block ending at %s", formatNumber(int(burnRate.TokensPerMinute)), output.FormatCost(burnRate.CostPerHour), ends),
+			Value:     burnRate.TokensPerMinute,
+			Threshold: a.alerts.BurnRate,
+			Time:      status.now,
+		})
+	}
+
+	if status.tokenLimit <= 0 {
+		return alerts
+	}
+	tokens := block.TokenCounts.GetTotal()
+	usedPercent := float64(tokens) / float64(status.tokenLimit) * 100
+	crossed := 0.0
+	for _, percent := range a.alerts.Percents {
+		if usedPercent >= percent && percent > a.percent {
+			crossed = percent
+		}
+	}
+	if crossed > 0 {
+		a.percent = crossed
+		alerts = append(alerts, notify.Alert{
+			Event: EventBlockUsage,
+			Title: fmt.Sprintf("Claude block at %.0f%% of its token limit", usedPercent),
+			Message: fmt.Sprintf("%s of %s tokens used (%s); the block ends at %s",
+				formatNumber(tokens), formatNumber(status.tokenLimit), output.FormatCost(block.CostUSD), ends),
+			Value:     usedPercent,
+			Threshold: crossed,
+			Time:      status.now,
+		})
+	}
+
+	if projection := status.projection; a.alerts.Projection && !a.projected && projection != nil && projection.TotalTokens > status.tokenLimit {
+		a.projected = true
+		alerts = append(alerts, notify.Alert{
+			Event: EventProjection,
+			Title: "Claude block projected to exceed its token limit",
+			Message: fmt.Sprintf("Projected %s of %s tokens by %s at the current burn rate",
+				formatNumber(projection.TotalTokens), formatNumber(status.tokenLimit), ends),
+			Value:     float64(projection.TotalTokens),
+			Threshold: float64(status.tokenLimit),
+			Time:      status.now,
+		})
+	}
+	return alerts
+}
+
+// currentStatus returns the usage status of block, the active one (nil =
+// none), given the entries of the last day at least
+func currentStatus(block *types.SessionBlock, entries []types.UsageEntry, tokenLimit int, method calculator.ProjectionMethod, now time.Time, loc *time.Location) usageStatus {
+	status := usageStatus{block: block, tokenLimit: tokenLimit, todayCost: todayCost(entries, now, loc), now: now, loc: loc}
+	if block != nil {
+		status.projection = calculator.ProjectBlockUsageWith(*block, method)
+		status.burnRate = calculator.CalculateBurnRate(*block)
+	}
+	return status
+}
+
+// todayCost sums the cost of entries since midnight in loc
+func todayCost(entries []types.UsageEntry, now time.Time, loc *time.Location) float64 {
+	local := now.In(loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	var cost float64
+	for _, entry := range entries {
+		if !entry.Timestamp.Before(midnight) {
+			cost += entry.Cost
+		}
+	}
+	return cost
+}
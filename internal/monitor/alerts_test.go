@@ -0,0 +1,92 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/calculator"
+	"github.com/sdpower/ccusage-go/internal/notify"
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAlerts(t *testing.T) {
+	alerts, err := ParseAlerts([]string{"95", "80%", "projection"})
+	require.NoError(t, err)
+	assert.Equal(t, Alerts{Percents: []float64{80, 95}, Projection: true}, alerts)
+
+	_, err = ParseAlerts([]string{"high"})
+	assert.Error(t, err)
+	_, err = ParseAlerts([]string{"0"})
+	assert.Error(t, err)
+
+	alerts, err = ParseAlerts(DefaultAlerts(calculator.UsageThresholds{Warn: 70, Critical: 92.5}))
+	require.NoError(t, err)
+	assert.Equal(t, Alerts{Percents: []float64{70, 92.5}, Projection: true}, alerts)
+}
+
+func TestBlockAlerts(t *testing.T) {
+	start := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	block := &types.SessionBlock{StartTime: start, EndTime: start.Add(5 * time.Hour)}
+	alerts := &blockAlerts{alerts: Alerts{Percents: []float64{80, 95}, Projection: true}}
+	check := func(tokens, projected int) []notify.Alert {
+		block.TokenCounts.InputTokens = tokens
+		return alerts.check(usageStatus{block: block, tokenLimit: 1000, projection: &types.ProjectedUsage{TotalTokens: projected}, now: start, loc: time.UTC})
+	}
+
+	assert.Empty(t, check(500, 900))
+	sent := check(850, 900)
+	require.Len(t, sent, 1)
+	assert.Equal(t, EventBlockUsage, sent[0].Event)
+	assert.Equal(t, "Claude block at 85% of its token limit", sent[0].Title)
+	assert.Contains(t, sent[0].Message, "850 of 1,000 tokens")
+	assert.Equal(t, 80.0, sent[0].Threshold)
+	assert.Empty(t, check(900, 950), "each threshold alerts once")
+
+	sent = check(960, 1200)
+	require.Len(t, sent, 2)
+	assert.Equal(t, EventProjection, sent[1].Event)
+	assert.Empty(t, check(990, 1300))
+
+	// A new block starts over, alerting only the highest threshold crossed
+	block.StartTime = block.StartTime.Add(5 * time.Hour)
+	sent = check(970, 900)
+	require.Len(t, sent, 1)
+	assert.Contains(t, sent[0].Title, "97%")
+
+	assert.Empty(t, alerts.check(usageStatus{block: block, now: start, loc: time.UTC}), "no token limit, no alerts")
+}
+
+func TestBurnRateAndDailyCostAlerts(t *testing.T) {
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	block := &types.SessionBlock{StartTime: day.Add(10 * time.Hour), EndTime: day.Add(15 * time.Hour)}
+	alerts := &blockAlerts{alerts: Alerts{BurnRate: 1000, DailyCost: 50}}
+	status := usageStatus{block: block, burnRate: &types.BurnRate{TokensPerMinute: 1500}, todayCost: 60, now: day.Add(11 * time.Hour), loc: time.UTC}
+
+	sent := alerts.check(status)
+	require.Len(t, sent, 2)
+	assert.Equal(t, EventDailyCost, sent[0].Event)
+	assert.Equal(t, "Claude usage today passed $50.00", sent[0].Title)
+	assert.Equal(t, EventBurnRate, sent[1].Event)
+	assert.Empty(t, alerts.check(status))
+
+	// The daily cost alerts again the next day, not in the next block
+	status.block = &types.SessionBlock{StartTime: day.Add(16 * time.Hour), EndTime: day.Add(21 * time.Hour)}
+	status.burnRate = nil
+	assert.Empty(t, alerts.check(status))
+	status.now = status.now.Add(24 * time.Hour)
+	sent = alerts.check(status)
+	require.Len(t, sent, 1)
+	assert.Equal(t, EventDailyCost, sent[0].Event)
+}
+
+func TestTodayCost(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	entries := []types.UsageEntry{
+		{Timestamp: now.Add(-13 * time.Hour), Cost: 5},
+		{Timestamp: now.Add(-2 * time.Hour), Cost: 2},
+		{Timestamp: now, Cost: 1},
+	}
+	assert.Equal(t, 3.0, todayCost(entries, now, time.UTC))
+}
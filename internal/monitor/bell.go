@@ -0,0 +1,56 @@
+package monitor
+
+import (
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sdpower/ccusage-go/internal/notify"
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// Bell rings in the live monitor when the active block crosses the critical
+// threshold and when it ends, for monitors kept in a background pane
+type Bell struct {
+	Sound string // File played instead of the terminal bell ("" = bell)
+}
+
+// blockBell tracks the rings of the active block
+type blockBell struct {
+	bell       Bell
+	blockStart time.Time // Active block last seen (zero = none)
+	critical   bool      // Rung for crossing the critical threshold
+}
+
+// check returns whether block, the active one (nil = none), at percent of
+// the token limit calls for a ring: it crossed critical, or the block seen
+// before ended
+func (b *blockBell) check(block *types.SessionBlock, percent, critical float64) bool {
+	ring := false
+	if block == nil || !block.StartTime.Equal(b.blockStart) {
+		ring = !b.blockStart.IsZero()
+		*b = blockBell{bell: b.bell}
+		if block != nil {
+			b.blockStart = block.StartTime
+		}
+	}
+	if block != nil && !b.critical && percent > critical {
+		b.critical = true
+		ring = true
+	}
+	return ring
+}
+
+// ring returns a command ringing the terminal bell or playing the sound.
+// A failed sound cannot be reported inside the display; notify.SoundAvailable
+// is checked before monitoring starts.
+func (b *blockBell) ring() tea.Cmd {
+	return func() tea.Msg {
+		if b.bell.Sound != "" {
+			notify.PlaySound(b.bell.Sound)
+		} else {
+			os.Stdout.WriteString("\a")
+		}
+		return nil
+	}
+}
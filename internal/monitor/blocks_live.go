@@ -20,8 +20,8 @@ import (
 	"github.com/sdpower/ccusage-go/internal/calculator"
 	"github.com/sdpower/ccusage-go/internal/loader"
 	"github.com/sdpower/ccusage-go/internal/output"
-	"github.com/sdpower/ccusage-go/internal/pricing"
 	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/sdpower/ccusage-go/pkg/pricing"
 	"github.com/sdpower/ccusage-go/internal/usage"
 )
 
@@ -41,6 +41,21 @@ type BlocksLiveConfig struct {
 	Timezone         *time.Location
 	UseGradient      bool  // Enable gradient progress bars
 	OptimizeMemory   bool  // Enable memory optimization (only load recent data)
+	NoCache          bool  // Disable the persistent dedupe store and parse cache
+	FileFilter       *loader.FileFilter // Include/exclude globs (nil reads everything)
+	IncludeSynthetic bool  // Keep <synthetic> model entries
+	CostMode         calculator.CostMode // Cost source ("" = auto)
+	BlockAnchor      calculator.BlockAnchor // Block start placement ("" = hour)
+	Projection       calculator.ProjectionMethod // Burn rate of the projection ("" = average)
+	GapThreshold     time.Duration // Idle time that ends a block (0 = session length)
+	ActiveWindow     time.Duration // Recency that keeps a block active (0 = gap threshold)
+	BlockSource      calculator.BlockSource // Entry streams blocks are found in ("" = merged)
+	Plan             calculator.Plan // Subscription plan whose capacity is shown ("" = none)
+	Alerts           *Alerts // Desktop and webhook alerts (nil = none)
+	Thresholds       calculator.UsageThresholds // Shares of the token limit at which bars turn yellow and red
+	Bell             *Bell // Ring on critical usage and block ends (nil = none)
+	SnapshotDir      string // Where snapshots are saved ("" = working directory)
+	Pricing          pricing.Options // Of the service pricing usage
 }
 
 // BlocksLiveModel represents the state of the live monitor
@@ -60,6 +75,36 @@ type BlocksLiveModel struct {
 	usageLimits    *usage.UsageResponse
 	usageLastFetch time.Time
 	cache          *loader.IncrementalCache // Incremental project-level cache
+	watcher        *loader.Watcher          // File change notifications (nil falls back to polling)
+	lastScan       time.Time                // Last time the data directory was rescanned
+	alerts         *blockAlerts             // Notifications sent for the active block (nil = disabled)
+	bell           *blockBell               // Rings for the active block (nil = disabled)
+	paused         bool                     // Reloading is paused and the display frozen
+	pausedAt       time.Time
+	frozen         string // Display while paused, rendered on pausing and on key presses and resizes
+	notice         string // Shown in the footer for snapshotNoticeDuration, e.g. where a snapshot was saved
+	noticeAt       time.Time
+	history        []types.SessionBlock     // Completed blocks, newest first
+	historyOffset  int                      // First history row shown
+	view           liveView                 // Switched with the keys of liveViews
+	weekLoaded     bool                     // The week view was shown, so files of the last week are loaded
+}
+
+// historyRows is the number of completed blocks the history panel shows at once
+const historyRows = 3
+
+// watcherRescanInterval forces a full rescan while watching, in case file events were missed
+const watcherRescanInterval = time.Minute
+
+// blocksDataChangedMsg is sent when the watcher reports modified JSONL files
+type blocksDataChangedMsg struct{}
+
+// waitForChangesCmd blocks until the watcher reports changed files
+func waitForChangesCmd(w *loader.Watcher) tea.Cmd {
+	return func() tea.Msg {
+		<-w.Changes()
+		return blocksDataChangedMsg{}
+	}
 }
 
 // blocksTickMsg is sent periodically to update the display
@@ -87,6 +132,9 @@ func (m *BlocksLiveModel) Init() tea.Cmd {
 	if m.usageClient != nil {
 		cmds = append(cmds, fetchUsageLimitsCmd(m.usageClient))
 	}
+	if m.watcher != nil {
+		cmds = append(cmds, waitForChangesCmd(m.watcher))
+	}
 	return tea.Batch(cmds...)
 }
 
@@ -98,11 +146,44 @@ func (m *BlocksLiveModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "q", "ctrl+c":
 			m.quitting = true
 			return m, tea.Quit
+		case "up", "k":
+			m.scrollHistory(-1)
+		case "down", "j":
+			m.scrollHistory(1)
+		case "p":
+			if m.paused {
+				m.paused, m.frozen = false, ""
+				m.reload()
+				return m, tea.Batch(m.notifyCmd(), m.bellCmd())
+			}
+			m.paused, m.pausedAt = true, time.Now()
+		case "S":
+			return m, saveSnapshotCmd(m.snapshot(), m.config.SnapshotDir)
+		default:
+			if view, ok := viewForKey(msg.String()); ok && m.setView(view) {
+				m.reload()
+			}
+		}
+		if m.paused {
+			m.frozen = m.render()
 		}
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		if m.paused {
+			m.frozen = m.render()
+		}
+
+	case snapshotSavedMsg:
+		m.notice, m.noticeAt = "📸 Snapshot saved to "+msg.path, time.Now()
+		if msg.err != nil {
+			m.notice = "⚠️  Snapshot failed: " + msg.err.Error()
+		}
+		if m.paused {
+			m.frozen = m.render()
+		}
+		return m, nil
 
 	case usageLimitsMsg:
 		if msg.response != nil {
@@ -111,40 +192,35 @@ func (m *BlocksLiveModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case blocksTickMsg:
-		// Use incremental cache for efficient reloading
-		entries, changed, err := m.cache.Update(
-			m.loader, m.calculator,
-			m.config.DataPath,
-			24*time.Hour,
-		)
-		if err != nil {
-			m.err = err
-			return m, blocksTickCmd(m.config.RefreshInterval)
+	case blocksDataChangedMsg:
+		if m.paused {
+			return m, waitForChangesCmd(m.watcher)
 		}
+		m.reload()
+		return m, tea.Batch(waitForChangesCmd(m.watcher), m.notifyCmd(), m.bellCmd())
 
-		if changed || m.activeBlock == nil {
-			// Data changed or no active block yet — recalculate
-			blocks := m.calculator.IdentifySessionBlocks(entries, m.config.SessionLength)
-			m.activeBlock = nil
-			for i := range blocks {
-				if blocks[i].IsActive {
-					m.activeBlock = &blocks[i]
-					break
-				}
+	case blocksTickMsg:
+		if m.notice != "" && time.Since(m.noticeAt) >= snapshotNoticeDuration {
+			m.notice = ""
+			if m.paused {
+				m.frozen = m.render()
 			}
-		} else if m.activeBlock != nil {
-			// Data unchanged, but check if active block has expired
-			if time.Now().After(m.activeBlock.EndTime) {
-				m.activeBlock = nil
+		}
+		if m.paused {
+			return m, blocksTickCmd(m.config.RefreshInterval)
+		}
+		// With a watcher, files are only rescanned on change events (plus a periodic safety rescan)
+		if m.watcher == nil || time.Since(m.lastScan) >= watcherRescanInterval {
+			if !m.reload() {
+				return m, blocksTickCmd(m.config.RefreshInterval)
 			}
+		} else if m.activeBlock != nil && time.Now().After(m.activeBlock.EndTime) {
+			// Data unchanged, but the active block has expired
+			m.activeBlock = nil
 		}
 
-		m.lastUpdate = time.Now()
-		m.err = nil
-
 		// Re-fetch usage limits if cache expired
-		cmds := []tea.Cmd{blocksTickCmd(m.config.RefreshInterval)}
+		cmds := []tea.Cmd{blocksTickCmd(m.config.RefreshInterval), m.notifyCmd(), m.bellCmd()}
 		if m.usageClient != nil && time.Since(m.usageLastFetch) > 5*time.Minute {
 			cmds = append(cmds, fetchUsageLimitsCmd(m.usageClient))
 		}
@@ -154,22 +230,118 @@ func (m *BlocksLiveModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// notifyCmd returns a command delivering the alerts the usage calls for, or
+// nil. A failed delivery cannot be reported inside the display;
+// notify.Available is checked before monitoring starts.
+func (m *BlocksLiveModel) notifyCmd() tea.Cmd {
+	if m.alerts == nil {
+		return nil
+	}
+	status := currentStatus(m.activeBlock, m.allEntries, m.config.TokenLimit, m.config.Projection, time.Now(), m.config.Timezone)
+	alerts := m.alerts.check(status)
+	if len(alerts) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		for _, alert := range alerts {
+			m.alerts.alerts.deliver(context.Background(), alert, nil)
+		}
+		return nil
+	}
+}
+
+// bellCmd returns a command ringing the bell when the active block calls
+// for it, or nil
+func (m *BlocksLiveModel) bellCmd() tea.Cmd {
+	if m.bell == nil {
+		return nil
+	}
+	percent := 0.0
+	if m.activeBlock != nil && m.config.TokenLimit > 0 {
+		percent = float64(m.activeBlock.TokenCounts.GetTotal()) / float64(m.config.TokenLimit) * 100
+	}
+	if !m.bell.check(m.activeBlock, percent, m.config.Thresholds.Critical) {
+		return nil
+	}
+	return m.bell.ring()
+}
+
+// reload refreshes entries through the incremental cache and recomputes the active block.
+// It returns false if loading failed.
+func (m *BlocksLiveModel) reload() bool {
+	m.lastScan = time.Now()
+	window := m.dataWindow()
+	entries, changed, err := m.cache.Update(
+		m.loader, m.calculator,
+		m.config.DataPath,
+		window,
+	)
+	if err != nil {
+		m.err = err
+		return false
+	}
+
+	if changed || m.activeBlock == nil {
+		// Data changed or no active block yet — recalculate
+		m.allEntries = entries
+		blocks := m.calculator.IdentifySessionBlocks(entries, m.config.SessionLength)
+		m.activeBlock = activeBlock(blocks)
+		m.history = completedBlocks(blocks)
+		m.scrollHistory(0)
+	} else if m.activeBlock != nil {
+		// Data unchanged, but check if active block has expired
+		if time.Now().After(m.activeBlock.EndTime) {
+			m.activeBlock = nil
+		}
+	}
+
+	m.lastUpdate = time.Now()
+	m.err = nil
+	return true
+}
+
 // View renders the display
 func (m *BlocksLiveModel) View() string {
 	if m.quitting {
 		return ""
 	}
+	if m.paused {
+		return m.frozen
+	}
+	return m.render()
+}
+
+// now returns the time the display is of: the current one, or that of
+// pausing
+func (m *BlocksLiveModel) now() time.Time {
+	if m.paused {
+		return m.pausedAt
+	}
+	return time.Now()
+}
 
+// render renders the current view
+func (m *BlocksLiveModel) render() string {
 	if m.err != nil {
 		return fmt.Sprintf("Error: %v\n\nPress 'q' to quit.", m.err)
 	}
 
+	if m.view != viewBlock {
+		return m.renderView(m.now())
+	}
+
 	if m.activeBlock == nil {
 		waitingStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("226")).
 			Bold(true)
+		if m.paused {
+			return waitingStyle.Render("No active session block found. ⏸ Paused") +
+				m.renderHistorySection() +
+				"\n\nPress p to resume, 'q' to quit."
+		}
 		return waitingStyle.Render("No active session block found. Waiting...") + 
-			"\n\nPress 'q' to quit."
+			m.renderHistorySection() +
+			"\n\nPress d, w or s for today, the week or sessions, p to pause, S to save a snapshot, 'q' to quit."
 	}
 
 	// Render active block display
@@ -179,7 +351,7 @@ func (m *BlocksLiveModel) View() string {
 // renderActiveBlock renders the active block display
 func (m *BlocksLiveModel) renderActiveBlock() string {
 	block := m.activeBlock
-	now := time.Now()
+	now := m.now()
 
 	// Calculate metrics
 	totalTokens := block.TokenCounts.GetTotal()
@@ -192,46 +364,10 @@ func (m *BlocksLiveModel) renderActiveBlock() string {
 	burnRate := calculator.CalculateBurnRate(*block)
 	
 	// Calculate projection
-	projection := calculator.ProjectBlockUsage(*block)
+	projection := calculator.ProjectBlockUsageWith(*block, m.config.Projection)
+
+	var rows []string
 
-	// Create a buffer for the table
-	var buf bytes.Buffer
-	
-	// Create table with tablewriter v1.0.9 API
-	table := tablewriter.NewTable(&buf,
-		tablewriter.WithRenderer(renderer.NewBlueprint(tw.Rendition{
-			Settings: tw.Settings{
-				Separators: tw.Separators{
-					BetweenRows: tw.On,
-				},
-			},
-		})),
-		tablewriter.WithConfig(tablewriter.Config{
-			Header: tw.CellConfig{
-				Alignment: tw.CellAlignment{Global: tw.AlignCenter}, // 標題置中
-			},
-			Row: tw.CellConfig{
-				Alignment: tw.CellAlignment{Global: tw.AlignLeft}, // 內容左對齊
-				Padding: tw.CellPadding{
-					Global: tw.Padding{
-						Bottom: " ",   // 在儲存格下方增加一個空格
-						Left:   " ",   // 左側保持一個空格
-						Right:  " ",   // 右側保持一個空格
-					},
-				},
-			},
-			Footer: tw.CellConfig{
-				Alignment: tw.CellAlignment{Global: tw.AlignCenter}, // Footer 置中
-			},
-		}),
-		tablewriter.WithHeaderAutoFormat(tw.Off),
-	)
-	
-	// Title row - use Header for center alignment
-	title := "CLAUDE CODE - LIVE TOKEN USAGE MONITOR (WITH GO)"
-	titleStyle := lipgloss.NewStyle().Bold(true)
-	table.Header([]string{titleStyle.Render(title)})
-	
 	// SESSION section
 	sessionLine := m.renderCompactSectionAsString(
 		"⏱️", "SESSION", 
@@ -244,7 +380,7 @@ func (m *BlocksLiveModel) renderActiveBlock() string {
 		"cyan",
 		fmt.Sprintf("%.1f%%", sessionPercent),
 	)
-	table.Append([]string{sessionLine})
+	rows = append(rows, sessionLine)
 	
 	// USAGE section
 	usagePercent := 0.0
@@ -265,12 +401,12 @@ func (m *BlocksLiveModel) renderActiveBlock() string {
 		}
 	}
 	
-	usageInfo := fmt.Sprintf("Tokens: %s (Burn Rate: %s token/min%s)  Limit: %s  Cost: $%.2f",
+	usageInfo := fmt.Sprintf("Tokens: %s (Burn Rate: %s token/min%s)  Limit: %s  Cost: %s",
 		formatNumberWithCommas(totalTokens),
 		formatNumberWithCommas(burnRateValue),
 		burnRateIndicator,
 		formatNumberWithCommas(m.config.TokenLimit),
-		block.CostUSD)
+		output.FormatCost(block.CostUSD))
 	
 	usageRightText := fmt.Sprintf("%.1f%% (%s/%s)",
 		usagePercent,
@@ -278,13 +414,7 @@ func (m *BlocksLiveModel) renderActiveBlock() string {
 		formatTokensShort(m.config.TokenLimit))
 	
 	// Determine usage color
-	usageColor := "green"
-	if usagePercent > 80 {
-		usageColor = "yellow"
-	}
-	if usagePercent > 95 {
-		usageColor = "red"
-	}
+	usageColor := m.thresholdColor(usagePercent)
 	
 	usageLine := m.renderCompactSectionAsString(
 		"🔥", "USAGE",
@@ -293,7 +423,12 @@ func (m *BlocksLiveModel) renderActiveBlock() string {
 		usageColor,
 		usageRightText,
 	)
-	table.Append([]string{usageLine})
+	rows = append(rows, usageLine)
+	
+	// TREND section
+	if trend := m.renderTrendSection(calculator.MinuteSeries(m.allEntries, now.Add(-trendWindow+time.Minute), now)); trend != "" {
+		rows = append(rows, trend)
+	}
 	
 	// PROJECTION section
 	if projection != nil && m.config.TokenLimit > 0 {
@@ -309,10 +444,14 @@ func (m *BlocksLiveModel) renderActiveBlock() string {
 			statusText = "✅ WITHIN LIMIT"
 		}
 		
-		projInfo := fmt.Sprintf("Status: %s  Tokens: %s  Cost: $%.2f",
+		projInfo := fmt.Sprintf("Status: %s  Tokens: %s (%s-%s)  Cost: %s (%s-%s)",
 			statusText,
 			formatNumberWithCommas(projection.TotalTokens),
-			projection.TotalCost)
+			formatTokensShort(projection.LowTokens),
+			formatTokensShort(projection.HighTokens),
+			output.FormatCost(projection.TotalCost),
+			output.FormatCost(projection.LowCost),
+			output.FormatCost(projection.HighCost))
 		
 		projRightText := fmt.Sprintf("%.1f%% (%s/%s)",
 			projPercent,
@@ -320,13 +459,7 @@ func (m *BlocksLiveModel) renderActiveBlock() string {
 			formatTokensShort(m.config.TokenLimit))
 		
 		// Determine projection color
-		projColor := "green"
-		if projPercent > 80 {
-			projColor = "yellow"
-		}
-		if projPercent > 95 {
-			projColor = "red"
-		}
+		projColor := m.thresholdColor(projPercent)
 		
 		projectionLine := m.renderCompactSectionAsString(
 			"📈", "PROJECTION",
@@ -335,33 +468,84 @@ func (m *BlocksLiveModel) renderActiveBlock() string {
 			projColor,
 			projRightText,
 		)
-		table.Append([]string{projectionLine})
+		rows = append(rows, projectionLine)
 	}
 	
+	// PLAN section
+	if m.config.Plan != "" {
+		rows = append(rows, m.renderPlanSection(calculator.PlanCapacity(m.config.Plan, block, m.allEntries, now)))
+	}
+
 	// LIMITS section
 	limitsSection := m.renderLimitsSection()
 	if limitsSection != "" {
-		table.Append([]string{limitsSection})
+		rows = append(rows, limitsSection)
 	}
 
-	// Models section
-	modelsText := "⚙️  Models: "
-	if len(block.Models) > 0 {
-		// Simplify model names using unified formatting function
-		simplifiedModels := []string{}
-		for _, model := range block.Models {
-			shortModel := output.ShortenModelName(model)
-			simplifiedModels = append(simplifiedModels, shortModel)
-		}
-		modelsText += strings.Join(simplifiedModels, ", ")
-	} else {
-		modelsText += "none"
+	// MODELS section
+	rows = append(rows, m.renderModelsSection(block.Entries))
+
+	// HISTORY section
+	if history := m.renderHistorySection(); history != "" {
+		rows = append(rows, history)
+	}
+	
+	return m.renderBox("CLAUDE CODE - LIVE TOKEN USAGE MONITOR (WITH GO)", rows)
+}
+
+// renderBox renders rows in the monitor's box under title, with the refresh
+// and view keys in the footer, centered in terminals wider than the box
+func (m *BlocksLiveModel) renderBox(title string, rows []string) string {
+	// Create a buffer for the table
+	var buf bytes.Buffer
+	
+	// Create table with tablewriter v1.0.9 API
+	table := tablewriter.NewTable(&buf,
+		tablewriter.WithRenderer(renderer.NewBlueprint(tw.Rendition{
+			Settings: tw.Settings{
+				Separators: tw.Separators{
+					BetweenRows: tw.On,
+				},
+			},
+		})),
+		tablewriter.WithConfig(tablewriter.Config{
+			Header: tw.CellConfig{
+				Alignment: tw.CellAlignment{Global: tw.AlignCenter}, // 標題置中
+			},
+			Row: tw.CellConfig{
+				Alignment: tw.CellAlignment{Global: tw.AlignLeft}, // 內容左對齊
+				Padding: tw.CellPadding{
+					Global: tw.Padding{
+						Bottom: " ",   // 在儲存格下方增加一個空格
+						Left:   " ",   // 左側保持一個空格
+						Right:  " ",   // 右側保持一個空格
+					},
+				},
+			},
+			Footer: tw.CellConfig{
+				Alignment: tw.CellAlignment{Global: tw.AlignCenter}, // Footer 置中
+			},
+		}),
+		tablewriter.WithHeaderAutoFormat(tw.Off),
+	)
+	
+	// Title row - use Header for center alignment
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	table.Header([]string{titleStyle.Render(title)})
+	for _, row := range rows {
+		table.Append([]string{row})
 	}
-	table.Append([]string{modelsText})
 	
 	// Footer (inside the box) - use Footer for center alignment
-	footerText := fmt.Sprintf("↻ Refreshing every %ds  •  Press Ctrl+C to stop",
-		int(m.config.RefreshInterval.Seconds()))
+	footerText := fmt.Sprintf("↻ Refreshing every %ds  •  %s  •  p Pause  S Snapshot  •  Ctrl+C to stop",
+		int(m.config.RefreshInterval.Seconds()), m.viewKeys())
+	if m.paused {
+		footerText = fmt.Sprintf("⏸ PAUSED at %s  •  %s  •  p Resume  S Snapshot  •  Ctrl+C to stop",
+			m.pausedAt.In(m.config.Timezone).Format("03:04:05 PM"), m.viewKeys())
+	}
+	if m.notice != "" {
+		footerText = m.notice
+	}
 	footerStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("240"))
 	table.Footer([]string{footerStyle.Render(footerText)})
@@ -395,6 +579,40 @@ func (m *BlocksLiveModel) renderActiveBlock() string {
 	return buf.String()
 }
 
+// thresholdColor returns the bar color of a share of a limit: green, yellow
+// from the warning threshold and red from the critical one
+func (m *BlocksLiveModel) thresholdColor(percent float64) string {
+	switch {
+	case percent > m.config.Thresholds.Critical:
+		return "red"
+	case percent > m.config.Thresholds.Warn:
+		return "yellow"
+	default:
+		return "green"
+	}
+}
+
+// renderPlanSection renders the estimated plan capacity left, with a bar of
+// the block's cost limit used
+func (m *BlocksLiveModel) renderPlanSection(capacity types.PlanCapacity) string {
+	usedPercent := 0.0
+	if capacity.BlockCostLimit > 0 {
+		usedPercent = capacity.BlockCostUsed / capacity.BlockCostLimit * 100
+	}
+	planColor := m.thresholdColor(usedPercent)
+	info := fmt.Sprintf("%s (estimated)  Block left: %s tokens, %s  Week left: %s of %s",
+		capacity.Plan,
+		formatNumberWithCommas(capacity.BlockTokensRemaining),
+		output.FormatCost(capacity.BlockCostRemaining),
+		output.FormatCost(capacity.WeekCostRemaining),
+		output.FormatCost(capacity.WeekCostLimit))
+	rightText := fmt.Sprintf("%.1f%% (%s/%s)",
+		usedPercent,
+		output.FormatCost(capacity.BlockCostUsed),
+		output.FormatCost(capacity.BlockCostLimit))
+	return m.renderCompactSectionAsString("🎯", "PLAN", usedPercent, info, planColor, rightText)
+}
+
 // renderLimitsSection renders the usage limits section for the table
 func (m *BlocksLiveModel) renderLimitsSection() string {
 	if m.usageLimits == nil {
@@ -461,37 +679,152 @@ func (m *BlocksLiveModel) renderCompactSectionAsString(icon, title string, perce
 	// Build left part (icon + title)
 	leftPart := fmt.Sprintf("%s %-9s", icon, title)
 	
-	// Determine progress bar width based on terminal width
-	// Min width: 95, Max width: 120
-	progressBarWidth := 40 // Default for minimum width
-	if m.width > 0 {
-		availableWidth := m.width - 2
-		if availableWidth >= 120 {
-			progressBarWidth = 50 // Use wider bar for max width
-		} else if availableWidth >= 100 {
-			progressBarWidth = 45 // Medium width
-		}
-	}
+	progressBarWidth, rightPadding := m.sectionBarWidth()
 	
 	// Build progress bar
 	progressBar := m.renderEnhancedProgressBar(percent, progressBarWidth, barColor)
 	
-	// Build the complete line with dynamic spacing
-	// Adjust spacing based on progress bar width
-	rightPadding := 20
-	if progressBarWidth == 50 {
-		rightPadding = 20
-	} else if progressBarWidth == 45 {
-		rightPadding = 15
-	} else {
-		rightPadding = 10
-	}
 	topLine := fmt.Sprintf("%-12s %s %*s", leftPart, progressBar, rightPadding, rightText)
 	
 	// Add spacing above and below for better readability
 	return fmt.Sprintf("\n%s\n%s\n", topLine, info)
 }
 
+// sectionBarWidth returns the width of a section's bar and of the text right
+// of it for the terminal width (min width: 95, max width: 120)
+func (m *BlocksLiveModel) sectionBarWidth() (barWidth, rightPadding int) {
+	barWidth, rightPadding = 40, 10 // Default for minimum width
+	if m.width > 0 {
+		availableWidth := m.width - 2
+		if availableWidth >= 120 {
+			barWidth, rightPadding = 50, 20 // Use wider bar for max width
+		} else if availableWidth >= 100 {
+			barWidth, rightPadding = 45, 15 // Medium width
+		}
+	}
+	return barWidth, rightPadding
+}
+
+// trendWindow is the span of the TREND sparkline
+const trendWindow = time.Hour
+
+// renderTrendSection renders the per-minute burn rate of series (the last
+// trendWindow, across blocks) as a sparkline, one character per minute or
+// per few minutes when the bars are narrower, or "" for an empty series
+func (m *BlocksLiveModel) renderTrendSection(series []types.BurnRatePoint) string {
+	if len(series) == 0 {
+		return ""
+	}
+	// Lines as long as the bars' above, leaving 10 columns for the rate
+	barWidth, barPadding := m.sectionBarWidth()
+	width, rightPadding := barWidth+barPadding-10, 10
+	perChar := (len(series) + width - 1) / width
+
+	peak := 0
+	var tokens []int
+	for i, point := range series {
+		if point.Tokens > peak {
+			peak = point.Tokens
+		}
+		if i%perChar == 0 {
+			tokens = append(tokens, 0)
+		}
+		tokens[len(tokens)-1] += point.Tokens
+	}
+
+	sparkline := output.Sparkline(tokens) + strings.Repeat(" ", width-len(tokens))
+	if !m.config.NoColor {
+		sparkline = lipgloss.NewStyle().Foreground(lipgloss.Color("39")).Render(sparkline)
+	}
+
+	leftPart := fmt.Sprintf("%s %-9s", "📉", "TREND")
+	rightText := fmt.Sprintf("%s/min", formatTokensShort(series[len(series)-1].Tokens))
+	topLine := fmt.Sprintf("%-12s %s %*s", leftPart, sparkline, rightPadding, rightText)
+	unit := "minute"
+	if perChar > 1 {
+		unit = fmt.Sprintf("%d minutes", perChar)
+	}
+	info := fmt.Sprintf("Tokens per %s over the last %d min  Peak: %s token/min",
+		unit, len(series), formatNumberWithCommas(peak))
+	return fmt.Sprintf("\n%s\n%s\n", topLine, info)
+}
+
+// renderModelsSection lists the tokens and cost of each model of entries,
+// the most costly first
+func (m *BlocksLiveModel) renderModelsSection(entries []types.UsageEntry) string {
+	breakdown := calculator.ModelBreakdown(entries)
+	if len(breakdown) == 0 {
+		return "⚙️  Models: none"
+	}
+
+	total := 0
+	for _, usage := range breakdown {
+		total += usage.TotalTokens
+	}
+	var b strings.Builder
+	b.WriteString("⚙️  Models")
+	for _, usage := range breakdown {
+		share := 0.0
+		if total > 0 {
+			share = float64(usage.TotalTokens) / float64(total) * 100
+		}
+		fmt.Fprintf(&b, "\n   %-18s %12s tokens (%5.1f%%)  %10s",
+			output.ShortenModelName(usage.Model),
+			formatNumberWithCommas(usage.TotalTokens),
+			share,
+			output.FormatCost(usage.Cost))
+	}
+	return b.String()
+}
+
+// scrollHistory moves the history panel by delta rows, keeping it within the
+// completed blocks
+func (m *BlocksLiveModel) scrollHistory(delta int) {
+	m.historyOffset = min(max(m.historyOffset+delta, 0), max(len(m.history)-historyRows, 0))
+}
+
+// renderHistorySection renders the completed blocks at the history offset,
+// or "" when there are none
+func (m *BlocksLiveModel) renderHistorySection() string {
+	if len(m.history) == 0 {
+		return ""
+	}
+	end := min(m.historyOffset+historyRows, len(m.history))
+	title := fmt.Sprintf("%s %-9s", "🕘", "HISTORY")
+	position := fmt.Sprintf("%d-%d of %d", m.historyOffset+1, end, len(m.history))
+	if len(m.history) > historyRows {
+		position += "  ↑/↓ to scroll"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n%-12s Recent blocks  %s\n", title, position)
+	for _, block := range m.history[m.historyOffset:end] {
+		duration := time.Duration(0)
+		if block.ActualEndTime != nil {
+			duration = block.ActualEndTime.Sub(block.StartTime)
+		}
+		fmt.Fprintf(&b, "%s-%s  %12s tokens  %10s  %7s\n",
+			block.StartTime.In(m.config.Timezone).Format("Jan 02 03:04 PM"),
+			block.EndTime.In(m.config.Timezone).Format("03:04 PM"),
+			formatNumberWithCommas(block.TokenCounts.GetTotal()),
+			output.FormatCost(block.CostUSD),
+			formatDuration(duration))
+	}
+	return b.String()
+}
+
+// completedBlocks returns the blocks of blocks with usage that are no longer
+// active, newest first
+func completedBlocks(blocks []types.SessionBlock) []types.SessionBlock {
+	var completed []types.SessionBlock
+	for i := len(blocks) - 1; i >= 0; i-- {
+		if !blocks[i].IsActive && !blocks[i].IsGap && len(blocks[i].Entries) > 0 {
+			completed = append(completed, blocks[i])
+		}
+	}
+	return completed
+}
+
 // renderCompactSection renders a compact single-line section with progress bar
 func (m *BlocksLiveModel) renderCompactSection(icon, title string, percent float64, info, barColor, rightText string, boxWidth int) string {
 	// Calculate layout widths
@@ -789,18 +1122,41 @@ func StartBlocksLiveMonitoring(config BlocksLiveConfig) error {
 	}
 
 	// Initialize services
-	pricingService := pricing.NewService()
+	pricingService := pricing.New(config.Pricing)
 	calc := calculator.New(pricingService)
+	calc.SetMode(config.CostMode)
+	calc.SetBlockAnchor(config.BlockAnchor, config.Timezone)
+	calc.SetGapThreshold(config.GapThreshold)
+	calc.SetActiveWindow(config.ActiveWindow)
+	calc.SetBlockSource(config.BlockSource)
+	calc.WarmPricing(context.Background())
 	dataLoader := loader.New()
 	
 	// Optimize for live mode: reduce concurrent file reads to minimize CPU usage
 	dataLoader.SetMaxWorkers(3) // Even more conservative for live monitoring
+	dataLoader.SetFileFilter(config.FileFilter)
+	dataLoader.SetIncludeSynthetic(config.IncludeSynthetic)
 	
 	// Enable debug mode if DEBUG env var is set
 	if os.Getenv("DEBUG") != "" {
 		dataLoader.SetDebug(true)
 	}
 
+	// Only recent files are reloaded, so remember older dedupe keys across restarts
+	if !config.NoCache {
+		if storePath, err := loader.DefaultDedupeStorePath(config.DataPath); err == nil {
+			store := loader.OpenDedupeStore(storePath)
+			dataLoader.SetDedupeStore(store)
+			defer store.Save()
+		}
+		// Each tick re-reads the active session file; the parse cache limits that to appended lines
+		if cachePath, err := loader.DefaultParseCachePath(); err == nil {
+			cache := loader.OpenParseCache(cachePath)
+			dataLoader.SetParseCache(cache)
+			defer cache.Save()
+		}
+	}
+
 	// Create initial model
 	model := &BlocksLiveModel{
 		config:        config,
@@ -811,6 +1167,20 @@ func StartBlocksLiveMonitoring(config BlocksLiveConfig) error {
 		usageClient:   usage.NewClient(),
 		cache:         loader.NewIncrementalCache(),
 	}
+	if config.Alerts != nil {
+		model.alerts = &blockAlerts{alerts: *config.Alerts}
+	}
+	if config.Bell != nil {
+		model.bell = &blockBell{bell: *config.Bell}
+	}
+
+	// React to file writes immediately; fall back to polling every tick if watching is unavailable
+	if watcher, err := loader.NewWatcher(config.DataPath); err == nil {
+		model.watcher = watcher
+		defer watcher.Close()
+	} else if os.Getenv("DEBUG") != "" {
+		fmt.Fprintf(os.Stderr, "Debug: File watching unavailable, polling instead: %v\n", err)
+	}
 
 	// Setup signal handling for graceful shutdown
 	sigChan := make(chan os.Signal, 1)
@@ -0,0 +1,126 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sdpower/ccusage-go/internal/calculator"
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlocksLiveHistory(t *testing.T) {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	var blocks []types.SessionBlock
+	for i := 0; i < 5; i++ {
+		blockStart := start.Add(time.Duration(i) * 6 * time.Hour)
+		lastActivity := blockStart.Add(90 * time.Minute)
+		blocks = append(blocks,
+			types.SessionBlock{
+				StartTime:     blockStart,
+				EndTime:       blockStart.Add(5 * time.Hour),
+				ActualEndTime: &lastActivity,
+				Entries:       []types.UsageEntry{{Timestamp: blockStart}},
+				TokenCounts:   types.TokenCounts{InputTokens: 1000 * (i + 1)},
+				CostUSD:       float64(i + 1),
+			},
+			types.SessionBlock{StartTime: blockStart.Add(5 * time.Hour), IsGap: true})
+	}
+	blocks[len(blocks)-2].IsActive = true
+
+	history := completedBlocks(blocks)
+	require.Len(t, history, 4)
+	assert.Equal(t, 4000, history[0].TokenCounts.GetTotal(), "newest first")
+	assert.Equal(t, 1000, history[3].TokenCounts.GetTotal())
+
+	m := &BlocksLiveModel{config: BlocksLiveConfig{Timezone: time.UTC}, history: history}
+	panel := m.renderHistorySection()
+	assert.Contains(t, panel, "1-3 of 4")
+	assert.Contains(t, panel, "Jun 01 06:00 PM-11:00 PM")
+	assert.Contains(t, panel, "1h 30m")
+	assert.NotContains(t, panel, "Jun 01 12:00 AM")
+
+	m.scrollHistory(5)
+	assert.Equal(t, 1, m.historyOffset, "stops at the oldest block")
+	assert.Contains(t, m.renderHistorySection(), "Jun 01 12:00 AM")
+	m.scrollHistory(-5)
+	assert.Equal(t, 0, m.historyOffset)
+
+	m.history = nil
+	assert.Empty(t, m.renderHistorySection())
+}
+
+func TestBlocksLiveModelsSection(t *testing.T) {
+	m := &BlocksLiveModel{config: BlocksLiveConfig{Timezone: time.UTC}}
+	entries := []types.UsageEntry{
+		{Model: "claude-sonnet-4-20250514", TotalTokens: 3000, Cost: 1},
+		{Model: "claude-opus-4-1-20250805", TotalTokens: 1000, Cost: 4},
+	}
+
+	lines := strings.Split(m.renderModelsSection(entries), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[1], "1,000 tokens ( 25.0%)")
+	assert.Contains(t, lines[1], "$4.00")
+	assert.Contains(t, lines[2], "3,000 tokens ( 75.0%)")
+
+	assert.Equal(t, "⚙️  Models: none", m.renderModelsSection(nil))
+}
+
+func TestBlocksLiveTrendSection(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	series := calculator.MinuteSeries([]types.UsageEntry{
+		{Timestamp: now.Add(-30 * time.Minute), InputTokens: 600},
+		{Timestamp: now, InputTokens: 1200},
+	}, now.Add(-trendWindow+time.Minute), now)
+	m := &BlocksLiveModel{config: BlocksLiveConfig{NoColor: true}, width: 140}
+
+	trend := m.renderTrendSection(series)
+	assert.Contains(t, trend, "Tokens per minute over the last 60 min  Peak: 1,200 token/min")
+	assert.Contains(t, trend, "1.2k/min")
+
+	m.width = 80
+	assert.Contains(t, m.renderTrendSection(series), "Tokens per 2 minutes over the last 60 min")
+	assert.Empty(t, m.renderTrendSection(nil))
+}
+
+func TestBlocksLiveThresholdColor(t *testing.T) {
+	m := &BlocksLiveModel{config: BlocksLiveConfig{Thresholds: calculator.UsageThresholds{Warn: 70, Critical: 90}}}
+	assert.Equal(t, "green", m.thresholdColor(70))
+	assert.Equal(t, "yellow", m.thresholdColor(75))
+	assert.Equal(t, "red", m.thresholdColor(90.5))
+}
+
+func TestBlockBell(t *testing.T) {
+	start := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	block := &types.SessionBlock{StartTime: start}
+	bell := &blockBell{}
+
+	assert.False(t, bell.check(block, 50, 95), "starting on an active block")
+	assert.True(t, bell.check(block, 96, 95), "crossing the critical threshold")
+	assert.False(t, bell.check(block, 99, 95), "rings once per block")
+	assert.True(t, bell.check(nil, 0, 95), "the block ended")
+	assert.False(t, bell.check(nil, 0, 95))
+
+	next := &types.SessionBlock{StartTime: start.Add(6 * time.Hour)}
+	assert.False(t, bell.check(next, 10, 95))
+	assert.True(t, bell.check(&types.SessionBlock{StartTime: start.Add(12 * time.Hour)}, 10, 95), "replaced by a new block")
+}
+
+func TestBlocksLivePause(t *testing.T) {
+	m := &BlocksLiveModel{config: BlocksLiveConfig{Timezone: time.UTC, RefreshInterval: time.Second}}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	require.True(t, m.paused)
+	assert.Contains(t, m.View(), "Paused")
+	assert.Contains(t, m.View(), "Press p to resume")
+
+	// Ticks and file changes keep the frozen display without reloading
+	start := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	m.activeBlock = &types.SessionBlock{StartTime: start, EndTime: start.Add(5 * time.Hour)}
+	_, cmd := m.Update(blocksTickMsg(time.Now()))
+	assert.NotNil(t, cmd, "the tick loop keeps running")
+	assert.Contains(t, m.View(), "No active session block found")
+	assert.True(t, m.lastScan.IsZero())
+}
@@ -0,0 +1,220 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sdpower/ccusage-go/internal/calculator"
+	"github.com/sdpower/ccusage-go/internal/output"
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// liveView is what the live monitor shows, switched with its key
+type liveView int
+
+const (
+	viewBlock liveView = iota
+	viewToday
+	viewWeek
+	viewSession
+)
+
+// liveViews lists the views in the order of the footer
+var liveViews = []struct {
+	view  liveView
+	key   string
+	label string
+}{
+	{viewBlock, "b", "Block"},
+	{viewToday, "d", "Today"},
+	{viewWeek, "w", "Week"},
+	{viewSession, "s", "Session"},
+}
+
+// weekDays is the number of days of the week view, today included
+const weekDays = 7
+
+// sessionViewRows is the number of sessions the session view lists
+const sessionViewRows = 5
+
+// viewForKey returns the view switched to by key
+func viewForKey(key string) (liveView, bool) {
+	for _, v := range liveViews {
+		if v.key == key {
+			return v.view, true
+		}
+	}
+	return viewBlock, false
+}
+
+// viewKeys returns the keys of the views for the footer, the current one
+// in brackets
+func (m *BlocksLiveModel) viewKeys() string {
+	keys := make([]string, len(liveViews))
+	for i, v := range liveViews {
+		keys[i] = v.key + " " + v.label
+		if v.view == m.view {
+			keys[i] = "[" + keys[i] + "]"
+		}
+	}
+	return strings.Join(keys, "  ")
+}
+
+// setView switches to view. It returns true when the view needs entries
+// older than the ones loaded, which takes a reload.
+func (m *BlocksLiveModel) setView(view liveView) bool {
+	m.view = view
+	if view == viewWeek && !m.weekLoaded {
+		// Keep loading the week once it was asked for, so switching back is instant
+		m.weekLoaded = true
+		return m.config.Plan == ""
+	}
+	return false
+}
+
+// dataWindow returns how far back files are loaded: the last day, or the
+// week for weekly plan capacity and the week view
+func (m *BlocksLiveModel) dataWindow() time.Duration {
+	if m.config.Plan != "" || m.weekLoaded {
+		return calculator.PlanWeek
+	}
+	return 24 * time.Hour
+}
+
+// renderView renders the current view other than the block view
+func (m *BlocksLiveModel) renderView(now time.Time) string {
+	switch m.view {
+	case viewWeek:
+		return m.renderBox("CLAUDE CODE - LAST 7 DAYS", m.weekRows(now))
+	case viewSession:
+		return m.renderBox("CLAUDE CODE - RECENT SESSIONS", m.sessionRows())
+	default:
+		return m.renderBox("CLAUDE CODE - TODAY", m.todayRows(now))
+	}
+}
+
+// startOfDay returns the midnight starting the day of t in the monitor's timezone
+func (m *BlocksLiveModel) startOfDay(t time.Time) time.Time {
+	local := t.In(m.config.Timezone)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, m.config.Timezone)
+}
+
+// todayRows returns the totals of today, its tokens per hour and its models
+func (m *BlocksLiveModel) todayRows(now time.Time) []string {
+	midnight := m.startOfDay(now)
+	var entries []types.UsageEntry
+	for _, entry := range m.allEntries {
+		if !entry.Timestamp.Before(midnight) {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		return []string{fmt.Sprintf("📅 %s\nNo usage yet today", midnight.Format("Monday, Jan 02"))}
+	}
+
+	totals := calculator.Aggregate(entries, func(types.UsageEntry) string { return "today" })["today"]
+	hours := calculator.Aggregate(entries, calculator.HourKey(m.config.Timezone))
+	perHour := make([]int, now.In(m.config.Timezone).Hour()+1)
+	for i := range perHour {
+		if hour, ok := hours[fmt.Sprintf("%02d", i)]; ok {
+			perHour[i] = hour.TotalTokens
+		}
+	}
+	sparkline := output.Sparkline(perHour)
+	if !m.config.NoColor {
+		sparkline = lipgloss.NewStyle().Foreground(lipgloss.Color("39")).Render(sparkline)
+	}
+
+	summary := fmt.Sprintf("📅 %s\nRequests: %s  Tokens: %s  Cost: %s\nTokens per hour since midnight: %s",
+		midnight.Format("Monday, Jan 02"),
+		formatNumberWithCommas(totals.RequestCount),
+		formatNumberWithCommas(totals.TotalTokens),
+		output.FormatCost(totals.Cost),
+		sparkline)
+	return []string{summary, m.renderModelsSection(entries)}
+}
+
+// weekRows returns the tokens and cost of each of the last days, with a bar
+// relative to the busiest one, and the models of the week
+func (m *BlocksLiveModel) weekRows(now time.Time) []string {
+	first := m.startOfDay(now).AddDate(0, 0, -(weekDays - 1))
+	var entries []types.UsageEntry
+	for _, entry := range m.allEntries {
+		if !entry.Timestamp.Before(first) {
+			entries = append(entries, entry)
+		}
+	}
+	days := calculator.Aggregate(entries, calculator.DailyKey(m.config.Timezone))
+
+	peak := 0
+	for _, day := range days {
+		peak = max(peak, day.TotalTokens)
+	}
+	var b strings.Builder
+	totalTokens, totalCost := 0, 0.0
+	b.WriteString("📆 Daily usage")
+	for i := 0; i < weekDays; i++ {
+		date := first.AddDate(0, 0, i)
+		tokens, cost := 0, 0.0
+		if day, ok := days[date.Format("2006-01-02")]; ok {
+			tokens, cost = day.TotalTokens, day.Cost
+		}
+		totalTokens += tokens
+		totalCost += cost
+		percent := 0.0
+		if peak > 0 {
+			percent = float64(tokens) / float64(peak) * 100
+		}
+		fmt.Fprintf(&b, "\n   %s  %s  %12s tokens  %10s",
+			date.Format("Mon Jan 02"),
+			m.renderEnhancedProgressBar(percent, 30, "cyan"),
+			formatNumberWithCommas(tokens),
+			output.FormatCost(cost))
+	}
+	fmt.Fprintf(&b, "\n\nTotal: %s tokens  %s", formatNumberWithCommas(totalTokens), output.FormatCost(totalCost))
+	return []string{b.String(), m.renderModelsSection(entries)}
+}
+
+// sessionRows returns the most recently active sessions, with their
+// duration, requests, tokens and cost
+func (m *BlocksLiveModel) sessionRows() []string {
+	sessions := calculator.Aggregate(m.allEntries, calculator.ConversationKey())
+	if len(sessions) == 0 {
+		return []string{"💬 No sessions yet"}
+	}
+	ids := make([]string, 0, len(sessions))
+	for id := range sessions {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := sessions[ids[i]], sessions[ids[j]]
+		if !a.LastSeen.Equal(b.LastSeen) {
+			return a.LastSeen.After(b.LastSeen)
+		}
+		return ids[i] < ids[j]
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "💬 Sessions, most recently active first (%d of %d)", min(len(ids), sessionViewRows), len(ids))
+	for _, id := range ids[:min(len(ids), sessionViewRows)] {
+		session := sessions[id]
+		name := session.SessionName
+		if name == "" {
+			name = id
+		}
+		if runes := []rune(name); len(runes) > 24 {
+			name = string(runes[:23]) + "…"
+		}
+		fmt.Fprintf(&b, "\n   %-24s  %s  %7s  %5d req  %12s tokens  %10s",
+			name,
+			session.LastSeen.In(m.config.Timezone).Format("Jan 02 03:04 PM"),
+			formatDuration(session.LastSeen.Sub(session.FirstSeen)),
+			session.RequestCount,
+			formatNumberWithCommas(session.TotalTokens),
+			output.FormatCost(session.Cost))
+	}
+	return []string{b.String()}
+}
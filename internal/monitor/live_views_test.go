@@ -0,0 +1,59 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/calculator"
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLiveViewSwitching(t *testing.T) {
+	m := &BlocksLiveModel{}
+	assert.Equal(t, 24*time.Hour, m.dataWindow())
+
+	view, ok := viewForKey("d")
+	require.True(t, ok)
+	assert.False(t, m.setView(view), "today is in the loaded day")
+	assert.Contains(t, m.viewKeys(), "[d Today]")
+
+	assert.True(t, m.setView(viewWeek), "the week needs older files")
+	assert.Equal(t, calculator.PlanWeek, m.dataWindow())
+	assert.False(t, m.setView(viewWeek))
+
+	_, ok = viewForKey("x")
+	assert.False(t, ok)
+}
+
+func TestLiveViews(t *testing.T) {
+	now := time.Date(2025, 6, 4, 15, 30, 0, 0, time.UTC)
+	m := &BlocksLiveModel{
+		config: BlocksLiveConfig{Timezone: time.UTC, NoColor: true},
+		allEntries: []types.UsageEntry{
+			{Timestamp: now.AddDate(0, 0, -2), SessionID: "older", Model: "claude-sonnet-4-20250514", InputTokens: 4000, TotalTokens: 4000, Cost: 2},
+			{Timestamp: now.Add(-3 * time.Hour), SessionID: "current", SessionName: "Fix the parser", Model: "claude-opus-4-1-20250805", InputTokens: 1000, TotalTokens: 1000, Cost: 1.5},
+			{Timestamp: now.Add(-time.Hour), SessionID: "current", Model: "claude-opus-4-1-20250805", InputTokens: 2000, TotalTokens: 2000, Cost: 0.5},
+		},
+	}
+
+	today := strings.Join(m.todayRows(now), "\n")
+	assert.Contains(t, today, "Wednesday, Jun 04")
+	assert.Contains(t, today, "Requests: 2  Tokens: 3,000  Cost: $2.00")
+
+	week := strings.Join(m.weekRows(now), "\n")
+	assert.Contains(t, week, "Thu May 29")
+	assert.Contains(t, week, "Mon Jun 02")
+	assert.Contains(t, week, "Total: 7,000 tokens  $4.00")
+
+	sessions := strings.Join(m.sessionRows(), "\n")
+	assert.Contains(t, sessions, "(2 of 2)")
+	assert.Less(t, strings.Index(sessions, "Fix the parser"), strings.Index(sessions, "older"), "most recent first")
+	assert.Contains(t, sessions, "2h 0m")
+
+	m.allEntries = nil
+	assert.Contains(t, strings.Join(m.todayRows(now), "\n"), "No usage yet today")
+	assert.Equal(t, []string{"💬 No sessions yet"}, m.sessionRows())
+}
@@ -3,14 +3,15 @@ package monitor
 import (
 	"context"
 	"fmt"
+	"os"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/sdpower/ccusage-go/internal/calculator"
 	"github.com/sdpower/ccusage-go/internal/loader"
-	"github.com/sdpower/ccusage-go/internal/pricing"
 	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/sdpower/ccusage-go/pkg/pricing"
 )
 
 type Monitor struct {
@@ -22,6 +23,12 @@ type Options struct {
 	Interval   time.Duration
 	NoColor    bool
 	Continuous bool
+	Daemon     bool            // Write the status file instead of showing a display
+	StatusFile string          // Of the daemon ("" = DefaultStatusPath)
+	Oneline    bool            // Print a one line summary instead of showing a display
+	Once       bool            // Print the summary once and exit
+	TokenLimit int             // Of a block, for the one line summary (0 = the most tokens of a past block)
+	Pricing    pricing.Options // Of the service pricing usage
 }
 
 type model struct {
@@ -47,6 +54,12 @@ func New(opts Options) *Monitor {
 }
 
 func (m *Monitor) Start(ctx context.Context) error {
+	if m.options.Daemon {
+		return m.runDaemon(ctx)
+	}
+	if m.options.Oneline {
+		return m.runOneline(ctx, os.Stdout)
+	}
 	if m.options.Continuous {
 		return m.startTUI(ctx)
 	}
@@ -65,8 +78,9 @@ func (m *Monitor) startTUI(ctx context.Context) error {
 }
 
 func (m *Monitor) runOnce(ctx context.Context) error {
-	pricingService := pricing.NewService()
+	pricingService := pricing.New(m.options.Pricing)
 	calc := calculator.New(pricingService)
+	calc.WarmPricing(ctx)
 	dataLoader := loader.New()
 
 	entries, err := dataLoader.LoadFromPath(ctx, m.options.DataPath)
@@ -205,7 +219,7 @@ type updateDataMsg struct {
 
 func (m model) updateData() tea.Cmd {
 	return func() tea.Msg {
-		pricingService := pricing.NewService()
+		pricingService := pricing.New(m.options.Pricing)
 		calc := calculator.New(pricingService)
 		dataLoader := loader.New()
 
@@ -0,0 +1,92 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/sdpower/ccusage-go/internal/calculator"
+	"github.com/sdpower/ccusage-go/internal/loader"
+	"github.com/sdpower/ccusage-go/internal/output"
+	"github.com/sdpower/ccusage-go/pkg/pricing"
+)
+
+// runOneline prints a one line summary of the active block and today every
+// interval, overwriting the previous one in a terminal, until ctx is done;
+// with Once it prints a single line for status bars such as tmux's
+func (m *Monitor) runOneline(ctx context.Context, w io.Writer) error {
+	calc := calculator.New(pricing.New(m.options.Pricing))
+	calc.WarmPricing(ctx)
+	dataLoader := loader.New()
+	dataLoader.SetMaxWorkers(3) // Runs for hours next to the user's work
+	cache := loader.NewIncrementalCache()
+
+	tokenLimit := m.options.TokenLimit
+	if tokenLimit == 0 {
+		entries, err := dataLoader.LoadFromPath(ctx, m.options.DataPath)
+		if err != nil {
+			return fmt.Errorf("failed to load usage data: %w", err)
+		}
+		tokenLimit = calculator.GetMaxTokensFromBlocks(calc.IdentifySessionBlocks(entries, calculator.DefaultSessionDurationHours))
+	}
+
+	terminal := false
+	if f, ok := w.(*os.File); ok && !m.options.Once {
+		terminal = isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+	}
+	for {
+		// The last day covers the active block and today
+		entries, _, err := cache.Update(dataLoader, calc, m.options.DataPath, 24*time.Hour)
+		if err != nil {
+			if m.options.Once {
+				return fmt.Errorf("failed to load usage data: %w", err)
+			}
+			fmt.Fprintf(os.Stderr, "Warning: failed to load usage data: %v\n", err)
+		} else {
+			blocks := calc.IdentifySessionBlocks(entries, calculator.DefaultSessionDurationHours)
+			line := onelineSummary(newStatus(activeBlock(blocks), entries, time.Now(), time.Local), tokenLimit)
+			if terminal {
+				// Back to the start of the line and clear the previous summary
+				fmt.Fprintf(w, "\r\033[K%s", line)
+			} else {
+				fmt.Fprintln(w, line)
+			}
+		}
+		if m.options.Once {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if terminal {
+				fmt.Fprintln(w)
+			}
+			return nil
+		case <-time.After(m.options.Interval):
+		}
+	}
+}
+
+// onelineSummary returns the active block's share of tokenLimit (its tokens
+// when 0), time left and burn rate, and today's cost, in one line
+func onelineSummary(status Status, tokenLimit int) string {
+	var parts []string
+	if block := status.Block; block != nil {
+		if tokenLimit > 0 {
+			parts = append(parts, fmt.Sprintf("Block %.0f%%", float64(block.TotalTokens)/float64(tokenLimit)*100))
+		} else {
+			parts = append(parts, fmt.Sprintf("Block %s tok", formatTokensShort(block.TotalTokens)))
+		}
+		parts = append(parts,
+			formatDuration(time.Duration(block.RemainingMinutes*float64(time.Minute)))+" left",
+			formatTokensShort(int(block.TokensPerMinute))+" tok/min")
+	} else {
+		parts = append(parts, "No active block")
+	}
+	parts = append(parts, "Today "+output.FormatCost(status.Today.Cost))
+	return strings.Join(parts, " · ")
+}
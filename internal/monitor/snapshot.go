@@ -0,0 +1,83 @@
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Snapshot is the live monitor's state saved with its snapshot key
+type Snapshot struct {
+	Status
+	View       string `json:"view"`        // Block, Today, Week or Session
+	TokenLimit int    `json:"token_limit"` // Of a block (0 = none)
+	Paused     bool   `json:"paused"`      // The display was frozen
+	Display    string `json:"display"`     // The rendered view, without colors
+}
+
+// snapshotNoticeDuration is how long the footer reports a saved snapshot
+const snapshotNoticeDuration = 5 * time.Second
+
+// snapshotSavedMsg reports where a snapshot was written, or why it was not
+type snapshotSavedMsg struct {
+	path string
+	err  error
+}
+
+// snapshot returns the current state of the display
+func (m *BlocksLiveModel) snapshot() Snapshot {
+	now := m.now()
+	view := ""
+	for _, v := range liveViews {
+		if v.view == m.view {
+			view = v.label
+		}
+	}
+	return Snapshot{
+		Status:     newStatus(m.activeBlock, m.allEntries, now, m.config.Timezone),
+		View:       view,
+		TokenLimit: m.config.TokenLimit,
+		Paused:     m.paused,
+		Display:    ansi.Strip(m.View()),
+	}
+}
+
+// saveSnapshotCmd returns a command writing snapshot to dir ("" = the
+// working directory) as a JSON file and a text file of the display, both
+// named after its time
+func saveSnapshotCmd(snapshot Snapshot, dir string) tea.Cmd {
+	return func() tea.Msg {
+		path, err := writeSnapshot(snapshot, dir)
+		return snapshotSavedMsg{path: path, err: err}
+	}
+}
+
+// writeSnapshot writes snapshot to dir, returning the path of the JSON file
+func writeSnapshot(snapshot Snapshot, dir string) (string, error) {
+	if dir == "" {
+		dir = "."
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	base := filepath.Join(dir, "ccusage-snapshot-"+snapshot.UpdatedAt.Format("20060102-150405"))
+	if err := os.WriteFile(base+".json", append(data, '\n'), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	display := strings.TrimRight(snapshot.Display, "\n") + "\n"
+	if err := os.WriteFile(base+".txt", []byte(display), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return base + ".json", nil
+}
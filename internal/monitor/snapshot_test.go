@@ -0,0 +1,52 @@
+package monitor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotKey(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "snapshots")
+	now := time.Now()
+	m := &BlocksLiveModel{
+		config: BlocksLiveConfig{Timezone: time.UTC, TokenLimit: 10000, SnapshotDir: dir},
+		view:   viewToday,
+		allEntries: []types.UsageEntry{
+			{Timestamp: now, Model: "claude-sonnet-4-20250514", InputTokens: 1500, TotalTokens: 1500, Cost: 0.5},
+		},
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	require.NotNil(t, cmd)
+	msg := cmd()
+	saved, ok := msg.(snapshotSavedMsg)
+	require.True(t, ok)
+	require.NoError(t, saved.err)
+	m.Update(msg)
+	assert.Contains(t, m.View(), "Snapshot saved to "+saved.path, "the frozen display reports it")
+
+	data, err := os.ReadFile(saved.path)
+	require.NoError(t, err)
+	var snapshot Snapshot
+	require.NoError(t, json.Unmarshal(data, &snapshot))
+	assert.Equal(t, "Today", snapshot.View)
+	assert.True(t, snapshot.Paused)
+	assert.Equal(t, 10000, snapshot.TokenLimit)
+	assert.Equal(t, 1500, snapshot.Today.TotalTokens)
+	assert.Contains(t, snapshot.Display, "Requests: 1  Tokens: 1,500")
+	assert.NotContains(t, snapshot.Display, "\x1b[")
+
+	text, err := os.ReadFile(strings.TrimSuffix(saved.path, ".json") + ".txt")
+	require.NoError(t, err)
+	assert.Equal(t, strings.TrimRight(snapshot.Display, "\n")+"\n", string(text))
+}
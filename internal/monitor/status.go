@@ -0,0 +1,156 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/cachedir"
+	"github.com/sdpower/ccusage-go/internal/calculator"
+	"github.com/sdpower/ccusage-go/internal/loader"
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/sdpower/ccusage-go/pkg/pricing"
+)
+
+// Status is what the monitor daemon writes to its status file for status
+// bars, editors and other tools
+type Status struct {
+	UpdatedAt time.Time    `json:"updated_at"`
+	Block     *BlockStatus `json:"block"` // Active block, null when there is none
+	Today     DayStatus    `json:"today"`
+}
+
+// BlockStatus describes the active block
+type BlockStatus struct {
+	StartTime        time.Time `json:"start_time"`
+	EndTime          time.Time `json:"end_time"`
+	RemainingMinutes float64   `json:"remaining_minutes"`
+	Requests         int       `json:"requests"`
+	TotalTokens      int       `json:"total_tokens"`
+	Cost             float64   `json:"cost"`
+	TokensPerMinute  float64   `json:"tokens_per_minute"`
+	CostPerHour      float64   `json:"cost_per_hour"`
+	ProjectedTokens  int       `json:"projected_tokens"` // At the end of the block, at the current burn rate
+	ProjectedCost    float64   `json:"projected_cost"`
+	Models           []string  `json:"models"`
+}
+
+// DayStatus describes the usage of the current day
+type DayStatus struct {
+	Date        string  `json:"date"` // YYYY-MM-DD, local time
+	Requests    int     `json:"requests"`
+	TotalTokens int     `json:"total_tokens"`
+	Cost        float64 `json:"cost"`
+}
+
+// DefaultStatusPath returns the status file of the monitor daemon,
+// status.json in the cache directory
+func DefaultStatusPath() (string, error) {
+	return cachedir.Path("status.json")
+}
+
+// runDaemon writes the status file every interval, without a display, until
+// ctx is done
+func (m *Monitor) runDaemon(ctx context.Context) error {
+	statusPath := m.options.StatusFile
+	if statusPath == "" {
+		var err error
+		if statusPath, err = DefaultStatusPath(); err != nil {
+			return fmt.Errorf("cannot locate the status file: %w", err)
+		}
+	}
+
+	calc := calculator.New(pricing.New(m.options.Pricing))
+	calc.WarmPricing(ctx)
+	dataLoader := loader.New()
+	dataLoader.SetMaxWorkers(3) // Runs for hours next to the user's work
+	cache := loader.NewIncrementalCache()
+	for {
+		// The last day covers the active block and today
+		entries, _, err := cache.Update(dataLoader, calc, m.options.DataPath, 24*time.Hour)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load usage data: %v\n", err)
+		} else {
+			blocks := calc.IdentifySessionBlocks(entries, calculator.DefaultSessionDurationHours)
+			status := newStatus(activeBlock(blocks), entries, time.Now(), time.Local)
+			if err := writeStatus(statusPath, status); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(m.options.Interval):
+		}
+	}
+}
+
+// newStatus returns the status of block, the active one (nil = none), and
+// of the day of now in loc among entries
+func newStatus(block *types.SessionBlock, entries []types.UsageEntry, now time.Time, loc *time.Location) Status {
+	local := now.In(loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	status := Status{UpdatedAt: now, Today: DayStatus{Date: midnight.Format("2006-01-02")}}
+	for _, entry := range entries {
+		if !entry.Timestamp.Before(midnight) {
+			status.Today.Requests++
+			status.Today.TotalTokens += entry.TotalTokens
+			status.Today.Cost += entry.Cost
+		}
+	}
+	if block == nil {
+		return status
+	}
+
+	blockStatus := &BlockStatus{
+		StartTime:        block.StartTime,
+		EndTime:          block.EndTime,
+		RemainingMinutes: max(block.EndTime.Sub(now).Minutes(), 0),
+		Requests:         len(block.Entries),
+		TotalTokens:      block.TokenCounts.GetTotal(),
+		Cost:             block.CostUSD,
+		Models:           append([]string(nil), block.Models...),
+	}
+	sort.Strings(blockStatus.Models)
+	if burnRate := calculator.CalculateBurnRate(*block); burnRate != nil {
+		blockStatus.TokensPerMinute = burnRate.TokensPerMinute
+		blockStatus.CostPerHour = burnRate.CostPerHour
+	}
+	if projection := calculator.ProjectBlockUsage(*block); projection != nil {
+		blockStatus.ProjectedTokens = projection.TotalTokens
+		blockStatus.ProjectedCost = projection.TotalCost
+	}
+	status.Block = blockStatus
+	return status
+}
+
+// writeStatus replaces the status file with status
+func writeStatus(path string, status Status) error {
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create status directory: %w", err)
+	}
+
+	// Write to a temp file and rename so readers never see a partial status
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create status file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write status file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write status file: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
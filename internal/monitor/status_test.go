@@ -0,0 +1,70 @@
+package monitor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStatus(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	entries := []types.UsageEntry{
+		{Timestamp: now.Add(-13 * time.Hour), TotalTokens: 500, Cost: 5},
+		{Timestamp: now.Add(-90 * time.Minute), TotalTokens: 1000, Cost: 1},
+		{Timestamp: now.Add(-30 * time.Minute), TotalTokens: 2000, Cost: 2},
+	}
+
+	status := newStatus(nil, entries, now, time.UTC)
+	assert.Nil(t, status.Block)
+	assert.Equal(t, DayStatus{Date: "2025-06-01", Requests: 2, TotalTokens: 3000, Cost: 3}, status.Today)
+
+	block := &types.SessionBlock{
+		StartTime:   now.Add(-2 * time.Hour),
+		EndTime:     now.Add(3 * time.Hour),
+		IsActive:    true,
+		Entries:     entries[1:],
+		TokenCounts: types.TokenCounts{InputTokens: 3000},
+		CostUSD:     3,
+		Models:      []string{"claude-sonnet-4-5", "claude-opus-4-1"},
+	}
+	status = newStatus(block, entries, now, time.UTC)
+	require.NotNil(t, status.Block)
+	assert.Equal(t, 180.0, status.Block.RemainingMinutes)
+	assert.Equal(t, 2, status.Block.Requests)
+	assert.Equal(t, 3000, status.Block.TotalTokens)
+	assert.Equal(t, []string{"claude-opus-4-1", "claude-sonnet-4-5"}, status.Block.Models)
+}
+
+func TestWriteStatus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ccusage", "status.json")
+	status := Status{UpdatedAt: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), Today: DayStatus{Date: "2025-06-01", Cost: 1.5}}
+	require.NoError(t, writeStatus(path, status))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var read Status
+	require.NoError(t, json.Unmarshal(data, &read))
+	assert.Equal(t, status, read)
+	assert.Contains(t, string(data), `"block": null`)
+
+	matches, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*.tmp"))
+	assert.Empty(t, matches, "the temp file is renamed")
+}
+
+func TestOnelineSummary(t *testing.T) {
+	status := Status{
+		Block: &BlockStatus{TotalTokens: 620000, RemainingMinutes: 133.5, TokensPerMinute: 1234},
+		Today: DayStatus{Cost: 12.345},
+	}
+	assert.Equal(t, "Block 62% · 2h 13m left · 1.2k tok/min · Today $12.35", onelineSummary(status, 1000000))
+	assert.Equal(t, "Block 620.0k tok · 2h 13m left · 1.2k tok/min · Today $12.35", onelineSummary(status, 0))
+
+	status.Block = nil
+	assert.Equal(t, "No active block · Today $12.35", onelineSummary(status, 1000000))
+}
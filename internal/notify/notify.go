@@ -0,0 +1,80 @@
+// Package notify delivers usage alerts: as native desktop notifications,
+// through osascript on macOS, PowerShell toasts on Windows and notify-send
+// (libnotify) elsewhere, to webhooks, and as sounds.
+package notify
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// windowsAppID is the application toasts are shown for on Windows. Toasts
+// need a registered app; PowerShell's is present on every installation.
+const windowsAppID = `{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe`
+
+// windowsToast shows a toast with the title and message of the
+// environment, sparing them from PowerShell quoting
+const windowsToast = `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $template.GetElementsByTagName('text')
+$text.Item(0).AppendChild($template.CreateTextNode($env:CCUSAGE_NOTIFY_TITLE)) > $null
+$text.Item(1).AppendChild($template.CreateTextNode($env:CCUSAGE_NOTIFY_MESSAGE)) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier($env:CCUSAGE_NOTIFY_APP).Show([Windows.UI.Notifications.ToastNotification]::new($template))`
+
+// Available returns an error when the notification tool of the platform
+// cannot be found, so callers can fail before relying on Send
+func Available() error {
+	tool := toolFor(runtime.GOOS)
+	if _, err := exec.LookPath(tool); err != nil {
+		return fmt.Errorf("desktop notifications need %s: %w", tool, err)
+	}
+	return nil
+}
+
+// Send shows a notification of title and message
+func Send(title, message string) error {
+	cmd := commandFor(runtime.GOOS, title, message)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", cmd.Args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// toolFor returns the program notifications are shown with on goos
+func toolFor(goos string) string {
+	switch goos {
+	case "darwin":
+		return "osascript"
+	case "windows":
+		return "powershell"
+	default:
+		return "notify-send"
+	}
+}
+
+// commandFor returns the command showing a notification on goos
+func commandFor(goos, title, message string) *exec.Cmd {
+	tool := toolFor(goos)
+	switch goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))
+		return exec.Command(tool, "-e", script)
+	case "windows":
+		cmd := exec.Command(tool, "-NoProfile", "-NonInteractive", "-Command", windowsToast)
+		cmd.Env = append(os.Environ(),
+			"CCUSAGE_NOTIFY_TITLE="+title,
+			"CCUSAGE_NOTIFY_MESSAGE="+message,
+			"CCUSAGE_NOTIFY_APP="+windowsAppID)
+		return cmd
+	default:
+		return exec.Command(tool, "--app-name=ccusage", title, message)
+	}
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,35 @@
+package notify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandFor(t *testing.T) {
+	cmd := commandFor("darwin", `Block at "95%"`, `1,000 tokens \ limit`)
+	assert.Equal(t, []string{"osascript", "-e", `display notification "1,000 tokens \\ limit" with title "Block at \"95%\""`}, cmd.Args)
+
+	cmd = commandFor("linux", "Title", "Message")
+	assert.Equal(t, []string{"notify-send", "--app-name=ccusage", "Title", "Message"}, cmd.Args)
+	assert.Equal(t, "notify-send", toolFor("freebsd"))
+
+	cmd = commandFor("windows", "Title", "Message")
+	assert.Equal(t, "powershell", cmd.Args[0])
+	assert.Contains(t, cmd.Env, "CCUSAGE_NOTIFY_TITLE=Title")
+	assert.Contains(t, cmd.Env, "CCUSAGE_NOTIFY_MESSAGE=Message")
+}
+
+func TestSoundCommandFor(t *testing.T) {
+	assert.Equal(t, []string{"afplay", "/tmp/done.aiff"}, soundCommandFor("darwin", "afplay", "/tmp/done.aiff").Args)
+	assert.Equal(t, []string{"paplay", "/tmp/done.wav"}, soundCommandFor("linux", "paplay", "/tmp/done.wav").Args)
+	assert.Equal(t, []string{"paplay", "aplay"}, soundToolsFor("linux"))
+
+	cmd := soundCommandFor("windows", "powershell", `C:\done.wav`)
+	assert.Equal(t, "powershell", cmd.Args[0])
+	assert.Contains(t, cmd.Env, `CCUSAGE_SOUND=C:\done.wav`)
+}
+
+func TestSoundAvailableNeedsFile(t *testing.T) {
+	assert.ErrorContains(t, SoundAvailable(t.TempDir()+"/missing.wav"), "cannot read sound file")
+}
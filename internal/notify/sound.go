@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// windowsSound plays the file of the environment to its end
+const windowsSound = `(New-Object Media.SoundPlayer $env:CCUSAGE_SOUND).PlaySync()`
+
+// soundToolsFor returns the programs that can play a sound file on goos, in
+// order of preference
+func soundToolsFor(goos string) []string {
+	switch goos {
+	case "darwin":
+		return []string{"afplay"}
+	case "windows":
+		return []string{"powershell"}
+	default:
+		// PulseAudio and PipeWire, then ALSA
+		return []string{"paplay", "aplay"}
+	}
+}
+
+// soundTool returns the first sound player of the platform found
+func soundTool() (string, error) {
+	tools := soundToolsFor(runtime.GOOS)
+	for _, tool := range tools {
+		if _, err := exec.LookPath(tool); err == nil {
+			return tool, nil
+		}
+	}
+	return "", fmt.Errorf("playing sounds needs %s", strings.Join(tools, " or "))
+}
+
+// SoundAvailable returns an error when the sound file at path or a player
+// for it is missing, so callers can fail before relying on PlaySound
+func SoundAvailable(path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("cannot read sound file: %w", err)
+	}
+	_, err := soundTool()
+	return err
+}
+
+// PlaySound plays the sound file at path, returning when it has played
+func PlaySound(path string) error {
+	tool, err := soundTool()
+	if err != nil {
+		return err
+	}
+	cmd := soundCommandFor(runtime.GOOS, tool, path)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", cmd.Args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// soundCommandFor returns the command playing path with tool on goos
+func soundCommandFor(goos, tool, path string) *exec.Cmd {
+	if goos == "windows" {
+		cmd := exec.Command(tool, "-NoProfile", "-NonInteractive", "-Command", windowsSound)
+		cmd.Env = append(os.Environ(), "CCUSAGE_SOUND="+path)
+		return cmd
+	}
+	return exec.Command(tool, path)
+}
@@ -0,0 +1,91 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Alert is a usage alert sent to the desktop or a webhook
+type Alert struct {
+	Event     string    `json:"event"`     // What crossed its threshold, e.g. "block_usage"
+	Title     string    `json:"title"`     // One line summary
+	Message   string    `json:"message"`   // Details
+	Value     float64   `json:"value"`     // Current value of the metric
+	Threshold float64   `json:"threshold"` // That it crossed
+	Time      time.Time `json:"time"`
+}
+
+// webhookTimeout limits each POST of an alert
+const webhookTimeout = 10 * time.Second
+
+// transport is used by webhooks created afterwards (see SetTransport)
+var transport http.RoundTripper
+
+// SetTransport makes webhooks created afterwards post through t, e.g. the
+// proxy and CA bundle of the pricing fetch (nil restores the default)
+func SetTransport(t http.RoundTripper) {
+	transport = t
+}
+
+// Webhook posts alerts to a URL: Slack and Discord incoming webhooks get
+// their message formats, other URLs the Alert as JSON
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhook validates an http(s) webhook URL
+func NewWebhook(rawURL string) (*Webhook, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid webhook URL %q, use an http or https URL", rawURL)
+	}
+	return &Webhook{url: rawURL, client: &http.Client{Timeout: webhookTimeout, Transport: transport}}, nil
+}
+
+// Post sends alert to the webhook
+func (w *Webhook) Post(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(w.payload(alert))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post alert: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to post alert: %s responded %s", req.URL.Host, resp.Status)
+	}
+	return nil
+}
+
+// payload returns the body posted for alert, in the format of the
+// webhook's service
+func (w *Webhook) payload(alert Alert) any {
+	text := alert.Title + "\n" + alert.Message
+	switch {
+	case strings.Contains(w.url, "hooks.slack.com/"):
+		return map[string]string{"text": "*" + alert.Title + "*\n" + alert.Message}
+	case strings.Contains(w.url, "discord.com/api/webhooks/"), strings.Contains(w.url, "discordapp.com/api/webhooks/"):
+		return map[string]string{"content": "**" + alert.Title + "**\n" + alert.Message}
+	default:
+		return struct {
+			Alert
+			Text string `json:"text"` // Title and message, for chat services
+		}{alert, text}
+	}
+}
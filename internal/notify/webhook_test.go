@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookPost(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, &received))
+	}))
+	defer server.Close()
+
+	webhook, err := NewWebhook(server.URL + "/alerts")
+	require.NoError(t, err)
+	alert := Alert{Event: "daily_cost", Title: "Daily cost over $50.00", Message: "$52.10 today", Value: 52.1, Threshold: 50, Time: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)}
+	require.NoError(t, webhook.Post(context.Background(), alert))
+	assert.Equal(t, "daily_cost", received["event"])
+	assert.Equal(t, 52.1, received["value"])
+	assert.Equal(t, "Daily cost over $50.00\n$52.10 today", received["text"])
+}
+
+func TestWebhookPayloadFormats(t *testing.T) {
+	alert := Alert{Title: "Title", Message: "Message"}
+	slack, err := NewWebhook("https://hooks.slack.com/services/T0/B0/x")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"text": "*Title*\nMessage"}, slack.payload(alert))
+
+	discord, err := NewWebhook("https://discord.com/api/webhooks/1/x")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"content": "**Title**\nMessage"}, discord.payload(alert))
+}
+
+func TestWebhookErrors(t *testing.T) {
+	for _, rawURL := range []string{"", "hooks.slack.com/services/x", "ftp://example.com/hook"} {
+		_, err := NewWebhook(rawURL)
+		assert.Error(t, err, rawURL)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	webhook, err := NewWebhook(server.URL)
+	require.NoError(t, err)
+	assert.ErrorContains(t, webhook.Post(context.Background(), Alert{}), "403 Forbidden")
+}
@@ -0,0 +1,153 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sdpower/ccusage-go/internal/calculator"
+)
+
+// FormatAgentGroups renders per-agent totals (keyed by calculator.AgentKey),
+// splitting main-conversation usage from sub-agent (Task tool) usage
+func (f *TableWriterFormatter) FormatAgentGroups(agentGroups map[string]*calculator.GroupTotals) string {
+	// Main conversation first, then sub-agents
+	return f.formatDimensionGroups("By Agent", "Agent", agentGroups, func(a, b string) bool {
+		if (a == calculator.AgentMain) != (b == calculator.AgentMain) {
+			return a == calculator.AgentMain
+		}
+		return a < b
+	})
+}
+
+// FormatCwdGroups renders per-working-directory totals (keyed by
+// calculator.CwdKey), most expensive directory first
+func (f *TableWriterFormatter) FormatCwdGroups(cwdGroups map[string]*calculator.GroupTotals) string {
+	return f.formatDimensionGroups("By Directory", "Directory", cwdGroups, func(a, b string) bool {
+		if cwdGroups[a].Cost != cwdGroups[b].Cost {
+			return cwdGroups[a].Cost > cwdGroups[b].Cost
+		}
+		return a < b
+	})
+}
+
+// FormatModelFamilyGroups renders per-model-family totals (keyed by
+// calculator.ModelFamilyKey), most expensive family first
+func (f *TableWriterFormatter) FormatModelFamilyGroups(familyGroups map[string]*calculator.GroupTotals) string {
+	return f.formatDimensionGroups("By Model Family", "Family", familyGroups, func(a, b string) bool {
+		if familyGroups[a].Cost != familyGroups[b].Cost {
+			return familyGroups[a].Cost > familyGroups[b].Cost
+		}
+		return a < b
+	})
+}
+
+// FormatAccountGroups renders per-account totals (keyed by
+// calculator.AccountKey), most expensive account first
+func (f *TableWriterFormatter) FormatAccountGroups(accountGroups map[string]*calculator.GroupTotals) string {
+	return f.formatDimensionGroups("By Account", "Account", accountGroups, func(a, b string) bool {
+		if accountGroups[a].Cost != accountGroups[b].Cost {
+			return accountGroups[a].Cost > accountGroups[b].Cost
+		}
+		return a < b
+	})
+}
+
+// formatDimensionGroups renders one row per group of a --group-by dimension,
+// ordered by less, with each group's share of the total cost
+func (f *TableWriterFormatter) formatDimensionGroups(title, label string, groups map[string]*calculator.GroupTotals, less func(a, b string) bool) string {
+	if len(groups) == 0 {
+		return f.formatEmptyReport()
+	}
+
+	var output strings.Builder
+	output.WriteString("\n")
+	output.WriteString(" ╭────────────────────────────────────────────────────╮\n")
+	output.WriteString(" │                                                    │\n")
+	output.WriteString(fmt.Sprintf(" │  %-50s│\n", "Claude Code Token Usage Report - "+title))
+	output.WriteString(" │                                                    │\n")
+	output.WriteString(" ╰────────────────────────────────────────────────────╯\n\n")
+
+	var buf bytes.Buffer
+	table := f.newTable(&buf)
+	table.Header([]string{
+		label + "\n",
+		"Sessions\n",
+		"Models\n",
+		"Input\n",
+		"Output\n",
+		"Cache\nCreate",
+		"Cache\nRead",
+		"Cache\nHit %",
+		"Total\nTokens",
+		billedCostHeader(),
+		"Share\n(Cost)",
+	})
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return less(keys[i], keys[j]) })
+
+	total := calculator.NewGroupTotals()
+	for _, group := range groups {
+		total.Merge(group)
+	}
+
+	for _, key := range keys {
+		group := groups[key]
+
+		var models []string
+		seen := make(map[string]bool)
+		for model := range group.Models {
+			if short := ShortenModelName(model); !seen[short] {
+				seen[short] = true
+				models = append(models, short)
+			}
+		}
+		sort.Strings(models)
+		modelsStr := "-"
+		if len(models) > 0 {
+			modelsStr = "- " + strings.Join(models, "\n- ")
+		}
+
+		share := "-"
+		if total.Cost > 0 {
+			share = fmt.Sprintf("%.1f%%", group.Cost/total.Cost*100)
+		}
+
+		table.Append([]string{
+			key,
+			fmt.Sprintf("%d", len(group.SessionIDs)),
+			modelsStr,
+			f.formatLargeNumber(group.InputTokens),
+			f.formatLargeNumber(group.OutputTokens),
+			f.formatLargeNumber(group.CacheCreationTokens),
+			f.formatLargeNumber(group.CacheReadTokens),
+			f.formatCacheHitRate(group.InputTokens, group.CacheReadTokens, true),
+			f.formatLargeNumber(group.TotalTokens),
+			FormatBilledCost(group.Cost),
+			share,
+		})
+	}
+
+	table.Footer([]string{
+		"Total",
+		fmt.Sprintf("%d", len(total.SessionIDs)),
+		"",
+		f.formatLargeNumber(total.InputTokens),
+		f.formatLargeNumber(total.OutputTokens),
+		f.formatLargeNumber(total.CacheCreationTokens),
+		f.formatLargeNumber(total.CacheReadTokens),
+		f.formatCacheHitRate(total.InputTokens, total.CacheReadTokens, false),
+		f.formatLargeNumber(total.TotalTokens),
+		FormatBilledCost(total.Cost),
+		"",
+	})
+	table.Render()
+
+	output.WriteString(f.colorizeTotalsTable(buf.String()))
+	return output.String()
+}
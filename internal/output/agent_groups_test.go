@@ -0,0 +1,51 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sdpower/ccusage-go/internal/calculator"
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatAgentGroupsSplitsMainAndSubagents(t *testing.T) {
+	key := calculator.AgentKey()
+	groups := map[string]*calculator.GroupTotals{}
+	for _, entry := range []types.UsageEntry{
+		{SessionID: "s1", Model: "claude-sonnet-4-5-20250514", InputTokens: 100, OutputTokens: 10, Cost: 3},
+		{SessionID: "s1", Model: "claude-sonnet-4-5-20250514", InputTokens: 50, OutputTokens: 5, Cost: 1, IsSidechain: true},
+	} {
+		k := key(entry)
+		if groups[k] == nil {
+			groups[k] = calculator.NewGroupTotals()
+		}
+		groups[k].Add(entry)
+	}
+
+	output := NewTableWriterFormatter(true).FormatAgentGroups(groups)
+
+	assert.Contains(t, output, "By Agent")
+	mainRow := strings.Index(output, calculator.AgentMain)
+	subRow := strings.Index(output, calculator.AgentSubagent)
+	assert.True(t, mainRow >= 0 && subRow > mainRow, "main should be listed before sub-agents")
+	assert.Contains(t, output, "75.0%")
+	assert.Contains(t, output, "25.0%")
+}
+
+func TestFormatCwdGroupsOrdersByCost(t *testing.T) {
+	groups := calculator.Aggregate([]types.UsageEntry{
+		{SessionID: "s1", Cwd: "/src/cheap", InputTokens: 10, Cost: 1},
+		{SessionID: "s2", Cwd: "/src/costly", InputTokens: 10, Cost: 2},
+		{SessionID: "s3", InputTokens: 10, Cost: 1},
+	}, calculator.CwdKey())
+
+	output := NewTableWriterFormatter(true).FormatCwdGroups(groups)
+
+	assert.Contains(t, output, "By Directory")
+	costly := strings.Index(output, "/src/costly")
+	cheap := strings.Index(output, "/src/cheap")
+	unknown := strings.Index(output, calculator.CwdUnknown)
+	assert.True(t, costly >= 0 && cheap > costly && unknown > cheap, "directories should be listed by cost, then name")
+	assert.Contains(t, output, "50.0%")
+}
@@ -0,0 +1,85 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// commitSubjectWidth is the longest commit subject shown before truncating
+const commitSubjectWidth = 50
+
+// FormatCommitUsage renders the usage leading up to each commit (from
+// calculator.UsageByCommit), newest first
+func (f *TableWriterFormatter) FormatCommitUsage(commits []types.CommitUsage) string {
+	var output strings.Builder
+	output.WriteString("\n")
+	output.WriteString(" ╭────────────────────────────────────────────────────╮\n")
+	output.WriteString(" │                                                    │\n")
+	output.WriteString(fmt.Sprintf(" │  %-50s│\n", "Claude Code Token Usage Report - By Commit"))
+	output.WriteString(" │                                                    │\n")
+	output.WriteString(" ╰────────────────────────────────────────────────────╯\n\n")
+
+	if len(commits) == 0 {
+		output.WriteString("No commits found.\n")
+		return output.String()
+	}
+
+	var total types.CommitUsage
+	for _, commit := range commits {
+		total.Requests += commit.Requests
+		total.TotalTokens += commit.TotalTokens
+		total.Cost += commit.Cost
+	}
+
+	var buf bytes.Buffer
+	table := f.newTable(&buf)
+	table.Header([]string{
+		"Commit\n",
+		"Committed\n(localtime)",
+		"Subject\n",
+		"Requests\n",
+		"Total\nTokens",
+		billedCostHeader(),
+		"Share\n(Cost)",
+	})
+	for _, commit := range commits {
+		hash, committed, subject := "-", "-", "(uncommitted)"
+		if commit.Hash != "" {
+			hash = commit.Hash[:min(len(commit.Hash), 8)]
+			committed = commit.CommittedAt.In(f.timezone).Format("2006-01-02 15:04")
+			subject = commit.Subject
+			if runes := []rune(subject); len(runes) > commitSubjectWidth {
+				subject = string(runes[:commitSubjectWidth-1]) + "…"
+			}
+		}
+		share := "-"
+		if total.Cost > 0 {
+			share = fmt.Sprintf("%.1f%%", commit.Cost/total.Cost*100)
+		}
+		table.Append([]string{
+			hash,
+			committed,
+			subject,
+			formatNumberWithCommas(commit.Requests),
+			formatNumberWithCommas(commit.TotalTokens),
+			FormatBilledCost(commit.Cost),
+			share,
+		})
+	}
+	table.Footer([]string{
+		"Total",
+		"",
+		"",
+		formatNumberWithCommas(total.Requests),
+		formatNumberWithCommas(total.TotalTokens),
+		FormatBilledCost(total.Cost),
+		"",
+	})
+	table.Render()
+
+	output.WriteString(f.colorizeTotalsTable(buf.String()))
+	return output.String()
+}
@@ -0,0 +1,125 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// FormatConversationReport renders one row per conversation (from
+// calculator.GenerateConversationReport) with its request count and duration
+func (f *TableWriterFormatter) FormatConversationReport(conversations []types.SessionInfo) string {
+	if len(conversations) == 0 {
+		return f.formatEmptySessionReport()
+	}
+
+	var output strings.Builder
+	output.WriteString(" ╭──────────────────────────────────────────────────────────╮\n")
+	output.WriteString(" │                                                          │\n")
+	output.WriteString(fmt.Sprintf(" │  %-56s│\n", "Claude Code Token Usage Report - By Conversation"))
+	output.WriteString(" │                                                          │\n")
+	output.WriteString(" ╰──────────────────────────────────────────────────────────╯\n\n")
+
+	var buf bytes.Buffer
+	table := f.newTable(&buf)
+	table.Header([]string{
+		"Conversation\n",
+		"Project\n",
+		"Models\n",
+		"Requests\n",
+		"Duration\n",
+		"Input\n",
+		"Output\n",
+		"Cache\nCreate",
+		"Cache\nRead",
+		"Total\nTokens",
+		"Peak\nContext",
+		billedCostHeader(),
+		"Last Activity\n(localtime)",
+	})
+
+	var totalRequests, totalInput, totalOutput, totalCache, totalCacheRead, totalTokens int
+	var totalCost, totalSavings float64
+
+	for _, conversation := range conversations {
+		name := conversation.SessionID
+		if conversation.SessionName != "" {
+			name = conversation.SessionName + "\n(" + conversation.SessionID + ")"
+		}
+
+		var models []string
+		seen := make(map[string]bool)
+		for _, model := range conversation.ModelsUsed {
+			if short := ShortenModelName(model); !seen[short] {
+				seen[short] = true
+				models = append(models, short)
+			}
+		}
+		sort.Strings(models)
+		modelsStr := "-"
+		if len(models) > 0 {
+			modelsStr = "- " + strings.Join(models, "\n- ")
+		}
+
+		totalRequests += conversation.RequestCount
+		totalInput += conversation.InputTokens
+		totalOutput += conversation.OutputTokens
+		totalCache += conversation.CacheCreationTokens
+		totalCacheRead += conversation.CacheReadTokens
+		totalTokens += conversation.TotalTokens
+		totalCost += conversation.TotalCost
+		totalSavings += conversation.CacheSavings
+
+		table.Append([]string{
+			name,
+			f.extractSessionDisplayName(conversation.ProjectPath, conversation.ProjectPath),
+			modelsStr,
+			formatNumberWithCommas(conversation.RequestCount),
+			formatConversationDuration(conversation.Duration),
+			f.formatLargeNumber(conversation.InputTokens),
+			f.formatLargeNumber(conversation.OutputTokens),
+			f.formatLargeNumber(conversation.CacheCreationTokens),
+			f.formatLargeNumber(conversation.CacheReadTokens),
+			f.formatLargeNumber(conversation.TotalTokens),
+			f.formatContextUtilization(conversation),
+			FormatBilledCost(conversation.TotalCost),
+			conversation.LastActivity.In(f.timezone).Format("2006-01-02 15:04"),
+		})
+	}
+
+	table.Footer([]string{
+		"Total",
+		"",
+		"",
+		formatNumberWithCommas(totalRequests),
+		"",
+		f.formatLargeNumber(totalInput),
+		f.formatLargeNumber(totalOutput),
+		f.formatLargeNumber(totalCache),
+		f.formatLargeNumber(totalCacheRead),
+		f.formatLargeNumber(totalTokens),
+		"",
+		FormatBilledCost(totalCost),
+		"",
+	})
+	table.Render()
+
+	output.WriteString(f.colorizeTotalsTable(buf.String()))
+	output.WriteString(formatCacheSavings(totalSavings))
+	return output.String()
+}
+
+// formatConversationDuration formats the time from a conversation's first to
+// its last request, e.g. 1h05m or 12m
+func formatConversationDuration(d time.Duration) string {
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	if hours > 0 {
+		return fmt.Sprintf("%dh%02dm", hours, minutes)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
@@ -0,0 +1,81 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// FormatCostDiscrepancies renders per-model recorded (costUSD) and computed
+// costs, with the models that could not be priced marked
+func (f *TableWriterFormatter) FormatCostDiscrepancies(report types.CostDiscrepancyReport) string {
+	var output strings.Builder
+	output.WriteString("\n")
+	output.WriteString(" ╭────────────────────────────────────────────────────╮\n")
+	output.WriteString(" │                                                    │\n")
+	output.WriteString(fmt.Sprintf(" │  %-50s│\n", "Recorded vs Computed Cost"))
+	output.WriteString(" │                                                    │\n")
+	output.WriteString(" ╰────────────────────────────────────────────────────╯\n\n")
+
+	if len(report.Models) == 0 {
+		output.WriteString("No requests with a logged cost (costUSD) found.\n")
+		return output.String()
+	}
+
+	var buf bytes.Buffer
+	table := f.newTable(&buf)
+	table.Header([]string{
+		"Model\n",
+		"Requests\n",
+		"Recorded\n(USD)",
+		"Computed\n(USD)",
+		"Difference\n(USD)",
+		"Difference\n(%)",
+	})
+
+	unpriced := false
+	for _, model := range report.Models {
+		name := ShortenModelName(model.Model)
+		if model.Unpriced {
+			name += " *"
+			unpriced = true
+		}
+		table.Append([]string{
+			name,
+			formatNumberWithCommas(model.Requests),
+			fmt.Sprintf("$%.4f", model.RecordedCost),
+			fmt.Sprintf("$%.4f", model.ComputedCost),
+			fmt.Sprintf("%+.4f", model.Difference),
+			formatDifferenceShare(model.Difference, model.RecordedCost),
+		})
+	}
+
+	table.Footer([]string{
+		"Total",
+		formatNumberWithCommas(report.Requests),
+		fmt.Sprintf("$%.4f", report.RecordedCost),
+		fmt.Sprintf("$%.4f", report.ComputedCost),
+		fmt.Sprintf("%+.4f", report.Difference),
+		formatDifferenceShare(report.Difference, report.RecordedCost),
+	})
+	table.Render()
+
+	output.WriteString(f.colorizeTotalsTable(buf.String()))
+	if unpriced {
+		output.WriteString("\n * No pricing found for the model; its computed cost is $0\n")
+	}
+	if report.UnrecordedRequests > 0 {
+		output.WriteString(fmt.Sprintf("\n %s requests without a logged cost were not compared\n", formatNumberWithCommas(report.UnrecordedRequests)))
+	}
+	return output.String()
+}
+
+// formatDifferenceShare formats a difference relative to the recorded cost
+func formatDifferenceShare(difference, recorded float64) string {
+	if recorded == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%+.1f%%", difference/recorded*100)
+}
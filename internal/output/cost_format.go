@@ -0,0 +1,179 @@
+package output
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// RoundingMode selects how costs are rounded to the displayed decimals
+type RoundingMode string
+
+const (
+	RoundNearest RoundingMode = "nearest" // As fmt rounds, which the tables always used
+	RoundUp      RoundingMode = "up"      // Toward +Inf, so no nonzero cost shows as $0.00
+	RoundDown    RoundingMode = "down"    // Toward -Inf
+)
+
+// maxCostDecimals caps CostFormat.Decimals; token prices have no more digits
+const maxCostDecimals = 8
+
+// CostFormat controls how report tables display dollar amounts
+type CostFormat struct {
+	Decimals int
+	Rounding RoundingMode
+}
+
+// DefaultCostFormat shows cents, rounded to the nearest
+var DefaultCostFormat = CostFormat{Decimals: 2, Rounding: RoundNearest}
+
+var costFormat = DefaultCostFormat
+
+// NewCostFormat validates a number of decimals and rounding mode (""
+// means nearest)
+func NewCostFormat(decimals int, rounding string) (CostFormat, error) {
+	if decimals < 0 || decimals > maxCostDecimals {
+		return CostFormat{}, fmt.Errorf("decimals must be between 0 and %d, got %d", maxCostDecimals, decimals)
+	}
+	mode := RoundingMode(rounding)
+	switch mode {
+	case "":
+		mode = RoundNearest
+	case RoundNearest, RoundUp, RoundDown:
+	default:
+		return CostFormat{}, fmt.Errorf("invalid rounding %q, use nearest, up or down", rounding)
+	}
+	return CostFormat{Decimals: decimals, Rounding: mode}, nil
+}
+
+// SetCostFormat sets how FormatCost displays every cost of the process
+func SetCostFormat(format CostFormat) {
+	costFormat = format
+}
+
+// FormatCost formats a dollar amount with the configured decimals and
+// rounding, converted to the display currency (see SetCurrency)
+func FormatCost(cost float64) string {
+	return costFormat.display(cost)
+}
+
+// display formats a dollar amount converted to the display currency
+func (c CostFormat) display(cost float64) string {
+	if displayCurrency.Code == USD.Code {
+		return c.Format(cost)
+	}
+	return displayCurrency.Symbol + strings.TrimPrefix(c.Format(cost*displayCurrency.PerUSD), "$")
+}
+
+// Currency is what report tables display costs in
+type Currency struct {
+	Code   string  // ISO 4217 code, shown in column headers
+	Symbol string  // Prefix of amounts
+	PerUSD float64 // Units of the currency one dollar buys
+}
+
+// USD displays costs as they are calculated
+var USD = Currency{Code: "USD", Symbol: "$", PerUSD: 1}
+
+var displayCurrency = USD
+
+// currencySymbols prefix the amounts of common currencies; others show
+// their code, e.g. "CHF 12.34"
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+	"CNY": "¥",
+	"KRW": "₩",
+	"INR": "₹",
+}
+
+// NewCurrency returns the display currency code worth perUSD units per dollar
+func NewCurrency(code string, perUSD float64) Currency {
+	symbol, exists := currencySymbols[code]
+	if !exists {
+		symbol = code + " "
+	}
+	return Currency{Code: code, Symbol: symbol, PerUSD: perUSD}
+}
+
+// SetCurrency sets the currency FormatCost and the cost column headers of
+// every table use (--currency); JSON and CSV output stays in US dollars
+func SetCurrency(currency Currency) {
+	displayCurrency = currency
+}
+
+// Billing is how the usage in reports is paid for
+type Billing string
+
+const (
+	BillingAPI          Billing = "api"          // Per token, at API rates
+	BillingSubscription Billing = "subscription" // Included in a Claude Pro or Max plan
+)
+
+var billing = BillingAPI
+
+// ParseBilling validates a --billing value ("" means api)
+func ParseBilling(value string) (Billing, error) {
+	switch mode := Billing(value); mode {
+	case "":
+		return BillingAPI, nil
+	case BillingAPI, BillingSubscription:
+		return mode, nil
+	}
+	return "", fmt.Errorf("invalid --billing %q, use api or subscription", value)
+}
+
+// SetBilling sets how the cost columns of report tables and the JSON
+// reports present costs (--billing)
+func SetBilling(mode Billing) {
+	billing = mode
+}
+
+// FormatBilledCost formats the cost of usage: under subscription billing
+// as nothing actually paid over its API-equivalent cost, e.g.
+// "$0.00 / $12.34"
+func FormatBilledCost(cost float64) string {
+	if billing != BillingSubscription {
+		return FormatCost(cost)
+	}
+	return FormatCost(0) + " / " + FormatCost(cost)
+}
+
+// billedCostHeader returns the header of the column FormatBilledCost fills
+func billedCostHeader() string {
+	if billing != BillingSubscription {
+		return costHeader("Cost")
+	}
+	return "Cost\nincluded in plan\n(" + displayCurrency.Code + ", actual / API)"
+}
+
+// BilledCost returns what JSON reports add under "billing" for usage of
+// API-equivalent cost: nil unless under subscription billing
+func BilledCost(cost float64) *types.BilledCost {
+	if billing != BillingSubscription {
+		return nil
+	}
+	return &types.BilledCost{Billing: string(billing), ActualCost: 0, APIEquivalentCost: cost}
+}
+
+// costHeader returns the header of a cost column: title over the display currency code
+func costHeader(title string) string {
+	return title + "\n(" + displayCurrency.Code + ")"
+}
+
+// Format formats a dollar amount, e.g. "$0.0042"
+func (c CostFormat) Format(cost float64) string {
+	scale := math.Pow(10, float64(c.Decimals))
+	switch c.Rounding {
+	case RoundUp:
+		// Drop float noise first so exact amounts are not bumped up a unit
+		cost = math.Ceil(math.Round(cost*scale*1e6)/1e6) / scale
+	case RoundDown:
+		cost = math.Floor(math.Round(cost*scale*1e6)/1e6) / scale
+	}
+	return fmt.Sprintf("$%.*f", c.Decimals, cost)
+}
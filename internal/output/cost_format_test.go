@@ -0,0 +1,79 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCostFormat(t *testing.T) {
+	assert.Equal(t, "$0.00", DefaultCostFormat.Format(0.0042))
+	assert.Equal(t, "$1.23", DefaultCostFormat.Format(1.234))
+
+	precise, err := NewCostFormat(4, "")
+	require.NoError(t, err)
+	assert.Equal(t, RoundNearest, precise.Rounding)
+	assert.Equal(t, "$0.0042", precise.Format(0.0042))
+
+	up, err := NewCostFormat(2, "up")
+	require.NoError(t, err)
+	assert.Equal(t, "$0.01", up.Format(0.0042), "no nonzero cost shows as $0.00")
+	assert.Equal(t, "$0.30", up.Format(0.1+0.2), "float noise is not rounded up")
+	assert.Equal(t, "$0.00", up.Format(0))
+
+	down, err := NewCostFormat(1, "down")
+	require.NoError(t, err)
+	assert.Equal(t, "$1.2", down.Format(1.29))
+
+	_, err = NewCostFormat(2, "banker")
+	assert.Error(t, err)
+	_, err = NewCostFormat(-1, "")
+	assert.Error(t, err)
+
+	defer SetCostFormat(DefaultCostFormat)
+	SetCostFormat(precise)
+	assert.Equal(t, "$0.0042", FormatCost(0.0042))
+	assert.Contains(t, formatCacheSavings(0.0042), "$0.0042")
+}
+
+func TestFormatCostCurrency(t *testing.T) {
+	defer SetCurrency(USD)
+	SetCurrency(NewCurrency("EUR", 0.5))
+	assert.Equal(t, "€1.50", FormatCost(3))
+	assert.Equal(t, "Cost\n(EUR)", costHeader("Cost"))
+
+	SetCurrency(NewCurrency("CHF", 2))
+	assert.Equal(t, "CHF 6.00", FormatCost(3))
+
+	SetCurrency(USD)
+	assert.Equal(t, "$3.00", FormatCost(3))
+	assert.Equal(t, "Cost\n(USD)", costHeader("Cost"))
+}
+
+func TestSubscriptionBilling(t *testing.T) {
+	mode, err := ParseBilling("")
+	require.NoError(t, err)
+	assert.Equal(t, BillingAPI, mode)
+	_, err = ParseBilling("team")
+	assert.EqualError(t, err, `invalid --billing "team", use api or subscription`)
+
+	assert.Equal(t, "$12.34", FormatBilledCost(12.34))
+	assert.Equal(t, "Cost\n(USD)", billedCostHeader())
+	assert.Nil(t, BilledCost(12.34))
+
+	defer SetBilling(BillingAPI)
+	SetBilling(BillingSubscription)
+	assert.Equal(t, "$0.00 / $12.34", FormatBilledCost(12.34))
+	assert.Equal(t, "Cost\nincluded in plan\n(USD, actual / API)", billedCostHeader())
+
+	result, err := NewFormatter(FormatterOptions{Format: "json"}).FormatUsageReport(types.UsageReport{TotalCost: 12.34})
+	require.NoError(t, err)
+	assert.Contains(t, result, `"total_cost": 12.34`, "the API-equivalent cost stays in total_cost")
+	assert.Contains(t, result, `"billing": {
+    "billing": "subscription",
+    "actual_cost": 0,
+    "api_equivalent_cost": 12.34
+  }`)
+}
@@ -3,7 +3,7 @@ package output
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -21,6 +21,10 @@ type FormatterOptions struct {
 	NoColor    bool
 	Responsive bool
 	MaxWidth   int
+	TableStyle string // "unicode", "ascii", "markdown", "minimal", "borderless"
+
+	// ProjectNames overrides displayed project names (optional)
+	ProjectNames ProjectNamer
 }
 
 func NewFormatter(opts FormatterOptions) *Formatter {
@@ -33,6 +37,7 @@ func NewFormatter(opts FormatterOptions) *Formatter {
 func (f *Formatter) FormatUsageReport(report types.UsageReport) (string, error) {
 	switch f.options.Format {
 	case "json":
+		report.Billing = BilledCost(report.TotalCost)
 		return f.formatJSON(report)
 	case "csv":
 		return f.formatCSV(report.Entries)
@@ -44,12 +49,22 @@ func (f *Formatter) FormatUsageReport(report types.UsageReport) (string, error)
 func (f *Formatter) FormatSessionReport(sessions []types.SessionInfo) (string, error) {
 	switch f.options.Format {
 	case "json":
+		if billing == BillingSubscription {
+			billed := make([]types.SessionInfo, len(sessions))
+			for i, session := range sessions {
+				session.Billing = BilledCost(session.TotalCost)
+				billed[i] = session
+			}
+			sessions = billed
+		}
 		return f.formatJSON(sessions)
 	case "csv":
 		return f.formatSessionCSV(sessions)
 	default:
 		// Use tablewriter formatter for better consistency
 		tableFormatter := NewTableWriterFormatter(f.options.NoColor)
+		tableFormatter.SetTableStyle(f.options.TableStyle)
+		tableFormatter.SetProjectNamer(f.options.ProjectNames)
 		return tableFormatter.FormatSessionReport(sessions), nil
 	}
 }
@@ -119,13 +134,17 @@ func (f *Formatter) formatTable(report types.UsageReport) (string, error) {
 	}
 	
 	summary := fmt.Sprintf(
-		"Period: %s to %s\nTotal Requests: %d\nTotal Cost: $%.4f\nTotal Tokens: %s\nAverage Cost: $%.4f",
+		"Period: %s to %s\nTotal Requests: %d\nTotal Cost: $%.4f\nTotal Tokens: %s\nAverage Cost: $%.4f\n"+
+			"Average Tokens/Request: %s\nOutput:Input Ratio: %.2f\nCost per 1K Output Tokens: $%.4f",
 		report.StartTime.Format("2006-01-02"),
 		report.EndTime.Format("2006-01-02"),
 		report.Summary.TotalRequests,
 		report.Summary.TotalCost,
 		f.formatNumber(report.Summary.TotalTokens),
 		report.Summary.AverageCost,
+		f.formatNumber(int(math.Round(report.Summary.AverageTokensPerRequest))),
+		report.Summary.OutputInputRatio,
+		report.Summary.CostPer1KOutputTokens,
 	)
 	
 	output.WriteString(summaryStyle.Render(summary))
@@ -247,12 +266,12 @@ func (f *Formatter) formatCSV(entries []types.UsageEntry) (string, error) {
 
 func (f *Formatter) formatSessionCSV(sessions []types.SessionInfo) (string, error) {
 	var output strings.Builder
-	output.WriteString("session_id,session_name,session_ids,source_files,start_time,end_time,duration_seconds,total_cost,cache_create_cost,cache_read_cost,total_tokens,request_count,project_path\n")
+	output.WriteString("session_id,session_name,session_ids,source_files,start_time,end_time,duration_seconds,total_cost,cache_create_cost,cache_read_cost,total_tokens,request_count,project_path,peak_context_tokens,context_utilization\n")
 
 	for _, session := range sessions {
 		sessionIDs := strings.Join(session.SessionIDs, ";")
 		sourceFiles := strings.Join(session.SourceFiles, ";")
-		output.WriteString(fmt.Sprintf("%s,%s,%s,%s,%s,%s,%.0f,%.6f,%.6f,%.6f,%d,%d,%s\n",
+		output.WriteString(fmt.Sprintf("%s,%s,%s,%s,%s,%s,%.0f,%.6f,%.6f,%.6f,%d,%d,%s,%d,%.4f\n",
 			session.SessionID,
 			session.SessionName,
 			sessionIDs,
@@ -266,6 +285,8 @@ func (f *Formatter) formatSessionCSV(sessions []types.SessionInfo) (string, erro
 			session.TotalTokens,
 			session.RequestCount,
 			session.ProjectPath,
+			session.PeakContextTokens,
+			session.ContextUtilization,
 		))
 	}
 	
@@ -294,8 +315,11 @@ func (f *Formatter) getProjectName(path string) string {
 	if path == "" {
 		return "unknown"
 	}
+	if name, ok := configuredProjectName(f.options.ProjectNames, path); ok {
+		return name
+	}
 	
-	parts := strings.Split(path, string(os.PathSeparator))
+	parts := splitPath(path)
 	if len(parts) > 0 {
 		return parts[len(parts)-1]
 	}
@@ -0,0 +1,83 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// FormatModelRates renders the effective pricing of models (ccusage pricing
+// list), per million tokens with the per-token rate below
+func (f *TableWriterFormatter) FormatModelRates(rates []types.ModelRates) string {
+	var output strings.Builder
+	output.WriteString("\n")
+	output.WriteString(" ╭────────────────────────────────────────────────────╮\n")
+	output.WriteString(" │                                                    │\n")
+	output.WriteString(" │  Model Pricing (USD)                               │\n")
+	output.WriteString(" │                                                    │\n")
+	output.WriteString(" ╰────────────────────────────────────────────────────╯\n\n")
+
+	var buf bytes.Buffer
+	table := f.newTable(&buf)
+	table.Header([]string{
+		"Model\n",
+		"Source\n",
+		"Input\n",
+		"Output\n",
+		"Cache\nCreate",
+		"Cache\nRead",
+	})
+	for _, r := range rates {
+		table.Append(modelRatesRow(r.Model, r))
+		if r.LongContext != nil {
+			table.Append(modelRatesRow("  prompts >200K tokens", *r.LongContext))
+		}
+	}
+	table.Render()
+	output.WriteString(buf.String())
+	return output.String()
+}
+
+// modelRatesRow formats one table row of rates
+func modelRatesRow(label string, r types.ModelRates) []string {
+	return []string{label, r.Source,
+		formatRate(r.Input, r.InputPerMTok), formatRate(r.Output, r.OutputPerMTok),
+		formatRate(r.CacheCreate, r.CacheCreatePerMTok), formatRate(r.CacheRead, r.CacheReadPerMTok)}
+}
+
+// formatRate formats a price as "$3/MTok" over the per-token "$0.000003"
+func formatRate(perToken, perMTok float64) string {
+	if perToken == 0 {
+		return "-"
+	}
+	return FormatPerMTok(perMTok) + "\n$" + strconv.FormatFloat(perToken, 'f', -1, 64)
+}
+
+// FormatPerMTok formats a price in dollars per million tokens as "$3.75/MTok"
+func FormatPerMTok(perMTok float64) string {
+	return "$" + strconv.FormatFloat(perMTok, 'f', -1, 64) + "/MTok"
+}
+
+// FormatRatesUsed lists the rates each model was priced with, in dollars per
+// million tokens (cost-check --debug)
+func FormatRatesUsed(rates []types.ModelRates) string {
+	if len(rates) == 0 {
+		return ""
+	}
+	var output strings.Builder
+	output.WriteString("\n Rates used (USD per million tokens):\n")
+	for _, r := range rates {
+		output.WriteString(fmt.Sprintf("  %s (%s): input %s, output %s, cache create %s, cache read %s\n",
+			r.Model, r.Source, FormatPerMTok(r.InputPerMTok), FormatPerMTok(r.OutputPerMTok),
+			FormatPerMTok(r.CacheCreatePerMTok), FormatPerMTok(r.CacheReadPerMTok)))
+		if lc := r.LongContext; lc != nil {
+			output.WriteString(fmt.Sprintf("    prompts >200K tokens: input %s, output %s, cache create %s, cache read %s\n",
+				FormatPerMTok(lc.InputPerMTok), FormatPerMTok(lc.OutputPerMTok),
+				FormatPerMTok(lc.CacheCreatePerMTok), FormatPerMTok(lc.CacheReadPerMTok)))
+		}
+	}
+	return output.String()
+}
@@ -0,0 +1,37 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatRate(t *testing.T) {
+	assert.Equal(t, "$3.75/MTok\n$0.00000375", formatRate(0.00000375, 3.75))
+	assert.Equal(t, "$0.03/MTok\n$0.00000003", formatRate(0.00000003, 0.03))
+	assert.Equal(t, "-", formatRate(0, 0))
+}
+
+func TestFormatModelRates(t *testing.T) {
+	f := NewTableWriterFormatter(true)
+	out := f.FormatModelRates([]types.ModelRates{{
+		Model: "claude-sonnet-4-5-20250929", Source: "litellm",
+		Input: 0.000003, Output: 0.000015, InputPerMTok: 3, OutputPerMTok: 15,
+		LongContext: &types.ModelRates{Input: 0.000006, Output: 0.0000225, InputPerMTok: 6, OutputPerMTok: 22.5},
+	}})
+	assert.Contains(t, out, "claude-sonnet-4-5-20250929")
+	assert.Contains(t, out, "litellm")
+	assert.Contains(t, out, "prompts >200K tokens")
+	assert.Contains(t, out, "$22.5/MTok")
+}
+
+func TestFormatRatesUsed(t *testing.T) {
+	assert.Empty(t, FormatRatesUsed(nil))
+
+	out := FormatRatesUsed([]types.ModelRates{{
+		Model: "claude-haiku-4-5-20251001", Source: "embedded",
+		InputPerMTok: 1, OutputPerMTok: 5, CacheCreatePerMTok: 1.25, CacheReadPerMTok: 0.1,
+	}})
+	assert.Contains(t, out, "claude-haiku-4-5-20251001 (embedded): input $1/MTok, output $5/MTok, cache create $1.25/MTok, cache read $0.1/MTok")
+}
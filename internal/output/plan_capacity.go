@@ -0,0 +1,33 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// FormatPlanCapacity renders the estimated capacity left in the current
+// block and week of a subscription plan (from calculator.PlanCapacity)
+func FormatPlanCapacity(capacity types.PlanCapacity) string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("Plan Capacity (%s, estimated):\n", capacity.Plan))
+	output.WriteString(fmt.Sprintf("  Block Tokens:     %s left of %s (%s)\n",
+		formatNumberWithCommas(capacity.BlockTokensRemaining), formatNumberWithCommas(capacity.BlockTokenLimit),
+		formatRemainingShare(float64(capacity.BlockTokensRemaining), float64(capacity.BlockTokenLimit))))
+	output.WriteString(fmt.Sprintf("  Block Cost:       %s left of %s (%s)\n",
+		FormatCost(capacity.BlockCostRemaining), FormatCost(capacity.BlockCostLimit),
+		formatRemainingShare(capacity.BlockCostRemaining, capacity.BlockCostLimit)))
+	output.WriteString(fmt.Sprintf("  Week Cost:        %s left of %s (%s, last 7 days)\n",
+		FormatCost(capacity.WeekCostRemaining), FormatCost(capacity.WeekCostLimit),
+		formatRemainingShare(capacity.WeekCostRemaining, capacity.WeekCostLimit)))
+	return output.String()
+}
+
+// formatRemainingShare formats what is left as a percentage of limit
+func formatRemainingShare(remaining, limit float64) string {
+	if limit <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", remaining/limit*100)
+}
@@ -0,0 +1,21 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatPlanCapacity(t *testing.T) {
+	text := FormatPlanCapacity(types.PlanCapacity{
+		Plan:            "Max 5x",
+		BlockTokenLimit: 88_000, BlockTokensRemaining: 22_000,
+		BlockCostLimit: 35, BlockCostRemaining: 7,
+		WeekCostLimit: 350, WeekCostRemaining: 350,
+	})
+	assert.Contains(t, text, "Plan Capacity (Max 5x, estimated):")
+	assert.Contains(t, text, "22,000 left of 88,000 (25.0%)")
+	assert.Contains(t, text, "$7.00 left of $35.00 (20.0%)")
+	assert.Contains(t, text, "$350.00 left of $350.00 (100.0%, last 7 days)")
+}
@@ -0,0 +1,29 @@
+package output
+
+import "strings"
+
+// ProjectNamer supplies user-configured display names for project paths.
+// config.Config implements it.
+type ProjectNamer interface {
+	ProjectName(projectPath string) (string, bool)
+}
+
+// SetProjectNamer installs display name overrides consulted before the
+// built-in project name heuristics
+func (f *TableWriterFormatter) SetProjectNamer(namer ProjectNamer) {
+	f.projectNamer = namer
+}
+
+// configuredProjectName returns the override for projectPath, if any
+func configuredProjectName(namer ProjectNamer, projectPath string) (string, bool) {
+	if namer == nil {
+		return "", false
+	}
+	return namer.ProjectName(projectPath)
+}
+
+// splitPath splits a file path on both / and \, since logs copied from
+// Windows keep their backslashes whatever platform reads them
+func splitPath(path string) []string {
+	return strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '\\' })
+}
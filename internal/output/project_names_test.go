@@ -0,0 +1,43 @@
+package output
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubProjectNamer map[string]string
+
+func (s stubProjectNamer) ProjectName(projectPath string) (string, bool) {
+	name, ok := s[projectPath]
+	return name, ok
+}
+
+func TestSessionReportUsesConfiguredProjectName(t *testing.T) {
+	sessions := []types.SessionInfo{
+		{
+			SessionID:    "/path/projects/-Users-me-src-go_src-internal-tool",
+			ProjectPath:  "/path/projects/-Users-me-src-go_src-internal-tool",
+			StartTime:    time.Now().Add(-time.Hour),
+			EndTime:      time.Now(),
+			LastActivity: time.Now(),
+			InputTokens:  100, OutputTokens: 50, TotalTokens: 150,
+			ModelsUsed: []string{"claude-sonnet-4-5-20250514"},
+		},
+	}
+	namer := stubProjectNamer{sessions[0].ProjectPath: "Internal Tool"}
+
+	formatter := NewTableWriterFormatter(true)
+	formatter.SetProjectNamer(namer)
+	assert.Contains(t, formatter.FormatSessionReport(sessions), "Internal Tool")
+
+	plain := NewFormatter(FormatterOptions{Format: "table", NoColor: true, ProjectNames: namer})
+	output, err := plain.FormatSessionReport(sessions)
+	assert.NoError(t, err)
+	assert.Contains(t, output, "Internal Tool")
+
+	// Unmapped projects keep the heuristic name
+	assert.NotEqual(t, "Internal Tool", NewTableWriterFormatter(true).extractSessionDisplayName(sessions[0].SessionID, sessions[0].ProjectPath))
+}
@@ -0,0 +1,34 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// FormatReportSections renders the sections added by --plugin, one titled
+// table each
+func (f *TableWriterFormatter) FormatReportSections(sections []types.ReportSection) string {
+	var output strings.Builder
+	for _, section := range sections {
+		output.WriteString("\n")
+		output.WriteString(section.Title + ":\n")
+		if len(section.Rows) == 0 {
+			output.WriteString("No rows.\n")
+			continue
+		}
+
+		var buf bytes.Buffer
+		table := f.newTable(&buf)
+		table.Header(section.Columns)
+		for _, row := range section.Rows {
+			cells := make([]string, max(len(section.Columns), len(row)))
+			copy(cells, row)
+			table.Append(cells)
+		}
+		table.Render()
+		output.WriteString(buf.String())
+	}
+	return output.String()
+}
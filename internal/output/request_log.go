@@ -0,0 +1,48 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// requestLineFormat lays out one request per line so the requests command can
+// print them as they arrive, like a log
+const requestLineFormat = "%-19s  %-12s  %10s  %10s  %12s  %12s  %9s  %s"
+
+// FormatRequestHeader returns the column header for FormatRequestLine
+func (f *TableWriterFormatter) FormatRequestHeader() string {
+	header := fmt.Sprintf(requestLineFormat, "Time", "Model", "Input", "Output", "Cache Create", "Cache Read", "Cost", "Project")
+	if !f.noColor {
+		header = "\033[36m" + header + "\033[0m"
+	}
+	return header + "\n" + strings.Repeat("-", 110) + "\n"
+}
+
+// FormatRequestLine renders a single request with its tokens and cost
+func (f *TableWriterFormatter) FormatRequestLine(entry types.UsageEntry) string {
+	project := f.extractSessionDisplayName(entry.ProjectPath, entry.ProjectPath)
+	if entry.SessionName != "" {
+		project += " (" + entry.SessionName + ")"
+	}
+
+	cacheCreate, _ := entry.Raw["cache_creation_input_tokens"].(int)
+	cacheRead, _ := entry.Raw["cache_read_input_tokens"].(int)
+
+	cost := CostFormat{Decimals: 4, Rounding: RoundNearest}.display(entry.Cost)
+	if !f.noColor {
+		cost = fmt.Sprintf("\033[33m%9s\033[0m", cost)
+	}
+
+	return fmt.Sprintf(requestLineFormat,
+		entry.Timestamp.In(f.timezone).Format("2006-01-02 15:04:05"),
+		ShortenModelName(entry.Model),
+		formatNumberWithCommas(entry.InputTokens),
+		formatNumberWithCommas(entry.OutputTokens),
+		formatNumberWithCommas(cacheCreate),
+		formatNumberWithCommas(cacheRead),
+		cost,
+		project,
+	) + "\n"
+}
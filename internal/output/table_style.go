@@ -0,0 +1,125 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/renderer"
+	"github.com/olekukonko/tablewriter/tw"
+)
+
+// Table styles accepted by --table-style
+const (
+	TableStyleUnicode    = "unicode"
+	TableStyleASCII      = "ascii"
+	TableStyleMarkdown   = "markdown"
+	TableStyleMinimal    = "minimal"
+	TableStyleBorderless = "borderless"
+)
+
+// TableStyles lists all supported table styles in display order
+var TableStyles = []string{
+	TableStyleUnicode,
+	TableStyleASCII,
+	TableStyleMarkdown,
+	TableStyleMinimal,
+	TableStyleBorderless,
+}
+
+// ValidateTableStyle returns an error if style is not a supported table style
+func ValidateTableStyle(style string) error {
+	for _, s := range TableStyles {
+		if s == style {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid table style %q (valid: %s)", style, strings.Join(TableStyles, ", "))
+}
+
+// SetTableStyle selects the renderer used for tables (defaults to unicode)
+func (f *TableWriterFormatter) SetTableStyle(style string) {
+	if style != "" {
+		f.tableStyle = style
+	}
+}
+
+// colorEnabled reports whether ANSI coloring should be applied to rendered tables.
+// Coloring relies on the unicode box characters, so other styles stay plain.
+func (f *TableWriterFormatter) colorEnabled() bool {
+	return !f.noColor && (f.tableStyle == "" || f.tableStyle == TableStyleUnicode)
+}
+
+// styledTable wraps a tablewriter table and adapts cell content to the table style
+type styledTable struct {
+	*tablewriter.Table
+	flatten bool // join multi-line cells (markdown cannot represent line breaks)
+}
+
+func (t *styledTable) cells(row []string) []string {
+	if !t.flatten {
+		return row
+	}
+	flat := make([]string, len(row))
+	for i, cell := range row {
+		flat[i] = strings.Join(strings.Fields(strings.ReplaceAll(cell, "\n", " ")), " ")
+	}
+	return flat
+}
+
+func (t *styledTable) Header(row []string) { t.Table.Header(t.cells(row)) }
+func (t *styledTable) Footer(row []string) { t.Table.Footer(t.cells(row)) }
+func (t *styledTable) Append(row []string) { t.Table.Append(t.cells(row)) }
+func (t *styledTable) Render()             { t.Table.Render() }
+
+// newTable creates a table writing to w using the configured table style
+func (f *TableWriterFormatter) newTable(w io.Writer) *styledTable {
+	table := tablewriter.NewTable(w,
+		tablewriter.WithRenderer(f.tableRenderer()),
+		tablewriter.WithConfig(tablewriter.Config{
+			Row: tw.CellConfig{
+				Alignment: tw.CellAlignment{Global: tw.AlignRight},
+			},
+		}),
+		tablewriter.WithHeaderAutoFormat(tw.Off), // Disable auto uppercase
+	)
+	return &styledTable{Table: table, flatten: f.tableStyle == TableStyleMarkdown}
+}
+
+// tableRenderer maps the table style to a tablewriter renderer
+func (f *TableWriterFormatter) tableRenderer() tw.Renderer {
+	switch f.tableStyle {
+	case TableStyleASCII:
+		return renderer.NewBlueprint(tw.Rendition{
+			Symbols:  tw.NewSymbols(tw.StyleASCII),
+			Settings: tw.Settings{Separators: tw.Separators{BetweenRows: tw.On}},
+		})
+	case TableStyleMarkdown:
+		return renderer.NewMarkdown()
+	case TableStyleMinimal:
+		// Header underline only, no outer borders or column separators
+		return renderer.NewBlueprint(tw.Rendition{
+			Borders: tw.BorderNone,
+			Symbols: tw.NewSymbols(tw.StyleLight),
+			Settings: tw.Settings{
+				Lines:      tw.Lines{ShowHeaderLine: tw.On, ShowFooterLine: tw.On},
+				Separators: tw.Separators{BetweenRows: tw.Off, BetweenColumns: tw.Off},
+			},
+		})
+	case TableStyleBorderless:
+		// Plain aligned columns without any lines
+		return renderer.NewBlueprint(tw.Rendition{
+			Borders: tw.BorderNone,
+			Symbols: tw.NewSymbols(tw.StyleNone),
+			Settings: tw.Settings{
+				Lines:      tw.Lines{ShowHeaderLine: tw.Off, ShowFooterLine: tw.Off},
+				Separators: tw.Separators{BetweenRows: tw.Off, BetweenColumns: tw.Off},
+			},
+		})
+	default:
+		return renderer.NewBlueprint(tw.Rendition{
+			Settings: tw.Settings{Separators: tw.Separators{BetweenRows: tw.On}},
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func createStyleTestEntries() []types.UsageEntry {
+	ts := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	return []types.UsageEntry{
+		{Timestamp: ts, DateKey: "2025-03-01", Model: "claude-sonnet-4-5-20250929", SessionID: "s1", InputTokens: 100, OutputTokens: 50, TotalTokens: 150, Cost: 1.5},
+		{Timestamp: ts, DateKey: "2025-03-01", Model: "claude-opus-4-1-20250805", SessionID: "s1", InputTokens: 10, OutputTokens: 5, TotalTokens: 15, Cost: 0.5},
+	}
+}
+
+func TestValidateTableStyle(t *testing.T) {
+	for _, style := range TableStyles {
+		assert.NoError(t, ValidateTableStyle(style))
+	}
+	assert.Error(t, ValidateTableStyle("fancy"))
+}
+
+func TestTableStyleASCII(t *testing.T) {
+	formatter := NewTableWriterFormatter(false)
+	formatter.SetTableStyle(TableStyleASCII)
+	output := formatter.FormatDailyReport(createStyleTestEntries())
+
+	assert.Contains(t, output, "+-")
+	assert.NotContains(t, output, "┌")
+	assert.NotContains(t, output, "\033[", "non-unicode styles should not be colorized")
+}
+
+func TestTableStyleMarkdownFlattensCells(t *testing.T) {
+	formatter := NewTableWriterFormatter(true)
+	formatter.SetTableStyle(TableStyleMarkdown)
+	output := formatter.FormatDailyReport(createStyleTestEntries())
+
+	assert.Contains(t, output, "| Cache Create |")
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, "Opus-4.1") {
+			assert.Contains(t, line, "Sonnet-4.5", "models should be joined on one markdown row")
+		}
+	}
+}
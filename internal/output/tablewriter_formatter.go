@@ -3,30 +3,36 @@ package output
 import (
 	"bytes"
 	"fmt"
-	"os"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/olekukonko/tablewriter"
-	"github.com/olekukonko/tablewriter/renderer"
-	"github.com/olekukonko/tablewriter/tw"
 	"github.com/sdpower/ccusage-go/internal/calculator"
 	"github.com/sdpower/ccusage-go/internal/types"
 )
 
+// Cache hit rate color thresholds (percent)
+const (
+	CacheHitGoodPercent = 80.0
+	CacheHitWarnPercent = 50.0
+)
+
 // TableWriterFormatter uses tablewriter for better table formatting
 type TableWriterFormatter struct {
-	noColor  bool
-	timezone *time.Location
+	noColor      bool
+	timezone     *time.Location
+	tableStyle   string
+	projectNamer ProjectNamer
+	projection   calculator.ProjectionMethod
 }
 
 func NewTableWriterFormatter(noColor bool) *TableWriterFormatter {
 	return &TableWriterFormatter{
-		noColor:  noColor,
-		timezone: time.Local, // Default to local timezone
+		noColor:    noColor,
+		timezone:   time.Local, // Default to local timezone
+		tableStyle: TableStyleUnicode,
 	}
 }
 
@@ -47,6 +53,12 @@ func (f *TableWriterFormatter) SetTimezone(loc *time.Location) {
 	}
 }
 
+// SetProjectionMethod selects the burn rate of the blocks report's PROJECTED
+// row ("" means average)
+func (f *TableWriterFormatter) SetProjectionMethod(method calculator.ProjectionMethod) {
+	f.projection = method
+}
+
 func (f *TableWriterFormatter) FormatDailyReport(entries []types.UsageEntry) string {
 	return f.FormatDailyReportWithFilter(entries, "", "")
 }
@@ -56,9 +68,11 @@ func (f *TableWriterFormatter) FormatMonthlyReport(entries []types.UsageEntry) s
 }
 
 func (f *TableWriterFormatter) FormatDailyReportWithFilter(entries []types.UsageEntry, since, until string) string {
-	// Group entries by date
-	dailyGroups := f.groupByDate(entries)
-	
+	return f.FormatDailyGroups(calculator.Aggregate(entries, calculator.DailyKey(f.timezone)), since, until)
+}
+
+// FormatDailyGroups renders the daily table from per-date totals (keyed YYYY-MM-DD)
+func (f *TableWriterFormatter) FormatDailyGroups(dailyGroups map[string]*calculator.GroupTotals, since, until string) string {
 	if len(dailyGroups) == 0 {
 		return f.formatEmptyReport()
 	}
@@ -81,18 +95,7 @@ func (f *TableWriterFormatter) FormatDailyReportWithFilter(entries []types.Usage
 	// Create table buffer
 	var buf bytes.Buffer
 	
-	// Create table with tablewriter v1.0.9 API
-	table := tablewriter.NewTable(&buf,
-		tablewriter.WithRenderer(renderer.NewBlueprint(tw.Rendition{
-			Settings: tw.Settings{Separators: tw.Separators{BetweenRows: tw.On}},
-		})),
-		tablewriter.WithConfig(tablewriter.Config{
-			Row: tw.CellConfig{
-				Alignment: tw.CellAlignment{Global: tw.AlignRight},
-			},
-		}),
-		tablewriter.WithHeaderAutoFormat(tw.Off), // Disable auto uppercase
-	)
+	table := f.newTable(&buf)
 	
 	// Set headers with multi-line support
 	table.Header([]string{
@@ -102,12 +105,13 @@ func (f *TableWriterFormatter) FormatDailyReportWithFilter(entries []types.Usage
 		"Input\n",
 		"Output\n",
 		"Cache\nCreate",
-		"CC Cost\n(USD)",
+		costHeader("CC Cost"),
 		"Cache\nRead",
-		"CR Cost\n(USD)",
+		costHeader("CR Cost"),
+		"Cache\nHit %",
 		"Total\nTokens",
-		"API Cost\n(USD)",
-		"Cost\n(USD)",
+		costHeader("API Cost"),
+		billedCostHeader(),
 	})
 
 	// Sort dates
@@ -125,60 +129,19 @@ func (f *TableWriterFormatter) FormatDailyReportWithFilter(entries []types.Usage
 	}
 	sort.Strings(dates)
 
-	var totalInput, totalOutput, totalCache, totalCacheRead, totalTokens int
-	var totalCost, totalAPICost, totalCCCost, totalCRCost float64
-	totalSessionSet := make(map[string]bool)
+	total := calculator.NewGroupTotals()
 
 	// Process each date
 	for _, date := range dates {
 		group := dailyGroups[date]
 
-		// Calculate aggregates for this date
-		var input, outputTokens, cache, cacheRead, tokens int
-		var cost, apiCost, ccCost, crCost float64
-		models := make(map[string]bool)
-		sessionSet := make(map[string]bool)
-
-		for _, entry := range group {
-			input += entry.InputTokens
-			outputTokens += entry.OutputTokens
-			cost += entry.Cost
-			apiCost += entry.APICost
-			ccCost += entry.CacheCreateCost
-			crCost += entry.CacheReadCost
-
-			// Count unique sessions
-			if entry.SessionID != "" {
-				sessionSet[entry.SessionID] = true
-				totalSessionSet[entry.SessionID] = true
-			}
-
-			// Skip synthetic model in display (but still count its tokens/cost)
-			if entry.Model != "" && entry.Model != "<synthetic>" {
-				models[entry.Model] = true
-			}
-
-			// Get cache values from Raw
-			if cc, ok := entry.Raw["cache_creation_input_tokens"].(int); ok {
-				cache += cc
-			}
-			if cr, ok := entry.Raw["cache_read_input_tokens"].(int); ok {
-				cacheRead += cr
-			}
-		}
-
-		// Calculate total tokens including cache (matches TypeScript's getTotalTokens)
-		tokens = input + outputTokens + cache + cacheRead
-
-		totalInput += input
-		totalOutput += outputTokens
-		totalCache += cache
-		totalCacheRead += cacheRead
-		totalTokens += tokens
-		totalAPICost += apiCost
-		totalCCCost += ccCost
-		totalCRCost += crCost
-		totalCost += cost
+		// Aggregates for this date
+		input, outputTokens := group.InputTokens, group.OutputTokens
+		cache, cacheRead, tokens := group.CacheCreationTokens, group.CacheReadTokens, group.TotalTokens
+		cost, apiCost, ccCost, crCost := group.Cost, group.APICost, group.CacheCreateCost, group.CacheReadCost
+		models := group.Models
+		sessionSet := group.SessionIDs
+		total.Merge(group)
 
 		// Format models list
 		var modelList []string
@@ -219,119 +182,47 @@ func (f *TableWriterFormatter) FormatDailyReportWithFilter(entries []types.Usage
 			f.formatCostOrDash(ccCost),
 			f.formatLargeNumber(cacheRead),
 			f.formatCostOrDash(crCost),
+			f.formatCacheHitRate(input, cacheRead, true),
 			f.formatLargeNumber(tokens),
-			fmt.Sprintf("$%.2f", apiCost),
-			fmt.Sprintf("$%.2f", cost),
+			FormatCost(apiCost),
+			FormatBilledCost(cost),
 		})
 	}
 
 	// Set footer
 	table.Footer([]string{
 		"Total",
-		fmt.Sprintf("%d", len(totalSessionSet)),
+		fmt.Sprintf("%d", len(total.SessionIDs)),
 		"",
-		f.formatLargeNumber(totalInput),
-		f.formatLargeNumber(totalOutput),
-		f.formatLargeNumber(totalCache),
-		f.formatCostOrDash(totalCCCost),
-		f.formatLargeNumber(totalCacheRead),
-		f.formatCostOrDash(totalCRCost),
-		f.formatLargeNumber(totalTokens),
-		fmt.Sprintf("$%.2f", totalAPICost),
-		fmt.Sprintf("$%.2f", totalCost),
+		f.formatLargeNumber(total.InputTokens),
+		f.formatLargeNumber(total.OutputTokens),
+		f.formatLargeNumber(total.CacheCreationTokens),
+		f.formatCostOrDash(total.CacheCreateCost),
+		f.formatLargeNumber(total.CacheReadTokens),
+		f.formatCostOrDash(total.CacheReadCost),
+		f.formatCacheHitRate(total.InputTokens, total.CacheReadTokens, false),
+		f.formatLargeNumber(total.TotalTokens),
+		FormatCost(total.APICost),
+		FormatBilledCost(total.Cost),
 	})
 
 	// Render table
 	table.Render()
 
-	// Apply color styling if enabled
 	tableOutput := buf.String()
-	if !f.noColor {
-		// Apply colors to table elements
-		gray := "\033[90m"     // Gray color for borders
-		cyan := "\033[36m"     // Cyan color for headers
-		yellow := "\033[33m"   // Yellow color for Total row
-		reset := "\033[0m"     // Reset color
-
-		lines := strings.Split(tableOutput, "\n")
-		var coloredOutput strings.Builder
-
-		for i, line := range lines {
-			if line == "" {
-				coloredOutput.WriteString("\n")
-				continue
-			}
-
-			// Check if this is a pure border line (no data)
-			if strings.HasPrefix(line, "┌") || strings.HasPrefix(line, "├") || strings.HasPrefix(line, "└") {
-				// Pure border line - all gray
-				coloredOutput.WriteString(gray + line + reset)
-			} else if strings.Contains(line, "│") {
-				// Line with data and borders
-				parts := strings.Split(line, "│")
-				for j, part := range parts {
-					if j > 0 {
-						coloredOutput.WriteString(gray + "│" + reset)
-					}
-
-					// Check content type
-					if i <= 2 && strings.TrimSpace(part) != "" {
-						// Header rows - use cyan
-						coloredOutput.WriteString(cyan + part + reset)
-					} else if strings.Contains(part, "Total") || (strings.Contains(line, "Total") && strings.TrimSpace(part) != "") {
-						// Total row - use yellow for all content
-						coloredOutput.WriteString(yellow + part + reset)
-					} else {
-						// Regular data - use default color (white)
-						coloredOutput.WriteString(part)
-					}
-				}
-			} else {
-				// Other lines
-				coloredOutput.WriteString(line)
-			}
-
-			if i < len(lines)-1 {
-				coloredOutput.WriteString("\n")
-			}
-		}
-
-		output.WriteString(coloredOutput.String())
-	} else {
-		output.WriteString(tableOutput)
-	}
+	output.WriteString(f.colorizeTotalsTable(tableOutput))
+	output.WriteString(formatCacheSavings(total.CacheSavings))
+	output.WriteString(formatBatchSpend(total))
 
 	return output.String()
 }
 
-func (f *TableWriterFormatter) groupByDate(entries []types.UsageEntry) map[string][]types.UsageEntry {
-	groups := make(map[string][]types.UsageEntry)
-	
-	for _, entry := range entries {
-		// Skip invalid timestamps
-		if entry.Timestamp.IsZero() || entry.Timestamp.Year() < 2020 {
-			continue
-		}
-		
-		// Use pre-computed DateKey from loader (already converted to correct timezone)
-		// This matches TypeScript's approach where timezone conversion happens during data loading
-		dateKey := entry.DateKey
-		if dateKey == "" {
-			// Fallback to timezone conversion if DateKey not set (for compatibility)
-			timeInZone := entry.Timestamp.In(f.timezone)
-			dateKey = timeInZone.Format("2006-01-02")
-		}
-		
-		groups[dateKey] = append(groups[dateKey], entry)
-	}
-	
-	return groups
+func (f *TableWriterFormatter) FormatMonthlyReportWithFilter(entries []types.UsageEntry, since, until string) string {
+	return f.FormatMonthlyGroups(calculator.Aggregate(entries, calculator.MonthlyKey(f.timezone)), since, until)
 }
 
-func (f *TableWriterFormatter) FormatMonthlyReportWithFilter(entries []types.UsageEntry, since, until string) string {
-	// Group entries by month
-	monthlyGroups := f.groupByMonth(entries)
-	
+// FormatMonthlyGroups renders the monthly table from per-month totals (keyed YYYY-MM)
+func (f *TableWriterFormatter) FormatMonthlyGroups(monthlyGroups map[string]*calculator.GroupTotals, since, until string) string {
 	if len(monthlyGroups) == 0 {
 		return f.formatEmptyMonthlyReport()
 	}
@@ -348,18 +239,7 @@ func (f *TableWriterFormatter) FormatMonthlyReportWithFilter(entries []types.Usa
 	// Create table buffer
 	var buf bytes.Buffer
 	
-	// Create table with tablewriter v1.0.9 API
-	table := tablewriter.NewTable(&buf,
-		tablewriter.WithRenderer(renderer.NewBlueprint(tw.Rendition{
-			Settings: tw.Settings{Separators: tw.Separators{BetweenRows: tw.On}},
-		})),
-		tablewriter.WithConfig(tablewriter.Config{
-			Row: tw.CellConfig{
-				Alignment: tw.CellAlignment{Global: tw.AlignRight},
-			},
-		}),
-		tablewriter.WithHeaderAutoFormat(tw.Off), // Disable auto uppercase
-	)
+	table := f.newTable(&buf)
 	
 	// Set headers with multi-line support
 	table.Header([]string{
@@ -369,12 +249,13 @@ func (f *TableWriterFormatter) FormatMonthlyReportWithFilter(entries []types.Usa
 		"Input\n",
 		"Output\n",
 		"Cache\nCreate",
-		"CC Cost\n(USD)",
+		costHeader("CC Cost"),
 		"Cache\nRead",
-		"CR Cost\n(USD)",
+		costHeader("CR Cost"),
+		"Cache\nHit %",
 		"Total\nTokens",
-		"API Cost\n(USD)",
-		"Cost\n(USD)",
+		costHeader("API Cost"),
+		billedCostHeader(),
 	})
 
 	// Sort months
@@ -391,50 +272,19 @@ func (f *TableWriterFormatter) FormatMonthlyReportWithFilter(entries []types.Usa
 	}
 	sort.Strings(months)
 
-	var totalInput, totalOutput, totalCache, totalCacheRead, totalTokens int
-	var totalCost, totalAPICost, totalCCCost, totalCRCost float64
-	totalSessionSet := make(map[string]bool)
+	total := calculator.NewGroupTotals()
 
 	// Process each month
 	for _, month := range months {
-		monthEntries := monthlyGroups[month]
+		group := monthlyGroups[month]
 
 		// Aggregate data for this month
-		var monthInput, monthOutput, monthCache, monthCacheRead, monthTotalTokens int
-		var monthCost, monthAPICost, monthCCCost, monthCRCost float64
-		modelMap := make(map[string]bool)
-		sessionSet := make(map[string]bool)
-
-		for _, entry := range monthEntries {
-			monthInput += entry.InputTokens
-			monthOutput += entry.OutputTokens
-			monthCost += entry.Cost
-			monthAPICost += entry.APICost
-			monthCCCost += entry.CacheCreateCost
-			monthCRCost += entry.CacheReadCost
-			monthTotalTokens += entry.TotalTokens
-
-			// Count unique sessions
-			if entry.SessionID != "" {
-				sessionSet[entry.SessionID] = true
-				totalSessionSet[entry.SessionID] = true
-			}
-
-			// Track cache tokens from Raw data
-			if entry.Raw != nil {
-				if cc, ok := entry.Raw["cache_creation_input_tokens"].(int); ok {
-					monthCache += cc
-				}
-				if cr, ok := entry.Raw["cache_read_input_tokens"].(int); ok {
-					monthCacheRead += cr
-				}
-			}
-
-			// Skip synthetic model in display (but still count its tokens/cost)
-			if entry.Model != "" && entry.Model != "<synthetic>" {
-				modelMap[entry.Model] = true
-			}
-		}
+		monthInput, monthOutput := group.InputTokens, group.OutputTokens
+		monthCache, monthCacheRead, monthTotalTokens := group.CacheCreationTokens, group.CacheReadTokens, group.TotalTokens
+		monthCost, monthAPICost, monthCCCost, monthCRCost := group.Cost, group.APICost, group.CacheCreateCost, group.CacheReadCost
+		modelMap := group.Models
+		sessionSet := group.SessionIDs
+		total.Merge(group)
 
 		// Format models list (same logic as daily format)
 		simplifiedModels := make(map[string]bool)
@@ -450,17 +300,6 @@ func (f *TableWriterFormatter) FormatMonthlyReportWithFilter(entries []types.Usa
 		sort.Strings(models)
 		modelsStr := "- " + strings.Join(models, "\n- ")
 
-		// Add totals
-		totalInput += monthInput
-		totalOutput += monthOutput
-		totalCache += monthCache
-		totalCacheRead += monthCacheRead
-		totalTokens += monthTotalTokens
-		totalCost += monthCost
-		totalAPICost += monthAPICost
-		totalCCCost += monthCCCost
-		totalCRCost += monthCRCost
-
 		// Format month as YYYY-MM (keep original format for monthly)
 		formattedMonth := month
 
@@ -475,115 +314,96 @@ func (f *TableWriterFormatter) FormatMonthlyReportWithFilter(entries []types.Usa
 			f.formatCostOrDash(monthCCCost),
 			f.formatLargeNumber(monthCacheRead),
 			f.formatCostOrDash(monthCRCost),
+			f.formatCacheHitRate(monthInput, monthCacheRead, true),
 			f.formatLargeNumber(monthTotalTokens),
-			fmt.Sprintf("$%.2f", monthAPICost),
-			fmt.Sprintf("$%.2f", monthCost),
+			FormatCost(monthAPICost),
+			FormatBilledCost(monthCost),
 		})
 	}
 
 	// Set footer
 	table.Footer([]string{
 		"Total",
-		fmt.Sprintf("%d", len(totalSessionSet)),
+		fmt.Sprintf("%d", len(total.SessionIDs)),
 		"",
-		f.formatLargeNumber(totalInput),
-		f.formatLargeNumber(totalOutput),
-		f.formatLargeNumber(totalCache),
-		f.formatCostOrDash(totalCCCost),
-		f.formatLargeNumber(totalCacheRead),
-		f.formatCostOrDash(totalCRCost),
-		f.formatLargeNumber(totalTokens),
-		fmt.Sprintf("$%.2f", totalAPICost),
-		fmt.Sprintf("$%.2f", totalCost),
+		f.formatLargeNumber(total.InputTokens),
+		f.formatLargeNumber(total.OutputTokens),
+		f.formatLargeNumber(total.CacheCreationTokens),
+		f.formatCostOrDash(total.CacheCreateCost),
+		f.formatLargeNumber(total.CacheReadTokens),
+		f.formatCostOrDash(total.CacheReadCost),
+		f.formatCacheHitRate(total.InputTokens, total.CacheReadTokens, false),
+		f.formatLargeNumber(total.TotalTokens),
+		FormatCost(total.APICost),
+		FormatBilledCost(total.Cost),
 	})
 
 	// Render table
 	table.Render()
 	tableOutput := buf.String()
 
-	// Apply color styling if enabled (same as daily format)
-	if !f.noColor {
-		// Apply colors to table elements
-		gray := "\033[90m"     // Gray color for borders
-		cyan := "\033[36m"     // Cyan color for headers
-		yellow := "\033[33m"   // Yellow color for Total row
-		reset := "\033[0m"     // Reset color
-		
-		lines := strings.Split(tableOutput, "\n")
-		var coloredOutput strings.Builder
-		
-		for i, line := range lines {
-			if line == "" {
-				coloredOutput.WriteString("\n")
-				continue
-			}
-			
-			// Check if this is a pure border line (no data)
-			if strings.HasPrefix(line, "┌") || strings.HasPrefix(line, "├") || strings.HasPrefix(line, "└") {
-				// Pure border line - all gray
-				coloredOutput.WriteString(gray + line + reset)
-			} else if strings.Contains(line, "│") {
-				// Line with data and borders
-				parts := strings.Split(line, "│")
-				for j, part := range parts {
-					if j > 0 {
-						coloredOutput.WriteString(gray + "│" + reset)
-					}
-					
-					// Check content type
-					if i <= 2 && strings.TrimSpace(part) != "" {
-						// Header rows - use cyan
-						coloredOutput.WriteString(cyan + part + reset)
-					} else if strings.Contains(part, "Total") || (strings.Contains(line, "Total") && strings.TrimSpace(part) != "") {
-						// Total row - use yellow for all content
-						coloredOutput.WriteString(yellow + part + reset)
-					} else {
-						// Regular data - use default color (white)
-						coloredOutput.WriteString(part)
-					}
-				}
-			} else {
-				// Other lines
-				coloredOutput.WriteString(line)
-			}
-			
-			if i < len(lines)-1 {
-				coloredOutput.WriteString("\n")
-			}
-		}
-		
-		output.WriteString(coloredOutput.String())
-	} else {
-		output.WriteString(tableOutput)
-	}
+	output.WriteString(f.colorizeTotalsTable(tableOutput))
 	
 	return output.String()
 }
 
-func (f *TableWriterFormatter) groupByMonth(entries []types.UsageEntry) map[string][]types.UsageEntry {
-	groups := make(map[string][]types.UsageEntry)
-	
-	for _, entry := range entries {
-		// Skip invalid timestamps
-		if entry.Timestamp.IsZero() || entry.Timestamp.Year() < 2020 {
+// colorizeTotalsTable colors a rendered totals table when color is enabled:
+// gray borders, cyan headers and a yellow Total row
+func (f *TableWriterFormatter) colorizeTotalsTable(tableOutput string) string {
+	if !f.colorEnabled() {
+		return tableOutput
+	}
+
+	// Apply colors to table elements
+	gray := "\033[90m"     // Gray color for borders
+	cyan := "\033[36m"     // Cyan color for headers
+	yellow := "\033[33m"   // Yellow color for Total row
+	reset := "\033[0m"     // Reset color
+
+	lines := strings.Split(tableOutput, "\n")
+	var coloredOutput strings.Builder
+
+	for i, line := range lines {
+		if line == "" {
+			coloredOutput.WriteString("\n")
 			continue
 		}
-		
-		// Use pre-computed DateKey from loader (already converted to correct timezone)
-		// Extract month (YYYY-MM) from DateKey (YYYY-MM-DD)
-		monthKey := ""
-		if entry.DateKey != "" && len(entry.DateKey) >= 7 {
-			monthKey = entry.DateKey[:7] // Take first 7 characters: YYYY-MM
+
+		// Check if this is a pure border line (no data)
+		if strings.HasPrefix(line, "┌") || strings.HasPrefix(line, "├") || strings.HasPrefix(line, "└") {
+			// Pure border line - all gray
+			coloredOutput.WriteString(gray + line + reset)
+		} else if strings.Contains(line, "│") {
+			// Line with data and borders
+			parts := strings.Split(line, "│")
+			for j, part := range parts {
+				if j > 0 {
+					coloredOutput.WriteString(gray + "│" + reset)
+				}
+
+				// Check content type
+				if i <= 2 && strings.TrimSpace(part) != "" {
+					// Header rows - use cyan
+					coloredOutput.WriteString(cyan + part + reset)
+				} else if strings.Contains(part, "Total") || (strings.Contains(line, "Total") && strings.TrimSpace(part) != "") {
+					// Total row - use yellow for all content
+					coloredOutput.WriteString(yellow + part + reset)
+				} else {
+					// Regular data - use default color (white)
+					coloredOutput.WriteString(part)
+				}
+			}
 		} else {
-			// Fallback to timezone conversion if DateKey not set
-			timeInZone := entry.Timestamp.In(f.timezone)
-			monthKey = timeInZone.Format("2006-01")
+			// Other lines
+			coloredOutput.WriteString(line)
+		}
+
+		if i < len(lines)-1 {
+			coloredOutput.WriteString("\n")
 		}
-		
-		groups[monthKey] = append(groups[monthKey], entry)
 	}
-	
-	return groups
+
+	return coloredOutput.String()
 }
 
 func (f *TableWriterFormatter) formatEmptyMonthlyReport() string {
@@ -681,11 +501,78 @@ func ShortenModelName(model string) string {
 	return model
 }
 
+// formatCacheHitRate formats cache_read / (input + cache_read) as a percentage,
+// optionally colored by CacheHitGoodPercent / CacheHitWarnPercent thresholds
+func (f *TableWriterFormatter) formatCacheHitRate(inputTokens, cacheReadTokens int, colorize bool) string {
+	rate, ok := calculator.CacheHitRate(inputTokens, cacheReadTokens)
+	if !ok {
+		return "-"
+	}
+	text := fmt.Sprintf("%.1f%%", rate)
+	if !colorize || !f.colorEnabled() {
+		return text
+	}
+	switch {
+	case rate >= CacheHitGoodPercent:
+		return "\033[32m" + text + "\033[0m"
+	case rate >= CacheHitWarnPercent:
+		return "\033[33m" + text + "\033[0m"
+	default:
+		return "\033[31m" + text + "\033[0m"
+	}
+}
+
+// formatContextUtilization formats the largest share of its context window a
+// session's requests used, flagged in red once it reaches
+// calculator.ContextWarnThreshold
+func (f *TableWriterFormatter) formatContextUtilization(session types.SessionInfo) string {
+	if session.ContextUtilization == 0 {
+		return "-"
+	}
+	text := fmt.Sprintf("%.0f%%", session.ContextUtilization*100)
+	if !session.NearContextLimit {
+		return text
+	}
+	text = "⚠ " + text
+	if !f.colorEnabled() {
+		return text
+	}
+	return "\033[31m" + text + "\033[0m"
+}
+
+// formatBatchSpend returns the line splitting spend between Batch API and
+// interactive requests, or nothing without batch requests
+func formatBatchSpend(total *calculator.GroupTotals) string {
+	if total.BatchRequests == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n Batch API: %s requests, %s (%s of spend); interactive: %s requests, %s\n",
+		formatNumberWithCommas(total.BatchRequests), FormatCost(total.BatchCost), formatCostShare(total.BatchCost, total.Cost),
+		formatNumberWithCommas(total.RequestCount-total.BatchRequests), FormatCost(total.Cost-total.BatchCost))
+}
+
+// formatCostShare formats part as a percentage of total
+func formatCostShare(part, total float64) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", part/total*100)
+}
+
+// formatCacheSavings returns the line stating what prompt caching saved, or
+// nothing when there were no cache reads to save on
+func formatCacheSavings(savings float64) string {
+	if savings <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n Prompt caching saved an estimated %s (cache reads priced at the full input rate)\n", FormatCost(savings))
+}
+
 func (f *TableWriterFormatter) formatCostOrDash(cost float64) string {
 	if cost == 0 {
 		return "-"
 	}
-	return fmt.Sprintf("$%.2f", cost)
+	return FormatCost(cost)
 }
 
 func (f *TableWriterFormatter) formatLargeNumber(n int) string {
@@ -726,17 +613,7 @@ func (f *TableWriterFormatter) FormatSessionDetailReport(sessions []types.Sessio
 
 	var buf bytes.Buffer
 
-	table := tablewriter.NewTable(&buf,
-		tablewriter.WithRenderer(renderer.NewBlueprint(tw.Rendition{
-			Settings: tw.Settings{Separators: tw.Separators{BetweenRows: tw.On}},
-		})),
-		tablewriter.WithConfig(tablewriter.Config{
-			Row: tw.CellConfig{
-				Alignment: tw.CellAlignment{Global: tw.AlignRight},
-			},
-		}),
-		tablewriter.WithHeaderAutoFormat(tw.Off),
-	)
+	table := f.newTable(&buf)
 
 	table.Header([]string{
 		"Session\n",
@@ -745,12 +622,13 @@ func (f *TableWriterFormatter) FormatSessionDetailReport(sessions []types.Sessio
 		"Input\n",
 		"Output\n",
 		"Cache\nCreate",
-		"CC Cost\n(USD)",
+		costHeader("CC Cost"),
 		"Cache\nRead",
-		"CR Cost\n(USD)",
+		costHeader("CR Cost"),
+		"Cache\nHit %",
 		"Total\nTokens",
-		"API Cost\n(USD)",
-		"Cost\n(USD)",
+		costHeader("API Cost"),
+		billedCostHeader(),
 		"Last Activity\n(localtime)",
 	})
 
@@ -793,7 +671,7 @@ func (f *TableWriterFormatter) FormatSessionDetailReport(sessions []types.Sessio
 
 		// Build multiline cells: each line = one source file
 		var fileLines, modelLines, inputLines, outputLines []string
-		var cacheCreateLines, ccCostLines, cacheReadLines, crCostLines, totalTokenLines, apiCostLines, costLines, activityLines []string
+		var cacheCreateLines, ccCostLines, cacheReadLines, crCostLines, cacheHitLines, totalTokenLines, apiCostLines, costLines, activityLines []string
 
 		for _, fs := range sessionFileStats {
 			// Extract short file name from path
@@ -816,9 +694,10 @@ func (f *TableWriterFormatter) FormatSessionDetailReport(sessions []types.Sessio
 			ccCostLines = append(ccCostLines, f.formatCostOrDash(fs.CacheCreateCost))
 			cacheReadLines = append(cacheReadLines, f.formatLargeNumber(fs.CacheReadTokens))
 			crCostLines = append(crCostLines, f.formatCostOrDash(fs.CacheReadCost))
+			cacheHitLines = append(cacheHitLines, f.formatCacheHitRate(fs.InputTokens, fs.CacheReadTokens, true))
 			totalTokenLines = append(totalTokenLines, f.formatLargeNumber(fs.TotalTokens))
-			apiCostLines = append(apiCostLines, fmt.Sprintf("$%.2f", fs.APICost))
-			costLines = append(costLines, fmt.Sprintf("$%.2f", fs.Cost))
+			apiCostLines = append(apiCostLines, FormatCost(fs.APICost))
+			costLines = append(costLines, FormatBilledCost(fs.Cost))
 			activityLines = append(activityLines, fs.LastActivity.In(f.timezone).Format("2006-01-02 15:04"))
 
 			totalInput += fs.InputTokens
@@ -847,6 +726,7 @@ func (f *TableWriterFormatter) FormatSessionDetailReport(sessions []types.Sessio
 			strings.Join(ccCostLines, "\n"),
 			strings.Join(cacheReadLines, "\n"),
 			strings.Join(crCostLines, "\n"),
+			strings.Join(cacheHitLines, "\n"),
 			strings.Join(totalTokenLines, "\n"),
 			strings.Join(apiCostLines, "\n"),
 			strings.Join(costLines, "\n"),
@@ -864,16 +744,17 @@ func (f *TableWriterFormatter) FormatSessionDetailReport(sessions []types.Sessio
 		f.formatCostOrDash(totalCCCost),
 		f.formatLargeNumber(totalCacheRead),
 		f.formatCostOrDash(totalCRCost),
+		f.formatCacheHitRate(totalInput, totalCacheRead, false),
 		f.formatLargeNumber(totalTokens),
-		fmt.Sprintf("$%.2f", totalAPICost),
-		fmt.Sprintf("$%.2f", totalCost),
+		FormatCost(totalAPICost),
+		FormatBilledCost(totalCost),
 		"",
 	})
 
 	table.Render()
 
 	tableOutput := buf.String()
-	if !f.noColor {
+	if f.colorEnabled() {
 		gray := "\033[90m"
 		cyan := "\033[36m"
 		yellow := "\033[33m"
@@ -923,7 +804,7 @@ func (f *TableWriterFormatter) FormatSessionDetailReport(sessions []types.Sessio
 
 // extractShortFilePath extracts a short display name from a JSONL file path
 func extractShortFilePath(filePath string) string {
-	parts := strings.Split(filePath, string(os.PathSeparator))
+	parts := splitPath(filePath)
 
 	// Find "subagents" directory and show from there
 	for i, part := range parts {
@@ -960,18 +841,7 @@ func (f *TableWriterFormatter) FormatSessionReportWithFilter(sessions []types.Se
 	// Create table buffer
 	var buf bytes.Buffer
 	
-	// Create table with tablewriter v1.0.9 API
-	table := tablewriter.NewTable(&buf,
-		tablewriter.WithRenderer(renderer.NewBlueprint(tw.Rendition{
-			Settings: tw.Settings{Separators: tw.Separators{BetweenRows: tw.On}},
-		})),
-		tablewriter.WithConfig(tablewriter.Config{
-			Row: tw.CellConfig{
-				Alignment: tw.CellAlignment{Global: tw.AlignRight},
-			},
-		}),
-		tablewriter.WithHeaderAutoFormat(tw.Off), // Disable auto uppercase
-	)
+	table := f.newTable(&buf)
 	
 	// Set headers with multi-line support
 	table.Header([]string{
@@ -981,17 +851,19 @@ func (f *TableWriterFormatter) FormatSessionReportWithFilter(sessions []types.Se
 		"Input\n",
 		"Output\n",
 		"Cache\nCreate",
-		"CC Cost\n(USD)",
+		costHeader("CC Cost"),
 		"Cache\nRead",
-		"CR Cost\n(USD)",
+		costHeader("CR Cost"),
+		"Cache\nHit %",
 		"Total\nTokens",
-		"API Cost\n(USD)",
-		"Cost\n(USD)",
+		"Peak\nContext",
+		costHeader("API Cost"),
+		billedCostHeader(),
 		"Last Activity\n(localtime)",
 	})
 
 	var totalInput, totalOutput, totalCache, totalCacheRead, totalTokens int
-	var totalCost, totalAPICost, totalCCCost, totalCRCost float64
+	var totalCost, totalAPICost, totalCCCost, totalCRCost, totalSavings float64
 	totalFileSet := make(map[string]bool)
 
 	// Process each session
@@ -1048,6 +920,7 @@ func (f *TableWriterFormatter) FormatSessionReportWithFilter(sessions []types.Se
 		totalAPICost += session.TotalAPICost
 		totalCCCost += session.CacheCreateCost
 		totalCRCost += session.CacheReadCost
+		totalSavings += session.CacheSavings
 
 		// Add row to table
 		table.Append([]string{
@@ -1060,9 +933,11 @@ func (f *TableWriterFormatter) FormatSessionReportWithFilter(sessions []types.Se
 			f.formatCostOrDash(session.CacheCreateCost),
 			f.formatLargeNumber(session.CacheReadTokens),
 			f.formatCostOrDash(session.CacheReadCost),
+			f.formatCacheHitRate(session.InputTokens, session.CacheReadTokens, true),
 			f.formatLargeNumber(session.TotalTokens),
-			fmt.Sprintf("$%.2f", session.TotalAPICost),
-			fmt.Sprintf("$%.2f", session.TotalCost),
+			f.formatContextUtilization(session),
+			FormatCost(session.TotalAPICost),
+			FormatBilledCost(session.TotalCost),
 			lastActivity,
 		})
 	}
@@ -1078,71 +953,20 @@ func (f *TableWriterFormatter) FormatSessionReportWithFilter(sessions []types.Se
 		f.formatCostOrDash(totalCCCost),
 		f.formatLargeNumber(totalCacheRead),
 		f.formatCostOrDash(totalCRCost),
+		f.formatCacheHitRate(totalInput, totalCacheRead, false),
 		f.formatLargeNumber(totalTokens),
-		fmt.Sprintf("$%.2f", totalAPICost),
-		fmt.Sprintf("$%.2f", totalCost),
+		"",
+		FormatCost(totalAPICost),
+		FormatBilledCost(totalCost),
 		"",
 	})
 
 	// Render table
 	table.Render()
 
-	// Apply color styling if enabled
 	tableOutput := buf.String()
-	if !f.noColor {
-		// Apply colors to table elements (same as daily format)
-		gray := "\033[90m"     // Gray color for borders
-		cyan := "\033[36m"     // Cyan color for headers
-		yellow := "\033[33m"   // Yellow color for Total row
-		reset := "\033[0m"     // Reset color
-		
-		lines := strings.Split(tableOutput, "\n")
-		var coloredOutput strings.Builder
-		
-		for i, line := range lines {
-			if line == "" {
-				coloredOutput.WriteString("\n")
-				continue
-			}
-			
-			// Check if this is a pure border line (no data)
-			if strings.HasPrefix(line, "┌") || strings.HasPrefix(line, "├") || strings.HasPrefix(line, "└") {
-				// Pure border line - all gray
-				coloredOutput.WriteString(gray + line + reset)
-			} else if strings.Contains(line, "│") {
-				// Line with data and borders
-				parts := strings.Split(line, "│")
-				for j, part := range parts {
-					if j > 0 {
-						coloredOutput.WriteString(gray + "│" + reset)
-					}
-					
-					// Check content type
-					if i <= 2 && strings.TrimSpace(part) != "" {
-						// Header rows - use cyan
-						coloredOutput.WriteString(cyan + part + reset)
-					} else if strings.Contains(part, "Total") || (strings.Contains(line, "Total") && strings.TrimSpace(part) != "") {
-						// Total row - use yellow for all content
-						coloredOutput.WriteString(yellow + part + reset)
-					} else {
-						// Regular data - use default color (white)
-						coloredOutput.WriteString(part)
-					}
-				}
-			} else {
-				// Other lines
-				coloredOutput.WriteString(line)
-			}
-			
-			if i < len(lines)-1 {
-				coloredOutput.WriteString("\n")
-			}
-		}
-		
-		output.WriteString(coloredOutput.String())
-	} else {
-		output.WriteString(tableOutput)
-	}
+	output.WriteString(f.colorizeTotalsTable(tableOutput))
+	output.WriteString(formatCacheSavings(totalSavings))
 	
 	return output.String()
 }
@@ -1155,9 +979,13 @@ func (f *TableWriterFormatter) extractSessionDisplayName(sessionID, projectPath
 	if sessionID == "unknown" || sessionID == "" {
 		return "unknown"
 	}
+
+	if name, ok := configuredProjectName(f.projectNamer, projectPath); ok {
+		return name
+	}
 	
 	// First check if this is a path containing "projects" directory
-	parts := strings.Split(sessionID, string(os.PathSeparator))
+	parts := splitPath(sessionID)
 	
 	// Find the "projects" directory
 	projectName := ""
@@ -1170,7 +998,7 @@ func (f *TableWriterFormatter) extractSessionDisplayName(sessionID, projectPath
 	}
 	
 	// If no projects directory found, use the last part
-	if projectName == "" {
+	if projectName == "" && len(parts) > 0 {
 		projectName = parts[len(parts)-1]
 	}
 	
@@ -1336,18 +1164,7 @@ func (f *TableWriterFormatter) FormatBlocksReport(blocks []types.SessionBlock, t
 	// Create table buffer
 	var buf bytes.Buffer
 	
-	// Create table with tablewriter v1.0.9 API
-	table := tablewriter.NewTable(&buf,
-		tablewriter.WithRenderer(renderer.NewBlueprint(tw.Rendition{
-			Settings: tw.Settings{Separators: tw.Separators{BetweenRows: tw.On}},
-		})),
-		tablewriter.WithConfig(tablewriter.Config{
-			Row: tw.CellConfig{
-				Alignment: tw.CellAlignment{Global: tw.AlignRight},
-			},
-		}),
-		tablewriter.WithHeaderAutoFormat(tw.Off), // Disable auto uppercase
-	)
+	table := f.newTable(&buf)
 	
 	// Build headers dynamically
 	headers := []string{
@@ -1357,15 +1174,15 @@ func (f *TableWriterFormatter) FormatBlocksReport(blocks []types.SessionBlock, t
 		"Input",
 		"Output",
 		"Cache\nCreate",
-		"CC Cost\n(USD)",
+		costHeader("CC Cost"),
 		"Cache\nRead",
-		"CR Cost\n(USD)",
+		costHeader("CR Cost"),
 		"Total\nTokens",
 	}
 	if tokenLimit > 0 {
 		headers = append(headers, "%")
 	}
-	headers = append(headers, "API Cost\n(USD)", "Cost\n(USD)")
+	headers = append(headers, costHeader("API Cost"), billedCostHeader())
 	
 	table.Header(headers)
 	
@@ -1411,7 +1228,7 @@ func (f *TableWriterFormatter) FormatBlocksReport(blocks []types.SessionBlock, t
 			ccCostStr := f.formatCostOrDash(block.CacheCreateCostUSD)
 			crCostStr := f.formatCostOrDash(block.CacheReadCostUSD)
 			apiCostStr := f.formatCostOrDash(block.APICostUSD)
-			costStr := fmt.Sprintf("$%.2f", block.CostUSD)
+			costStr := FormatBilledCost(block.CostUSD)
 
 			// Build row
 			row := []string{timeStr, statusStr, modelsStr, inputStr, outputStr, cacheCreateStr, ccCostStr, cacheReadStr, crCostStr, totalTokensStr}
@@ -1451,9 +1268,13 @@ func (f *TableWriterFormatter) FormatBlocksReport(blocks []types.SessionBlock, t
 				}
 				
 				// PROJECTED row
-				if projection := calculator.ProjectBlockUsage(block); projection != nil {
+				if projection := calculator.ProjectBlockUsageWith(block, f.projection); projection != nil {
+					assumption := "(assuming current burn rate)"
+					if f.projection == calculator.ProjectionRecent {
+						assumption = "(assuming recent burn rate)"
+					}
 					projectedRow := []string{
-						"(assuming current burn rate)",
+						assumption,
 						"PROJECTED", // Will be colored yellow
 						"", "", "", "", "", "", "",
 						formatNumberWithCommas(projection.TotalTokens),
@@ -1464,7 +1285,7 @@ func (f *TableWriterFormatter) FormatBlocksReport(blocks []types.SessionBlock, t
 						projectedRow = append(projectedRow, fmt.Sprintf("%.1f%%", percentage))
 					}
 
-					projectedRow = append(projectedRow, "", fmt.Sprintf("$%.2f", projection.TotalCost))
+					projectedRow = append(projectedRow, "", FormatBilledCost(projection.TotalCost))
 					table.Append(projectedRow)
 				}
 			}
@@ -1476,7 +1297,7 @@ func (f *TableWriterFormatter) FormatBlocksReport(blocks []types.SessionBlock, t
 	tableOutput := buf.String()
 	
 	// Apply coloring if not disabled
-	if !f.noColor {
+	if f.colorEnabled() {
 		var coloredOutput strings.Builder
 		lines := strings.Split(tableOutput, "\n")
 		
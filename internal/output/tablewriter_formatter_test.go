@@ -2,6 +2,11 @@ package output
 
 import (
 	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/calculator"
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestShortenModelName(t *testing.T) {
@@ -45,4 +50,38 @@ func TestShortenModelName(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestFormatCacheHitRate(t *testing.T) {
+	f := NewTableWriterFormatter(true)
+
+	assert.Equal(t, "-", f.formatCacheHitRate(0, 0, true))
+	assert.Equal(t, "75.0%", f.formatCacheHitRate(100, 300, true))
+	assert.Equal(t, "0.0%", f.formatCacheHitRate(100, 0, true))
+
+	colored := NewTableWriterFormatter(false)
+	assert.Equal(t, "\033[32m90.0%\033[0m", colored.formatCacheHitRate(10, 90, true))
+	assert.Equal(t, "\033[33m60.0%\033[0m", colored.formatCacheHitRate(40, 60, true))
+	assert.Equal(t, "\033[31m10.0%\033[0m", colored.formatCacheHitRate(90, 10, true))
+	assert.Equal(t, "90.0%", colored.formatCacheHitRate(10, 90, false))
+}
+
+func TestDailyReportShowsCacheSavings(t *testing.T) {
+	f := NewTableWriterFormatter(true)
+	entries := []types.UsageEntry{
+		{Timestamp: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC), Model: "claude-sonnet-4-5-20250514", InputTokens: 10, Cost: 1, CacheSavings: 2.5},
+	}
+	assert.Contains(t, f.FormatDailyReport(entries), "Prompt caching saved an estimated $2.50")
+
+	entries[0].CacheSavings = 0
+	assert.NotContains(t, f.FormatDailyReport(entries), "Prompt caching saved")
+}
+
+func TestFormatBatchSpend(t *testing.T) {
+	total := calculator.NewGroupTotals()
+	total.Add(types.UsageEntry{Cost: 3})
+	assert.Empty(t, formatBatchSpend(total))
+
+	total.Add(types.UsageEntry{Cost: 1, ServiceTier: calculator.ServiceTierBatch})
+	assert.Contains(t, formatBatchSpend(total), "Batch API: 1 requests, $1.00 (25.0% of spend); interactive: 1 requests, $3.00")
+}
@@ -0,0 +1,230 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/sdpower/ccusage-go/internal/calculator"
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// sparkLevels are the bar heights of a sparkline, lowest first
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// histogramWidth is the width of the longest bar of a histogram column
+const histogramWidth = 30
+
+// Sparkline draws values as one bar character each, scaled to the largest
+// value; zero values get the lowest bar
+func Sparkline(values []int) string {
+	peak := 0
+	for _, value := range values {
+		if value > peak {
+			peak = value
+		}
+	}
+
+	var spark strings.Builder
+	for _, value := range values {
+		level := 0
+		if peak > 0 && value > 0 {
+			level = 1 + value*(len(sparkLevels)-2)/peak
+		}
+		spark.WriteRune(sparkLevels[level])
+	}
+	return spark.String()
+}
+
+// histogramBar draws value as a bar of up to histogramWidth blocks relative
+// to peak, padded to the full width so bars line up in right-aligned cells
+func histogramBar(value, peak int) string {
+	width := 0
+	if peak > 0 && value > 0 {
+		width = max(value*histogramWidth/peak, 1)
+	}
+	return strings.Repeat("█", width) + strings.Repeat(" ", histogramWidth-width)
+}
+
+// FormatHourOfDayProfile renders tokens and cost per hour of the day as a
+// histogram table, with a sparkline of the whole day above it
+func (f *TableWriterFormatter) FormatHourOfDayProfile(profile []types.HourUsage) string {
+	var output strings.Builder
+	output.WriteString("\n")
+	output.WriteString(" ╭────────────────────────────────────────────────────╮\n")
+	output.WriteString(" │                                                    │\n")
+	output.WriteString(fmt.Sprintf(" │  %-50s│\n", "Usage by Hour of Day"))
+	output.WriteString(" │                                                    │\n")
+	output.WriteString(" ╰────────────────────────────────────────────────────╯\n\n")
+
+	tokens := make([]int, len(profile))
+	total := types.HourUsage{}
+	peak := types.HourUsage{Hour: -1}
+	for i, hour := range profile {
+		tokens[i] = hour.TotalTokens
+		total.Requests += hour.Requests
+		total.TotalTokens += hour.TotalTokens
+		total.Cost += hour.Cost
+		if hour.TotalTokens > peak.TotalTokens {
+			peak = hour
+		}
+	}
+	if total.Requests == 0 {
+		output.WriteString("No usage data found.\n")
+		return output.String()
+	}
+
+	output.WriteString(fmt.Sprintf(" 00h %s 23h\n\n", Sparkline(tokens)))
+
+	var buf bytes.Buffer
+	table := f.newTable(&buf)
+	table.Header([]string{
+		"Hour\n",
+		"Requests\n",
+		"Total\nTokens",
+		costHeader("Cost"),
+		"Share\n",
+		"Tokens\n",
+	})
+	for _, hour := range profile {
+		table.Append([]string{
+			fmt.Sprintf("%02d:00", hour.Hour),
+			formatNumberWithCommas(hour.Requests),
+			formatNumberWithCommas(hour.TotalTokens),
+			FormatCost(hour.Cost),
+			formatShare(hour.TotalTokens, total.TotalTokens),
+			histogramBar(hour.TotalTokens, peak.TotalTokens),
+		})
+	}
+	table.Footer([]string{
+		"Total",
+		formatNumberWithCommas(total.Requests),
+		formatNumberWithCommas(total.TotalTokens),
+		FormatCost(total.Cost),
+		"100.0%",
+		"",
+	})
+	table.Render()
+
+	output.WriteString(f.colorizeTotalsTable(buf.String()))
+	if peak.Hour >= 0 {
+		output.WriteString(fmt.Sprintf("\n Busiest hour: %02d:00 (%s tokens, %s)\n",
+			peak.Hour, formatNumberWithCommas(peak.TotalTokens), FormatCost(peak.Cost)))
+	}
+	return output.String()
+}
+
+// formatShare formats part as a percentage of total
+func formatShare(part, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", float64(part)/float64(total)*100)
+}
+
+// FormatDayOfWeekProfile renders usage per day of the week as a histogram
+// table, followed by the weekday and weekend totals
+func (f *TableWriterFormatter) FormatDayOfWeekProfile(profile []types.DayOfWeekUsage) string {
+	var output strings.Builder
+	output.WriteString("\n")
+	output.WriteString(" ╭────────────────────────────────────────────────────╮\n")
+	output.WriteString(" │                                                    │\n")
+	output.WriteString(fmt.Sprintf(" │  %-50s│\n", "Usage by Day of Week"))
+	output.WriteString(" │                                                    │\n")
+	output.WriteString(" ╰────────────────────────────────────────────────────╯\n\n")
+
+	weekdays, weekend := calculator.SplitWeekend(profile)
+	total := weekdays.TotalTokens + weekend.TotalTokens
+	if weekdays.Requests+weekend.Requests == 0 {
+		output.WriteString("No usage data found.\n")
+		return output.String()
+	}
+	peak := 0
+	for _, day := range profile {
+		peak = max(peak, day.TotalTokens)
+	}
+
+	header := []string{
+		"Day\n",
+		"Active\nDays",
+		"Requests\n",
+		"Total\nTokens",
+		costHeader("Cost"),
+		"Cost per\nActive Day",
+		"Share\n",
+		"Tokens\n",
+	}
+	row := func(day types.DayOfWeekUsage, bar string) []string {
+		perDay := "-"
+		if day.ActiveDays > 0 {
+			perDay = FormatCost(day.Cost/float64(day.ActiveDays))
+		}
+		return []string{
+			day.Day,
+			formatNumberWithCommas(day.ActiveDays),
+			formatNumberWithCommas(day.Requests),
+			formatNumberWithCommas(day.TotalTokens),
+			FormatCost(day.Cost),
+			perDay,
+			formatShare(day.TotalTokens, total),
+			bar,
+		}
+	}
+
+	var buf bytes.Buffer
+	table := f.newTable(&buf)
+	table.Header(header)
+	for _, day := range profile {
+		table.Append(row(day, histogramBar(day.TotalTokens, peak)))
+	}
+	table.Render()
+	output.WriteString(buf.String())
+
+	buf.Reset()
+	table = f.newTable(&buf)
+	table.Header(header)
+	split := max(weekdays.TotalTokens, weekend.TotalTokens)
+	table.Append(row(weekdays, histogramBar(weekdays.TotalTokens, split)))
+	table.Append(row(weekend, histogramBar(weekend.TotalTokens, split)))
+	table.Render()
+	output.WriteString("\n")
+	output.WriteString(buf.String())
+	return output.String()
+}
+
+// FormatUsageStreaks renders streaks of active days, the busiest week and
+// the average active hours per day
+func (f *TableWriterFormatter) FormatUsageStreaks(streaks types.UsageStreaks) string {
+	var output strings.Builder
+	output.WriteString("\n")
+	output.WriteString(" ╭────────────────────────────────────────────────────╮\n")
+	output.WriteString(" │                                                    │\n")
+	output.WriteString(fmt.Sprintf(" │  %-50s│\n", "Usage Streaks"))
+	output.WriteString(" │                                                    │\n")
+	output.WriteString(" ╰────────────────────────────────────────────────────╯\n\n")
+
+	if streaks.ActiveDays == 0 {
+		output.WriteString("No usage data found.\n")
+		return output.String()
+	}
+
+	longest := formatDays(streaks.LongestStreak)
+	if streaks.LongestStreak > 1 {
+		longest += fmt.Sprintf(" (%s to %s)", streaks.LongestStreakStart, streaks.LongestStreakEnd)
+	}
+	output.WriteString(fmt.Sprintf(" Active days:           %s\n", formatNumberWithCommas(streaks.ActiveDays)))
+	output.WriteString(fmt.Sprintf(" Current streak:        %s\n", formatDays(streaks.CurrentStreak)))
+	output.WriteString(fmt.Sprintf(" Longest streak:        %s\n", longest))
+	output.WriteString(fmt.Sprintf(" Busiest week:          week of %s (%s tokens, %s)\n",
+		streaks.BusiestWeek, formatNumberWithCommas(streaks.BusiestWeekTokens), FormatCost(streaks.BusiestWeekCost)))
+	output.WriteString(fmt.Sprintf(" Active hours per day:  %.1f\n", streaks.AvgActiveHours))
+	return output.String()
+}
+
+// formatDays formats a number of days, e.g. "1 day" or "12 days"
+func formatDays(days int) string {
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
@@ -0,0 +1,59 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sdpower/ccusage-go/internal/calculator"
+	"github.com/sdpower/ccusage-go/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "▁▂█▁", Sparkline([]int{0, 1, 70, 0}))
+	assert.Equal(t, "▁▁", Sparkline([]int{0, 0}))
+}
+
+func TestFormatHourOfDayProfile(t *testing.T) {
+	profile := make([]types.HourUsage, 24)
+	for hour := range profile {
+		profile[hour].Hour = hour
+	}
+	output := NewTableWriterFormatter(true).FormatHourOfDayProfile(profile)
+	assert.Contains(t, output, "No usage data found.")
+
+	profile[9] = types.HourUsage{Hour: 9, Requests: 3, TotalTokens: 3000, Cost: 0.3}
+	profile[14] = types.HourUsage{Hour: 14, Requests: 1, TotalTokens: 1000, Cost: 0.1}
+	output = NewTableWriterFormatter(true).FormatHourOfDayProfile(profile)
+	assert.Contains(t, output, "75.0%")
+	assert.Contains(t, output, strings.Repeat("█", histogramWidth))
+	assert.Contains(t, output, "Busiest hour: 09:00")
+}
+
+func TestFormatDayOfWeekProfile(t *testing.T) {
+	profile := calculator.New(nil).DayOfWeekProfile(nil)
+	output := NewTableWriterFormatter(true).FormatDayOfWeekProfile(profile)
+	assert.Contains(t, output, "No usage data found.")
+
+	profile[0] = types.DayOfWeekUsage{Day: "Monday", ActiveDays: 2, Requests: 4, TotalTokens: 3000, Cost: 3}
+	profile[6] = types.DayOfWeekUsage{Day: "Sunday", Weekend: true, ActiveDays: 1, Requests: 1, TotalTokens: 1000, Cost: 2}
+	output = NewTableWriterFormatter(true).FormatDayOfWeekProfile(profile)
+	assert.Contains(t, output, "$1.50", "cost per active Monday")
+	assert.Contains(t, output, calculator.DayWeekend)
+	assert.Contains(t, output, "25.0%")
+}
+
+func TestFormatUsageStreaks(t *testing.T) {
+	output := NewTableWriterFormatter(true).FormatUsageStreaks(types.UsageStreaks{})
+	assert.Contains(t, output, "No usage data found.")
+
+	output = NewTableWriterFormatter(true).FormatUsageStreaks(types.UsageStreaks{
+		ActiveDays: 5, CurrentStreak: 1, LongestStreak: 3,
+		LongestStreakStart: "2025-03-01", LongestStreakEnd: "2025-03-03",
+		BusiestWeek: "2025-03-03", BusiestWeekTokens: 1200, BusiestWeekCost: 3.1, AvgActiveHours: 1.25,
+	})
+	assert.Contains(t, output, "Current streak:        1 day\n")
+	assert.Contains(t, output, "3 days (2025-03-01 to 2025-03-03)")
+	assert.Contains(t, output, "week of 2025-03-03 (1,200 tokens, $3.10)")
+	assert.Contains(t, output, "Active hours per day:  1.2")
+}
@@ -0,0 +1,91 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+)
+
+// Exec is an Aggregator backed by an external command. The command reads one
+// JSON entry per line on stdin and, once stdin is closed, writes
+// {"sections": [{"title": ..., "columns": [...], "rows": [[...]]}]} to
+// stdout. Its stderr is passed through.
+type Exec struct {
+	args []string
+
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	enc    *json.Encoder
+	stdout bytes.Buffer
+	err    error
+}
+
+// NewExec creates an aggregator running args; the command is started with the first entry
+func NewExec(args []string) *Exec {
+	return &Exec{args: args}
+}
+
+// start launches the command
+func (e *Exec) start() {
+	if len(e.args) == 0 {
+		e.err = fmt.Errorf("no command configured")
+		return
+	}
+	e.cmd = exec.Command(e.args[0], e.args[1:]...)
+	e.cmd.Stdout = &e.stdout
+	e.cmd.Stderr = os.Stderr
+	stdin, err := e.cmd.StdinPipe()
+	if err != nil {
+		e.err = err
+		return
+	}
+	if err := e.cmd.Start(); err != nil {
+		e.err = err
+		return
+	}
+	e.stdin = stdin
+	e.enc = json.NewEncoder(stdin)
+}
+
+// Add writes entry to the command's stdin. A write error is reported by Sections.
+func (e *Exec) Add(entry types.UsageEntry) {
+	if e.cmd == nil && e.err == nil {
+		e.start()
+	}
+	if e.err != nil {
+		return
+	}
+	if err := e.enc.Encode(entry); err != nil {
+		e.err = err
+	}
+}
+
+// Sections closes stdin, waits for the command and decodes its output
+func (e *Exec) Sections() ([]types.ReportSection, error) {
+	if e.cmd == nil && e.err == nil {
+		e.start() // A report without entries still gets the command's sections
+	}
+	if e.stdin != nil {
+		e.stdin.Close()
+		// The command's exit status explains a failed write better than EPIPE
+		if err := e.cmd.Wait(); err != nil {
+			return nil, fmt.Errorf("%s: %w", e.args[0], err)
+		}
+	}
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	var result struct {
+		Sections []types.ReportSection `json:"sections"`
+	}
+	if err := json.Unmarshal(e.stdout.Bytes(), &result); err != nil {
+		return nil, fmt.Errorf("%s: invalid output: %w", e.args[0], err)
+	}
+	return result.Sections, nil
+}
@@ -0,0 +1,119 @@
+// Package plugin runs the aggregators selected with --plugin: Go aggregators
+// registered with pkg/plugin, or external commands speaking the Exec
+// protocol. Either receives the report's normalized, cost-calculated entries
+// and returns sections rendered below the report.
+package plugin
+
+import (
+	"fmt"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	pkgplugin "github.com/sdpower/ccusage-go/pkg/plugin"
+)
+
+// Aggregator receives every entry of one report, then returns the sections
+// it adds to the report: a Go aggregator registered with pkg/plugin, or Exec
+type Aggregator interface {
+	Add(entry types.UsageEntry)
+	Sections() ([]types.ReportSection, error)
+}
+
+// Set is the aggregators selected for one report. The zero Set has none.
+type Set struct {
+	names []string
+	aggs  []Aggregator
+}
+
+// New creates the aggregators named by --plugin: a registered Go aggregator,
+// else the external command configured under that name in commands
+func New(names []string, commands map[string][]string) (*Set, error) {
+	set := &Set{}
+	for _, name := range names {
+		var agg Aggregator
+		if factory, ok := pkgplugin.Lookup(name); ok {
+			agg = registered{factory()}
+		} else if args, ok := commands[name]; ok {
+			agg = NewExec(args)
+		} else {
+			return nil, fmt.Errorf("unknown --plugin %q: register it or configure its command under \"plugins\"", name)
+		}
+		set.names = append(set.names, name)
+		set.aggs = append(set.aggs, agg)
+	}
+	return set, nil
+}
+
+// Len returns the number of aggregators in the set
+func (s *Set) Len() int {
+	return len(s.aggs)
+}
+
+// Add passes entry to every aggregator
+func (s *Set) Add(entry types.UsageEntry) {
+	for _, agg := range s.aggs {
+		agg.Add(entry)
+	}
+}
+
+// AddAll passes entries to every aggregator
+func (s *Set) AddAll(entries []types.UsageEntry) {
+	for _, entry := range entries {
+		s.Add(entry)
+	}
+}
+
+// Sections collects the sections of every aggregator in --plugin order
+func (s *Set) Sections() ([]types.ReportSection, error) {
+	var sections []types.ReportSection
+	for i, agg := range s.aggs {
+		added, err := agg.Sections()
+		if err != nil {
+			return nil, fmt.Errorf("plugin %s: %w", s.names[i], err)
+		}
+		sections = append(sections, added...)
+	}
+	return sections, nil
+}
+
+// registered adapts a pkg/plugin aggregator to the report's entries
+type registered struct {
+	agg pkgplugin.Aggregator
+}
+
+func (r registered) Add(entry types.UsageEntry) {
+	r.agg.Add(pkgplugin.Entry{
+		ID:                    entry.ID,
+		Timestamp:             entry.Timestamp,
+		DateKey:               entry.DateKey,
+		ProjectPath:           entry.ProjectPath,
+		Model:                 entry.Model,
+		InputTokens:           entry.InputTokens,
+		OutputTokens:          entry.OutputTokens,
+		TotalTokens:           entry.TotalTokens,
+		CacheCreation1hTokens: entry.CacheCreation1hTokens,
+		Cost:                  entry.Cost,
+		APICost:               entry.APICost,
+		CacheCreateCost:       entry.CacheCreateCost,
+		CacheReadCost:         entry.CacheReadCost,
+		CacheSavings:          entry.CacheSavings,
+		SessionID:             entry.SessionID,
+		SessionName:           entry.SessionName,
+		IsSidechain:           entry.IsSidechain,
+		AgentID:               entry.AgentID,
+		Cwd:                   entry.Cwd,
+		ServiceTier:           entry.ServiceTier,
+		Account:               entry.Account,
+	})
+}
+
+func (r registered) Sections() ([]types.ReportSection, error) {
+	added, err := r.agg.Sections()
+	if err != nil {
+		return nil, err
+	}
+	sections := make([]types.ReportSection, len(added))
+	for i, section := range added {
+		sections[i] = types.ReportSection(section)
+	}
+	return sections, nil
+}
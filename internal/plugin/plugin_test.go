@@ -0,0 +1,134 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/types"
+	pkgplugin "github.com/sdpower/ccusage-go/pkg/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// requestCounter is a Go aggregator counting requests per project
+type requestCounter struct {
+	counts map[string]int
+}
+
+func (c *requestCounter) Add(entry pkgplugin.Entry) {
+	c.counts[entry.ProjectPath]++
+}
+
+func (c *requestCounter) Sections() ([]pkgplugin.Section, error) {
+	section := pkgplugin.Section{Title: "Requests", Columns: []string{"Project", "Requests"}}
+	for project, n := range c.counts {
+		section.Rows = append(section.Rows, []string{project, fmt.Sprint(n)})
+	}
+	return []pkgplugin.Section{section}, nil
+}
+
+func init() {
+	pkgplugin.Register("test-requests", func() pkgplugin.Aggregator {
+		return &requestCounter{counts: make(map[string]int)}
+	})
+}
+
+func TestRegisteredAggregator(t *testing.T) {
+	set, err := New([]string{"test-requests"}, nil)
+	require.NoError(t, err)
+	set.AddAll([]types.UsageEntry{{ProjectPath: "app"}, {ProjectPath: "app"}})
+
+	sections, err := set.Sections()
+	require.NoError(t, err)
+	assert.Equal(t, []types.ReportSection{{
+		Title:   "Requests",
+		Columns: []string{"Project", "Requests"},
+		Rows:    [][]string{{"app", "2"}},
+	}}, sections)
+}
+
+func TestRegisteredAggregatorGetsEntryFields(t *testing.T) {
+	var got []pkgplugin.Entry
+	pkgplugin.Register("test-entries", func() pkgplugin.Aggregator {
+		return recorder{&got}
+	})
+	set, err := New([]string{"test-entries"}, nil)
+	require.NoError(t, err)
+
+	entry := types.UsageEntry{
+		ID: "msg:req", Timestamp: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), DateKey: "2025-06-01",
+		ProjectPath: "app", Model: "claude-sonnet-4-5", InputTokens: 100, OutputTokens: 10, TotalTokens: 110,
+		CacheCreation1hTokens: 5, Cost: 1.5, APICost: 1, CacheCreateCost: 0.3, CacheReadCost: 0.2, CacheSavings: 0.1,
+		SessionID: "s1", SessionName: "title", IsSidechain: true, AgentID: "agent", Cwd: "/work/app",
+		ServiceTier: "batch", Account: "team@example.com", SourceFile: "/logs/s1.jsonl",
+	}
+	set.Add(entry)
+
+	// The public entry encodes like the entries external commands read
+	want, err := json.Marshal(entry)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	encoded, err := json.Marshal(got[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, string(want), string(encoded))
+}
+
+// recorder is a Go aggregator keeping the entries it is given
+type recorder struct {
+	entries *[]pkgplugin.Entry
+}
+
+func (r recorder) Add(entry pkgplugin.Entry) {
+	*r.entries = append(*r.entries, entry)
+}
+
+func (r recorder) Sections() ([]pkgplugin.Section, error) {
+	return nil, nil
+}
+
+func TestNewRejectsUnknownPlugin(t *testing.T) {
+	_, err := New([]string{"missing"}, map[string][]string{"other": {"true"}})
+	assert.ErrorContains(t, err, `unknown --plugin "missing"`)
+
+	set, err := New(nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, set.Len())
+	sections, err := set.Sections()
+	require.NoError(t, err)
+	assert.Empty(t, sections)
+}
+
+func TestExecAggregator(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	script := `n=$(wc -l | tr -d ' '); printf '{"sections":[{"title":"Lines","columns":["Entries"],"rows":[["%s"]]}]}' "$n"`
+	set, err := New([]string{"lines"}, map[string][]string{"lines": {"sh", "-c", script}})
+	require.NoError(t, err)
+	set.AddAll([]types.UsageEntry{{Model: "a"}, {Model: "b"}, {Model: "c"}})
+
+	sections, err := set.Sections()
+	require.NoError(t, err)
+	require.Len(t, sections, 1)
+	assert.Equal(t, "Lines", sections[0].Title)
+	assert.Equal(t, [][]string{{"3"}}, sections[0].Rows)
+}
+
+func TestExecAggregatorErrors(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	for name, args := range map[string][]string{
+		"exit status": {"sh", "-c", "cat >/dev/null; exit 3"},
+		"bad output":  {"sh", "-c", "cat >/dev/null; echo nope"},
+		"not found":   {"ccusage-no-such-plugin"},
+	} {
+		agg := NewExec(args)
+		agg.Add(types.UsageEntry{Model: "a"})
+		_, err := agg.Sections()
+		assert.Error(t, err, name)
+	}
+}
@@ -28,6 +28,7 @@ type SessionBlock struct {
 	CacheReadCostUSD     float64     `json:"cache_read_cost_usd"`      // Cache read cost
 	Models               []string    `json:"models"`                   // Unique models used
 	UsageLimitResetTime  *time.Time  `json:"usage_limit_reset_time,omitempty"` // Claude API usage limit reset time
+	Source               string      `json:"source,omitempty"`         // Data directory or log file of the block's entries, set with a block source
 }
 
 // BurnRate represents usage burn rate calculations
@@ -37,11 +38,22 @@ type BurnRate struct {
 	CostPerHour                 float64 `json:"cost_per_hour"`
 }
 
+// BurnRatePoint is one minute of a block's burn-rate series
+type BurnRatePoint struct {
+	Minute  time.Time `json:"minute"`   // Start of the minute
+	Tokens  int       `json:"tokens"`   // Tokens used during the minute, including cache tokens
+	CostUSD float64   `json:"cost_usd"` // Cost of the minute's requests
+}
+
 // ProjectedUsage represents projected usage for remaining time in a session block
 type ProjectedUsage struct {
 	TotalTokens      int     `json:"total_tokens"`
 	TotalCost        float64 `json:"total_cost"`
 	RemainingMinutes float64 `json:"remaining_minutes"`
+	LowTokens        int     `json:"low_tokens"`  // Lower bound of the confidence interval of TotalTokens
+	HighTokens       int     `json:"high_tokens"` // Upper bound of the confidence interval of TotalTokens
+	LowCost          float64 `json:"low_cost"`
+	HighCost         float64 `json:"high_cost"`
 }
 
 // TokenLimitStatus represents the status of token usage against a limit
@@ -52,6 +64,21 @@ type TokenLimitStatus struct {
 	Status         string  `json:"status"` // "ok", "warning", or "exceeds"
 }
 
+// PlanCapacity is the estimated remaining capacity of a subscription plan in
+// the current block and the rolling week
+type PlanCapacity struct {
+	Plan                 string  `json:"plan"`
+	BlockTokenLimit      int     `json:"block_token_limit"` // Input and output tokens only
+	BlockTokensUsed      int     `json:"block_tokens_used"`
+	BlockTokensRemaining int     `json:"block_tokens_remaining"`
+	BlockCostLimit       float64 `json:"block_cost_limit"`
+	BlockCostUsed        float64 `json:"block_cost_used"`
+	BlockCostRemaining   float64 `json:"block_cost_remaining"`
+	WeekCostLimit        float64 `json:"week_cost_limit"`
+	WeekCostUsed         float64 `json:"week_cost_used"`
+	WeekCostRemaining    float64 `json:"week_cost_remaining"`
+}
+
 // GetTotalTokens calculates the total number of tokens from TokenCounts
 func (tc TokenCounts) GetTotal() int {
 	return tc.InputTokens + tc.OutputTokens + tc.CacheCreationInputTokens + tc.CacheReadInputTokens
@@ -3,6 +3,7 @@ package types
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 var (
@@ -47,3 +48,38 @@ func (e ParseError) Error() string {
 func (e ParseError) Unwrap() error {
 	return e.Err
 }
+
+// FileValidationCount records how many lines of a file failed validation
+type FileValidationCount struct {
+	Path         string
+	InvalidLines int
+	TotalLines   int
+	Errors       []ParseError // Details of the first failures, in line order
+}
+
+// StrictValidationError is returned in strict mode when the share of lines
+// failing validation exceeds the allowed error rate
+type StrictValidationError struct {
+	InvalidLines int
+	TotalLines   int
+	MaxErrorRate float64               // Percent of lines
+	Files        []FileValidationCount // Files with invalid lines, worst first
+	MoreFiles    int                   // Files with invalid lines left out of Files
+}
+
+func (e StrictValidationError) Error() string {
+	rate := 0.0
+	if e.TotalLines > 0 {
+		rate = float64(e.InvalidLines) / float64(e.TotalLines) * 100
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "strict mode: %d of %d lines (%.2f%%) failed validation, above the %.2f%% limit",
+		e.InvalidLines, e.TotalLines, rate, e.MaxErrorRate)
+	for _, f := range e.Files {
+		fmt.Fprintf(&b, "\n  %s: %d of %d lines invalid", f.Path, f.InvalidLines, f.TotalLines)
+	}
+	if e.MoreFiles > 0 {
+		fmt.Fprintf(&b, "\n  ... and %d more files", e.MoreFiles)
+	}
+	return b.String()
+}
@@ -1,9 +1,16 @@
 package types
 
 import (
+	"encoding/json"
 	"time"
+
+	"github.com/sdpower/ccusage-go/pkg/pricing"
 )
 
+// SyntheticModel is the model of entries Claude Code writes itself (e.g. for
+// errors) rather than receiving from the API
+const SyntheticModel = "<synthetic>"
+
 type UsageEntry struct {
 	ID           string                 `json:"id"`
 	Timestamp    time.Time              `json:"timestamp"`
@@ -14,35 +21,125 @@ type UsageEntry struct {
 	OutputTokens int                    `json:"output_tokens"`
 	TotalTokens  int                    `json:"total_tokens"`
 	Cost         float64                `json:"cost,omitempty"`
+	CostFromLog  bool                   `json:"-"` // Cost was read from the log (costUSD) rather than computed
 	APICost        float64                `json:"api_cost,omitempty"`  // input + output only, no cache
 	CacheCreateCost float64               `json:"cache_create_cost,omitempty"`
 	CacheReadCost  float64                `json:"cache_read_cost,omitempty"`
+	CacheSavings   float64                `json:"cache_savings,omitempty"` // Estimated saving of cache reads over the full input price
 	SessionID      string                 `json:"session_id"`
 	SessionName  string                 `json:"session_name,omitempty"`
 	BlockType    string                 `json:"block_type,omitempty"`
+	IsSidechain  bool                   `json:"is_sidechain,omitempty"` // Written by a sub-agent (Task tool), not the main conversation
+	AgentID      string                 `json:"agent_id,omitempty"`
+	Cwd          string                 `json:"cwd,omitempty"` // Working directory the request was made from
+	ServiceTier  string                 `json:"service_tier,omitempty"` // usage.service_tier: standard, priority or batch
+	CacheCreation1hTokens int               `json:"cache_creation_1h_tokens,omitempty"` // Cache creation tokens written for 1 hour (usage.cache_creation.ephemeral_1h_input_tokens), not 5 minutes
+	Account      string                 `json:"account,omitempty"` // account or userId of merged team exports
 	SourceFile   string                 `json:"-"`
 	Raw          map[string]interface{} `json:"-"`
+	RawJSON      json.RawMessage        `json:"raw,omitempty"` // Original log line, kept with Loader.SetKeepRaw
 }
 
 type UsageReport struct {
-	Period      string       `json:"period"`
-	StartTime   time.Time    `json:"start_time"`
-	EndTime     time.Time    `json:"end_time"`
-	TotalCost   float64      `json:"total_cost"`
-	TotalTokens int          `json:"total_tokens"`
-	Entries     []UsageEntry `json:"entries"`
-	Summary     UsageSummary `json:"summary"`
+	Period      string          `json:"period"`
+	StartTime   time.Time       `json:"start_time"`
+	EndTime     time.Time       `json:"end_time"`
+	TotalCost   float64         `json:"total_cost"`
+	TotalTokens int             `json:"total_tokens"`
+	Entries     []UsageEntry    `json:"entries"`
+	Summary     UsageSummary    `json:"summary"`
+	Metadata    *ReportMetadata `json:"metadata,omitempty"` // Set with --debug
+	Sections    []ReportSection `json:"sections,omitempty"` // Added by --plugin
+	Billing     *BilledCost     `json:"billing,omitempty"`  // Set with --billing subscription
+
+	// UnknownModels have no pricing data: their computed costs are estimates
+	// at default rates
+	UnknownModels []string `json:"unknown_models,omitempty"`
+}
+
+// BilledCost is what usage paid for by a Claude subscription cost: nothing
+// per request, against the API-equivalent cost total_cost still holds
+type BilledCost struct {
+	Billing           string  `json:"billing"`
+	ActualCost        float64 `json:"actual_cost"`
+	APIEquivalentCost float64 `json:"api_equivalent_cost"`
+}
+
+// ReportSection is a table a plugin adds to a report. Rows may be shorter
+// than Columns; missing cells are left empty.
+type ReportSection struct {
+	Title   string     `json:"title"`
+	Columns []string   `json:"columns"`
+	Rows    [][]string `json:"rows"`
+}
+
+// ReportMetadata describes how a report was produced
+type ReportMetadata struct {
+	Loader  *LoaderStats   `json:"loader,omitempty"`
+	Pricing *pricing.Stats `json:"pricing,omitempty"`
+}
+
+// LoaderStats summarizes the work done by one load
+type LoaderStats struct {
+	FilesScanned         int            `json:"files_scanned"`                // JSONL files found under the data directories
+	FilesSkipped         int            `json:"files_skipped"`                // Files left out by --include/--exclude, limits or the date range
+	LinesParsed          int            `json:"lines_parsed"`                 // Non-empty lines read from the remaining files
+	ParseErrors          int            `json:"parse_errors"`                 // Lines that failed validation
+	BadTimestamps        int            `json:"bad_timestamps"`               // Usage entries among ParseErrors dropped for a missing or invalid timestamp
+	DuplicatesRemoved    int            `json:"duplicates_removed"`           // Entries dropped as copies of an earlier request
+	DuplicatesAcrossRuns int            `json:"duplicates_across_runs"`       // Among DuplicatesRemoved, copies of a request another file held on an earlier run (dedupe store)
+	DuplicatesByFile     map[string]int `json:"duplicates_by_file,omitempty"` // DuplicatesRemoved per file the dropped copy was read from
+	WallTimeMs           int64          `json:"wall_time_ms"`
 }
 
 type UsageSummary struct {
-	TotalRequests int            `json:"total_requests"`
-	TotalCost     float64        `json:"total_cost"`
-	TotalTokens   int            `json:"total_tokens"`
-	InputTokens   int            `json:"input_tokens"`
-	OutputTokens  int            `json:"output_tokens"`
-	Models        map[string]int `json:"models"`
-	Projects      map[string]int `json:"projects"`
-	AverageCost   float64        `json:"average_cost"`
+	TotalRequests           int                    `json:"total_requests"`
+	TotalCost               float64                `json:"total_cost"`
+	TotalTokens             int                    `json:"total_tokens"`
+	InputTokens             int                    `json:"input_tokens"`
+	OutputTokens            int                    `json:"output_tokens"`
+	Models                  map[string]int         `json:"models"`
+	Projects                map[string]int         `json:"projects"`
+	AverageCost             float64                `json:"average_cost"`
+	AverageTokensPerRequest float64                `json:"average_tokens_per_request"`
+	OutputInputRatio        float64                `json:"output_input_ratio"`        // Output tokens per input token (0 without input)
+	CostPer1KOutputTokens   float64                `json:"cost_per_1k_output_tokens"` // 0 without output tokens
+	CacheSavings            float64                `json:"cache_savings"`             // Estimated saving from prompt caching
+	ModelFamilies           map[string]*ModelUsage `json:"model_families"`            // Usage per model family (Opus, Sonnet, Haiku, GPT, Other)
+	BatchRequests           int                    `json:"batch_requests"`            // Requests made through the Batch API
+	BatchCost               float64                `json:"batch_cost"`                // Cost of the batch requests; the rest is interactive
+}
+
+// HourUsage is the usage of one hour of the day, summed over every day of a report
+type HourUsage struct {
+	Hour        int     `json:"hour"` // 0-23 in the report timezone
+	Requests    int     `json:"requests"`
+	TotalTokens int     `json:"total_tokens"`
+	Cost        float64 `json:"cost"`
+}
+
+// DayOfWeekUsage is the usage of one day of the week (or of all weekdays or
+// weekend days), summed over every date of a report
+type DayOfWeekUsage struct {
+	Day         string  `json:"day"` // Monday to Sunday, or Weekdays/Weekend
+	Weekend     bool    `json:"weekend"`
+	ActiveDays  int     `json:"active_days"` // Dates with any usage
+	Requests    int     `json:"requests"`
+	TotalTokens int     `json:"total_tokens"`
+	Cost        float64 `json:"cost"`
+}
+
+// UsageStreaks describes how regularly usage happens over a report's dates
+type UsageStreaks struct {
+	ActiveDays         int     `json:"active_days"`    // Dates with any usage
+	CurrentStreak      int     `json:"current_streak"` // Consecutive active days up to today (or yesterday, if today is still idle)
+	LongestStreak      int     `json:"longest_streak"`
+	LongestStreakStart string  `json:"longest_streak_start,omitempty"` // YYYY-MM-DD
+	LongestStreakEnd   string  `json:"longest_streak_end,omitempty"`
+	BusiestWeek        string  `json:"busiest_week,omitempty"` // Monday (YYYY-MM-DD) of the most expensive week
+	BusiestWeekTokens  int     `json:"busiest_week_tokens"`
+	BusiestWeekCost    float64 `json:"busiest_week_cost"`
+	AvgActiveHours     float64 `json:"avg_active_hours"` // Distinct hours with usage per active day
 }
 
 type SessionInfo struct {
@@ -59,6 +156,7 @@ type SessionInfo struct {
 	CacheCreateCost      float64       `json:"cache_create_cost"`
 	CacheReadTokens      int           `json:"cache_read_tokens"`
 	CacheReadCost        float64       `json:"cache_read_cost"`
+	CacheSavings         float64       `json:"cache_savings"` // Estimated saving from prompt caching
 	RequestCount         int           `json:"request_count"`
 	ProjectPath          string        `json:"project_path"`
 	SessionName          string        `json:"session_name,omitempty"`
@@ -66,6 +164,10 @@ type SessionInfo struct {
 	SourceFiles          []string      `json:"source_files,omitempty"`
 	ModelsUsed           []string      `json:"models_used"`
 	LastActivity         time.Time     `json:"last_activity"`
+	PeakContextTokens    int           `json:"peak_context_tokens"`          // Largest request prompt (input + cache tokens)
+	ContextUtilization   float64       `json:"context_utilization"`          // Highest share (0-1) of the model's context window used
+	NearContextLimit     bool          `json:"near_context_limit,omitempty"` // ContextUtilization reached calculator.ContextWarnThreshold
+	Billing              *BilledCost   `json:"billing,omitempty"`            // Set with --billing subscription
 }
 
 type SourceFileStat struct {
@@ -84,6 +186,41 @@ type SourceFileStat struct {
 	EntryCount        int       `json:"entry_count"`
 }
 
+// ModelCostDiscrepancy compares, for one model, the cost logged with its
+// requests (costUSD) with the cost computed from their tokens
+type ModelCostDiscrepancy struct {
+	Model        string  `json:"model"`
+	Requests     int     `json:"requests"`
+	RecordedCost float64 `json:"recorded_cost"`
+	ComputedCost float64 `json:"computed_cost"`
+	Difference   float64 `json:"difference"`         // Computed minus recorded
+	Unpriced     bool    `json:"unpriced,omitempty"` // No pricing found, so nothing was computed
+}
+
+// CostDiscrepancyReport totals recorded and computed costs of the requests
+// that logged a cost, per model and overall
+type CostDiscrepancyReport struct {
+	Models             []ModelCostDiscrepancy `json:"models"` // Largest absolute difference first
+	Requests           int                    `json:"requests"`
+	RecordedCost       float64                `json:"recorded_cost"`
+	ComputedCost       float64                `json:"computed_cost"`
+	Difference         float64                `json:"difference"`
+	UnrecordedRequests int                    `json:"unrecorded_requests"` // Requests without a logged cost, not compared
+	Rates              []ModelRates           `json:"rates,omitempty"`     // Pricing of each model, with --debug
+}
+
+// CommitUsage is the usage that led up to a git commit: the requests made
+// after the previous commit, up to and including the commit time
+type CommitUsage struct {
+	Hash        string    `json:"hash"` // Empty for work not committed yet
+	Subject     string    `json:"subject"`
+	CommittedAt time.Time `json:"committed_at"`
+	Since       time.Time `json:"since,omitempty"` // Time of the previous commit; zero when unbounded
+	Requests    int       `json:"requests"`
+	TotalTokens int       `json:"total_tokens"`
+	Cost        float64   `json:"cost"`
+}
+
 type BlockInfo struct {
 	BlockType   string    `json:"block_type"`
 	Count       int       `json:"count"`
@@ -33,4 +33,24 @@ type ModelUsage struct {
 	TotalTokens              int     `json:"total_tokens"`
 	Cost                     float64 `json:"cost"`
 	RequestCount             int     `json:"request_count"`
-}
\ No newline at end of file
+}
+// ModelRates is the pricing costs of a model are calculated with, in dollars
+// per token and per million tokens (the unit Anthropic publishes), and where
+// it came from (override, litellm, embedded or default)
+type ModelRates struct {
+	Model       string  `json:"model,omitempty"`
+	Source      string  `json:"source,omitempty"`
+	Input       float64 `json:"input_cost_per_token"`
+	Output      float64 `json:"output_cost_per_token"`
+	CacheCreate float64 `json:"cache_creation_input_token_cost"`
+	CacheRead   float64 `json:"cache_read_input_token_cost"`
+
+	InputPerMTok       float64 `json:"input_usd_per_mtok"`
+	OutputPerMTok      float64 `json:"output_usd_per_mtok"`
+	CacheCreatePerMTok float64 `json:"cache_creation_usd_per_mtok"`
+	CacheReadPerMTok   float64 `json:"cache_read_usd_per_mtok"`
+
+	// LongContext holds the rates (only) of prompts over 200K tokens, nil
+	// without a long-context tier
+	LongContext *ModelRates `json:"above_200k_tokens,omitempty"`
+}
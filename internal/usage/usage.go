@@ -28,6 +28,15 @@ type UsageResponse struct {
 	SevenDayOpus   *UsageLimitEntry `json:"seven_day_opus"`
 }
 
+// offline is applied to every client (see SetOffline)
+var offline bool
+
+// SetOffline stops clients from calling the usage API (--no-network):
+// GetUsage then reports no usage limits
+func SetOffline(enabled bool) {
+	offline = enabled
+}
+
 // Client handles fetching usage limits from the Claude OAuth API
 type Client struct {
 	httpClient *http.Client
@@ -48,6 +57,10 @@ func NewClient() *Client {
 // GetUsage returns the current usage limits, using cache if available.
 // Returns nil on any error (graceful degradation).
 func (c *Client) GetUsage(ctx context.Context) *UsageResponse {
+	if offline {
+		return nil
+	}
+
 	// Check cache
 	c.cacheMux.RLock()
 	if c.cache != nil && time.Since(c.cacheTime) < cacheTTL {
@@ -0,0 +1,66 @@
+// Package cli runs the ccusage command line. A program that registers its own
+// aggregators with pkg/plugin gets them under --plugin by calling Main:
+//
+//	import (
+//		_ "example.com/team/rollup" // calls plugin.Register in init
+//
+//		"github.com/sdpower/ccusage-go/pkg/cli"
+//	)
+//
+//	func main() { cli.Main("team-build") }
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sdpower/ccusage-go/internal/commands"
+	"github.com/spf13/cobra"
+)
+
+// Main runs ccusage with the process arguments, reporting version for
+// --version, and exits with status 1 on error
+func Main(version string) {
+	ctx := context.Background()
+
+	rootCmd := &cobra.Command{
+		Use:     "ccusage",
+		Short:   "Claude Code usage analysis tool",
+		Long:    `A CLI tool for analyzing Claude Code usage data from local JSONL files.`,
+		Version: version,
+	}
+
+	var global commands.GlobalFlags
+	global.Register(rootCmd)
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		return global.Apply()
+	}
+	rootCmd.PersistentPostRunE = func(cmd *cobra.Command, args []string) error {
+		// The report is already printed: main reports the error, without usage
+		cmd.SilenceUsage, cmd.SilenceErrors = true, true
+		return global.Check()
+	}
+
+	rootCmd.AddCommand(
+		commands.NewDailyCommand(),
+		commands.NewMonthlyCommand(),
+		commands.NewWeeklyCommand(),
+		commands.NewSessionCommand(),
+		commands.NewRequestsCommand(),
+		commands.NewBlocksCommand(),
+		commands.NewMonitorCommand(),
+		commands.NewCostCheckCommand(),
+		commands.NewStatsCommand(),
+		commands.NewCommitsCommand(),
+		commands.NewPricingCommand(),
+		commands.NewDoctorCommand(),
+		commands.NewAlertCommand(),
+		commands.NewCacheCommand(),
+	)
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
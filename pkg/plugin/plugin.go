@@ -0,0 +1,98 @@
+// Package plugin lets organizations add their own rollups to ccusage reports
+// without forking. A Go aggregator registered from an init function is
+// selectable with --plugin name in any program built with cli.Main:
+//
+//	func init() {
+//		plugin.Register("team-rollup", func() plugin.Aggregator { return newRollup() })
+//	}
+//
+// The aggregator receives the report's normalized, cost-calculated entries
+// and returns sections rendered below the report.
+package plugin
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Entry is one request of a report, with its costs calculated. Its JSON form
+// is what external plugin commands read on stdin.
+type Entry struct {
+	ID                    string    `json:"id"`
+	Timestamp             time.Time `json:"timestamp"`
+	DateKey               string    `json:"date_key,omitempty"` // YYYY-MM-DD in the report's timezone
+	ProjectPath           string    `json:"project_path"`
+	Model                 string    `json:"model"`
+	InputTokens           int       `json:"input_tokens"`
+	OutputTokens          int       `json:"output_tokens"`
+	TotalTokens           int       `json:"total_tokens"`
+	CacheCreation1hTokens int       `json:"cache_creation_1h_tokens,omitempty"` // Cache creation tokens written for 1 hour rather than 5 minutes
+	Cost                  float64   `json:"cost,omitempty"`
+	APICost               float64   `json:"api_cost,omitempty"` // input + output only, no cache
+	CacheCreateCost       float64   `json:"cache_create_cost,omitempty"`
+	CacheReadCost         float64   `json:"cache_read_cost,omitempty"`
+	CacheSavings          float64   `json:"cache_savings,omitempty"` // Estimated saving of cache reads over the full input price
+	SessionID             string    `json:"session_id"`
+	SessionName           string    `json:"session_name,omitempty"`
+	IsSidechain           bool      `json:"is_sidechain,omitempty"` // Written by a sub-agent (Task tool), not the main conversation
+	AgentID               string    `json:"agent_id,omitempty"`
+	Cwd                   string    `json:"cwd,omitempty"`          // Working directory the request was made from
+	ServiceTier           string    `json:"service_tier,omitempty"` // standard, priority or batch
+	Account               string    `json:"account,omitempty"`      // account or userId of merged team exports
+}
+
+// Section is a table an aggregator adds to a report. Rows may be shorter
+// than Columns; missing cells are left empty.
+type Section struct {
+	Title   string     `json:"title"`
+	Columns []string   `json:"columns"`
+	Rows    [][]string `json:"rows"`
+}
+
+// Aggregator receives every entry of one report, then returns the sections
+// it adds to the report
+type Aggregator interface {
+	Add(entry Entry)
+	Sections() ([]Section, error)
+}
+
+// Factory creates the aggregator for one report
+type Factory func() Aggregator
+
+var (
+	registryMu sync.Mutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes a Go aggregator selectable with --plugin name. It is meant
+// to be called from init functions and panics when name is already taken.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("plugin: %q registered twice", name))
+	}
+	registry[name] = factory
+}
+
+// Lookup returns the factory registered under name
+func Lookup(name string) (Factory, bool) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	factory, ok := registry[name]
+	return factory, ok
+}
+
+// Registered returns the names of the registered Go aggregators, sorted
+func Registered() []string {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,25 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type noSections struct{}
+
+func (noSections) Add(Entry)                    {}
+func (noSections) Sections() ([]Section, error) { return nil, nil }
+
+func TestRegister(t *testing.T) {
+	Register("test-register", func() Aggregator { return noSections{} })
+
+	assert.Contains(t, Registered(), "test-register")
+	factory, ok := Lookup("test-register")
+	assert.True(t, ok)
+	assert.Equal(t, noSections{}, factory())
+	assert.Panics(t, func() { Register("test-register", nil) })
+
+	_, ok = Lookup("test-missing")
+	assert.False(t, ok)
+}
@@ -0,0 +1,64 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sdpower/ccusage-go/internal/cachedir"
+)
+
+// DefaultCachePath returns where fetched LiteLLM pricing is saved across
+// runs: pricing.json in ccusage's cache directory, under $XDG_CACHE_HOME or
+// else the platform's user cache directory (e.g. ~/.cache/ccusage)
+func DefaultCachePath() (string, error) {
+	return cachedir.Path("pricing.json")
+}
+
+// readDiskCache loads the saved LiteLLM pricing and the time it was fetched
+// (the file's modification time)
+func (s *Service) readDiskCache() (LiteLLMResponse, time.Time, error) {
+	if s.diskPath == "" {
+		return nil, time.Time{}, errors.New("no pricing cache file")
+	}
+	info, err := os.Stat(s.diskPath)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	data, err := os.ReadFile(s.diskPath)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	response, _, err := DecodeLiteLLM(data)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid pricing cache %s: %w", s.diskPath, err)
+	}
+	return response, info.ModTime(), nil
+}
+
+// writeDiskCache saves fetched LiteLLM pricing for later runs
+func (s *Service) writeDiskCache(data []byte) error {
+	if s.diskPath == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(s.diskPath), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// Write to a temp file and rename so concurrent runs never read a partial file
+	tmp, err := os.CreateTemp(filepath.Dir(s.diskPath), filepath.Base(s.diskPath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create pricing cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write pricing cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write pricing cache: %w", err)
+	}
+	return os.Rename(tmp.Name(), s.diskPath)
+}
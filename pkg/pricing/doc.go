@@ -0,0 +1,18 @@
+// Package pricing looks up the per-token prices of Claude and other models
+// and is usable outside ccusage.
+//
+// A Service prices a model from, in order: overrides (a pricing file, see
+// LoadOverrides), LiteLLM's model_prices_and_context_window.json (fetched at
+// most once an hour and saved under ~/.cache/ccusage), the prices embedded
+// at release time, and generic default rates. Model names match across
+// spellings such as "claude-sonnet-4-5", "claude-sonnet-4.5" and
+// "anthropic.claude-sonnet-4-5-20250929-v1:0". With BackendOpenRouter,
+// OpenRouter's model list replaces LiteLLM's data.
+//
+//	svc := pricing.New(pricing.Options{Offline: true})
+//	p, source := svc.ModelPrice(ctx, "claude-sonnet-4-5")
+//	fmt.Println(pricing.PerMTok(p.InputCostPerToken), source) // 3 embedded
+//
+// A Service takes all of its configuration from the Options given to New,
+// so services with different settings can be used side by side.
+package pricing
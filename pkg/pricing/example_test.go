@@ -0,0 +1,31 @@
+package pricing_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sdpower/ccusage-go/pkg/pricing"
+)
+
+func Example() {
+	svc := pricing.New(pricing.Options{Offline: true, NoDiskCache: true})
+
+	p, source := svc.ModelPrice(context.Background(), "claude-sonnet-4.5")
+	fmt.Printf("$%g/MTok input, $%g/MTok output (%s)\n",
+		pricing.PerMTok(p.InputCostPerToken), pricing.PerMTok(p.OutputCostPerToken), source)
+	// Output: $3/MTok input, $15/MTok output (embedded)
+}
+
+func ExampleOverrides() {
+	svc := pricing.New(pricing.Options{
+		Offline:     true,
+		NoDiskCache: true,
+		Overrides: pricing.Overrides{Prices: map[string]pricing.ModelPricing{
+			"my-finetune": {InputCostPerToken: 0.000002, OutputCostPerToken: 0.000008},
+		}},
+	})
+
+	p, source := svc.ModelPrice(context.Background(), "my-finetune")
+	fmt.Println(pricing.PerMTok(p.OutputCostPerToken), source)
+	// Output: 8 override
+}
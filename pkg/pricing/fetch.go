@@ -0,0 +1,121 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+const (
+	// fetchAttempts bounds the tries of one LiteLLM fetch
+	fetchAttempts = 3
+
+	// DefaultTimeout limits a whole LiteLLM fetch, retries included; each
+	// try gets a fetchAttempts-th of the timeout, so an unreachable network
+	// costs seconds rather than a long hang per attempt
+	DefaultTimeout = 15 * time.Second
+
+	// fetchRetryDelay is the backoff before the first retry; it doubles for
+	// each further retry, plus up to as much random jitter
+	fetchRetryDelay = 500 * time.Millisecond
+)
+
+// errFetchFailed is returned by refreshCache while a failed fetch is not retried
+var errFetchFailed = errors.New("LiteLLM pricing fetch failed recently")
+
+// statusError is a non-200 response of the LiteLLM server
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("API returned status %d", e.code)
+}
+
+// fetchWithRetry fetches the LiteLLM pricing, retrying transient failures up
+// to fetchAttempts times with exponential backoff and jitter, within the
+// service's timeout, and records it in the service's stats
+func (s *Service) fetchWithRetry(ctx context.Context) (data []byte, response LiteLLMResponse, err error) {
+	start := time.Now()
+	defer func() { s.stats.recordFetch(time.Since(start), err) }()
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	delay := s.retryDelay
+	for attempt := 1; ; attempt++ {
+		data, response, err = s.fetch(ctx)
+		if err == nil {
+			return data, response, nil
+		}
+		if attempt == fetchAttempts || !retryable(ctx, err) {
+			return nil, nil, s.fetchError(ctx, err, attempt)
+		}
+
+		wait := delay
+		if delay > 0 {
+			wait += rand.N(delay)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, nil, s.fetchError(ctx, err, attempt)
+		case <-time.After(wait):
+		}
+		delay *= 2
+	}
+}
+
+// fetchError describes the last error of a fetch given up after attempt tries
+func (s *Service) fetchError(ctx context.Context, err error, attempt int) error {
+	if attempt > 1 {
+		err = fmt.Errorf("%w (after %d attempts)", err, attempt)
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("no response within %s: %w", s.timeout, err)
+	}
+	return err
+}
+
+// retryable reports whether a failed fetch may succeed when tried again:
+// network errors, rate limiting and server errors, unless ctx is done
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var status *statusError
+	if errors.As(err, &status) {
+		return status.code == http.StatusTooManyRequests || status.code >= 500
+	}
+	return true
+}
+
+// fetch downloads the pricing data, returning the JSON to save on disk (in
+// LiteLLM's format) and its decoding
+func (s *Service) fetch(ctx context.Context) ([]byte, LiteLLMResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, &statusError{code: resp.StatusCode}
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	response, data, err := s.backend.decode(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, response, nil
+}
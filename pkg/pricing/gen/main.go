@@ -0,0 +1,105 @@
+// Command gen writes the embedded pricing table (embedded_pricing.json) from
+// LiteLLM's pricing data, keeping the Claude and common OpenAI models and
+// their per-token rates. Run it through go generate in pkg/pricing.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"time"
+)
+
+// rates are the LiteLLM fields the embedded table keeps
+type rates struct {
+	InputCostPerToken           float64 `json:"input_cost_per_token,omitempty"`
+	OutputCostPerToken          float64 `json:"output_cost_per_token,omitempty"`
+	CacheCreationInputTokenCost float64 `json:"cache_creation_input_token_cost,omitempty"`
+	CacheReadInputTokenCost     float64 `json:"cache_read_input_token_cost,omitempty"`
+	// 1-hour cache writes (cache_creation_input_token_cost covers 5-minute ones)
+	CacheCreationInputTokenCostAbove1Hr float64 `json:"cache_creation_input_token_cost_above_1hr,omitempty"`
+
+	InputCostPerTokenAbove200K           float64 `json:"input_cost_per_token_above_200k_tokens,omitempty"`
+	OutputCostPerTokenAbove200K          float64 `json:"output_cost_per_token_above_200k_tokens,omitempty"`
+	CacheCreationInputTokenCostAbove200K float64 `json:"cache_creation_input_token_cost_above_200k_tokens,omitempty"`
+	CacheReadInputTokenCostAbove200K     float64 `json:"cache_read_input_token_cost_above_200k_tokens,omitempty"`
+}
+
+// model is a LiteLLM entry
+type model struct {
+	rates
+	Provider string `json:"litellm_provider"`
+}
+
+// kept selects the models of the embedded table: Anthropic's Claude models and
+// the OpenAI models Codex CLI uses, under their bare names
+var kept = map[string]*regexp.Regexp{
+	"anthropic": regexp.MustCompile(`^claude-`),
+	"openai":    regexp.MustCompile(`^(gpt-5|gpt-4o|gpt-4$|gpt-4\.1|gpt-3\.5-turbo$|o3|o4-mini)`),
+}
+
+func main() {
+	url := flag.String("url", "https://raw.githubusercontent.com/BerriAI/litellm/main/model_prices_and_context_window.json", "LiteLLM pricing JSON to read")
+	in := flag.String("in", "", "Read the LiteLLM pricing JSON from this file instead of -url")
+	out := flag.String("o", "embedded_pricing.json", "File to write")
+	flag.Parse()
+
+	if err := run(*url, *in, *out); err != nil {
+		fmt.Fprintf(os.Stderr, "gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(url, in, out string) error {
+	data, err := read(url, in)
+	if err != nil {
+		return err
+	}
+	var models map[string]json.RawMessage
+	if err := json.Unmarshal(data, &models); err != nil {
+		return fmt.Errorf("invalid LiteLLM pricing: %w", err)
+	}
+
+	table := make(map[string]rates)
+	for name, raw := range models {
+		var m model
+		if json.Unmarshal(raw, &m) != nil { // e.g. sample_spec documents the fields as strings
+			continue
+		}
+		if re := kept[m.Provider]; re == nil || !re.MatchString(name) || m.InputCostPerToken == 0 {
+			continue
+		}
+		table[name] = m.rates
+	}
+	if len(table) == 0 {
+		return fmt.Errorf("no Claude or OpenAI models found")
+	}
+
+	// Map keys are sorted, so regenerating unchanged prices leaves the file as is
+	encoded, err := json.MarshalIndent(table, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(out, append(encoded, '\n'), 0o644)
+}
+
+// read returns the contents of file in, else of url
+func read(url, in string) ([]byte, error) {
+	if in != "" {
+		return os.ReadFile(in)
+	}
+	client := &http.Client{Timeout: time.Minute}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
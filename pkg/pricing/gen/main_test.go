@@ -0,0 +1,39 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "litellm.json")
+	require.NoError(t, os.WriteFile(in, []byte(`{
+		"sample_spec": {"input_cost_per_token": "input cost per token", "litellm_provider": "one of the providers"},
+		"claude-sonnet-4-5": {"litellm_provider": "anthropic", "max_tokens": 64000, "input_cost_per_token": 3e-06, "output_cost_per_token": 1.5e-05,
+			"input_cost_per_token_above_200k_tokens": 6e-06},
+		"anthropic/claude-sonnet-4-5": {"litellm_provider": "anthropic", "input_cost_per_token": 3e-06},
+		"bedrock/anthropic.claude-3-haiku": {"litellm_provider": "bedrock", "input_cost_per_token": 2.5e-07},
+		"gpt-5": {"litellm_provider": "openai", "input_cost_per_token": 1.25e-06, "output_cost_per_token": 1e-05},
+		"text-embedding-3-small": {"litellm_provider": "openai", "input_cost_per_token": 2e-08}
+	}`), 0o644))
+
+	out := filepath.Join(dir, "embedded_pricing.json")
+	require.NoError(t, run("", in, out))
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	var table map[string]map[string]float64
+	require.NoError(t, json.Unmarshal(data, &table))
+	assert.Equal(t, map[string]map[string]float64{
+		"claude-sonnet-4-5": {"input_cost_per_token": 3e-06, "output_cost_per_token": 1.5e-05, "input_cost_per_token_above_200k_tokens": 6e-06},
+		"gpt-5":             {"input_cost_per_token": 1.25e-06, "output_cost_per_token": 1e-05},
+	}, table)
+
+	require.NoError(t, os.WriteFile(in, []byte(`{}`), 0o644))
+	assert.Error(t, run("", in, out))
+}
@@ -0,0 +1,71 @@
+package pricing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// sampleSpecKey is the LiteLLM entry documenting the fields, not a model
+const sampleSpecKey = "sample_spec"
+
+// DecodeLiteLLM decodes LiteLLM's model_prices_and_context_window.json
+// tolerantly: the file mixes models with other entries and is edited by
+// hand, so an entry that is not an object, has a non-numeric or negative
+// rate, or is sample_spec is skipped instead of failing the whole file.
+// Rates written as numeric strings ("0.000003") are accepted. skipped names
+// the entries left out; the file is only rejected when it is not a JSON
+// object or prices no model at all.
+func DecodeLiteLLM(data []byte) (response LiteLLMResponse, skipped []string, err error) {
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, nil, fmt.Errorf("invalid LiteLLM pricing: %w", err)
+	}
+
+	response = make(LiteLLMResponse, len(entries))
+	for name, raw := range entries {
+		if name == sampleSpecKey {
+			continue
+		}
+		pricing, err := decodeLiteLLMEntry(raw)
+		if err != nil {
+			skipped = append(skipped, name)
+			continue
+		}
+		response[name] = pricing
+	}
+	if len(response) == 0 {
+		return nil, skipped, errors.New("invalid LiteLLM pricing: no model has valid rates")
+	}
+	return response, skipped, nil
+}
+
+// decodeLiteLLMEntry decodes the rates of one model, retrying with numeric
+// strings converted to numbers when the plain decoding fails
+func decodeLiteLLMEntry(raw json.RawMessage) (ModelPricing, error) {
+	var pricing ModelPricing
+	err := json.Unmarshal(raw, &pricing)
+	if err != nil {
+		var fields map[string]any
+		if json.Unmarshal(raw, &fields) != nil {
+			return ModelPricing{}, err // Not an object
+		}
+		for key, value := range fields {
+			if s, ok := value.(string); ok {
+				if f, parseErr := strconv.ParseFloat(s, 64); parseErr == nil {
+					fields[key] = f
+				}
+			}
+		}
+		converted, _ := json.Marshal(fields)
+		pricing = ModelPricing{}
+		if err := json.Unmarshal(converted, &pricing); err != nil {
+			return ModelPricing{}, err
+		}
+	}
+	if negativeRate(pricing) {
+		return ModelPricing{}, errors.New("negative rate")
+	}
+	return pricing, nil
+}
@@ -0,0 +1,66 @@
+package pricing
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testdata/litellm_excerpt.json is an excerpt of LiteLLM's
+// model_prices_and_context_window.json keeping the shapes of its entries
+// (sample_spec, nested objects, arrays, nulls, image and local models), plus
+// malformed entries the decoder must skip
+func TestDecodeLiteLLMExcerpt(t *testing.T) {
+	data, err := os.ReadFile("testdata/litellm_excerpt.json")
+	require.NoError(t, err)
+
+	response, skipped, err := DecodeLiteLLM(data)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"deepinfra/legacy-alias", "replicate/broken-rate", "example/negative-rate"}, skipped)
+	assert.NotContains(t, response, "sample_spec")
+	assert.Len(t, response, 9)
+
+	sonnet := response["claude-sonnet-4-5"]
+	assert.Equal(t, 0.000003, sonnet.InputCostPerToken)
+	assert.Equal(t, 0.000015, sonnet.OutputCostPerToken)
+	assert.Equal(t, 0.000006, sonnet.CacheCreationInputTokenCostAbove1Hr)
+	assert.Equal(t, 0.0000225, sonnet.OutputCostPerTokenAbove200K)
+
+	// Numeric strings are rates, nulls are missing ones
+	assert.Equal(t, 1.8e-07, response["openrouter/qwen/qwen-2.5-coder-32b-instruct"].OutputCostPerToken)
+	assert.Zero(t, response["together_ai/meta-llama/Llama-3.3-70B-Instruct-Turbo"].OutputCostPerToken)
+	assert.Contains(t, response, "dall-e-3")
+	assert.Contains(t, response, "ollama/llama3")
+}
+
+func TestDecodeLiteLLMRejectsUnusableFiles(t *testing.T) {
+	for _, data := range []string{
+		`<html>rate limited</html>`,
+		`["claude-sonnet-4-5"]`,
+		`{}`,
+		`{"sample_spec": {"input_cost_per_token": 0}, "broken": "x"}`,
+	} {
+		_, _, err := DecodeLiteLLM([]byte(data))
+		assert.Error(t, err, data)
+	}
+}
+
+func TestFetchSkipsMalformedEntries(t *testing.T) {
+	transport := &fakeTransport{body: `{
+		"sample_spec": {"max_tokens": "LEGACY parameter", "input_cost_per_token": "input cost per token"},
+		"test-model": {"input_cost_per_token": 0.5, "output_cost_per_token": 2},
+		"broken-model": {"input_cost_per_token": "free"}
+	}`}
+	s := newTestService(t, transport)
+	ctx := context.Background()
+
+	pricing, source := s.ModelPrice(ctx, "test-model")
+	assert.Equal(t, SourceLiteLLM, source)
+	assert.Equal(t, 0.5, pricing.InputCostPerToken)
+	_, source = s.ModelPrice(ctx, "broken-model")
+	assert.Equal(t, SourceDefault, source)
+	assert.Equal(t, 1, transport.requests)
+}
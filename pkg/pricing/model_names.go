@@ -0,0 +1,85 @@
+package pricing
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// Snapshot dates (-20250929, @20250929) and -latest aliases
+	modelDateSuffix = regexp.MustCompile(`[-@](\d{8}|latest)$`)
+	// Bedrock revision suffixes (-v1:0, -v2)
+	modelRevisionSuffix = regexp.MustCompile(`-v\d+(:\d+)?$`)
+	// Dotted versions (claude-sonnet-4.5, claude-3.5-sonnet)
+	modelDottedVersion = regexp.MustCompile(`(\d)\.(\d)`)
+)
+
+// normalizeModel reduces a model ID to the name shared by all its spellings:
+// lower case, without provider or region prefixes (anthropic/,
+// bedrock/us.anthropic.), snapshot date, Bedrock revision or dots in the
+// version, so anthropic/claude-sonnet-4-5-20250929 and claude-sonnet-4.5
+// both become claude-sonnet-4-5
+func normalizeModel(model string) string {
+	name := strings.ToLower(strings.TrimSpace(model))
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.LastIndex(name, "anthropic."); i >= 0 {
+		name = name[i+len("anthropic."):]
+	}
+	name = modelRevisionSuffix.ReplaceAllString(name, "")
+	name = modelDateSuffix.ReplaceAllString(name, "")
+	return modelDottedVersion.ReplaceAllString(name, "$1-$2")
+}
+
+// modelIndex maps normalized model names to the key of a pricing table
+// that prices them
+type modelIndex map[string]string
+
+// newModelIndex indexes the keys of a pricing table by normalized name. When several
+// keys share a name, a key without provider prefix wins over anthropic/ and
+// that over other providers (whose regional prices can differ), then the
+// latest snapshot.
+func newModelIndex[T any](prices map[string]T) modelIndex {
+	index := make(modelIndex, len(prices))
+	for key := range prices {
+		name := normalizeModel(key)
+		if current, exists := index[name]; exists && !preferModelKey(key, current) {
+			continue
+		}
+		index[name] = key
+	}
+	return index
+}
+
+// preferModelKey reports whether key is a better match than current for
+// their shared normalized name
+func preferModelKey(key, current string) bool {
+	if rank, currentRank := modelKeyRank(key), modelKeyRank(current); rank != currentRank {
+		return rank < currentRank
+	}
+	return key > current
+}
+
+func modelKeyRank(key string) int {
+	switch {
+	case !strings.Contains(key, "/") && !strings.Contains(key, "anthropic."):
+		return 0
+	case strings.HasPrefix(key, "anthropic/") || strings.HasPrefix(key, "openai/"):
+		return 1
+	}
+	return 2
+}
+
+// lookupModel finds the pricing of model in prices: the exact key, else
+// the key the index resolves its normalized name to
+func lookupModel[T any](prices map[string]T, index modelIndex, model string) (T, bool) {
+	if pricing, exists := prices[model]; exists {
+		return pricing, true
+	}
+	if key, exists := index[normalizeModel(model)]; exists {
+		return prices[key], true
+	}
+	var none T
+	return none, false
+}
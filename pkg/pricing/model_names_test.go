@@ -0,0 +1,71 @@
+package pricing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeModel(t *testing.T) {
+	for model, want := range map[string]string{
+		"claude-sonnet-4-5-20250929":                           "claude-sonnet-4-5",
+		"anthropic/claude-sonnet-4-5-20250929":                 "claude-sonnet-4-5",
+		"openrouter/anthropic/claude-sonnet-4.5":               "claude-sonnet-4-5",
+		"bedrock/us.anthropic.claude-sonnet-4-5-20250929-v1:0": "claude-sonnet-4-5",
+		"vertex_ai/claude-sonnet-4-5@20250929":                 "claude-sonnet-4-5",
+		" Claude-3-5-Haiku-Latest ":                            "claude-3-5-haiku",
+		"claude-3.5-sonnet":                                    "claude-3-5-sonnet",
+		"gpt-4.1":                                              "gpt-4-1",
+		"gpt-5":                                                "gpt-5",
+	} {
+		assert.Equal(t, want, normalizeModel(model), model)
+	}
+}
+
+func TestLookupModel(t *testing.T) {
+	prices := map[string]ModelPricing{
+		"claude-sonnet-4-5":                          {InputCostPerToken: 1},
+		"claude-sonnet-4-5-20250929":                 {InputCostPerToken: 2},
+		"anthropic/claude-opus-4-1":                  {InputCostPerToken: 3},
+		"bedrock/eu.anthropic.claude-opus-4-1-v1:0":  {InputCostPerToken: 4},
+		"claude-3-5-sonnet-20240620":                 {InputCostPerToken: 5},
+		"claude-3-5-sonnet-20241022":                 {InputCostPerToken: 6},
+		"bedrock/us.anthropic.claude-haiku-4-5-v1:0": {InputCostPerToken: 7},
+	}
+	index := newModelIndex(prices)
+
+	for model, want := range map[string]float64{
+		"claude-sonnet-4-5":                    1, // Exact keys win
+		"claude-sonnet-4-5-20250929":           2,
+		"anthropic/claude-sonnet-4-5-20250929": 2, // Latest snapshot of the shared name
+		"claude-sonnet-4.5":                    2,
+		"claude-opus-4-1-20250805":             3, // anthropic/ before other providers
+		"claude-3-5-sonnet":                    6,
+		"claude-haiku-4-5":                     7,
+	} {
+		pricing, ok := lookupModel(prices, index, model)
+		if assert.True(t, ok, model) {
+			assert.Equal(t, want, pricing.InputCostPerToken, model)
+		}
+	}
+
+	_, ok := lookupModel(prices, index, "claude-opus-4-5")
+	assert.False(t, ok)
+}
+
+func TestModelPriceNormalizesNames(t *testing.T) {
+	s := newTestService(t, &fakeTransport{body: `{"claude-sonnet-4-5-20250929": {"input_cost_per_token": 0.5}}`})
+	ctx := context.Background()
+
+	pricing, source := s.ModelPrice(ctx, "anthropic/claude-sonnet-4-5")
+	assert.Equal(t, SourceLiteLLM, source)
+	assert.Equal(t, 0.5, pricing.InputCostPerToken)
+
+	// Offline, the embedded table resolves the same spellings
+	offline := useTransport(New(Options{Offline: true}), &fakeTransport{})
+	offline.diskPath = ""
+	pricing, source = offline.ModelPrice(ctx, "bedrock/us.anthropic.claude-opus-4-1-20250805-v1:0")
+	assert.Equal(t, SourceEmbedded, source)
+	assert.Equal(t, 15.0, PerMTok(pricing.InputCostPerToken))
+}
@@ -0,0 +1,134 @@
+package pricing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Backend is the kind of pricing data a Service fetches
+type Backend string
+
+const (
+	BackendLiteLLM    Backend = "litellm"    // LiteLLM's model_prices_and_context_window.json
+	BackendOpenRouter Backend = "openrouter" // OpenRouter's model list API, for traffic billed by OpenRouter
+)
+
+// OpenRouterURL is OpenRouter's model list, fetched by BackendOpenRouter
+// unless another URL is set
+const OpenRouterURL = "https://openrouter.ai/api/v1/models"
+
+// ParseBackend validates a backend name ("" means BackendLiteLLM)
+func ParseBackend(name string) (Backend, error) {
+	switch backend := Backend(name); backend {
+	case "":
+		return BackendLiteLLM, nil
+	case BackendLiteLLM, BackendOpenRouter:
+		return backend, nil
+	}
+	return "", fmt.Errorf("invalid pricing source %q, use litellm or openrouter", name)
+}
+
+// defaultURL returns where the backend's pricing is fetched from by default
+func (b Backend) defaultURL() string {
+	if b == BackendOpenRouter {
+		return OpenRouterURL
+	}
+	return DefaultSourceURL
+}
+
+// source returns the Source of the prices the backend fetched
+func (b Backend) source() Source {
+	if b == BackendOpenRouter {
+		return SourceOpenRouter
+	}
+	return SourceLiteLLM
+}
+
+// title names the backend in messages
+func (b Backend) title() string {
+	if b == BackendOpenRouter {
+		return "OpenRouter"
+	}
+	return "LiteLLM"
+}
+
+// decode converts fetched data to LiteLLM's format, also returning what the
+// disk cache saves: the data itself, or its conversion to LiteLLM's format
+func (b Backend) decode(data []byte) (LiteLLMResponse, []byte, error) {
+	if b != BackendOpenRouter {
+		response, _, err := DecodeLiteLLM(data)
+		return response, data, err
+	}
+	response, _, err := DecodeOpenRouter(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	converted, err := json.Marshal(response)
+	return response, converted, err
+}
+
+// openRouterModels is the response of OpenRouter's model list API. Prices
+// are in dollars per token, as decimal strings.
+type openRouterModels struct {
+	Data []struct {
+		ID      string `json:"id"`
+		Pricing struct {
+			Prompt          string `json:"prompt"`
+			Completion      string `json:"completion"`
+			InputCacheRead  string `json:"input_cache_read"`
+			InputCacheWrite string `json:"input_cache_write"`
+		} `json:"pricing"`
+	} `json:"data"`
+}
+
+// DecodeOpenRouter converts OpenRouter's model list to LiteLLM's format,
+// under OpenRouter's model IDs such as "anthropic/claude-sonnet-4.5" (which
+// match "claude-sonnet-4-5-20250929", see the package documentation). Like
+// DecodeLiteLLM it skips models whose prices are not numbers or are
+// negative, such as routers priced per request ("-1"), naming them in
+// skipped.
+func DecodeOpenRouter(data []byte) (response LiteLLMResponse, skipped []string, err error) {
+	var models openRouterModels
+	if err := json.Unmarshal(data, &models); err != nil {
+		return nil, nil, fmt.Errorf("invalid OpenRouter pricing: %w", err)
+	}
+
+	response = make(LiteLLMResponse, len(models.Data))
+	for _, model := range models.Data {
+		if model.ID == "" {
+			continue
+		}
+		var pricing ModelPricing
+		var invalid bool
+		for _, field := range []struct {
+			value string
+			rate  *float64
+		}{
+			{model.Pricing.Prompt, &pricing.InputCostPerToken},
+			{model.Pricing.Completion, &pricing.OutputCostPerToken},
+			{model.Pricing.InputCacheRead, &pricing.CacheReadInputTokenCost},
+			{model.Pricing.InputCacheWrite, &pricing.CacheCreationInputTokenCost},
+		} {
+			if field.value == "" {
+				continue // Not charged separately
+			}
+			rate, err := strconv.ParseFloat(field.value, 64)
+			if err != nil || rate < 0 {
+				invalid = true
+				break
+			}
+			*field.rate = rate
+		}
+		if invalid {
+			skipped = append(skipped, model.ID)
+			continue
+		}
+		response[model.ID] = pricing
+	}
+	if len(response) == 0 {
+		return nil, skipped, errors.New("invalid OpenRouter pricing: no model has valid rates")
+	}
+	return response, skipped, nil
+}
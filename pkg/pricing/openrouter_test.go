@@ -0,0 +1,73 @@
+package pricing
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testdata/openrouter_models.json is an excerpt of OpenRouter's
+// /api/v1/models response
+func TestDecodeOpenRouter(t *testing.T) {
+	data, err := os.ReadFile("testdata/openrouter_models.json")
+	require.NoError(t, err)
+
+	response, skipped, err := DecodeOpenRouter(data)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"openrouter/auto"}, skipped)
+	assert.Len(t, response, 4)
+	assert.Equal(t, ModelPricing{
+		InputCostPerToken:           0.000003,
+		OutputCostPerToken:          0.000015,
+		CacheCreationInputTokenCost: 0.00000375,
+		CacheReadInputTokenCost:     0.0000003,
+	}, response["anthropic/claude-sonnet-4.5"])
+	assert.Zero(t, response["openai/gpt-5"].CacheCreationInputTokenCost)
+	assert.Zero(t, response["meta-llama/llama-3.3-70b-instruct:free"].InputCostPerToken)
+
+	for _, invalid := range []string{`[]`, `{"data": []}`, `{"data": [{"id": "x", "pricing": {"prompt": "n/a"}}]}`} {
+		_, _, err := DecodeOpenRouter([]byte(invalid))
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestOpenRouterBackend(t *testing.T) {
+	data, err := os.ReadFile("testdata/openrouter_models.json")
+	require.NoError(t, err)
+	setTestHome(t)
+	transport := &fakeTransport{body: string(data)}
+	s := useTransport(New(Options{Backend: BackendOpenRouter, Transport: transport}), transport)
+	ctx := context.Background()
+
+	// Claude Code's model IDs match OpenRouter's
+	pricing, source := s.ModelPrice(ctx, "claude-sonnet-4-5-20250929")
+	assert.Equal(t, SourceOpenRouter, source)
+	assert.Equal(t, 0.000003, pricing.InputCostPerToken)
+	_, source = s.ModelPrice(ctx, "claude-opus-4-1-20250805")
+	assert.Equal(t, SourceOpenRouter, source)
+	assert.Equal(t, OpenRouterURL, transport.url)
+
+	// The on-disk copy, in LiteLLM's format, serves the next run
+	next := useTransport(New(Options{Backend: BackendOpenRouter}), transport)
+	pricing, source = next.ModelPrice(ctx, "claude-sonnet-4-5")
+	assert.Equal(t, SourceOpenRouter, source)
+	assert.Equal(t, 0.0000003, pricing.CacheReadInputTokenCost)
+	assert.Equal(t, 1, transport.requests)
+	defaultPath, err := DefaultCachePath()
+	require.NoError(t, err)
+	assert.NotEqual(t, defaultPath, next.diskPath)
+}
+
+func TestParseBackend(t *testing.T) {
+	backend, err := ParseBackend("")
+	require.NoError(t, err)
+	assert.Equal(t, BackendLiteLLM, backend)
+	backend, err = ParseBackend("openrouter")
+	require.NoError(t, err)
+	assert.Equal(t, BackendOpenRouter, backend)
+	_, err = ParseBackend("anthropic")
+	assert.Error(t, err)
+}
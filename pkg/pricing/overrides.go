@@ -0,0 +1,135 @@
+package pricing
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// Overrides is the pricing of a pricing file (--pricing-file)
+type Overrides struct {
+	Prices  map[string]ModelPricing   // Rates that always apply
+	History map[string][]DatedPricing // Rates of date ranges, sorted and disjoint
+}
+
+// DatedPricing is the pricing of a model in effect from From (inclusive)
+// until Until (exclusive); a zero time leaves that end open
+type DatedPricing struct {
+	From    time.Time
+	Until   time.Time
+	Pricing ModelPricing
+}
+
+// datedEntry is a DatedPricing in a pricing file, dates as YYYY-MM-DD (UTC)
+type datedEntry struct {
+	ModelPricing
+	EffectiveFrom  string `json:"effective_from"`
+	EffectiveUntil string `json:"effective_until"`
+}
+
+// LoadOverrides reads a pricing file (--pricing-file): a JSON object mapping
+// model names to per-token rates in LiteLLM's format, e.g.
+// {"claude-sonnet-4-5-20250929": {"input_cost_per_token": 0.0000025, "output_cost_per_token": 0.0000125}}.
+// Other LiteLLM fields are ignored, so entries can be copied from its data.
+// A model may instead map to a list of rates with "effective_from" and
+// "effective_until" dates, for rates that changed; usage outside all of
+// its ranges gets the fetched or embedded pricing.
+func LoadOverrides(path string) (Overrides, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Overrides{}, fmt.Errorf("failed to read pricing file: %w", err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return Overrides{}, fmt.Errorf("invalid pricing file %s: %w", path, err)
+	}
+
+	loaded := Overrides{Prices: make(map[string]ModelPricing), History: make(map[string][]DatedPricing)}
+	for model, value := range raw {
+		if !bytes.HasPrefix(bytes.TrimSpace(value), []byte("[")) {
+			var p ModelPricing
+			if err := json.Unmarshal(value, &p); err != nil {
+				return Overrides{}, fmt.Errorf("invalid pricing file %s: %s: %w", path, model, err)
+			}
+			if negativeRate(p) {
+				return Overrides{}, fmt.Errorf("invalid pricing file %s: %s has a negative rate", path, model)
+			}
+			loaded.Prices[model] = p
+			continue
+		}
+
+		history, err := parseHistory(value)
+		if err != nil {
+			return Overrides{}, fmt.Errorf("invalid pricing file %s: %s: %w", path, model, err)
+		}
+		loaded.History[model] = history
+	}
+	return loaded, nil
+}
+
+// parseHistory reads the dated rates of a model, sorted by start
+func parseHistory(value json.RawMessage) ([]DatedPricing, error) {
+	var entries []datedEntry
+	if err := json.Unmarshal(value, &entries); err != nil {
+		return nil, err
+	}
+	history := make([]DatedPricing, 0, len(entries))
+	for _, entry := range entries {
+		if negativeRate(entry.ModelPricing) {
+			return nil, errors.New("negative rate")
+		}
+		dated := DatedPricing{Pricing: entry.ModelPricing}
+		var err error
+		if dated.From, err = parseEffectiveDate(entry.EffectiveFrom); err != nil {
+			return nil, err
+		}
+		if dated.Until, err = parseEffectiveDate(entry.EffectiveUntil); err != nil {
+			return nil, err
+		}
+		if !dated.From.IsZero() && !dated.Until.IsZero() && !dated.From.Before(dated.Until) {
+			return nil, fmt.Errorf("effective_from %s is not before effective_until %s", entry.EffectiveFrom, entry.EffectiveUntil)
+		}
+		history = append(history, dated)
+	}
+
+	sort.Slice(history, func(i, j int) bool { return history[i].From.Before(history[j].From) })
+	for i := 1; i < len(history); i++ {
+		if previous := history[i-1].Until; previous.IsZero() || previous.After(history[i].From) {
+			return nil, errors.New("date ranges overlap")
+		}
+	}
+	return history, nil
+}
+
+// parseEffectiveDate parses a YYYY-MM-DD date as UTC midnight ("" is zero)
+func parseEffectiveDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD", value)
+	}
+	return date, nil
+}
+
+func negativeRate(p ModelPricing) bool {
+	return p.InputCostPerToken < 0 || p.OutputCostPerToken < 0 || p.CacheCreationInputTokenCost < 0 || p.CacheReadInputTokenCost < 0 ||
+		p.CacheCreationInputTokenCostAbove1Hr < 0 ||
+		p.InputCostPerTokenAbove200K < 0 || p.OutputCostPerTokenAbove200K < 0 ||
+		p.CacheCreationInputTokenCostAbove200K < 0 || p.CacheReadInputTokenCostAbove200K < 0
+}
+
+// pricingAt returns the pricing of history in effect at t
+func pricingAt(history []DatedPricing, t time.Time) (ModelPricing, bool) {
+	for _, dated := range history {
+		if (dated.From.IsZero() || !t.Before(dated.From)) && (dated.Until.IsZero() || t.Before(dated.Until)) {
+			return dated.Pricing, true
+		}
+	}
+	return ModelPricing{}, false
+}
@@ -0,0 +1,477 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTransport answers every request with body, or fails when body is empty,
+// counting the requests. The first len(statuses) requests get those status
+// codes instead.
+type fakeTransport struct {
+	body     string
+	statuses []int
+	requests int
+	url      string // Of the last request
+}
+
+func (t *fakeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	t.url = req.URL.String()
+	status := http.StatusOK
+	if t.requests <= len(t.statuses) {
+		status = t.statuses[t.requests-1]
+	} else if t.body == "" {
+		return nil, errors.New("no network")
+	}
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(strings.NewReader(t.body)),
+		Request:    req,
+	}, nil
+}
+
+// newTestService returns a service saving its pricing under a temporary
+// home directory, see useTransport
+func newTestService(t *testing.T, transport *fakeTransport) *Service {
+	t.Helper()
+	return newTestServiceWith(t, Options{}, transport)
+}
+
+// newTestServiceWith is newTestService configured by opts
+func newTestServiceWith(t *testing.T, opts Options, transport *fakeTransport) *Service {
+	t.Helper()
+	setTestHome(t)
+	return useTransport(New(opts), transport)
+}
+
+// setTestHome points the home and cache directories to a temporary directory
+func setTestHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, ".cache"))
+}
+
+// useTransport makes s fetch through transport with a short retry backoff,
+// discarding warnings
+func useTransport(s *Service, transport *fakeTransport) *Service {
+	s.client.Transport = transport
+	s.retryDelay = time.Millisecond
+	s.warnings = io.Discard
+	return s
+}
+
+const testPricing = `{"test-model": {"input_cost_per_token": 0.5, "output_cost_per_token": 2}}`
+
+func TestOfflineSkipsFetch(t *testing.T) {
+	transport := &fakeTransport{body: testPricing}
+	s := newTestServiceWith(t, Options{Offline: true}, transport)
+
+	input, output, _, _, err := s.GetModelPrice(context.Background(), "claude-sonnet-4-5-20250929")
+	require.NoError(t, err)
+	assert.Equal(t, 0.000003, input)
+	assert.Equal(t, 0.000015, output)
+
+	_, _, _, _, ok := s.GetLongContextPrice(context.Background(), "claude-sonnet-4-5-20250929")
+	assert.True(t, ok)
+	assert.Zero(t, transport.requests)
+}
+
+func TestDiskCache(t *testing.T) {
+	transport := &fakeTransport{body: testPricing}
+	s := newTestService(t, transport)
+	path := s.diskPath
+	assert.Equal(t, filepath.Join(os.Getenv("HOME"), ".cache", "ccusage", "pricing.json"), path)
+
+	// A fetch saves the pricing for later runs
+	input, _, _, _, err := s.GetModelPrice(context.Background(), "test-model")
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, input)
+	assert.Equal(t, 1, transport.requests)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, testPricing, string(data))
+
+	// A later run within the TTL reads it instead of fetching
+	transport = &fakeTransport{}
+	next := useTransport(NewService(), transport)
+	_, output, _, _, err := next.GetModelPrice(context.Background(), "test-model")
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, output)
+	assert.Zero(t, transport.requests)
+
+	// An outdated copy is used when LiteLLM cannot be reached
+	old := time.Now().Add(-48 * time.Hour)
+	require.NoError(t, os.Chtimes(path, old, old))
+	stale := useTransport(NewService(), transport)
+	input, _, _, _, err = stale.GetModelPrice(context.Background(), "test-model")
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, input)
+	assert.Equal(t, fetchAttempts, transport.requests)
+
+	// and in offline mode, without trying
+	offline := useTransport(New(Options{Offline: true}), transport)
+	input, _, _, _, err = offline.GetModelPrice(context.Background(), "test-model")
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, input)
+	assert.Equal(t, fetchAttempts, transport.requests)
+}
+
+func TestDiskCacheIgnoresInvalidFile(t *testing.T) {
+	transport := &fakeTransport{body: testPricing}
+	s := newTestService(t, transport)
+	require.NoError(t, os.MkdirAll(filepath.Dir(s.diskPath), 0o755))
+	require.NoError(t, os.WriteFile(s.diskPath, []byte("{"), 0o644))
+
+	input, _, _, _, err := s.GetModelPrice(context.Background(), "test-model")
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, input)
+	assert.Equal(t, 1, transport.requests)
+}
+
+func TestOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"claude-sonnet-4-5-20250929": {"input_cost_per_token": 0.0000025, "output_cost_per_token": 0.0000125, "litellm_provider": "anthropic"},
+		"in-house-model": {"input_cost_per_token": 0.000001, "output_cost_per_token": 0.000002,
+			"input_cost_per_token_above_200k_tokens": 0.000004}
+	}`), 0o644))
+	pricing, err := LoadOverrides(path)
+	require.NoError(t, err)
+
+	transport := &fakeTransport{}
+	s := newTestServiceWith(t, Options{Overrides: pricing}, transport)
+	ctx := context.Background()
+
+	// Negotiated rates replace the model's pricing, long-context tier included
+	input, output, cacheCreate, _, err := s.GetModelPrice(ctx, "claude-sonnet-4-5-20250929")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0.0000025, 0.0000125, 0}, []float64{input, output, cacheCreate})
+	_, _, _, _, ok := s.GetLongContextPrice(ctx, "claude-sonnet-4-5-20250929")
+	assert.False(t, ok)
+
+	// Unlisted models are priced
+	input, _, _, _, err = s.GetModelPrice(ctx, "in-house-model")
+	require.NoError(t, err)
+	assert.Equal(t, 0.000001, input)
+	input, output, _, _, ok = s.GetLongContextPrice(ctx, "in-house-model")
+	assert.True(t, ok)
+	assert.Equal(t, []float64{0.000004, 0.000002}, []float64{input, output})
+	assert.Zero(t, transport.requests)
+
+	// Other models keep the usual pricing
+	input, _, _, _, err = s.GetModelPrice(ctx, "claude-haiku-4-5-20251001")
+	require.NoError(t, err)
+	assert.Equal(t, 0.000001, input)
+}
+
+func TestLoadOverridesRejectsInvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"syntax.json":         `{"model": `,
+		"negative.json":       `{"model": {"input_cost_per_token": -1}}`,
+		"dated-negative.json": `{"model": [{"input_cost_per_token": -1}]}`,
+		"bad-date.json":       `{"model": [{"effective_from": "2025/10/01", "input_cost_per_token": 1}]}`,
+		"inverted.json":       `{"model": [{"effective_from": "2025-10-01", "effective_until": "2025-09-01"}]}`,
+		"overlap.json": `{"model": [{"effective_until": "2025-10-02", "input_cost_per_token": 1},
+			{"effective_from": "2025-10-01", "input_cost_per_token": 2}]}`,
+		"open-overlap.json": `{"model": [{"input_cost_per_token": 1}, {"effective_from": "2025-10-01", "input_cost_per_token": 2}]}`,
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		_, err := LoadOverrides(path)
+		assert.Error(t, err, name)
+	}
+	_, err := LoadOverrides(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestPriceHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"claude-opus-4-1": [
+			{"effective_from": "2025-11-01", "input_cost_per_token": 0.00001},
+			{"effective_until": "2025-11-01", "input_cost_per_token": 0.00002}
+		],
+		"in-house-model": [{"effective_from": "2025-06-01", "effective_until": "2025-07-01", "input_cost_per_token": 0.000004}]
+	}`), 0o644))
+	loaded, err := LoadOverrides(path)
+	require.NoError(t, err)
+	require.Len(t, loaded.History["claude-opus-4-1"], 2)
+	assert.True(t, loaded.History["claude-opus-4-1"][0].From.IsZero(), "sorted by start")
+
+	s := newTestServiceWith(t, Options{Overrides: loaded}, &fakeTransport{})
+	ctx := context.Background()
+	day := func(date string) time.Time {
+		parsed, err := time.Parse("2006-01-02 15:04", date)
+		require.NoError(t, err)
+		return parsed
+	}
+
+	// Each request gets the rates in effect then, matched across spellings
+	input, _, _, _, err := s.GetModelPriceAt(ctx, "claude-opus-4-1-20250805", day("2025-10-31 23:59"))
+	require.NoError(t, err)
+	assert.Equal(t, 0.00002, input)
+	input, _, _, _, err = s.GetModelPriceAt(ctx, "claude-opus-4-1-20250805", day("2025-11-01 00:00"))
+	require.NoError(t, err)
+	assert.Equal(t, 0.00001, input)
+
+	// Outside every range the usual pricing applies
+	pricing, source := s.ModelPriceAt(ctx, "in-house-model", day("2025-06-15 12:00"))
+	assert.Equal(t, SourceOverride, source)
+	assert.Equal(t, 0.000004, pricing.InputCostPerToken)
+	_, source = s.ModelPriceAt(ctx, "in-house-model", day("2025-07-01 00:00"))
+	assert.Equal(t, SourceDefault, source)
+	assert.False(t, s.IsDefaultPriced(ctx, "in-house-model"))
+	assert.Contains(t, s.KnownModels(), "in-house-model")
+
+	// --price-as-of replaces every request's time
+	asOf := useTransport(New(Options{Overrides: loaded, AsOf: day("2025-10-01 00:00")}), &fakeTransport{})
+	input, _, _, _, err = asOf.GetModelPriceAt(ctx, "claude-opus-4-1", day("2025-12-01 00:00"))
+	require.NoError(t, err)
+	assert.Equal(t, 0.00002, input)
+}
+
+func TestModelPriceSources(t *testing.T) {
+	overrides := Overrides{Prices: map[string]ModelPricing{"in-house-model": {InputCostPerToken: 0.000001}}}
+	s := newTestServiceWith(t, Options{Overrides: overrides}, &fakeTransport{body: testPricing})
+	ctx := context.Background()
+
+	for model, want := range map[string]Source{
+		"in-house-model":            SourceOverride,
+		"test-model":                SourceLiteLLM,
+		"claude-haiku-4-5-20251001": SourceEmbedded,
+		"unknown-model":             SourceDefault,
+	} {
+		_, source := s.ModelPrice(ctx, model)
+		assert.Equal(t, want, source, model)
+	}
+
+	models := s.KnownModels()
+	assert.Contains(t, models, "in-house-model")
+	assert.Contains(t, models, "claude-haiku-4-5-20251001")
+	assert.NotContains(t, models, "test-model")
+}
+
+func TestFetchRetries(t *testing.T) {
+	ctx := context.Background()
+
+	// Transient failures are retried
+	transport := &fakeTransport{body: testPricing, statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}}
+	s := newTestService(t, transport)
+	_, source := s.ModelPrice(ctx, "test-model")
+	assert.Equal(t, SourceLiteLLM, source)
+	assert.Equal(t, 3, transport.requests)
+
+	// A missing file is not
+	transport = &fakeTransport{body: testPricing, statuses: []int{http.StatusNotFound}}
+	s = newTestService(t, transport)
+	_, source = s.ModelPrice(ctx, "test-model")
+	assert.Equal(t, SourceDefault, source)
+	assert.Equal(t, 1, transport.requests)
+}
+
+func TestFetchFailureIsRemembered(t *testing.T) {
+	transport := &fakeTransport{}
+	s := newTestService(t, transport)
+	var warnings strings.Builder
+	s.warnings = &warnings
+	ctx := context.Background()
+
+	_, source := s.ModelPrice(ctx, "claude-haiku-4-5-20251001")
+	assert.Equal(t, SourceEmbedded, source)
+	_, source = s.ModelPrice(ctx, "unknown-model")
+	assert.Equal(t, SourceDefault, source)
+
+	// One failed fetch, reported once, serves the run
+	assert.Equal(t, fetchAttempts, transport.requests)
+	assert.Equal(t, 1, strings.Count(warnings.String(), "Warning: failed to fetch LiteLLM pricing, using embedded prices"))
+	assert.Contains(t, warnings.String(), "after 3 attempts")
+}
+
+func TestPerMTok(t *testing.T) {
+	assert.Equal(t, 3.75, PerMTok(0.00000375))
+	assert.Equal(t, 0.03, PerMTok(0.00000003))
+	assert.Equal(t, 22.5, PerMTok(0.0000225))
+}
+
+func TestEmbeddedPricing(t *testing.T) {
+	for model, want := range map[string][2]float64{
+		"claude-opus-4-5-20251101":   {5, 25},
+		"claude-opus-4-1-20250805":   {15, 75},
+		"claude-sonnet-4-5-20250929": {3, 15},
+		"claude-sonnet-4-20250514":   {3, 15},
+		"claude-haiku-4-5-20251001":  {1, 5},
+		"gpt-5-codex":                {1.25, 10},
+	} {
+		pricing, ok := embeddedPricing[model]
+		require.True(t, ok, model)
+		assert.Equal(t, want, [2]float64{PerMTok(pricing.InputCostPerToken), PerMTok(pricing.OutputCostPerToken)}, model)
+	}
+
+	opus := embeddedPricing["claude-opus-4-1-20250805"]
+	assert.Equal(t, 18.75, PerMTok(opus.CacheCreationInputTokenCost))
+	assert.Equal(t, 1.5, PerMTok(opus.CacheReadInputTokenCost))
+	_, ok := embeddedPricing["claude-sonnet-4-5-20250929"].LongContext()
+	assert.True(t, ok)
+
+	s := newTestService(t, &fakeTransport{})
+	s.offline = true
+	hour, ok := s.GetCacheWrite1hPriceAt(context.Background(), "claude-sonnet-4-5-20250929", time.Time{})
+	assert.True(t, ok)
+	assert.Equal(t, 6.0, PerMTok(hour))
+	_, ok = s.GetCacheWrite1hPriceAt(context.Background(), "gpt-5", time.Time{})
+	assert.False(t, ok)
+}
+
+func TestStrictPricing(t *testing.T) {
+	s := newTestServiceWith(t, Options{Offline: true, Strict: true}, &fakeTransport{})
+	ctx := context.Background()
+
+	input, _, _, _, err := s.GetModelPrice(ctx, "claude-sonnet-4-5-20250929")
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, PerMTok(input))
+
+	input, _, _, _, err = s.GetModelPrice(ctx, "strict-unknown-model")
+	var unknown *UnknownModelError
+	require.ErrorAs(t, err, &unknown)
+	assert.Equal(t, "strict-unknown-model", unknown.Model)
+	assert.Zero(t, input)
+	assert.Equal(t, []string{"strict-unknown-model"}, s.UnknownModels())
+
+	// Each service reports its own unknown models
+	other := newTestServiceWith(t, Options{Offline: true}, &fakeTransport{})
+	assert.Empty(t, other.UnknownModels())
+	_, _, _, _, err = other.GetModelPrice(ctx, "other-unknown-model")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"other-unknown-model"}, other.UnknownModels())
+	assert.Equal(t, []string{"strict-unknown-model"}, s.UnknownModels())
+
+	// ModelPrice still reports the default rates, e.g. for pricing list
+	_, source := s.ModelPrice(ctx, "strict-unknown-model")
+	assert.Equal(t, SourceDefault, source)
+}
+
+func TestNewOptions(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "litellm.json")
+	transport := &fakeTransport{body: testPricing}
+	s := New(Options{
+		SourceURL: "https://mirror.example.com/prices.json",
+		CachePath: path,
+		Transport: transport,
+		Overrides: Overrides{Prices: map[string]ModelPricing{"my-model": {InputCostPerToken: 0.25}}},
+		Strict:    true,
+		Warnings:  io.Discard,
+	})
+
+	pricing, source := s.ModelPrice(ctx, "test-model")
+	assert.Equal(t, SourceLiteLLM, source)
+	assert.Equal(t, 0.5, pricing.InputCostPerToken)
+	assert.Equal(t, "https://mirror.example.com/prices.json", transport.url)
+	assert.FileExists(t, path)
+
+	pricing, source = s.ModelPrice(ctx, "my-model")
+	assert.Equal(t, SourceOverride, source)
+	assert.Equal(t, 0.25, pricing.InputCostPerToken)
+
+	_, _, _, _, err := s.GetModelPrice(ctx, "unknown-model")
+	var unknown *UnknownModelError
+	assert.ErrorAs(t, err, &unknown)
+
+	memoryOnly := New(Options{NoDiskCache: true, Transport: transport, Warnings: io.Discard})
+	assert.False(t, memoryOnly.offline)
+	assert.Empty(t, memoryOnly.diskPath)
+	assert.Equal(t, DefaultSourceURL, memoryOnly.url)
+}
+
+func TestRefresh(t *testing.T) {
+	ctx := context.Background()
+	transport := &fakeTransport{body: testPricing}
+	s := newTestService(t, transport)
+
+	// Refresh fetches even while the cached pricing is fresh
+	_, source := s.ModelPrice(ctx, "test-model")
+	assert.Equal(t, SourceLiteLLM, source)
+	transport.body = `{"test-model": {"input_cost_per_token": 0.75}}`
+	require.NoError(t, s.Refresh(ctx))
+	assert.Equal(t, 2, transport.requests)
+	pricing, _ := s.ModelPrice(ctx, "test-model")
+	assert.Equal(t, 0.75, pricing.InputCostPerToken)
+	data, err := os.ReadFile(s.diskPath)
+	require.NoError(t, err)
+	assert.JSONEq(t, transport.body, string(data))
+
+	// A failed refresh keeps the pricing loaded before
+	transport.body = ""
+	assert.Error(t, s.Refresh(ctx))
+	pricing, _ = s.ModelPrice(ctx, "test-model")
+	assert.Equal(t, 0.75, pricing.InputCostPerToken)
+
+	offline := New(Options{Offline: true, NoDiskCache: true})
+	assert.Error(t, offline.Refresh(ctx))
+}
+
+func TestWarm(t *testing.T) {
+	transport := &fakeTransport{body: testPricing}
+	s := newTestService(t, transport)
+	ctx := context.Background()
+
+	s.Warm(ctx)
+	// Lookups racing the warm-up wait for it instead of fetching again
+	done := make(chan Source)
+	for i := 0; i < 4; i++ {
+		go func() {
+			_, source := s.ModelPrice(ctx, "test-model")
+			done <- source
+		}()
+	}
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, SourceLiteLLM, <-done)
+	}
+	assert.Equal(t, 1, transport.requests)
+}
+
+func TestResolvedPricesAreMemoized(t *testing.T) {
+	transport := &fakeTransport{body: testPricing}
+	s := newTestService(t, transport)
+	ctx := context.Background()
+
+	_, source := s.ModelPrice(ctx, "test-model")
+	assert.Equal(t, SourceLiteLLM, source)
+	_, source = s.ModelPrice(ctx, "claude-haiku-4-5-20251001")
+	assert.Equal(t, SourceEmbedded, source)
+	table := s.table.Load()
+	_, memoized := table.resolved.Load("claude-haiku-4-5-20251001")
+	assert.True(t, memoized)
+
+	// A refresh starts over with the new pricing
+	transport.body = `{"claude-haiku-4-5-20251001": {"input_cost_per_token": 0.25}}`
+	require.NoError(t, s.Refresh(ctx))
+	pricing, source := s.ModelPrice(ctx, "claude-haiku-4-5-20251001")
+	assert.Equal(t, SourceLiteLLM, source)
+	assert.Equal(t, 0.25, pricing.InputCostPerToken)
+}
+
+func TestOfflineWithoutCacheDoesNotRetry(t *testing.T) {
+	s := New(Options{Offline: true, NoDiskCache: true})
+	ctx := context.Background()
+	_, source := s.ModelPrice(ctx, "claude-haiku-4-5-20251001")
+	assert.Equal(t, SourceEmbedded, source)
+
+	// The empty table serves later lookups until it expires
+	table := s.table.Load()
+	require.NotNil(t, table)
+	s.ModelPrice(ctx, "unknown-model")
+	assert.Same(t, table, s.table.Load())
+}
@@ -0,0 +1,46 @@
+package pricing
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"path/filepath"
+	"regexp"
+)
+
+// DefaultSourceURL is where LiteLLM pricing is fetched from unless Options.SourceURL changes it
+const DefaultSourceURL = "https://raw.githubusercontent.com/BerriAI/litellm/main/model_prices_and_context_window.json"
+
+var commitPattern = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// CommitSourceURL returns the URL of the LiteLLM pricing at a git commit, for
+// reproducible cost reports
+func CommitSourceURL(commit string) (string, error) {
+	if !commitPattern.MatchString(commit) {
+		return "", fmt.Errorf("invalid LiteLLM commit %q, use a commit hash (7-40 hex digits)", commit)
+	}
+	return "https://raw.githubusercontent.com/BerriAI/litellm/" + commit + "/model_prices_and_context_window.json", nil
+}
+
+// ValidateSourceURL returns an error unless rawURL is an http(s) URL that
+// Options.SourceURL can fetch pricing from, e.g. an internal mirror
+func ValidateSourceURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid pricing URL %q, use an http(s) URL", rawURL)
+	}
+	return nil
+}
+
+// sourceCachePath returns the on-disk cache file of the pricing at rawURL:
+// pricing.json for DefaultSourceURL, else a file named after the URL's hash
+// so that sources never share a copy
+func sourceCachePath(rawURL string) (string, error) {
+	path, err := DefaultCachePath()
+	if err != nil || rawURL == DefaultSourceURL {
+		return path, err
+	}
+	h := fnv.New64a()
+	h.Write([]byte(rawURL))
+	return filepath.Join(filepath.Dir(path), fmt.Sprintf("pricing-%016x.json", h.Sum64())), nil
+}
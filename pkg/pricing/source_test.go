@@ -0,0 +1,60 @@
+package pricing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitSourceURL(t *testing.T) {
+	u, err := CommitSourceURL("0123abcd")
+	require.NoError(t, err)
+	assert.Equal(t, "https://raw.githubusercontent.com/BerriAI/litellm/0123abcd/model_prices_and_context_window.json", u)
+
+	for _, invalid := range []string{"main", "0123", "../../x", "0123ABCD"} {
+		_, err := CommitSourceURL(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestSourceURL(t *testing.T) {
+	for _, invalid := range []string{"mirror/prices.json", "ftp://mirror/prices.json", "https://"} {
+		assert.Error(t, ValidateSourceURL(invalid), invalid)
+	}
+
+	const mirror = "https://mirror.example.com/litellm/prices.json"
+	require.NoError(t, ValidateSourceURL(mirror))
+	transport := &fakeTransport{body: testPricing}
+	s := newTestServiceWith(t, Options{SourceURL: mirror}, transport)
+	_, source := s.ModelPrice(context.Background(), "test-model")
+	assert.Equal(t, SourceLiteLLM, source)
+	assert.Equal(t, mirror, transport.url)
+
+	// Each source keeps its own on-disk copy
+	defaultPath, err := DefaultCachePath()
+	require.NoError(t, err)
+	assert.NotEqual(t, defaultPath, s.diskPath)
+	assert.FileExists(t, s.diskPath)
+	assert.NoFileExists(t, defaultPath)
+}
+
+func TestPinnedSourceNeverExpires(t *testing.T) {
+	commitURL, err := CommitSourceURL("0123abcd")
+	require.NoError(t, err)
+
+	transport := &fakeTransport{body: testPricing}
+	s := newTestServiceWith(t, Options{SourceURL: commitURL, Pinned: true}, transport)
+	require.NoError(t, os.MkdirAll(filepath.Dir(s.diskPath), 0o755))
+	require.NoError(t, os.WriteFile(s.diskPath, []byte(testPricing), 0o644))
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	require.NoError(t, os.Chtimes(s.diskPath, old, old))
+
+	_, source := s.ModelPrice(context.Background(), "test-model")
+	assert.Equal(t, SourceLiteLLM, source)
+	assert.Zero(t, transport.requests)
+}
@@ -0,0 +1,127 @@
+package pricing
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Load describes where the pricing table of a Service was loaded from
+type Load string
+
+const (
+	LoadNone      Load = ""           // Not loaded yet
+	LoadDiskCache Load = "disk_cache" // The on-disk copy, within the cache TTL or pinned
+	LoadFetched   Load = "fetched"    // Fetched during the run
+	LoadStale     Load = "stale_disk" // An outdated on-disk copy, offline or after a failed fetch
+	LoadEmpty     Load = "empty"      // Nothing: offline without a copy, or the fetch failed
+)
+
+// Stats describes how a Service priced models, for --debug and the
+// metadata of JSON reports
+type Stats struct {
+	Backend        Backend          `json:"backend"`
+	Load           Load             `json:"load"`             // Of the pricing table in use
+	DiskCacheHits  int              `json:"disk_cache_hits"`  // Loads served by the on-disk copy, fresh or stale
+	Fetches        int              `json:"fetches"`          // Fetches of the pricing data, retries not counted
+	FetchErrors    int              `json:"fetch_errors"`     // Among Fetches, the failed ones
+	FetchLatencyMs int64            `json:"fetch_latency_ms"` // Spent fetching, retries included
+	Lookups        int64            `json:"lookups"`          // Prices looked up
+	MemoHits       int64            `json:"memo_hits"`        // Among Lookups, those of a model already resolved
+	BySource       map[Source]int64 `json:"by_source"`        // Lookups per source; embedded and default are fallbacks
+	Models         []ModelSource    `json:"models"`           // The source that priced each model, sorted
+}
+
+// ModelSource is where the pricing of a model came from
+type ModelSource struct {
+	Model  string `json:"model"`
+	Source Source `json:"source"`
+}
+
+// serviceStats collects the Stats of a Service. Lookups are counted with
+// atomics, as they happen per request from concurrent callers.
+type serviceStats struct {
+	mux           sync.Mutex // Guards the load and fetch fields
+	load          Load
+	diskCacheHits int
+	fetches       int
+	fetchErrors   int
+	fetchLatency  time.Duration
+	lookups       atomic.Int64
+	memoHits      atomic.Int64
+	bySource      map[Source]*atomic.Int64 // Fixed at creation, read concurrently
+	models        sync.Map                 // model → Source
+	unknown       sync.Map                 // model → true, priced at the default rates by GetModelPriceAt
+}
+
+func newServiceStats() *serviceStats {
+	bySource := make(map[Source]*atomic.Int64)
+	for _, source := range []Source{SourceOverride, SourceLiteLLM, SourceOpenRouter, SourceEmbedded, SourceDefault} {
+		bySource[source] = new(atomic.Int64)
+	}
+	return &serviceStats{bySource: bySource}
+}
+
+// recordLookup counts a lookup of model priced by source
+func (st *serviceStats) recordLookup(model string, source Source, memoized bool) {
+	st.lookups.Add(1)
+	if memoized {
+		st.memoHits.Add(1)
+	}
+	st.bySource[source].Add(1)
+	if _, exists := st.models.Load(model); !exists {
+		st.models.Store(model, source)
+	}
+}
+
+// recordLoad records how the pricing table was loaded
+func (st *serviceStats) recordLoad(load Load) {
+	st.mux.Lock()
+	defer st.mux.Unlock()
+	st.load = load
+	if load == LoadDiskCache || load == LoadStale {
+		st.diskCacheHits++
+	}
+}
+
+// recordFetch records a fetch that took latency, failed when err is set
+func (st *serviceStats) recordFetch(latency time.Duration, err error) {
+	st.mux.Lock()
+	defer st.mux.Unlock()
+	st.fetches++
+	st.fetchLatency += latency
+	if err != nil {
+		st.fetchErrors++
+	}
+}
+
+// Stats returns how the service priced models so far
+func (s *Service) Stats() Stats {
+	st := s.stats
+	st.mux.Lock()
+	stats := Stats{
+		Backend:        s.backend,
+		Load:           st.load,
+		DiskCacheHits:  st.diskCacheHits,
+		Fetches:        st.fetches,
+		FetchErrors:    st.fetchErrors,
+		FetchLatencyMs: st.fetchLatency.Milliseconds(),
+	}
+	st.mux.Unlock()
+
+	stats.Lookups = st.lookups.Load()
+	stats.MemoHits = st.memoHits.Load()
+	stats.BySource = make(map[Source]int64)
+	for source, count := range st.bySource {
+		if n := count.Load(); n > 0 {
+			stats.BySource[source] = n
+		}
+	}
+	st.models.Range(func(model, source any) bool {
+		stats.Models = append(stats.Models, ModelSource{Model: model.(string), Source: source.(Source)})
+		return true
+	})
+	sort.Slice(stats.Models, func(i, j int) bool { return stats.Models[i].Model < stats.Models[j].Model })
+	return stats
+}
@@ -0,0 +1,47 @@
+package pricing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	transport := &fakeTransport{body: `{"test-model": {"input_cost_per_token": 0.5, "output_cost_per_token": 2}}`}
+	s := newTestService(t, transport)
+	ctx := context.Background()
+	assert.Equal(t, LoadNone, s.Stats().Load)
+
+	s.ModelPrice(ctx, "test-model")
+	s.ModelPrice(ctx, "test-model")
+	s.ModelPrice(ctx, "unknown-model")
+	stats := s.Stats()
+	assert.Equal(t, BackendLiteLLM, stats.Backend)
+	assert.Equal(t, LoadFetched, stats.Load)
+	assert.Equal(t, 1, stats.Fetches)
+	assert.Zero(t, stats.FetchErrors)
+	assert.Equal(t, int64(3), stats.Lookups)
+	assert.Equal(t, int64(1), stats.MemoHits)
+	assert.Equal(t, map[Source]int64{SourceLiteLLM: 2, SourceDefault: 1}, stats.BySource)
+	assert.Equal(t, []ModelSource{{"test-model", SourceLiteLLM}, {"unknown-model", SourceDefault}}, stats.Models)
+
+	// A second run reads the on-disk copy
+	next := useTransport(NewService(), transport)
+	next.ModelPrice(ctx, "test-model")
+	stats = next.Stats()
+	assert.Equal(t, LoadDiskCache, stats.Load)
+	assert.Equal(t, 1, stats.DiskCacheHits)
+	assert.Zero(t, stats.Fetches)
+}
+
+func TestStatsFailedFetch(t *testing.T) {
+	s := newTestService(t, &fakeTransport{})
+	s.ModelPrice(context.Background(), "claude-sonnet-4-5")
+
+	stats := s.Stats()
+	assert.Equal(t, LoadEmpty, stats.Load)
+	assert.Equal(t, 1, stats.Fetches)
+	assert.Equal(t, 1, stats.FetchErrors)
+	assert.Equal(t, map[Source]int64{SourceEmbedded: 1}, stats.BySource)
+}
@@ -0,0 +1,29 @@
+package pricing
+
+import (
+	"fmt"
+	"sort"
+)
+
+// UnknownModelError is returned by GetModelPrice of a strict service for a
+// model without pricing
+type UnknownModelError struct {
+	Model string
+}
+
+func (e *UnknownModelError) Error() string {
+	return fmt.Sprintf("no pricing for model %q", e.Model)
+}
+
+// UnknownModels returns the models the service was asked to price but found
+// no pricing for, sorted: their costs are estimates at the default rates, or
+// missing with Options.Strict
+func (s *Service) UnknownModels() []string {
+	var models []string
+	s.stats.unknown.Range(func(model, _ any) bool {
+		models = append(models, model.(string))
+		return true
+	})
+	sort.Strings(models)
+	return models
+}
@@ -0,0 +1,94 @@
+package pricing
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// TransportOptions tune the HTTP transport of NewTransportWith. Zero values
+// keep the settings of http.DefaultTransport.
+type TransportOptions struct {
+	// Proxy replaces the HTTPS_PROXY/HTTP_PROXY/NO_PROXY environment
+	Proxy string
+	// CABundle names a PEM file of certificates trusted in addition to the
+	// system ones
+	CABundle string
+
+	// DialTimeout and TLSHandshakeTimeout limit connecting to the server,
+	// so a stalled connection fails fast and is retried
+	DialTimeout         time.Duration
+	TLSHandshakeTimeout time.Duration
+	// KeepAlive is the TCP keep-alive period of connections (negative
+	// disables keep-alive probes)
+	KeepAlive time.Duration
+	// DisableKeepAlives closes each connection after its request, for
+	// processes that fetch once
+	DisableKeepAlives bool
+}
+
+// NewTransport returns an HTTP transport for LiteLLM fetches behind corporate
+// proxies and TLS-intercepting firewalls. proxyURL replaces the
+// HTTPS_PROXY/HTTP_PROXY/NO_PROXY environment ("" keeps it); caBundle names a
+// PEM file of certificates trusted in addition to the system ones.
+func NewTransport(proxyURL, caBundle string) (*http.Transport, error) {
+	return NewTransportWith(TransportOptions{Proxy: proxyURL, CABundle: caBundle})
+}
+
+// NewTransportWith returns an HTTP transport for LiteLLM fetches configured
+// by opts
+func NewTransportWith(opts TransportOptions) (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.DialTimeout > 0 || opts.KeepAlive != 0 {
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second} // Those of http.DefaultTransport
+		if opts.DialTimeout > 0 {
+			dialer.Timeout = opts.DialTimeout
+		}
+		if opts.KeepAlive != 0 {
+			dialer.KeepAlive = opts.KeepAlive
+		}
+		t.DialContext = dialer.DialContext
+	}
+	if opts.TLSHandshakeTimeout > 0 {
+		t.TLSHandshakeTimeout = opts.TLSHandshakeTimeout
+	}
+	t.DisableKeepAlives = opts.DisableKeepAlives
+
+	proxyURL, caBundle := opts.Proxy, opts.CABundle
+	if proxyURL != "" {
+		u, err := url.Parse(proxyURL)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid proxy %q, use e.g. http://proxy.example.com:8080", proxyURL)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("invalid proxy %q: scheme must be http, https or socks5", proxyURL)
+		}
+		t.Proxy = http.ProxyURL(u)
+	}
+	if caBundle != "" {
+		pem, err := os.ReadFile(caBundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool() // No system pool (e.g. some Windows setups): trust the bundle only
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("invalid CA bundle %s: no PEM certificates found", caBundle)
+		}
+		t.TLSClientConfig = t.TLSClientConfig.Clone()
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		t.TLSClientConfig.RootCAs = pool
+	}
+	return t, nil
+}
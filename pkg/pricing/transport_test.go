@@ -0,0 +1,100 @@
+package pricing
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTransportProxy(t *testing.T) {
+	transport, err := NewTransport("http://proxy.example.com:8080", "")
+	require.NoError(t, err)
+	req, _ := http.NewRequest("GET", "https://raw.githubusercontent.com/", nil)
+	proxy, err := transport.Proxy(req)
+	require.NoError(t, err)
+	assert.Equal(t, "http://proxy.example.com:8080", proxy.String())
+
+	for _, invalid := range []string{"proxy.example.com:8080", "ftp://proxy.example.com", "http://"} {
+		_, err := NewTransport(invalid, "")
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestNewTransportCABundle(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testPricing))
+	}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0) // The untrusted attempt fails the handshake
+	server.StartTLS()
+	defer server.Close()
+
+	// The test server's self-signed certificate stands in for a firewall's
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(bundle, certPEM, 0o644))
+
+	_, err := (&http.Client{}).Get(server.URL)
+	require.Error(t, err)
+
+	transport, err := NewTransport("", bundle)
+	require.NoError(t, err)
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	invalid := filepath.Join(t.TempDir(), "invalid.pem")
+	require.NoError(t, os.WriteFile(invalid, []byte("not a certificate"), 0o644))
+	_, err = NewTransport("", invalid)
+	assert.Error(t, err)
+	_, err = NewTransport("", filepath.Join(t.TempDir(), "missing.pem"))
+	assert.Error(t, err)
+}
+
+func TestNewTransportWith(t *testing.T) {
+	transport, err := NewTransportWith(TransportOptions{
+		Proxy:               "http://proxy.example.com:8080",
+		DialTimeout:         time.Second,
+		TLSHandshakeTimeout: 2 * time.Second,
+		KeepAlive:           -1,
+		DisableKeepAlives:   true,
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, transport.Proxy)
+	assert.NotNil(t, transport.DialContext)
+	assert.Equal(t, 2*time.Second, transport.TLSHandshakeTimeout)
+	assert.True(t, transport.DisableKeepAlives)
+
+	_, err = NewTransportWith(TransportOptions{Proxy: "ftp://proxy.example.com"})
+	assert.Error(t, err)
+}
+
+// stalledTransport never answers, like a network that drops packets
+type stalledTransport struct{}
+
+func (stalledTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+func TestFetchTimeout(t *testing.T) {
+	s := New(Options{NoDiskCache: true, Transport: stalledTransport{}, Timeout: 150 * time.Millisecond, Warnings: io.Discard})
+	s.retryDelay = time.Millisecond
+
+	start := time.Now()
+	err := s.Refresh(context.Background())
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Contains(t, err.Error(), "no response within 150ms")
+	assert.Equal(t, 50*time.Millisecond, s.client.Timeout)
+}